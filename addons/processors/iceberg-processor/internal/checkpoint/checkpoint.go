@@ -21,21 +21,26 @@ import (
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
 )
 
-// Lease ties a worker to a partition with TTL-based ownership.
+// Lease ties a worker to a partition with TTL-based ownership. Table leases,
+// used for maintenance, set Table instead of Topic and Partition.
 type Lease struct {
 	Topic     string
 	Partition int32
+	Table     string
 	OwnerID   string
 	ExpiresAt int64
 	LeaseID   int64
 }
 
-// OffsetState tracks the last committed offset for a partition.
+// OffsetState tracks the last committed offset for a partition. Skipped is
+// set when the record at Offset was dead-lettered or filtered instead of
+// written, so a sink that commits offsets with its data may trail it.
 type OffsetState struct {
 	Topic     string
 	Partition int32
 	Offset    int64
 	Timestamp int64
+	Skipped   bool
 }
 
 // Store persists leases and offsets.
@@ -43,6 +48,15 @@ type Store interface {
 	ClaimLease(ctx context.Context, topic string, partition int32, ownerID string) (Lease, error)
 	RenewLease(ctx context.Context, lease Lease) error
 	ReleaseLease(ctx context.Context, lease Lease) error
+	ListLeases(ctx context.Context) ([]Lease, error)
+	// ClaimTableLease takes the maintenance lease of a table. It is renewed
+	// and released like partition leases but is not listed by ListLeases.
+	ClaimTableLease(ctx context.Context, table string, ownerID string) (Lease, error)
+	// RegisterWorker announces or refreshes a live worker. Workers that stop
+	// heartbeating drop out of ListWorkers after the lease TTL.
+	RegisterWorker(ctx context.Context, ownerID string) error
+	UnregisterWorker(ctx context.Context, ownerID string) error
+	ListWorkers(ctx context.Context) ([]string, error)
 	LoadOffset(ctx context.Context, topic string, partition int32) (OffsetState, error)
 	CommitOffset(ctx context.Context, state OffsetState) error
 }
@@ -61,6 +75,10 @@ func (n *noopStore) ClaimLease(ctx context.Context, topic string, partition int3
 	return Lease{Topic: topic, Partition: partition, OwnerID: ownerID}, nil
 }
 
+func (n *noopStore) ClaimTableLease(ctx context.Context, table string, ownerID string) (Lease, error) {
+	return Lease{Table: table, OwnerID: ownerID}, nil
+}
+
 func (n *noopStore) RenewLease(ctx context.Context, lease Lease) error {
 	return nil
 }
@@ -69,6 +87,22 @@ func (n *noopStore) ReleaseLease(ctx context.Context, lease Lease) error {
 	return nil
 }
 
+func (n *noopStore) ListLeases(ctx context.Context) ([]Lease, error) {
+	return nil, nil
+}
+
+func (n *noopStore) RegisterWorker(ctx context.Context, ownerID string) error {
+	return nil
+}
+
+func (n *noopStore) UnregisterWorker(ctx context.Context, ownerID string) error {
+	return nil
+}
+
+func (n *noopStore) ListWorkers(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
 func (n *noopStore) LoadOffset(ctx context.Context, topic string, partition int32) (OffsetState, error) {
 	return OffsetState{Topic: topic, Partition: partition, Offset: 0}, nil
 }
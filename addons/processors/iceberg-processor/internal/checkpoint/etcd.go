@@ -20,10 +20,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
 )
@@ -35,6 +40,9 @@ type etcdStore struct {
 	client          *clientv3.Client
 	prefix          string
 	leaseTTLSeconds int
+
+	mu           sync.Mutex
+	workerLeases map[string]clientv3.LeaseID
 }
 
 func NewEtcdStore(cfg config.Config) (Store, error) {
@@ -61,10 +69,23 @@ func NewEtcdStore(cfg config.Config) (Store, error) {
 		return nil, err
 	}
 
-	return &etcdStore{client: client, prefix: keyPrefix, leaseTTLSeconds: ttlSeconds}, nil
+	return &etcdStore{
+		client:          client,
+		prefix:          keyPrefix,
+		leaseTTLSeconds: ttlSeconds,
+		workerLeases:    make(map[string]clientv3.LeaseID),
+	}, nil
 }
 
 func (s *etcdStore) ClaimLease(ctx context.Context, topic string, partition int32, ownerID string) (Lease, error) {
+	return s.claim(ctx, Lease{Topic: topic, Partition: partition, OwnerID: ownerID})
+}
+
+func (s *etcdStore) ClaimTableLease(ctx context.Context, table string, ownerID string) (Lease, error) {
+	return s.claim(ctx, Lease{Table: table, OwnerID: ownerID})
+}
+
+func (s *etcdStore) claim(ctx context.Context, claimed Lease) (Lease, error) {
 	lease, err := s.client.Grant(ctx, int64(s.leaseTTLSeconds))
 	if err != nil {
 		return Lease{}, err
@@ -72,7 +93,7 @@ func (s *etcdStore) ClaimLease(ctx context.Context, topic string, partition int3
 
 	now := time.Now()
 	payload := leaseState{
-		OwnerID:        ownerID,
+		OwnerID:        claimed.OwnerID,
 		LeaseExpiresAt: now.Add(time.Duration(s.leaseTTLSeconds) * time.Second).UnixMilli(),
 		Generation:     1,
 	}
@@ -81,7 +102,7 @@ func (s *etcdStore) ClaimLease(ctx context.Context, topic string, partition int3
 		return Lease{}, err
 	}
 
-	key := s.leaseKey(topic, partition)
+	key := s.keyFor(claimed)
 	txn := s.client.Txn(ctx)
 	resp, err := txn.If(clientv3.Compare(clientv3.Version(key), "=", 0)).
 		Then(clientv3.OpPut(key, string(data), clientv3.WithLease(lease.ID))).
@@ -95,13 +116,9 @@ func (s *etcdStore) ClaimLease(ctx context.Context, topic string, partition int3
 		return Lease{}, errors.New("lease already held")
 	}
 
-	return Lease{
-		Topic:     topic,
-		Partition: partition,
-		OwnerID:   ownerID,
-		ExpiresAt: payload.LeaseExpiresAt,
-		LeaseID:   int64(lease.ID),
-	}, nil
+	claimed.ExpiresAt = payload.LeaseExpiresAt
+	claimed.LeaseID = int64(lease.ID)
+	return claimed, nil
 }
 
 func (s *etcdStore) RenewLease(ctx context.Context, lease Lease) error {
@@ -124,19 +141,95 @@ func (s *etcdStore) RenewLease(ctx context.Context, lease Lease) error {
 		return err
 	}
 
-	key := s.leaseKey(lease.Topic, lease.Partition)
-	_, err = s.client.Put(ctx, key, string(data), clientv3.WithLease(clientv3.LeaseID(lease.LeaseID)))
+	_, err = s.client.Put(ctx, s.keyFor(lease), string(data), clientv3.WithLease(clientv3.LeaseID(lease.LeaseID)))
 	return err
 }
 
 func (s *etcdStore) ReleaseLease(ctx context.Context, lease Lease) error {
-	if lease.LeaseID != 0 {
-		_, _ = s.client.Revoke(ctx, clientv3.LeaseID(lease.LeaseID))
+	if lease.LeaseID == 0 {
+		_, err := s.client.Delete(ctx, s.keyFor(lease))
+		return err
+	}
+	// Revoking deletes the key only while it is still attached to our lease,
+	// so a late release cannot drop a lease another worker has since claimed.
+	_, err := s.client.Revoke(ctx, clientv3.LeaseID(lease.LeaseID))
+	if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *etcdStore) ListLeases(ctx context.Context) ([]Lease, error) {
+	prefix := s.prefix + "/leases/"
+	resp, err := s.client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	leases := make([]Lease, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		lease, ok := parseLease(strings.TrimPrefix(string(kv.Key), prefix), kv)
+		if ok {
+			leases = append(leases, lease)
+		}
+	}
+	return leases, nil
+}
+
+func (s *etcdStore) RegisterWorker(ctx context.Context, ownerID string) error {
+	s.mu.Lock()
+	leaseID := s.workerLeases[ownerID]
+	s.mu.Unlock()
+	if leaseID != 0 {
+		if _, err := s.client.KeepAliveOnce(ctx, leaseID); err == nil {
+			return nil
+		} else if !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+			return err
+		}
+	}
+
+	lease, err := s.client.Grant(ctx, int64(s.leaseTTLSeconds))
+	if err != nil {
+		return err
+	}
+	if _, err := s.client.Put(ctx, s.workerKey(ownerID), ownerID, clientv3.WithLease(lease.ID)); err != nil {
+		_, _ = s.client.Revoke(ctx, lease.ID)
+		return err
+	}
+	s.mu.Lock()
+	s.workerLeases[ownerID] = lease.ID
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *etcdStore) UnregisterWorker(ctx context.Context, ownerID string) error {
+	s.mu.Lock()
+	leaseID := s.workerLeases[ownerID]
+	delete(s.workerLeases, ownerID)
+	s.mu.Unlock()
+	if leaseID == 0 {
+		return nil
+	}
+	_, err := s.client.Revoke(ctx, leaseID)
+	if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return nil
 	}
-	_, err := s.client.Delete(ctx, s.leaseKey(lease.Topic, lease.Partition))
 	return err
 }
 
+func (s *etcdStore) ListWorkers(ctx context.Context) ([]string, error) {
+	prefix := s.prefix + "/workers/"
+	resp, err := s.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+	workers := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		workers = append(workers, strings.TrimPrefix(string(kv.Key), prefix))
+	}
+	sort.Strings(workers)
+	return workers, nil
+}
+
 func (s *etcdStore) LoadOffset(ctx context.Context, topic string, partition int32) (OffsetState, error) {
 	resp, err := s.client.Get(ctx, s.offsetKey(topic, partition))
 	if err != nil {
@@ -156,6 +249,7 @@ func (s *etcdStore) LoadOffset(ctx context.Context, topic string, partition int3
 		Partition: partition,
 		Offset:    state.Offset,
 		Timestamp: state.LastTimestampMs,
+		Skipped:   state.Skipped,
 	}, nil
 }
 
@@ -165,6 +259,7 @@ func (s *etcdStore) CommitOffset(ctx context.Context, state OffsetState) error {
 		Offset:          state.Offset,
 		LastTimestampMs: state.Timestamp,
 		UpdatedAt:       now,
+		Skipped:         state.Skipped,
 	}
 	data, err := json.Marshal(payload)
 	if err != nil {
@@ -207,6 +302,21 @@ func (s *etcdStore) leaseKey(topic string, partition int32) string {
 	return fmt.Sprintf("%s/leases/%s/%d", s.prefix, topic, partition)
 }
 
+func (s *etcdStore) tableLeaseKey(table string) string {
+	return fmt.Sprintf("%s/maintenance/%s", s.prefix, table)
+}
+
+func (s *etcdStore) keyFor(lease Lease) string {
+	if lease.Table != "" {
+		return s.tableLeaseKey(lease.Table)
+	}
+	return s.leaseKey(lease.Topic, lease.Partition)
+}
+
+func (s *etcdStore) workerKey(ownerID string) string {
+	return fmt.Sprintf("%s/workers/%s", s.prefix, ownerID)
+}
+
 func (s *etcdStore) offsetKey(topic string, partition int32) string {
 	return fmt.Sprintf("%s/offsets/%s/%d", s.prefix, topic, partition)
 }
@@ -233,6 +343,31 @@ type offsetState struct {
 	Offset          int64 `json:"offset"`
 	LastTimestampMs int64 `json:"last_timestamp_ms"`
 	UpdatedAt       int64 `json:"updated_at"`
+	Skipped         bool  `json:"skipped,omitempty"`
+}
+
+// parseLease decodes a lease entry whose key, relative to the leases prefix,
+// is "<topic>/<partition>".
+func parseLease(rel string, kv *mvccpb.KeyValue) (Lease, bool) {
+	idx := strings.LastIndex(rel, "/")
+	if idx <= 0 {
+		return Lease{}, false
+	}
+	partition, err := strconv.ParseInt(rel[idx+1:], 10, 32)
+	if err != nil {
+		return Lease{}, false
+	}
+	var state leaseState
+	if err := json.Unmarshal(kv.Value, &state); err != nil {
+		return Lease{}, false
+	}
+	return Lease{
+		Topic:     rel[:idx],
+		Partition: int32(partition),
+		OwnerID:   state.OwnerID,
+		ExpiresAt: state.LeaseExpiresAt,
+		LeaseID:   kv.Lease,
+	}, true
 }
 
 func minWatermark(kvs []*mvccpb.KeyValue) (offsetState, error) {
@@ -57,6 +57,44 @@ func TestLeaseExpirationRecoveryEtcd(t *testing.T) {
 	}
 }
 
+func TestTableLeaseEtcd(t *testing.T) {
+	endpoints := etcdEndpointsFromEnv(t)
+
+	cfg := config.Config{
+		Etcd: config.EtcdConfig{Endpoints: endpoints},
+		Offsets: config.OffsetConfig{
+			Backend:   "etcd",
+			KeyPrefix: "processors-test-" + strconv.FormatInt(time.Now().UnixNano(), 10),
+		},
+	}
+
+	store, err := NewEtcdStore(cfg)
+	if err != nil {
+		t.Fatalf("NewEtcdStore: %v", err)
+	}
+
+	ctx := context.Background()
+	lease, err := store.ClaimTableLease(ctx, "prod.orders", "worker-a")
+	if err != nil {
+		t.Fatalf("ClaimTableLease worker-a: %v", err)
+	}
+	if _, err := store.ClaimTableLease(ctx, "prod.orders", "worker-b"); err == nil {
+		t.Fatalf("expected table lease conflict")
+	}
+	if leases, err := store.ListLeases(ctx); err != nil || len(leases) != 0 {
+		t.Fatalf("expected table leases to be hidden from ListLeases, got %v err=%v", leases, err)
+	}
+	if err := store.RenewLease(ctx, lease); err != nil {
+		t.Fatalf("RenewLease: %v", err)
+	}
+	if err := store.ReleaseLease(ctx, lease); err != nil {
+		t.Fatalf("ReleaseLease: %v", err)
+	}
+	if _, err := store.ClaimTableLease(ctx, "prod.orders", "worker-b"); err != nil {
+		t.Fatalf("expected table lease after release: %v", err)
+	}
+}
+
 func TestCommitAndLoadOffsetEtcd(t *testing.T) {
 	endpoints := etcdEndpointsFromEnv(t)
 
@@ -87,6 +125,64 @@ func TestCommitAndLoadOffsetEtcd(t *testing.T) {
 	}
 }
 
+func TestWorkersAndLeasesEtcd(t *testing.T) {
+	endpoints := etcdEndpointsFromEnv(t)
+
+	cfg := config.Config{
+		Etcd: config.EtcdConfig{Endpoints: endpoints},
+		Offsets: config.OffsetConfig{
+			Backend:   "etcd",
+			KeyPrefix: "processors-test-" + strconv.FormatInt(time.Now().UnixNano(), 10),
+		},
+	}
+
+	store, err := NewEtcdStore(cfg)
+	if err != nil {
+		t.Fatalf("NewEtcdStore: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, owner := range []string{"worker-b", "worker-a", "worker-a"} {
+		if err := store.RegisterWorker(ctx, owner); err != nil {
+			t.Fatalf("RegisterWorker %s: %v", owner, err)
+		}
+	}
+	workers, err := store.ListWorkers(ctx)
+	if err != nil {
+		t.Fatalf("ListWorkers: %v", err)
+	}
+	if strings.Join(workers, ",") != "worker-a,worker-b" {
+		t.Fatalf("unexpected workers: %v", workers)
+	}
+	if err := store.UnregisterWorker(ctx, "worker-b"); err != nil {
+		t.Fatalf("UnregisterWorker: %v", err)
+	}
+	if workers, _ := store.ListWorkers(ctx); len(workers) != 1 {
+		t.Fatalf("expected 1 worker after unregister, got %v", workers)
+	}
+
+	lease, err := store.ClaimLease(ctx, "orders", 3, "worker-a")
+	if err != nil {
+		t.Fatalf("ClaimLease: %v", err)
+	}
+	leases, err := store.ListLeases(ctx)
+	if err != nil {
+		t.Fatalf("ListLeases: %v", err)
+	}
+	if len(leases) != 1 || leases[0].Topic != "orders" || leases[0].Partition != 3 || leases[0].OwnerID != "worker-a" {
+		t.Fatalf("unexpected leases: %#v", leases)
+	}
+	if err := store.ReleaseLease(ctx, lease); err != nil {
+		t.Fatalf("ReleaseLease: %v", err)
+	}
+	if err := store.ReleaseLease(ctx, lease); err != nil {
+		t.Fatalf("second ReleaseLease: %v", err)
+	}
+	if leases, _ := store.ListLeases(ctx); len(leases) != 0 {
+		t.Fatalf("expected no leases after release, got %#v", leases)
+	}
+}
+
 func etcdEndpointsFromEnv(t *testing.T) []string {
 	t.Helper()
 
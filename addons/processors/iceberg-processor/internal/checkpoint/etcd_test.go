@@ -49,3 +49,13 @@ func TestMinWatermark(t *testing.T) {
 		t.Fatalf("expected min timestamp 50, got %d", min.LastTimestampMs)
 	}
 }
+
+func TestLeaseKeys(t *testing.T) {
+	store := &etcdStore{prefix: "processors"}
+	if key := store.keyFor(Lease{Topic: "orders", Partition: 3}); key != "processors/leases/orders/3" {
+		t.Fatalf("unexpected partition lease key %q", key)
+	}
+	if key := store.keyFor(Lease{Table: "prod.orders"}); key != "processors/maintenance/prod.orders" {
+		t.Fatalf("unexpected table lease key %q", key)
+	}
+}
@@ -18,6 +18,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -33,6 +34,23 @@ type Config struct {
 	Mappings  []Mapping       `yaml:"mappings"`
 	Offsets   OffsetConfig    `yaml:"offsets"`
 	Processor ProcessorConfig `yaml:"processor"`
+	DLQ       DLQConfig       `yaml:"dlq"`
+	// Maintenance compacts, expires and cleans up mapped tables in the
+	// background. It is disabled by default.
+	Maintenance MaintenanceConfig `yaml:"maintenance"`
+	Sink        SinkConfig        `yaml:"sink"`
+}
+
+// SinkConfig selects the table format: iceberg (default) or delta.
+type SinkConfig struct {
+	Type  string      `yaml:"type"`
+	Delta DeltaConfig `yaml:"delta"`
+}
+
+// DeltaConfig configures the Delta Lake sink. Each mapped table is stored at
+// <warehouse>/<table>, with the dots of the table name as path separators.
+type DeltaConfig struct {
+	Warehouse string `yaml:"warehouse"`
 }
 
 type S3Config struct {
@@ -51,6 +69,56 @@ type OffsetConfig struct {
 
 type ProcessorConfig struct {
 	PollIntervalSeconds int `yaml:"poll_interval_seconds"`
+	MaxLeases           int `yaml:"max_leases"`
+	// FlushIntervalSeconds caps how long records are buffered across segments
+	// before they are committed, when the target file size is not reached.
+	FlushIntervalSeconds int `yaml:"flush_interval_seconds"`
+}
+
+// DLQConfig selects where rejected records go. An empty type disables the
+// dead-letter queue.
+type DLQConfig struct {
+	Type  string         `yaml:"type"`
+	Kafka DLQKafkaConfig `yaml:"kafka"`
+	S3    DLQS3Config    `yaml:"s3"`
+	Retry RetryConfig    `yaml:"retry"`
+}
+
+type DLQKafkaConfig struct {
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+}
+
+type DLQS3Config struct {
+	Bucket string `yaml:"bucket"`
+	Prefix string `yaml:"prefix"`
+}
+
+// RetryConfig bounds sink write retries within one poll. Batches that still
+// fail are retried on the next poll.
+type RetryConfig struct {
+	MaxAttempts      int `yaml:"max_attempts"`
+	InitialBackoffMs int `yaml:"initial_backoff_ms"`
+	MaxBackoffMs     int `yaml:"max_backoff_ms"`
+}
+
+// MaintenanceConfig schedules table maintenance. Each run holds a per-table
+// lease in the offsets store, so only one worker maintains a table at a time.
+type MaintenanceConfig struct {
+	Enabled                bool             `yaml:"enabled"`
+	IntervalSeconds        int              `yaml:"interval_seconds"`
+	Compaction             CompactionConfig `yaml:"compaction"`
+	SnapshotRetentionHours int              `yaml:"snapshot_retention_hours"`
+	RetainLastSnapshots    int              `yaml:"retain_last_snapshots"`
+	OrphanOlderThanHours   int              `yaml:"orphan_older_than_hours"`
+}
+
+// CompactionConfig selects the data files that are rewritten. Files below
+// SmallFileBytes are merged once a partition has MinInputFiles of them. Zero
+// SmallFileBytes means three quarters of the mapping's target file size.
+type CompactionConfig struct {
+	MinInputFiles  int   `yaml:"min_input_files"`
+	SmallFileBytes int64 `yaml:"small_file_bytes"`
 }
 
 type DiscoveryConfig struct {
@@ -68,7 +136,11 @@ type SchemaConfig struct {
 	Registry RegistryConfig `yaml:"registry"`
 }
 
+// RegistryConfig points at the schema registry. Type "static" (default)
+// serves JSON Schema documents at <base_url>/<topic>.json; "confluent" is a
+// Confluent-compatible registry with Avro, Protobuf and JSON Schema subjects.
 type RegistryConfig struct {
+	Type           string `yaml:"type"`
 	BaseURL        string `yaml:"base_url"`
 	TimeoutSeconds int    `yaml:"timeout_seconds"`
 	CacheSeconds   int    `yaml:"cache_seconds"`
@@ -80,18 +152,58 @@ type Mapping struct {
 	Mode                string `yaml:"mode"`
 	CreateTableIfAbsent bool   `yaml:"create_table_if_missing"`
 	Schema              MappingSchemaConfig `yaml:"schema"`
+	// KeyColumns identify a row in upsert mode. Empty means the Kafka key.
+	KeyColumns []string `yaml:"key_columns"`
+	// Envelope selects how upsert payloads are decoded: "" for plain rows or
+	// "debezium" for Debezium change events.
+	Envelope  string           `yaml:"envelope"`
+	Partition []PartitionField `yaml:"partition_spec"`
+	SortOrder []SortField      `yaml:"sort_order"`
+	Write     WriteConfig      `yaml:"write"`
+}
+
+// PartitionField partitions a table by a column transform: identity, year,
+// month, day, hour, bucket[N] or truncate[N].
+type PartitionField struct {
+	Column    string `yaml:"column"`
+	Transform string `yaml:"transform"`
+}
+
+type SortField struct {
+	Column    string `yaml:"column"`
+	Direction string `yaml:"direction"`
+	NullOrder string `yaml:"null_order"`
+}
+
+// WriteConfig sizes data files. TargetFileSizeBytes also bounds how many
+// bytes a partition buffers before committing.
+type WriteConfig struct {
+	TargetFileSizeBytes int64 `yaml:"target_file_size_bytes"`
+	RowGroupSizeBytes   int64 `yaml:"row_group_size_bytes"`
+	RowGroupLimit       int64 `yaml:"row_group_limit"`
 }
 
 type MappingSchemaConfig struct {
-	Source            string   `yaml:"source"`
-	Columns           []Column `yaml:"columns"`
-	AllowTypeWidening bool     `yaml:"allow_type_widening"`
+	Source  string   `yaml:"source"`
+	Columns []Column `yaml:"columns"`
+	// Subject is the Confluent registry subject; defaults to <topic>-value.
+	Subject string `yaml:"subject"`
+	// Message is the full name of the Protobuf message that holds the table
+	// columns. Without it, the message selected by the most recently decoded
+	// payload is used, then the first message of the schema.
+	Message           string `yaml:"message"`
+	AllowTypeWidening bool   `yaml:"allow_type_widening"`
 }
 
+// Column is a table column. Struct columns list their Fields; list and map
+// columns describe their items, or map values, in Element. Map keys are
+// strings.
 type Column struct {
-	Name     string `yaml:"name"`
-	Type     string `yaml:"type"`
-	Required bool   `yaml:"required"`
+	Name     string   `yaml:"name"`
+	Type     string   `yaml:"type"`
+	Required bool     `yaml:"required"`
+	Fields   []Column `yaml:"fields"`
+	Element  *Column  `yaml:"element"`
 }
 
 type IcebergConfig struct {
@@ -99,12 +211,29 @@ type IcebergConfig struct {
 	Warehouse string        `yaml:"warehouse"`
 }
 
+// CatalogConfig selects the Iceberg catalog. Type is rest, sql, glue or
+// hadoop. URI is the REST endpoint or, for sql, the database DSN.
 type CatalogConfig struct {
-	Type     string `yaml:"type"`
-	URI      string `yaml:"uri"`
-	Token    string `yaml:"token"`
-	Username string `yaml:"username"`
-	Password string `yaml:"password"`
+	Type     string            `yaml:"type"`
+	URI      string            `yaml:"uri"`
+	Token    string            `yaml:"token"`
+	Username string            `yaml:"username"`
+	Password string            `yaml:"password"`
+	SQL      SQLCatalogConfig  `yaml:"sql"`
+	Glue     GlueCatalogConfig `yaml:"glue"`
+}
+
+// SQLCatalogConfig configures the JDBC-style SQL catalog.
+type SQLCatalogConfig struct {
+	// Dialect is sqlite or postgres. It defaults from the URI scheme.
+	Dialect string `yaml:"dialect"`
+}
+
+// GlueCatalogConfig configures the AWS Glue catalog. Credentials come from
+// the default AWS chain.
+type GlueCatalogConfig struct {
+	Region    string `yaml:"region"`
+	CatalogID string `yaml:"catalog_id"`
 }
 
 func Load(path string) (Config, error) {
@@ -117,15 +246,32 @@ func Load(path string) (Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return Config{}, fmt.Errorf("parse config: %w", err)
 	}
+	applyEnvOverrides(&cfg)
 
 	if cfg.S3.Bucket == "" {
 		return Config{}, fmt.Errorf("s3.bucket is required")
 	}
-	if cfg.Iceberg.Catalog.Type == "" {
-		return Config{}, fmt.Errorf("iceberg.catalog.type is required")
+	if cfg.Sink.Type == "" {
+		cfg.Sink.Type = "iceberg"
 	}
-	if cfg.Iceberg.Catalog.URI == "" {
-		return Config{}, fmt.Errorf("iceberg.catalog.uri is required")
+	switch cfg.Sink.Type {
+	case "iceberg":
+		if err := validateCatalog(&cfg); err != nil {
+			return Config{}, err
+		}
+	case "delta":
+		warehouse := cfg.Sink.Delta.Warehouse
+		if warehouse == "" {
+			return Config{}, fmt.Errorf("sink.delta.warehouse is required for sink.type=delta")
+		}
+		if strings.Contains(warehouse, "://") && !strings.HasPrefix(warehouse, "s3://") && !strings.HasPrefix(warehouse, "file://") {
+			return Config{}, fmt.Errorf("sink.delta.warehouse must be an s3:// URI, a file:// URI or a local path")
+		}
+		if cfg.Maintenance.Enabled {
+			return Config{}, fmt.Errorf("maintenance is only supported for sink.type=iceberg")
+		}
+	default:
+		return Config{}, fmt.Errorf("sink.type must be iceberg or delta")
 	}
 	if cfg.Discovery.Mode == "" {
 		cfg.Discovery.Mode = "auto"
@@ -145,9 +291,85 @@ func Load(path string) (Config, error) {
 	if cfg.Processor.PollIntervalSeconds == 0 {
 		cfg.Processor.PollIntervalSeconds = 5
 	}
+	if cfg.Processor.MaxLeases == 0 {
+		cfg.Processor.MaxLeases = 16
+	}
+	if cfg.Processor.MaxLeases < 0 {
+		return Config{}, fmt.Errorf("processor.max_leases must be >= 0")
+	}
+	if cfg.Processor.FlushIntervalSeconds == 0 {
+		cfg.Processor.FlushIntervalSeconds = 30
+	}
+	if cfg.Processor.FlushIntervalSeconds < 0 {
+		return Config{}, fmt.Errorf("processor.flush_interval_seconds must be >= 0")
+	}
+	if cfg.DLQ.Retry.MaxAttempts == 0 {
+		cfg.DLQ.Retry.MaxAttempts = 5
+	}
+	if cfg.DLQ.Retry.InitialBackoffMs == 0 {
+		cfg.DLQ.Retry.InitialBackoffMs = 200
+	}
+	if cfg.DLQ.Retry.MaxBackoffMs == 0 {
+		cfg.DLQ.Retry.MaxBackoffMs = 10000
+	}
+	if cfg.Maintenance.IntervalSeconds == 0 {
+		cfg.Maintenance.IntervalSeconds = 3600
+	}
+	if cfg.Maintenance.Compaction.MinInputFiles == 0 {
+		cfg.Maintenance.Compaction.MinInputFiles = 5
+	}
+	if cfg.Maintenance.SnapshotRetentionHours == 0 {
+		cfg.Maintenance.SnapshotRetentionHours = 120
+	}
+	if cfg.Maintenance.RetainLastSnapshots == 0 {
+		cfg.Maintenance.RetainLastSnapshots = 1
+	}
+	if cfg.Maintenance.OrphanOlderThanHours == 0 {
+		cfg.Maintenance.OrphanOlderThanHours = 72
+	}
+	if cfg.Maintenance.IntervalSeconds < 0 || cfg.Maintenance.SnapshotRetentionHours < 0 || cfg.Maintenance.RetainLastSnapshots < 0 || cfg.Maintenance.OrphanOlderThanHours < 0 {
+		return Config{}, fmt.Errorf("maintenance intervals, retention and retain_last_snapshots must be >= 0")
+	}
+	if cfg.Maintenance.Compaction.MinInputFiles < 2 {
+		return Config{}, fmt.Errorf("maintenance.compaction.min_input_files must be >= 2")
+	}
+	if cfg.Maintenance.Compaction.SmallFileBytes < 0 {
+		return Config{}, fmt.Errorf("maintenance.compaction.small_file_bytes must be >= 0")
+	}
+	switch cfg.DLQ.Type {
+	case "":
+	case "kafka":
+		if len(cfg.DLQ.Kafka.Brokers) == 0 {
+			return Config{}, fmt.Errorf("dlq.kafka.brokers is required for dlq.type=kafka")
+		}
+		if cfg.DLQ.Kafka.Topic == "" {
+			cfg.DLQ.Kafka.Topic = "{topic}.dlq"
+		}
+	case "s3":
+		if cfg.DLQ.S3.Bucket == "" {
+			cfg.DLQ.S3.Bucket = cfg.S3.Bucket
+		}
+		if cfg.DLQ.S3.Prefix == "" {
+			cfg.DLQ.S3.Prefix = "dlq"
+		}
+	default:
+		return Config{}, fmt.Errorf("dlq.type must be kafka or s3")
+	}
 	if cfg.Schema.Mode != "off" && cfg.Schema.Registry.BaseURL == "" {
 		return Config{}, fmt.Errorf("schema.registry.base_url is required when schema.mode is enabled")
 	}
+	if cfg.Schema.Registry.Type == "" {
+		cfg.Schema.Registry.Type = "static"
+	}
+	switch cfg.Schema.Registry.Type {
+	case "static":
+	case "confluent":
+		if cfg.Schema.Mode != "off" {
+			return Config{}, fmt.Errorf("schema.mode validation requires schema.registry.type=static")
+		}
+	default:
+		return Config{}, fmt.Errorf("schema.registry.type must be static or confluent")
+	}
 	if cfg.Offsets.Backend == "etcd" && len(cfg.Etcd.Endpoints) == 0 {
 		return Config{}, fmt.Errorf("etcd.endpoints is required for offsets.backend=etcd")
 	}
@@ -161,8 +383,17 @@ func Load(path string) (Config, error) {
 		if mapping.Mode == "" {
 			mapping.Mode = "append"
 		}
-		if mapping.Mode != "append" {
-			return Config{}, fmt.Errorf("mappings[%d].mode must be append", i)
+		switch mapping.Mode {
+		case "append":
+			if len(mapping.KeyColumns) > 0 || mapping.Envelope != "" {
+				return Config{}, fmt.Errorf("mappings[%d].key_columns and envelope require mode=upsert", i)
+			}
+		case "upsert":
+			if mapping.Envelope != "" && mapping.Envelope != "debezium" {
+				return Config{}, fmt.Errorf("mappings[%d].envelope must be debezium", i)
+			}
+		default:
+			return Config{}, fmt.Errorf("mappings[%d].mode must be append or upsert", i)
 		}
 		if mapping.Schema.Source == "" {
 			if len(mapping.Schema.Columns) > 0 {
@@ -179,14 +410,8 @@ func Load(path string) (Config, error) {
 				return Config{}, fmt.Errorf("mappings[%d].schema.columns is required for schema.source=mapping", i)
 			}
 			for cIdx, col := range mapping.Schema.Columns {
-				if col.Name == "" {
-					return Config{}, fmt.Errorf("mappings[%d].schema.columns[%d].name is required", i, cIdx)
-				}
-				if col.Type == "" {
-					return Config{}, fmt.Errorf("mappings[%d].schema.columns[%d].type is required", i, cIdx)
-				}
-				if !isSupportedColumnType(col.Type) {
-					return Config{}, fmt.Errorf("mappings[%d].schema.columns[%d].type %q is not supported", i, cIdx, col.Type)
+				if err := validateColumn(col, true); err != nil {
+					return Config{}, fmt.Errorf("mappings[%d].schema.columns[%d]%w", i, cIdx, err)
 				}
 			}
 		case "registry":
@@ -197,12 +422,181 @@ func Load(path string) (Config, error) {
 		default:
 			return Config{}, fmt.Errorf("mappings[%d].schema.source %q is not supported", i, mapping.Schema.Source)
 		}
+		if mapping.Mode == "upsert" && len(mapping.Partition) > 0 {
+			return Config{}, fmt.Errorf("mappings[%d].partition_spec is not supported with mode=upsert", i)
+		}
+		if cfg.Sink.Type == "delta" && (mapping.Mode != "append" || len(mapping.Partition) > 0) {
+			return Config{}, fmt.Errorf("mappings[%d]: sink.type=delta supports mode=append without partition_spec", i)
+		}
+		for pIdx, field := range mapping.Partition {
+			if field.Column == "" {
+				return Config{}, fmt.Errorf("mappings[%d].partition_spec[%d].column is required", i, pIdx)
+			}
+			if !isSupportedTransform(field.Transform) {
+				return Config{}, fmt.Errorf("mappings[%d].partition_spec[%d].transform %q is not supported", i, pIdx, field.Transform)
+			}
+		}
+		for sIdx, field := range mapping.SortOrder {
+			if field.Column == "" {
+				return Config{}, fmt.Errorf("mappings[%d].sort_order[%d].column is required", i, sIdx)
+			}
+			if field.Direction == "" {
+				field.Direction = "asc"
+			}
+			if field.Direction != "asc" && field.Direction != "desc" {
+				return Config{}, fmt.Errorf("mappings[%d].sort_order[%d].direction must be asc or desc", i, sIdx)
+			}
+			if field.NullOrder == "" {
+				if field.Direction == "asc" {
+					field.NullOrder = "first"
+				} else {
+					field.NullOrder = "last"
+				}
+			}
+			if field.NullOrder != "first" && field.NullOrder != "last" {
+				return Config{}, fmt.Errorf("mappings[%d].sort_order[%d].null_order must be first or last", i, sIdx)
+			}
+			mapping.SortOrder[sIdx] = field
+		}
+		if mapping.Write.TargetFileSizeBytes == 0 {
+			mapping.Write.TargetFileSizeBytes = 128 << 20
+		}
+		if mapping.Write.TargetFileSizeBytes < 0 || mapping.Write.RowGroupSizeBytes < 0 || mapping.Write.RowGroupLimit < 0 {
+			return Config{}, fmt.Errorf("mappings[%d].write sizes must be >= 0", i)
+		}
+		if mapping.Schema.Source != "registry" {
+			for _, name := range mapping.KeyColumns {
+				if !hasColumn(mapping.Schema.Columns, name) {
+					return Config{}, fmt.Errorf("mappings[%d].key_columns %q is not a schema column", i, name)
+				}
+			}
+		}
 		cfg.Mappings[i] = mapping
 	}
 
 	return cfg, nil
 }
 
+// applyEnvOverrides reads catalog credentials from the environment so they
+// can be injected from a Secret rather than written into the config file.
+func applyEnvOverrides(cfg *Config) {
+	for key, target := range map[string]*string{
+		"ICEBERG_CATALOG_TOKEN":    &cfg.Iceberg.Catalog.Token,
+		"ICEBERG_CATALOG_USERNAME": &cfg.Iceberg.Catalog.Username,
+		"ICEBERG_CATALOG_PASSWORD": &cfg.Iceberg.Catalog.Password,
+	} {
+		if value := os.Getenv(key); value != "" {
+			*target = value
+		}
+	}
+}
+
+// validateCatalog checks the Iceberg catalog settings and fills in defaults.
+func validateCatalog(cfg *Config) error {
+	if cfg.Iceberg.Catalog.Type == "" {
+		return fmt.Errorf("iceberg.catalog.type is required")
+	}
+	switch cfg.Iceberg.Catalog.Type {
+	case "rest":
+		if cfg.Iceberg.Catalog.URI == "" {
+			return fmt.Errorf("iceberg.catalog.uri is required")
+		}
+	case "sql":
+		if cfg.Iceberg.Catalog.URI == "" {
+			return fmt.Errorf("iceberg.catalog.uri is required for iceberg.catalog.type=sql")
+		}
+		if cfg.Iceberg.Catalog.SQL.Dialect == "" {
+			cfg.Iceberg.Catalog.SQL.Dialect = "sqlite"
+			if strings.HasPrefix(cfg.Iceberg.Catalog.URI, "postgres://") || strings.HasPrefix(cfg.Iceberg.Catalog.URI, "postgresql://") {
+				cfg.Iceberg.Catalog.SQL.Dialect = "postgres"
+			}
+		}
+		if cfg.Iceberg.Catalog.SQL.Dialect != "sqlite" && cfg.Iceberg.Catalog.SQL.Dialect != "postgres" {
+			return fmt.Errorf("iceberg.catalog.sql.dialect must be sqlite or postgres")
+		}
+		if cfg.Iceberg.Warehouse == "" {
+			return fmt.Errorf("iceberg.warehouse is required for iceberg.catalog.type=sql")
+		}
+	case "glue":
+		if cfg.Iceberg.Catalog.Glue.Region == "" {
+			cfg.Iceberg.Catalog.Glue.Region = cfg.S3.Region
+		}
+		if cfg.Iceberg.Catalog.Glue.Region == "" {
+			return fmt.Errorf("iceberg.catalog.glue.region or s3.region is required for iceberg.catalog.type=glue")
+		}
+		if cfg.Iceberg.Warehouse == "" {
+			return fmt.Errorf("iceberg.warehouse is required for iceberg.catalog.type=glue")
+		}
+	case "hadoop":
+		if cfg.Iceberg.Warehouse == "" {
+			return fmt.Errorf("iceberg.warehouse is required for iceberg.catalog.type=hadoop")
+		}
+		if strings.Contains(cfg.Iceberg.Warehouse, "://") && !strings.HasPrefix(cfg.Iceberg.Warehouse, "file://") {
+			return fmt.Errorf("iceberg.warehouse must be a local path or file:// URI for iceberg.catalog.type=hadoop")
+		}
+	default:
+		return fmt.Errorf("iceberg.catalog.type must be rest, sql, glue or hadoop")
+	}
+	return nil
+}
+
+func isSupportedTransform(value string) bool {
+	switch value {
+	case "identity", "year", "month", "day", "hour":
+		return true
+	}
+	for _, prefix := range []string{"bucket[", "truncate["} {
+		if strings.HasPrefix(value, prefix) && strings.HasSuffix(value, "]") {
+			n, err := strconv.Atoi(value[len(prefix) : len(value)-1])
+			return err == nil && n > 0
+		}
+	}
+	return false
+}
+
+func hasColumn(columns []Column, name string) bool {
+	for _, col := range columns {
+		if col.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateColumn checks a column and its nested fields. Errors start with the
+// path below the column, so callers can prefix the column itself.
+func validateColumn(col Column, named bool) error {
+	if named && col.Name == "" {
+		return fmt.Errorf(".name is required")
+	}
+	if col.Type == "" {
+		return fmt.Errorf(".type is required")
+	}
+	switch strings.ToLower(col.Type) {
+	case "struct":
+		if len(col.Fields) == 0 {
+			return fmt.Errorf(".fields is required for type struct")
+		}
+		for i, field := range col.Fields {
+			if err := validateColumn(field, true); err != nil {
+				return fmt.Errorf(".fields[%d]%w", i, err)
+			}
+		}
+	case "list", "map":
+		if col.Element == nil {
+			return fmt.Errorf(".element is required for type %s", col.Type)
+		}
+		if err := validateColumn(*col.Element, false); err != nil {
+			return fmt.Errorf(".element%w", err)
+		}
+	default:
+		if !isSupportedColumnType(col.Type) {
+			return fmt.Errorf(".type %q is not supported", col.Type)
+		}
+	}
+	return nil
+}
+
 func isSupportedColumnType(value string) bool {
 	switch strings.ToLower(value) {
 	case "boolean", "int", "long", "float", "double", "string", "binary", "timestamp", "date":
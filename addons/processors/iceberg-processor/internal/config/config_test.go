@@ -18,6 +18,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -63,6 +64,9 @@ func TestLoadDefaultsOffsetsBackend(t *testing.T) {
 	if cfg.Processor.PollIntervalSeconds != 5 {
 		t.Fatalf("expected default poll interval 5, got %d", cfg.Processor.PollIntervalSeconds)
 	}
+	if cfg.Processor.MaxLeases != 16 {
+		t.Fatalf("expected default max leases 16, got %d", cfg.Processor.MaxLeases)
+	}
 	if cfg.Mappings[0].Mode != "append" {
 		t.Fatalf("expected default mapping mode append, got %q", cfg.Mappings[0].Mode)
 	}
@@ -153,3 +157,348 @@ func TestLoadRejectsRegistrySourceWithoutBaseURL(t *testing.T) {
 		t.Fatalf("expected error for schema.source=registry without base_url")
 	}
 }
+
+func TestLoadDLQDefaults(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\ndlq:\n  type: s3\nmappings:\n  - topic: orders\n    table: prod.orders\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.DLQ.S3.Bucket != "test-bucket" || cfg.DLQ.S3.Prefix != "dlq" {
+		t.Fatalf("unexpected dlq s3 defaults: %+v", cfg.DLQ.S3)
+	}
+	if cfg.DLQ.Retry.MaxAttempts != 5 || cfg.DLQ.Retry.InitialBackoffMs != 200 || cfg.DLQ.Retry.MaxBackoffMs != 10000 {
+		t.Fatalf("unexpected retry defaults: %+v", cfg.DLQ.Retry)
+	}
+}
+
+func TestLoadRejectsKafkaDLQWithoutBrokers(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\ndlq:\n  type: kafka\nmappings:\n  - topic: orders\n    table: prod.orders\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected error for dlq.type=kafka without brokers")
+	}
+}
+
+func TestLoadUpsertMapping(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\nmappings:\n  - topic: db.orders\n    table: prod.orders\n    mode: upsert\n    envelope: debezium\n    key_columns: [order_id]\n    schema:\n      columns:\n        - name: order_id\n          type: long\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Mappings[0].Mode != "upsert" || cfg.Mappings[0].Envelope != "debezium" || len(cfg.Mappings[0].KeyColumns) != 1 {
+		t.Fatalf("unexpected upsert mapping: %+v", cfg.Mappings[0])
+	}
+}
+
+func TestLoadRejectsUnknownKeyColumn(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\nmappings:\n  - topic: orders\n    table: prod.orders\n    mode: upsert\n    key_columns: [id]\n    schema:\n      columns:\n        - name: order_id\n          type: long\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected error for key column missing from schema columns")
+	}
+}
+
+func TestLoadRejectsEnvelopeInAppendMode(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\nmappings:\n  - topic: orders\n    table: prod.orders\n    envelope: debezium\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected error for envelope without mode=upsert")
+	}
+}
+
+func TestLoadLayoutDefaults(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\nmappings:\n  - topic: orders\n    table: prod.orders\n    partition_spec:\n      - column: _ts\n        transform: day\n      - column: customer_id\n        transform: bucket[16]\n    sort_order:\n      - column: customer_id\n      - column: _ts\n        direction: desc\n    schema:\n      columns:\n        - name: customer_id\n          type: long\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Processor.FlushIntervalSeconds != 30 {
+		t.Fatalf("expected default flush interval 30, got %d", cfg.Processor.FlushIntervalSeconds)
+	}
+	mapping := cfg.Mappings[0]
+	if mapping.Write.TargetFileSizeBytes != 128<<20 {
+		t.Fatalf("expected default target file size 128MiB, got %d", mapping.Write.TargetFileSizeBytes)
+	}
+	if len(mapping.Partition) != 2 {
+		t.Fatalf("expected 2 partition fields, got %+v", mapping.Partition)
+	}
+	if mapping.SortOrder[0].Direction != "asc" || mapping.SortOrder[0].NullOrder != "first" {
+		t.Fatalf("unexpected asc sort defaults: %+v", mapping.SortOrder[0])
+	}
+	if mapping.SortOrder[1].NullOrder != "last" {
+		t.Fatalf("expected desc sort to default to nulls last, got %+v", mapping.SortOrder[1])
+	}
+}
+
+func TestLoadRejectsUnknownPartitionTransform(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\nmappings:\n  - topic: orders\n    table: prod.orders\n    partition_spec:\n      - column: _ts\n        transform: week\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected error for unsupported partition transform")
+	}
+}
+
+func TestLoadRejectsPartitionedUpsert(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\nmappings:\n  - topic: orders\n    table: prod.orders\n    mode: upsert\n    partition_spec:\n      - column: _ts\n        transform: day\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected error for partition_spec with mode=upsert")
+	}
+}
+
+func TestLoadMaintenanceDefaults(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\nmaintenance:\n  enabled: true\nmappings:\n  - topic: orders\n    table: prod.orders\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	m := cfg.Maintenance
+	if m.IntervalSeconds != 3600 || m.Compaction.MinInputFiles != 5 {
+		t.Fatalf("unexpected maintenance defaults: %+v", m)
+	}
+	if m.SnapshotRetentionHours != 120 || m.RetainLastSnapshots != 1 || m.OrphanOlderThanHours != 72 {
+		t.Fatalf("unexpected retention defaults: %+v", m)
+	}
+}
+
+func TestLoadRejectsSingleFileCompaction(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\nmaintenance:\n  enabled: true\n  compaction:\n    min_input_files: 1\nmappings:\n  - topic: orders\n    table: prod.orders\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected error for compaction.min_input_files=1")
+	}
+}
+
+func TestLoadSQLCatalogDefaultsDialect(t *testing.T) {
+	cases := map[string]string{
+		"file:/var/lib/kafscale/catalog.db":        "sqlite",
+		"postgres://iceberg@db:5432/catalog":       "postgres",
+		"postgresql://iceberg@db:5432/catalog?x=1": "postgres",
+	}
+	for uri, dialect := range cases {
+		data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: sql\n    uri: " + uri + "\n  warehouse: file:///var/lib/kafscale/warehouse\noffsets:\n  backend: noop\nmappings:\n  - topic: orders\n    table: prod.orders\n")
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("load config for %s: %v", uri, err)
+		}
+		if cfg.Iceberg.Catalog.SQL.Dialect != dialect {
+			t.Fatalf("expected dialect %s for %s, got %s", dialect, uri, cfg.Iceberg.Catalog.SQL.Dialect)
+		}
+	}
+}
+
+func TestLoadGlueCatalogDefaultsRegion(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\n  region: eu-west-1\niceberg:\n  catalog:\n    type: glue\n  warehouse: s3://warehouse/prod\netcd:\n  endpoints:\n    - http://etcd:2379\nmappings:\n  - topic: orders\n    table: prod.orders\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Iceberg.Catalog.Glue.Region != "eu-west-1" {
+		t.Fatalf("expected glue region from s3.region, got %q", cfg.Iceberg.Catalog.Glue.Region)
+	}
+}
+
+func TestLoadRejectsInvalidCatalogs(t *testing.T) {
+	cases := map[string]string{
+		"unknown type":        "    type: hive\n    uri: thrift://metastore:9083\n  warehouse: s3://warehouse/prod\n",
+		"sql without uri":     "    type: sql\n  warehouse: /var/lib/kafscale/warehouse\n",
+		"sql dialect":         "    type: sql\n    uri: mysql://db/catalog\n    sql:\n      dialect: mysql\n  warehouse: /var/lib/kafscale/warehouse\n",
+		"glue without region": "    type: glue\n  warehouse: s3://warehouse/prod\n",
+		"hadoop on s3":        "    type: hadoop\n  warehouse: s3://warehouse/prod\n",
+		"hadoop no warehouse": "    type: hadoop\n",
+	}
+	for name, catalog := range cases {
+		data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n" + catalog + "etcd:\n  endpoints:\n    - http://etcd:2379\nmappings:\n  - topic: orders\n    table: prod.orders\n")
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+
+		if _, err := Load(path); err == nil {
+			t.Fatalf("expected error for %s", name)
+		}
+	}
+}
+
+func TestLoadDeltaSink(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\nsink:\n  type: delta\n  delta:\n    warehouse: s3://lake/delta\netcd:\n  endpoints:\n    - http://etcd:2379\nmappings:\n  - topic: orders\n    table: prod.orders\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Sink.Type != "delta" || cfg.Sink.Delta.Warehouse != "s3://lake/delta" {
+		t.Fatalf("unexpected sink config %+v", cfg.Sink)
+	}
+	if cfg.Mappings[0].Mode != "append" {
+		t.Fatalf("expected append mode, got %q", cfg.Mappings[0].Mode)
+	}
+}
+
+func TestLoadRejectsInvalidDeltaSinks(t *testing.T) {
+	cases := map[string]string{
+		"unknown sink":      "sink:\n  type: hudi\n",
+		"no warehouse":      "sink:\n  type: delta\n",
+		"gcs warehouse":     "sink:\n  type: delta\n  delta:\n    warehouse: gs://lake/delta\n",
+		"maintenance":       "sink:\n  type: delta\n  delta:\n    warehouse: /var/lib/kafscale/delta\nmaintenance:\n  enabled: true\n",
+		"upsert":            "sink:\n  type: delta\n  delta:\n    warehouse: /var/lib/kafscale/delta\nmappings:\n  - topic: orders\n    table: prod.orders\n    mode: upsert\n    key_columns: [order_id]\n",
+		"partitioned table": "sink:\n  type: delta\n  delta:\n    warehouse: /var/lib/kafscale/delta\nmappings:\n  - topic: orders\n    table: prod.orders\n    partition_spec:\n      - column: region\n",
+	}
+	for name, sink := range cases {
+		data := "s3:\n  bucket: test-bucket\netcd:\n  endpoints:\n    - http://etcd:2379\n" + sink
+		if !strings.Contains(sink, "mappings:") {
+			data += "mappings:\n  - topic: orders\n    table: prod.orders\n"
+		}
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+
+		if _, err := Load(path); err == nil {
+			t.Fatalf("expected error for %s", name)
+		}
+	}
+}
+
+func TestLoadConfluentRegistryWithNestedColumns(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\nschema:\n  registry:\n    type: confluent\n    base_url: http://registry:8081\netcd:\n  endpoints:\n    - http://etcd:2379\nmappings:\n  - topic: orders\n    table: prod.orders\n    schema:\n      subject: shop.orders-value\n  - topic: events\n    table: prod.events\n    schema:\n      source: mapping\n      columns:\n        - name: customer\n          type: struct\n          fields:\n            - name: name\n              type: string\n        - name: tags\n          type: list\n          element:\n            type: string\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Schema.Registry.Type != "confluent" {
+		t.Fatalf("unexpected registry type %q", cfg.Schema.Registry.Type)
+	}
+	if cfg.Mappings[0].Schema.Source != "registry" || cfg.Mappings[0].Schema.Subject != "shop.orders-value" {
+		t.Fatalf("unexpected registry mapping %+v", cfg.Mappings[0].Schema)
+	}
+	columns := cfg.Mappings[1].Schema.Columns
+	if len(columns) != 2 || len(columns[0].Fields) != 1 || columns[1].Element == nil || columns[1].Element.Type != "string" {
+		t.Fatalf("unexpected nested columns %+v", columns)
+	}
+}
+
+func TestLoadRejectsInvalidRegistryAndColumns(t *testing.T) {
+	cases := map[string]string{
+		"unknown registry":      "schema:\n  registry:\n    type: apicurio\n    base_url: http://registry:8081\n",
+		"confluent validation":  "schema:\n  mode: strict\n  registry:\n    type: confluent\n    base_url: http://registry:8081\n",
+		"struct without fields": "mappings:\n  - topic: orders\n    table: prod.orders\n    schema:\n      source: mapping\n      columns:\n        - name: customer\n          type: struct\n",
+		"list without element":  "mappings:\n  - topic: orders\n    table: prod.orders\n    schema:\n      source: mapping\n      columns:\n        - name: tags\n          type: list\n",
+		"unnamed nested field":  "mappings:\n  - topic: orders\n    table: prod.orders\n    schema:\n      source: mapping\n      columns:\n        - name: customer\n          type: struct\n          fields:\n            - type: string\n",
+	}
+	for name, extra := range cases {
+		data := "s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\netcd:\n  endpoints:\n    - http://etcd:2379\n" + extra
+		if !strings.Contains(extra, "mappings:") {
+			data += "mappings:\n  - topic: orders\n    table: prod.orders\n"
+		}
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+
+		if _, err := Load(path); err == nil {
+			t.Fatalf("expected error for %s", name)
+		}
+	}
+}
+
+func TestLoadCatalogCredentialsFromEnv(t *testing.T) {
+	t.Setenv("ICEBERG_CATALOG_TOKEN", "env-token")
+	t.Setenv("ICEBERG_CATALOG_PASSWORD", "env-password")
+	data := []byte("s3:\n  bucket: test-bucket\niceberg:\n  catalog:\n    type: rest\n    uri: http://catalog\n    token: file-token\n    username: alice\noffsets:\n  backend: noop\nmappings:\n  - topic: orders\n    table: prod.orders\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	catalog := cfg.Iceberg.Catalog
+	if catalog.Token != "env-token" || catalog.Username != "alice" || catalog.Password != "env-password" {
+		t.Fatalf("unexpected catalog credentials: %+v", catalog)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/sink"
+)
+
+const (
+	ReasonSchema  = "schema"
+	ReasonConvert = "convert"
+)
+
+// Entry is a rejected record together with why it was rejected.
+type Entry struct {
+	Reason    string        `json:"reason"`
+	Error     string        `json:"error"`
+	Attempts  int           `json:"attempts"`
+	FailedAt  int64         `json:"failed_at_ms"`
+	Topic     string        `json:"topic"`
+	Partition int32         `json:"partition"`
+	Offset    int64         `json:"offset"`
+	Timestamp int64         `json:"timestamp_ms"`
+	Key       []byte        `json:"key,omitempty"`
+	Value     []byte        `json:"value,omitempty"`
+	Headers   []EntryHeader `json:"headers,omitempty"`
+}
+
+type EntryHeader struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// Writer delivers rejected records to a dead-letter destination.
+type Writer interface {
+	Write(ctx context.Context, entries []Entry) error
+	Close() error
+}
+
+// New returns the configured dead-letter writer, or nil when dlq.type is
+// empty.
+func New(cfg config.Config) (Writer, error) {
+	switch cfg.DLQ.Type {
+	case "":
+		return nil, nil
+	case "kafka":
+		return newKafkaWriter(cfg.DLQ.Kafka)
+	case "s3":
+		return newS3Writer(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported dlq.type %q", cfg.DLQ.Type)
+	}
+}
+
+// NewEntries wraps rejected records with a shared reason and error.
+func NewEntries(records []sink.Record, reason string, err error, attempts int) []Entry {
+	now := time.Now().UnixMilli()
+	entries := make([]Entry, 0, len(records))
+	for _, record := range records {
+		headers := make([]EntryHeader, 0, len(record.Headers))
+		for _, header := range record.Headers {
+			headers = append(headers, EntryHeader{Key: header.Key, Value: header.Value})
+		}
+		entries = append(entries, Entry{
+			Reason:    reason,
+			Error:     err.Error(),
+			Attempts:  attempts,
+			FailedAt:  now,
+			Topic:     record.Topic,
+			Partition: record.Partition,
+			Offset:    record.Offset,
+			Timestamp: record.Timestamp,
+			Key:       record.Key,
+			Value:     record.Value,
+			Headers:   headers,
+		})
+	}
+	return entries
+}
+
+// topicFor expands the {topic} placeholder of a DLQ topic template.
+func topicFor(template, topic string) string {
+	return strings.ReplaceAll(template, "{topic}", topic)
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/decoder"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/sink"
+)
+
+type fakePutter struct {
+	objects map[string]string
+}
+
+func (f *fakePutter) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[*params.Key] = string(body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func testEntries() []Entry {
+	records := []sink.Record{
+		{Topic: "orders", Partition: 1, Offset: 7, Key: []byte("k"), Value: []byte(`{"id":1}`), Headers: []decoder.Header{{Key: "trace", Value: []byte("abc")}}},
+		{Topic: "orders", Partition: 1, Offset: 9, Value: []byte(`{"id":2}`)},
+		{Topic: "orders", Partition: 0, Offset: 3, Value: []byte(`{"id":3}`)},
+	}
+	return NewEntries(records, ReasonConvert, errors.New("decode failed"), 1)
+}
+
+func TestS3WriterWritesNDJSONPerPartition(t *testing.T) {
+	putter := &fakePutter{objects: map[string]string{}}
+	w := &s3Writer{client: putter, bucket: "bucket", prefix: "dlq"}
+	if err := w.Write(context.Background(), testEntries()); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if len(putter.objects) != 2 {
+		t.Fatalf("expected one object per partition, got %d", len(putter.objects))
+	}
+	for key, body := range putter.objects {
+		if !strings.HasPrefix(key, "dlq/orders/partition=1/7-9-") && !strings.HasPrefix(key, "dlq/orders/partition=0/3-3-") {
+			t.Fatalf("unexpected object key %q", key)
+		}
+		if !strings.HasPrefix(key, "dlq/orders/partition=1/") {
+			continue
+		}
+		scanner := bufio.NewScanner(strings.NewReader(body))
+		var lines []Entry
+		for scanner.Scan() {
+			var entry Entry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				t.Fatalf("decode line: %v", err)
+			}
+			lines = append(lines, entry)
+		}
+		if len(lines) != 2 || lines[0].Reason != ReasonConvert || lines[0].Error != "decode failed" || lines[0].Attempts != 1 {
+			t.Fatalf("unexpected entries: %+v", lines)
+		}
+		if string(lines[0].Value) != `{"id":1}` || lines[0].Headers[0].Key != "trace" {
+			t.Fatalf("original record not preserved: %+v", lines[0])
+		}
+	}
+}
+
+func TestKafkaRecordCarriesFailureHeaders(t *testing.T) {
+	record := kafkaRecord("{topic}.dlq", testEntries()[0])
+	if record.Topic != "orders.dlq" || string(record.Key) != "k" || string(record.Value) != `{"id":1}` {
+		t.Fatalf("unexpected record: %+v", record)
+	}
+	headers := map[string]string{}
+	for _, header := range record.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	if headers["trace"] != "abc" || headers["kafscale.dlq.reason"] != ReasonConvert || headers["kafscale.dlq.error"] != "decode failed" {
+		t.Fatalf("unexpected headers: %v", headers)
+	}
+	if headers["kafscale.dlq.topic"] != "orders" || headers["kafscale.dlq.partition"] != "1" || headers["kafscale.dlq.offset"] != "7" || headers["kafscale.dlq.attempts"] != "1" {
+		t.Fatalf("unexpected source headers: %v", headers)
+	}
+}
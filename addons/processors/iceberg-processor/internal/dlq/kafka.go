@@ -0,0 +1,80 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+const headerPrefix = "kafscale.dlq."
+
+type kafkaWriter struct {
+	client *kgo.Client
+	topic  string
+}
+
+func newKafkaWriter(cfg config.DLQKafkaConfig) (Writer, error) {
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(cfg.Brokers...),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.AllowAutoTopicCreation(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaWriter{client: client, topic: cfg.Topic}, nil
+}
+
+func (w *kafkaWriter) Write(ctx context.Context, entries []Entry) error {
+	records := make([]*kgo.Record, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, kafkaRecord(w.topic, entry))
+	}
+	return w.client.ProduceSync(ctx, records...).FirstErr()
+}
+
+func (w *kafkaWriter) Close() error {
+	w.client.Close()
+	return nil
+}
+
+// kafkaRecord keeps the original key, value and headers and describes the
+// failure in kafscale.dlq.* headers.
+func kafkaRecord(template string, entry Entry) *kgo.Record {
+	headers := make([]kgo.RecordHeader, 0, len(entry.Headers)+6)
+	for _, header := range entry.Headers {
+		headers = append(headers, kgo.RecordHeader{Key: header.Key, Value: header.Value})
+	}
+	headers = append(headers,
+		kgo.RecordHeader{Key: headerPrefix + "reason", Value: []byte(entry.Reason)},
+		kgo.RecordHeader{Key: headerPrefix + "error", Value: []byte(entry.Error)},
+		kgo.RecordHeader{Key: headerPrefix + "attempts", Value: []byte(strconv.Itoa(entry.Attempts))},
+		kgo.RecordHeader{Key: headerPrefix + "topic", Value: []byte(entry.Topic)},
+		kgo.RecordHeader{Key: headerPrefix + "partition", Value: []byte(strconv.FormatInt(int64(entry.Partition), 10))},
+		kgo.RecordHeader{Key: headerPrefix + "offset", Value: []byte(strconv.FormatInt(entry.Offset, 10))},
+	)
+	return &kgo.Record{
+		Topic:   topicFor(template, entry.Topic),
+		Key:     entry.Key,
+		Value:   entry.Value,
+		Headers: headers,
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dlq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+type objectPutter interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+type s3Writer struct {
+	client objectPutter
+	bucket string
+	prefix string
+}
+
+func newS3Writer(cfg config.Config) (Writer, error) {
+	loadOptions := []func(*awsconfig.LoadOptions) error{}
+	if cfg.S3.Region != "" {
+		loadOptions = append(loadOptions, awsconfig.WithRegion(cfg.S3.Region))
+	}
+	if cfg.S3.Endpoint != "" {
+		resolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, _ ...interface{}) (aws.Endpoint, error) {
+			if service == s3.ServiceID {
+				return aws.Endpoint{URL: cfg.S3.Endpoint, SigningRegion: region}, nil
+			}
+			return aws.Endpoint{}, &aws.EndpointNotFoundError{}
+		})
+		loadOptions = append(loadOptions, awsconfig.WithEndpointResolverWithOptions(resolver))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	client := s3.NewFromConfig(awsCfg, func(opts *s3.Options) {
+		if cfg.S3.PathStyle {
+			opts.UsePathStyle = true
+		}
+	})
+	return &s3Writer{client: client, bucket: cfg.DLQ.S3.Bucket, prefix: cfg.DLQ.S3.Prefix}, nil
+}
+
+// Write stores one NDJSON object per topic partition under
+// <prefix>/<topic>/partition=<n>/<first>-<last>-<unix_ms>.ndjson.
+func (w *s3Writer) Write(ctx context.Context, entries []Entry) error {
+	type partitionKey struct {
+		topic     string
+		partition int32
+	}
+	groups := make(map[partitionKey][]Entry)
+	keys := make([]partitionKey, 0)
+	for _, entry := range entries {
+		key := partitionKey{topic: entry.Topic, partition: entry.Partition}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], entry)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].topic != keys[j].topic {
+			return keys[i].topic < keys[j].topic
+		}
+		return keys[i].partition < keys[j].partition
+	})
+
+	for _, key := range keys {
+		group := groups[key]
+		var body bytes.Buffer
+		enc := json.NewEncoder(&body)
+		for _, entry := range group {
+			if err := enc.Encode(entry); err != nil {
+				return err
+			}
+		}
+		objectKey := path.Join(w.prefix, key.topic, fmt.Sprintf("partition=%d", key.partition),
+			fmt.Sprintf("%d-%d-%d.ndjson", group[0].Offset, group[len(group)-1].Offset, time.Now().UnixMilli()))
+		if _, err := w.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(w.bucket),
+			Key:         aws.String(objectKey),
+			Body:        bytes.NewReader(body.Bytes()),
+			ContentType: aws.String("application/x-ndjson"),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *s3Writer) Close() error {
+	return nil
+}
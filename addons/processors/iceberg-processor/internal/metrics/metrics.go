@@ -77,6 +77,69 @@ var (
 		},
 		[]string{"topic", "partition"},
 	)
+	SinkRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sink_retries_total",
+			Help:      "Sink write retries per topic.",
+		},
+		[]string{"topic"},
+	)
+	DeadLettered = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dead_lettered_total",
+			Help:      "Records sent to the dead-letter queue by topic and reason.",
+		},
+		[]string{"topic", "reason"},
+	)
+	AssignedPartitions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "assigned_partitions",
+			Help:      "Partitions leased per worker.",
+		},
+		[]string{"worker"},
+	)
+	LiveWorkers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "live_workers",
+			Help:      "Live workers seen during the last rebalance.",
+		},
+	)
+	LeaseHandoffs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "lease_handoffs_total",
+			Help:      "Partition leases given up by reason.",
+		},
+		[]string{"reason"},
+	)
+	MaintenanceRuns = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "maintenance_runs_total",
+			Help:      "Table maintenance runs by table and result.",
+		},
+		[]string{"table", "result"},
+	)
+	MaintenanceFiles = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "maintenance_files_total",
+			Help:      "Files changed by table maintenance, by table and action.",
+		},
+		[]string{"table", "action"},
+	)
+	ExpiredSnapshots = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "expired_snapshots_total",
+			Help:      "Snapshots expired by table maintenance.",
+		},
+		[]string{"table"},
+	)
 )
 
 func init() {
@@ -88,5 +151,13 @@ func init() {
 		LastOffset,
 		WatermarkOffset,
 		WatermarkTimestamp,
+		SinkRetries,
+		DeadLettered,
+		AssignedPartitions,
+		LiveWorkers,
+		LeaseHandoffs,
+		MaintenanceRuns,
+		MaintenanceFiles,
+		ExpiredSnapshots,
 	)
 }
@@ -0,0 +1,113 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/metrics"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/sink"
+)
+
+// startMaintenance runs table maintenance in the background when it is
+// enabled and the sink supports it. The returned func stops it and waits for
+// an in-flight run to finish.
+func (p *Processor) startMaintenance(ctx context.Context, ownerID string) func() {
+	maintainer, ok := p.sink.(sink.Maintainer)
+	if !ok || !p.cfg.Maintenance.Enabled {
+		return func() {}
+	}
+	interval := time.Duration(p.cfg.Maintenance.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+				p.maintainTables(runCtx, maintainer, ownerID)
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// maintainTables maintains each mapped table whose lease this worker can
+// take. Tables leased by another worker are skipped until the next run.
+func (p *Processor) maintainTables(ctx context.Context, maintainer sink.Maintainer, ownerID string) {
+	seen := make(map[string]bool, len(p.cfg.Mappings))
+	for _, mapping := range p.cfg.Mappings {
+		if seen[mapping.Table] || ctx.Err() != nil {
+			continue
+		}
+		seen[mapping.Table] = true
+		p.maintainTable(ctx, maintainer, mapping, ownerID)
+	}
+}
+
+func (p *Processor) maintainTable(ctx context.Context, maintainer sink.Maintainer, mapping config.Mapping, ownerID string) {
+	lease, err := p.store.ClaimTableLease(ctx, mapping.Table, ownerID)
+	if err != nil {
+		return
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	leaseLost := make(chan error, 1)
+	stopRenew := p.startLeaseRenewal(runCtx, lease, leaseLost)
+	go func() {
+		select {
+		case err := <-leaseLost:
+			log.Printf("maintenance lease lost table=%s: %v", mapping.Table, err)
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	result, err := maintainer.Maintain(runCtx, mapping.Topic)
+	cancel()
+	stopRenew()
+	releaseCtx, releaseCancel := context.WithTimeout(context.Background(), releaseTimeout)
+	if releaseErr := p.store.ReleaseLease(releaseCtx, lease); releaseErr != nil {
+		log.Printf("maintenance lease release failed table=%s: %v", mapping.Table, releaseErr)
+	}
+	releaseCancel()
+
+	if err != nil {
+		log.Printf("maintenance failed table=%s: %v", mapping.Table, err)
+		metrics.MaintenanceRuns.WithLabelValues(mapping.Table, "error").Inc()
+		metrics.ErrorsTotal.WithLabelValues("maintenance").Inc()
+		return
+	}
+	metrics.MaintenanceRuns.WithLabelValues(mapping.Table, "ok").Inc()
+	metrics.MaintenanceFiles.WithLabelValues(mapping.Table, "rewritten").Add(float64(result.RewrittenFiles))
+	metrics.MaintenanceFiles.WithLabelValues(mapping.Table, "added").Add(float64(result.AddedFiles))
+	metrics.MaintenanceFiles.WithLabelValues(mapping.Table, "orphan_deleted").Add(float64(result.DeletedOrphans))
+	metrics.ExpiredSnapshots.WithLabelValues(mapping.Table).Add(float64(result.ExpiredSnapshots))
+	log.Printf("maintenance table=%s rewritten=%d added=%d expired=%d orphans=%d",
+		mapping.Table, result.RewrittenFiles, result.AddedFiles, result.ExpiredSnapshots, result.DeletedOrphans)
+}
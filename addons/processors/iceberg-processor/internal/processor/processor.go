@@ -17,6 +17,7 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -26,6 +27,7 @@ import (
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/decoder"
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/discovery"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/dlq"
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/metrics"
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/schema"
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/sink"
@@ -33,6 +35,8 @@ import (
 
 var leaseRenewInterval = 10 * time.Second
 
+const releaseTimeout = 5 * time.Second
+
 // Processor wires discovery, decoding, checkpointing, and sink writing.
 type Processor struct {
 	cfg       config.Config
@@ -41,6 +45,8 @@ type Processor struct {
 	store     checkpoint.Store
 	sink      sink.Writer
 	validator schema.Validator
+	dlq       dlq.Writer
+	ownerID   string
 }
 
 func New(cfg config.Config) (*Processor, error) {
@@ -64,6 +70,10 @@ func New(cfg config.Config) (*Processor, error) {
 	if err != nil {
 		return nil, err
 	}
+	deadLetters, err := dlq.New(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Processor{
 		cfg:       cfg,
@@ -72,13 +82,15 @@ func New(cfg config.Config) (*Processor, error) {
 		store:     store,
 		sink:      writer,
 		validator: validator,
+		dlq:       deadLetters,
+		ownerID:   workerID(),
 	}, nil
 }
 
 func (p *Processor) Run(ctx context.Context) error {
-	ownerID, err := os.Hostname()
-	if err != nil || ownerID == "" {
-		ownerID = "worker"
+	ownerID := p.ownerID
+	if ownerID == "" {
+		ownerID = workerID()
 	}
 
 	pollInterval := time.Duration(p.cfg.Processor.PollIntervalSeconds) * time.Second
@@ -88,128 +100,278 @@ func (p *Processor) Run(ctx context.Context) error {
 	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
-	var (
-		activeLease checkpoint.Lease
-		hasLease    bool
-		stopRenew   func()
-		leaseLost   = make(chan error, 1)
-	)
+	sched := newScheduler(p, ownerID)
+	stopMaintenance := p.startMaintenance(ctx, ownerID)
 	defer func() {
-		if hasLease {
-			stopRenew()
-			_ = p.store.ReleaseLease(ctx, activeLease)
-		}
+		stopMaintenance()
+		sched.releaseAll("shutdown")
+		releaseCtx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+		defer cancel()
+		_ = p.store.UnregisterWorker(releaseCtx, ownerID)
 	}()
 
 	for {
 		select {
 		case <-ctx.Done():
+			stopMaintenance()
+			sched.releaseAll("shutdown")
 			_ = p.sink.Close(ctx)
-			return nil
-		case err := <-leaseLost:
-			if hasLease {
-				stopRenew()
-				_ = p.store.ReleaseLease(ctx, activeLease)
-				hasLease = false
-			}
-			if err != nil {
-				log.Printf("lease renewal failed: %v", err)
-				metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
+			if p.dlq != nil {
+				_ = p.dlq.Close()
 			}
+			return nil
+		case key := <-sched.lost:
+			sched.drop(key, "lost")
 			continue
+		case err := <-sched.failed:
+			return err
 		case <-ticker.C:
+			if err := p.store.RegisterWorker(ctx, ownerID); err != nil {
+				log.Printf("worker heartbeat failed: %v", err)
+				metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
+			}
 			segments, err := p.discover.ListCompleted(ctx)
 			if err != nil {
 				metrics.ErrorsTotal.WithLabelValues("discover").Inc()
 				continue
 			}
+			sched.rebalance(ctx, segments)
+		}
+	}
+}
 
-			if !hasLease {
-				for _, seg := range segments {
-					lease, err := p.store.ClaimLease(ctx, seg.Topic, seg.Partition, ownerID)
-					if err != nil {
-						metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
-						continue
-					}
-					activeLease = lease
-					hasLease = true
-					stopRenew = p.startLeaseRenewal(ctx, lease, leaseLost)
-					break
+// errRetrySegment stops a partition's batch so the failed segment and
+// everything after it are retried on the next poll instead of being skipped.
+var errRetrySegment = errors.New("retry segment on next poll")
+
+// recoverOffset returns the committed position of a newly leased partition.
+// Writers that commit offsets together with their data are authoritative,
+// except for a checkpoint ahead of them because its last records were
+// dead-lettered or filtered. Otherwise the checkpoint store is the fallback
+// and is healed when it lags behind.
+func (p *Processor) recoverOffset(ctx context.Context, topic string, partition int32) (int64, error) {
+	state, loadErr := p.store.LoadOffset(ctx, topic, partition)
+	if tracker, ok := p.sink.(sink.OffsetTracker); ok {
+		offset, found, err := tracker.CommittedOffset(ctx, topic, partition)
+		if err != nil {
+			metrics.ErrorsTotal.WithLabelValues("sink").Inc()
+			return 0, err
+		}
+		if loadErr == nil && state.Skipped && (!found || state.Offset > offset) {
+			return state.Offset, nil
+		}
+		if found {
+			if loadErr != nil || state.Offset != offset {
+				log.Printf("recovered offset from sink topic=%s partition=%d offset=%d checkpoint=%d", topic, partition, offset, state.Offset)
+				if err := p.store.CommitOffset(ctx, checkpoint.OffsetState{
+					Topic:     topic,
+					Partition: partition,
+					Offset:    offset,
+					Timestamp: time.Now().UnixMilli(),
+				}); err != nil {
+					metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
 				}
 			}
+			return offset, nil
+		}
+	}
+	if loadErr != nil {
+		metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
+		return 0, loadErr
+	}
+	return state.Offset, nil
+}
 
-			if !hasLease {
-				continue
+// processSegment writes the records of seg above offset and returns the new
+// committed offset. It is readSegment followed by flush, without buffering
+// across segments.
+func (p *Processor) processSegment(ctx context.Context, seg discovery.SegmentRef, offset int64) (int64, error) {
+	var buf partitionBuffer
+	if err := p.readSegment(ctx, seg, offset, &buf); err != nil {
+		return offset, err
+	}
+	if buf.empty() {
+		return offset, nil
+	}
+	return p.flush(ctx, &buf, offset)
+}
+
+// readSegment adds the records of seg above offset to buf. Records rejected
+// by the schema go to the dead-letter queue when one is configured. Strict
+// schema failures without a DLQ are fatal; transient failures return
+// errRetrySegment and leave buf unchanged.
+func (p *Processor) readSegment(ctx context.Context, seg discovery.SegmentRef, offset int64, buf *partitionBuffer) error {
+	decoded, err := p.decode.Decode(ctx, seg.SegmentKey, seg.IndexKey, seg.Topic, seg.Partition)
+	if err != nil {
+		metrics.ErrorsTotal.WithLabelValues("decode").Inc()
+		return errRetrySegment
+	}
+
+	records := mapRecords(decoded)
+	if dropped := len(records); dropped > 0 {
+		records = filterRecords(records, offset)
+		dropped -= len(records)
+		if dropped > 0 {
+			metrics.RecordsTotal.WithLabelValues(seg.Topic, "dropped").Add(float64(dropped))
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	last := records[len(records)-1]
+
+	records, rejected, err := validateRecords(ctx, records, p.validator)
+	if err != nil {
+		log.Printf("schema validation unavailable topic=%s partition=%d: %v", seg.Topic, seg.Partition, err)
+		metrics.ErrorsTotal.WithLabelValues("schema").Inc()
+		return errRetrySegment
+	}
+	if len(rejected) > 0 {
+		metrics.ErrorsTotal.WithLabelValues("schema").Inc()
+		metrics.RecordsTotal.WithLabelValues(seg.Topic, "invalid").Add(float64(len(rejected)))
+		switch {
+		case p.dlq != nil:
+			entries := make([]dlq.Entry, 0, len(rejected))
+			for _, r := range rejected {
+				entries = append(entries, dlq.NewEntries([]sink.Record{r.record}, dlq.ReasonSchema, r.err, 1)...)
 			}
+			if err := p.deadLetter(ctx, entries); err != nil {
+				return errRetrySegment
+			}
+		case p.validator.Mode() == schema.ModeStrict:
+			return rejected[0].err
+		}
+	}
+	buf.add(records, last)
+	return nil
+}
 
-			for _, seg := range segments {
-				if seg.Topic != activeLease.Topic || seg.Partition != activeLease.Partition {
-					continue
-				}
+// flush writes the buffered records as one batch and commits the offset of
+// the last record read. Records the sink cannot decode or convert are
+// dead-lettered when a DLQ is configured and the rest of the batch is written
+// again. Any other sink failure returns errRetrySegment without committing.
+// buf is reset either way.
+func (p *Processor) flush(ctx context.Context, buf *partitionBuffer, offset int64) (int64, error) {
+	records, last := buf.records, buf.last
+	buf.reset()
+	for len(records) > 0 {
+		err := p.writeWithRetry(ctx, records)
+		if err == nil {
+			metrics.RecordsTotal.WithLabelValues(last.Topic, "written").Add(float64(len(records)))
+			metrics.BatchesTotal.WithLabelValues(last.Topic).Inc()
+			break
+		}
+		var recordErr *sink.RecordError
+		if p.dlq == nil || !errors.As(err, &recordErr) {
+			return offset, errRetrySegment
+		}
+		remaining := withoutRecord(records, recordErr.Record)
+		if len(remaining) == len(records) {
+			return offset, errRetrySegment
+		}
+		metrics.RecordsTotal.WithLabelValues(last.Topic, "invalid").Inc()
+		if err := p.deadLetter(ctx, dlq.NewEntries([]sink.Record{recordErr.Record}, dlq.ReasonConvert, recordErr.Err, 1)); err != nil {
+			return offset, errRetrySegment
+		}
+		records = remaining
+	}
+	metrics.LastOffset.WithLabelValues(last.Topic, fmt.Sprintf("%d", last.Partition)).Set(float64(last.Offset))
 
-				state, err := p.store.LoadOffset(ctx, seg.Topic, seg.Partition)
-				if err != nil {
-					metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
-					continue
-				}
+	// A sink that commits offsets with its data only knows about the records
+	// it wrote. When the last record read was dead-lettered or filtered, the
+	// checkpoint store is the only record of the offset, so a failed commit
+	// has to be retried rather than mirrored later.
+	skipped := len(records) == 0 || records[len(records)-1].Offset != last.Offset
+	if err := p.store.CommitOffset(ctx, checkpoint.OffsetState{
+		Topic:     last.Topic,
+		Partition: last.Partition,
+		Offset:    last.Offset,
+		Timestamp: time.Now().UnixMilli(),
+		Skipped:   skipped,
+	}); err != nil {
+		metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
+		if skipped {
+			return offset, errRetrySegment
+		}
+		return last.Offset, nil
+	}
+	metrics.WatermarkOffset.WithLabelValues(last.Topic, fmt.Sprintf("%d", last.Partition)).Set(float64(last.Offset))
+	metrics.WatermarkTimestamp.WithLabelValues(last.Topic, fmt.Sprintf("%d", last.Partition)).Set(float64(last.Timestamp))
+	return last.Offset, nil
+}
 
-				decoded, err := p.decode.Decode(ctx, seg.SegmentKey, seg.IndexKey, seg.Topic, seg.Partition)
-				if err != nil {
-					metrics.ErrorsTotal.WithLabelValues("decode").Inc()
-					continue
-				}
+// targetBytes returns how many record bytes a partition of topic buffers
+// before it is flushed. Zero flushes after every segment.
+func (p *Processor) targetBytes(topic string) int64 {
+	for _, mapping := range p.cfg.Mappings {
+		if mapping.Topic == topic {
+			return mapping.Write.TargetFileSizeBytes
+		}
+	}
+	return 0
+}
 
-				records := mapRecords(decoded)
-				if dropped := len(records); dropped > 0 {
-					records = filterRecords(records, state.Offset)
-					dropped -= len(records)
-					if dropped > 0 {
-						metrics.RecordsTotal.WithLabelValues(seg.Topic, "dropped").Add(float64(dropped))
-					}
-				}
-				records, invalid, err := validateRecords(ctx, records, p.validator)
-				if err != nil {
-					metrics.ErrorsTotal.WithLabelValues("schema").Inc()
-					return err
-				}
-				if invalid > 0 {
-					metrics.RecordsTotal.WithLabelValues(seg.Topic, "invalid").Add(float64(invalid))
-				}
-				if len(records) == 0 {
-					continue
-				}
+// writeWithRetry writes records to the sink, backing off exponentially
+// between attempts up to dlq.retry.max_attempts. Records the sink cannot
+// convert are not retried.
+func (p *Processor) writeWithRetry(ctx context.Context, records []sink.Record) error {
+	retry := p.cfg.DLQ.Retry
+	maxAttempts := retry.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	backoff := time.Duration(retry.InitialBackoffMs) * time.Millisecond
+	maxBackoff := time.Duration(retry.MaxBackoffMs) * time.Millisecond
 
-				start := time.Now()
-				if err := p.sink.Write(ctx, records); err != nil {
-					first := records[0]
-					last := records[len(records)-1]
-					log.Printf("sink write failed topic=%s partition=%d offsets=%d-%d: %T %v", first.Topic, first.Partition, first.Offset, last.Offset, err, err)
-					log.Printf("sink write error details: %+v", err)
-					metrics.ErrorsTotal.WithLabelValues("sink").Inc()
-					continue
-				}
-				metrics.WriteLatency.WithLabelValues(seg.Topic).Observe(float64(time.Since(start).Milliseconds()))
+	first := records[0]
+	last := records[len(records)-1]
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := p.sink.Write(ctx, records)
+		if err == nil {
+			metrics.WriteLatency.WithLabelValues(first.Topic).Observe(float64(time.Since(start).Milliseconds()))
+			return nil
+		}
+		log.Printf("sink write failed topic=%s partition=%d offsets=%d-%d attempt=%d/%d: %T %v", first.Topic, first.Partition, first.Offset, last.Offset, attempt, maxAttempts, err, err)
+		metrics.ErrorsTotal.WithLabelValues("sink").Inc()
+		var recordErr *sink.RecordError
+		if attempt >= maxAttempts || ctx.Err() != nil || errors.As(err, &recordErr) {
+			return err
+		}
 
-				last := records[len(records)-1]
-				if err := p.store.CommitOffset(ctx, checkpoint.OffsetState{
-					Topic:     last.Topic,
-					Partition: last.Partition,
-					Offset:    last.Offset,
-					Timestamp: time.Now().UnixMilli(),
-				}); err != nil {
-					metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
-					continue
-				}
-				metrics.RecordsTotal.WithLabelValues(seg.Topic, "written").Add(float64(len(records)))
-				metrics.BatchesTotal.WithLabelValues(seg.Topic).Inc()
-				metrics.LastOffset.WithLabelValues(seg.Topic, fmt.Sprintf("%d", seg.Partition)).Set(float64(last.Offset))
-				metrics.WatermarkOffset.WithLabelValues(seg.Topic, fmt.Sprintf("%d", seg.Partition)).Set(float64(last.Offset))
-				metrics.WatermarkTimestamp.WithLabelValues(seg.Topic, fmt.Sprintf("%d", seg.Partition)).Set(float64(last.Timestamp))
-			}
+		metrics.SinkRetries.WithLabelValues(first.Topic).Inc()
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+		if maxBackoff > 0 && backoff > maxBackoff {
+			backoff = maxBackoff
 		}
 	}
 }
 
+func (p *Processor) deadLetter(ctx context.Context, entries []dlq.Entry) error {
+	if err := p.dlq.Write(ctx, entries); err != nil {
+		log.Printf("dead-letter write failed topic=%s partition=%d records=%d: %v", entries[0].Topic, entries[0].Partition, len(entries), err)
+		metrics.ErrorsTotal.WithLabelValues("dlq").Inc()
+		return err
+	}
+	metrics.DeadLettered.WithLabelValues(entries[0].Topic, entries[0].Reason).Add(float64(len(entries)))
+	return nil
+}
+
+func workerID() string {
+	ownerID, err := os.Hostname()
+	if err != nil || ownerID == "" {
+		ownerID = "worker"
+	}
+	return ownerID
+}
+
 func filterRecords(records []sink.Record, offset int64) []sink.Record {
 	if len(records) == 0 {
 		return records
@@ -224,6 +386,19 @@ func filterRecords(records []sink.Record, offset int64) []sink.Record {
 	return filtered
 }
 
+// withoutRecord returns records minus the one at the partition and offset of
+// drop.
+func withoutRecord(records []sink.Record, drop sink.Record) []sink.Record {
+	out := make([]sink.Record, 0, len(records))
+	for _, record := range records {
+		if record.Partition == drop.Partition && record.Offset == drop.Offset {
+			continue
+		}
+		out = append(out, record)
+	}
+	return out
+}
+
 func mapRecords(records []decoder.Record) []sink.Record {
 	out := make([]sink.Record, 0, len(records))
 	for _, record := range records {
@@ -240,24 +415,32 @@ func mapRecords(records []decoder.Record) []sink.Record {
 	return out
 }
 
-func validateRecords(ctx context.Context, records []sink.Record, validator schema.Validator) ([]sink.Record, int, error) {
+type rejectedRecord struct {
+	record sink.Record
+	err    error
+}
+
+// validateRecords splits records into valid and rejected ones. Failures that
+// are not about the payload itself, such as an unreachable registry, are
+// returned as an error so the batch is retried.
+func validateRecords(ctx context.Context, records []sink.Record, validator schema.Validator) ([]sink.Record, []rejectedRecord, error) {
 	if validator == nil || validator.Mode() == schema.ModeOff {
-		return records, 0, nil
+		return records, nil, nil
 	}
 
 	valid := records[:0]
-	invalid := 0
+	var rejected []rejectedRecord
 	for _, record := range records {
 		if err := validator.Validate(ctx, record.Topic, record.Value); err != nil {
-			if validator.Mode() == schema.ModeStrict {
-				return nil, invalid, err
+			if !errors.Is(err, schema.ErrInvalidPayload) {
+				return nil, nil, err
 			}
-			invalid++
+			rejected = append(rejected, rejectedRecord{record: record, err: err})
 			continue
 		}
 		valid = append(valid, record)
 	}
-	return valid, invalid, nil
+	return valid, rejected, nil
 }
 
 func (p *Processor) startLeaseRenewal(ctx context.Context, lease checkpoint.Lease, leaseLost chan<- error) func() {
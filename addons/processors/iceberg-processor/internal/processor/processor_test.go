@@ -17,6 +17,7 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -27,6 +28,8 @@ import (
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/decoder"
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/discovery"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/dlq"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/schema"
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/sink"
 )
 
@@ -54,7 +57,11 @@ func TestFilterRecords(t *testing.T) {
 type testStore struct {
 	mu           sync.Mutex
 	offsets      map[string]int64
+	skipped      map[string]bool
 	claimed      []checkpoint.Lease
+	held         map[string]checkpoint.Lease
+	tables       map[string]string
+	workers      []string
 	renewCalls   int32
 	releaseCalls int32
 }
@@ -62,8 +69,29 @@ type testStore struct {
 func (s *testStore) ClaimLease(ctx context.Context, topic string, partition int32, ownerID string) (checkpoint.Lease, error) {
 	lease := checkpoint.Lease{Topic: topic, Partition: partition, OwnerID: ownerID}
 	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.held == nil {
+		s.held = make(map[string]checkpoint.Lease)
+	}
+	if _, ok := s.held[offsetKey(topic, partition)]; ok {
+		return checkpoint.Lease{}, errors.New("lease already held")
+	}
+	s.held[offsetKey(topic, partition)] = lease
 	s.claimed = append(s.claimed, lease)
-	s.mu.Unlock()
+	return lease, nil
+}
+
+func (s *testStore) ClaimTableLease(ctx context.Context, table string, ownerID string) (checkpoint.Lease, error) {
+	lease := checkpoint.Lease{Table: table, OwnerID: ownerID}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tables == nil {
+		s.tables = make(map[string]string)
+	}
+	if _, ok := s.tables[table]; ok {
+		return checkpoint.Lease{}, errors.New("lease already held")
+	}
+	s.tables[table] = ownerID
 	return lease, nil
 }
 
@@ -74,9 +102,53 @@ func (s *testStore) RenewLease(ctx context.Context, lease checkpoint.Lease) erro
 
 func (s *testStore) ReleaseLease(ctx context.Context, lease checkpoint.Lease) error {
 	atomic.AddInt32(&s.releaseCalls, 1)
+	s.mu.Lock()
+	if lease.Table != "" {
+		delete(s.tables, lease.Table)
+	} else {
+		delete(s.held, offsetKey(lease.Topic, lease.Partition))
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *testStore) ListLeases(ctx context.Context) ([]checkpoint.Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	leases := make([]checkpoint.Lease, 0, len(s.held))
+	for _, lease := range s.held {
+		leases = append(leases, lease)
+	}
+	return leases, nil
+}
+
+func (s *testStore) RegisterWorker(ctx context.Context, ownerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !containsString(s.workers, ownerID) {
+		s.workers = append(s.workers, ownerID)
+	}
 	return nil
 }
 
+func (s *testStore) UnregisterWorker(ctx context.Context, ownerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, worker := range s.workers {
+		if worker == ownerID {
+			s.workers = append(s.workers[:i], s.workers[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *testStore) ListWorkers(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.workers...), nil
+}
+
 func (s *testStore) LoadOffset(ctx context.Context, topic string, partition int32) (checkpoint.OffsetState, error) {
 	key := offsetKey(topic, partition)
 	s.mu.Lock()
@@ -88,7 +160,7 @@ func (s *testStore) LoadOffset(ctx context.Context, topic string, partition int3
 	if !ok {
 		offset = -1
 	}
-	return checkpoint.OffsetState{Topic: topic, Partition: partition, Offset: offset}, nil
+	return checkpoint.OffsetState{Topic: topic, Partition: partition, Offset: offset, Skipped: s.skipped[key]}, nil
 }
 
 func (s *testStore) CommitOffset(ctx context.Context, state checkpoint.OffsetState) error {
@@ -98,6 +170,10 @@ func (s *testStore) CommitOffset(ctx context.Context, state checkpoint.OffsetSta
 		s.offsets = make(map[string]int64)
 	}
 	s.offsets[key] = state.Offset
+	if s.skipped == nil {
+		s.skipped = make(map[string]bool)
+	}
+	s.skipped[key] = state.Skipped
 	s.mu.Unlock()
 	return nil
 }
@@ -118,6 +194,29 @@ func (d *testDecoder) Decode(ctx context.Context, segmentKey string, indexKey st
 	return d.records[segmentKey], nil
 }
 
+type failingDecoder struct {
+	testDecoder
+	fail string
+}
+
+func (d *failingDecoder) Decode(ctx context.Context, segmentKey string, indexKey string, topic string, partition int32) ([]decoder.Record, error) {
+	if segmentKey == d.fail {
+		return nil, errors.New("decode failed")
+	}
+	return d.testDecoder.Decode(ctx, segmentKey, indexKey, topic, partition)
+}
+
+// trackingSink commits offsets with its writes, like the Iceberg sink.
+type trackingSink struct {
+	testSink
+	committed map[int32]int64
+}
+
+func (s *trackingSink) CommittedOffset(ctx context.Context, topic string, partition int32) (int64, bool, error) {
+	offset, ok := s.committed[partition]
+	return offset, ok, nil
+}
+
 type testSink struct {
 	mu     sync.Mutex
 	all    []sink.Record
@@ -240,6 +339,460 @@ func TestProcessorPinsLeaseToSinglePartition(t *testing.T) {
 	}
 }
 
+func TestRecoverOffsetPrefersSink(t *testing.T) {
+	store := &testStore{offsets: map[string]int64{offsetKey("orders", 0): 5}}
+	p := &Processor{store: store, sink: &trackingSink{committed: map[int32]int64{0: 11}}}
+
+	offset, err := p.recoverOffset(context.Background(), "orders", 0)
+	if err != nil || offset != 11 {
+		t.Fatalf("expected offset 11 from sink, got %d err=%v", offset, err)
+	}
+	if store.offsets[offsetKey("orders", 0)] != 11 {
+		t.Fatalf("expected checkpoint store to be healed, got %v", store.offsets)
+	}
+
+	offset, err = p.recoverOffset(context.Background(), "orders", 1)
+	if err != nil || offset != -1 {
+		t.Fatalf("expected checkpoint fallback for untracked partition, got %d err=%v", offset, err)
+	}
+}
+
+func TestRecoverOffsetKeepsSkippedCheckpoint(t *testing.T) {
+	store := &testStore{
+		offsets: map[string]int64{offsetKey("orders", 0): 14, offsetKey("orders", 1): 3},
+		skipped: map[string]bool{offsetKey("orders", 0): true, offsetKey("orders", 1): true},
+	}
+	p := &Processor{store: store, sink: &trackingSink{committed: map[int32]int64{0: 11, 1: 7}}}
+
+	offset, err := p.recoverOffset(context.Background(), "orders", 0)
+	if err != nil || offset != 14 {
+		t.Fatalf("expected dead-lettered records to stay committed at 14, got %d err=%v", offset, err)
+	}
+	offset, err = p.recoverOffset(context.Background(), "orders", 1)
+	if err != nil || offset != 7 {
+		t.Fatalf("expected the sink to win once it is ahead, got %d err=%v", offset, err)
+	}
+}
+
+func TestSchedulerSkipsOffsetsCommittedBySink(t *testing.T) {
+	segments := []discovery.SegmentRef{
+		{Topic: "orders", Partition: 0, SegmentKey: "segment-0"},
+		{Topic: "orders", Partition: 0, SegmentKey: "segment-1"},
+		{Topic: "orders", Partition: 0, SegmentKey: "segment-2"},
+	}
+	records := map[string][]decoder.Record{
+		"segment-0": {{Topic: "orders", Partition: 0, Offset: 10}, {Topic: "orders", Partition: 0, Offset: 11}},
+		"segment-1": {{Topic: "orders", Partition: 0, Offset: 12}},
+		"segment-2": {{Topic: "orders", Partition: 0, Offset: 13}},
+	}
+	store := &testStore{}
+	sinkWriter := &trackingSink{testSink: testSink{writes: make(chan struct{}, 4)}, committed: map[int32]int64{0: 10}}
+	p := &Processor{
+		cfg:    config.Config{Processor: config.ProcessorConfig{MaxLeases: 1}},
+		decode: &failingDecoder{testDecoder: testDecoder{records: records}, fail: "segment-1"},
+		store:  store,
+		sink:   sinkWriter,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	sched := newScheduler(p, "worker-a")
+	sched.rebalance(ctx, segments)
+	select {
+	case <-sinkWriter.writes:
+	case <-ctx.Done():
+		t.Fatalf("timed out waiting for write")
+	}
+	sched.releaseAll("shutdown")
+
+	sinkWriter.mu.Lock()
+	defer sinkWriter.mu.Unlock()
+	if len(sinkWriter.all) != 1 || sinkWriter.all[0].Offset != 11 {
+		t.Fatalf("expected only offset 11 to be written, got %+v", sinkWriter.all)
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.offsets[offsetKey("orders", 0)] != 11 {
+		t.Fatalf("expected batch to stop at the failed segment, got %v", store.offsets)
+	}
+}
+
+// flakySink fails its first n writes, n being failures.
+type flakySink struct {
+	testSink
+	failures int
+	calls    int
+}
+
+func (s *flakySink) Write(ctx context.Context, records []sink.Record) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("commit conflict")
+	}
+	return s.testSink.Write(ctx, records)
+}
+
+type testDLQ struct {
+	entries []dlq.Entry
+}
+
+func (d *testDLQ) Write(ctx context.Context, entries []dlq.Entry) error {
+	d.entries = append(d.entries, entries...)
+	return nil
+}
+
+func (d *testDLQ) Close() error {
+	return nil
+}
+
+// evenValidator rejects the payload "even".
+type evenValidator struct {
+	mode schema.Mode
+}
+
+func (v evenValidator) Mode() schema.Mode { return v.mode }
+
+func (v evenValidator) Validate(ctx context.Context, topic string, payload []byte) error {
+	if string(payload) == "even" {
+		return fmt.Errorf("%w: even offset", schema.ErrInvalidPayload)
+	}
+	return nil
+}
+
+func dlqTestProcessor(sinkWriter sink.Writer, deadLetters dlq.Writer, validator schema.Validator, maxAttempts int) (*Processor, *testStore) {
+	store := &testStore{}
+	records := map[string][]decoder.Record{
+		"segment-0": {
+			{Topic: "orders", Partition: 0, Offset: 10, Value: []byte("even")},
+			{Topic: "orders", Partition: 0, Offset: 11, Value: []byte("odd")},
+		},
+	}
+	p := &Processor{
+		cfg:       config.Config{DLQ: config.DLQConfig{Retry: config.RetryConfig{MaxAttempts: maxAttempts, InitialBackoffMs: 1, MaxBackoffMs: 2}}},
+		decode:    &testDecoder{records: records},
+		store:     store,
+		sink:      sinkWriter,
+		validator: validator,
+		dlq:       deadLetters,
+	}
+	return p, store
+}
+
+func TestProcessSegmentRetriesSinkWrites(t *testing.T) {
+	sinkWriter := &flakySink{failures: 2}
+	p, _ := dlqTestProcessor(sinkWriter, nil, nil, 3)
+
+	offset, err := p.processSegment(context.Background(), discovery.SegmentRef{Topic: "orders", SegmentKey: "segment-0"}, -1)
+	if err != nil || offset != 11 {
+		t.Fatalf("expected write to succeed on the third attempt, got offset=%d err=%v", offset, err)
+	}
+	if sinkWriter.calls != 3 || len(sinkWriter.all) != 2 {
+		t.Fatalf("unexpected sink calls %d with %d records", sinkWriter.calls, len(sinkWriter.all))
+	}
+}
+
+func TestProcessSegmentKeepsOffsetAfterRetryBudget(t *testing.T) {
+	sinkWriter := &flakySink{failures: 10}
+	deadLetters := &testDLQ{}
+	p, store := dlqTestProcessor(sinkWriter, deadLetters, nil, 3)
+
+	offset, err := p.processSegment(context.Background(), discovery.SegmentRef{Topic: "orders", SegmentKey: "segment-0"}, -1)
+	if !errors.Is(err, errRetrySegment) || offset != -1 {
+		t.Fatalf("expected retry on next poll, got offset=%d err=%v", offset, err)
+	}
+	if sinkWriter.calls != 3 || len(deadLetters.entries) != 0 {
+		t.Fatalf("expected 3 attempts and no dead letters, got %d and %d", sinkWriter.calls, len(deadLetters.entries))
+	}
+	if _, ok := store.offsets[offsetKey("orders", 0)]; ok {
+		t.Fatalf("expected no checkpoint, got %v", store.offsets)
+	}
+}
+
+// unconvertibleSink rejects the record at offset bad with a RecordError.
+type unconvertibleSink struct {
+	testSink
+	bad   int64
+	calls int
+}
+
+func (s *unconvertibleSink) Write(ctx context.Context, records []sink.Record) error {
+	s.calls++
+	for _, record := range records {
+		if record.Offset == s.bad {
+			return &sink.RecordError{Record: record, Err: errors.New("payload is not a JSON object")}
+		}
+	}
+	return s.testSink.Write(ctx, records)
+}
+
+func TestProcessSegmentDeadLettersUnconvertibleRecords(t *testing.T) {
+	sinkWriter := &unconvertibleSink{bad: 10}
+	deadLetters := &testDLQ{}
+	p, store := dlqTestProcessor(sinkWriter, deadLetters, nil, 3)
+
+	offset, err := p.processSegment(context.Background(), discovery.SegmentRef{Topic: "orders", SegmentKey: "segment-0"}, -1)
+	if err != nil || offset != 11 {
+		t.Fatalf("expected the convertible record to be written, got offset=%d err=%v", offset, err)
+	}
+	if sinkWriter.calls != 2 || len(sinkWriter.all) != 1 || sinkWriter.all[0].Offset != 11 {
+		t.Fatalf("expected a second write without the bad record, got %d calls with %+v", sinkWriter.calls, sinkWriter.all)
+	}
+	if len(deadLetters.entries) != 1 || deadLetters.entries[0].Reason != dlq.ReasonConvert || deadLetters.entries[0].Offset != 10 {
+		t.Fatalf("unexpected dead letters: %+v", deadLetters.entries)
+	}
+	if store.offsets[offsetKey("orders", 0)] != 11 || store.skipped[offsetKey("orders", 0)] {
+		t.Fatalf("expected written checkpoint at 11, got %v skipped=%v", store.offsets, store.skipped)
+	}
+
+	sinkWriter.bad = 11
+	offset, err = p.processSegment(context.Background(), discovery.SegmentRef{Topic: "orders", SegmentKey: "segment-0"}, 10)
+	if err != nil || offset != 11 || !store.skipped[offsetKey("orders", 0)] {
+		t.Fatalf("expected a skipped checkpoint at 11, got offset=%d err=%v skipped=%v", offset, err, store.skipped)
+	}
+
+	p.dlq = nil
+	if _, err := p.processSegment(context.Background(), discovery.SegmentRef{Topic: "orders", SegmentKey: "segment-0"}, 10); !errors.Is(err, errRetrySegment) {
+		t.Fatalf("expected retry on next poll without a DLQ, got %v", err)
+	}
+}
+
+func TestProcessSegmentDeadLettersInvalidRecords(t *testing.T) {
+	sinkWriter := &flakySink{}
+	deadLetters := &testDLQ{}
+	p, _ := dlqTestProcessor(sinkWriter, deadLetters, evenValidator{mode: schema.ModeStrict}, 1)
+
+	offset, err := p.processSegment(context.Background(), discovery.SegmentRef{Topic: "orders", SegmentKey: "segment-0"}, -1)
+	if err != nil || offset != 11 {
+		t.Fatalf("expected strict mode with a DLQ to continue, got offset=%d err=%v", offset, err)
+	}
+	if len(sinkWriter.all) != 1 || sinkWriter.all[0].Offset != 11 {
+		t.Fatalf("expected only the valid record to be written, got %+v", sinkWriter.all)
+	}
+	if len(deadLetters.entries) != 1 || deadLetters.entries[0].Reason != dlq.ReasonSchema || deadLetters.entries[0].Offset != 10 {
+		t.Fatalf("unexpected dead letters: %+v", deadLetters.entries)
+	}
+
+	p.dlq = nil
+	if _, err := p.processSegment(context.Background(), discovery.SegmentRef{Topic: "orders", SegmentKey: "segment-0"}, -1); !errors.Is(err, schema.ErrInvalidPayload) {
+		t.Fatalf("expected strict mode without a DLQ to fail, got %v", err)
+	}
+}
+
+func partitionSegments(partitions int) ([]discovery.SegmentRef, map[string][]decoder.Record) {
+	segments := make([]discovery.SegmentRef, 0, partitions)
+	records := make(map[string][]decoder.Record)
+	for i := 0; i < partitions; i++ {
+		key := fmt.Sprintf("segment-%d", i)
+		segments = append(segments, discovery.SegmentRef{Topic: "orders", Partition: int32(i), SegmentKey: key})
+		records[key] = []decoder.Record{{Topic: "orders", Partition: int32(i), Offset: 10, Timestamp: 1}}
+	}
+	return segments, records
+}
+
+func heldPartitions(s *scheduler) []int32 {
+	var out []int32
+	for _, key := range s.heldKeys() {
+		out = append(out, key.partition)
+	}
+	return out
+}
+
+func TestSchedulerClaimsFairShare(t *testing.T) {
+	segments, records := partitionSegments(4)
+	store := &testStore{workers: []string{"worker-a", "worker-b"}}
+	sinkWriter := &testSink{writes: make(chan struct{}, 4)}
+	p := &Processor{
+		cfg:      config.Config{Processor: config.ProcessorConfig{MaxLeases: 16}},
+		discover: &testLister{segments: segments},
+		decode:   &testDecoder{records: records},
+		store:    store,
+		sink:     sinkWriter,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	a := newScheduler(p, "worker-a")
+	b := newScheduler(p, "worker-b")
+	defer a.releaseAll("shutdown")
+	defer b.releaseAll("shutdown")
+	a.rebalance(ctx, segments)
+	b.rebalance(ctx, segments)
+
+	if got := fmt.Sprint(heldPartitions(a)); got != "[0 1]" {
+		t.Fatalf("expected worker-a to hold [0 1], got %s", got)
+	}
+	if got := fmt.Sprint(heldPartitions(b)); got != "[2 3]" {
+		t.Fatalf("expected worker-b to hold [2 3], got %s", got)
+	}
+
+	for i := 0; i < 4; i++ {
+		select {
+		case <-sinkWriter.writes:
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for partition writes")
+		}
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	for i := 0; i < 4; i++ {
+		if store.offsets[offsetKey("orders", int32(i))] != 10 {
+			t.Fatalf("expected offset commit for partition %d, got %v", i, store.offsets)
+		}
+	}
+}
+
+func TestSchedulerHandsOffWhenWorkerJoins(t *testing.T) {
+	segments, records := partitionSegments(4)
+	store := &testStore{workers: []string{"worker-a"}}
+	p := &Processor{
+		cfg:      config.Config{Processor: config.ProcessorConfig{MaxLeases: 3}},
+		discover: &testLister{segments: segments},
+		decode:   &testDecoder{records: records},
+		store:    store,
+		sink:     &testSink{writes: make(chan struct{}, 4)},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	a := newScheduler(p, "worker-a")
+	defer a.releaseAll("shutdown")
+	a.rebalance(ctx, segments)
+	if got := fmt.Sprint(heldPartitions(a)); got != "[0 1 2]" {
+		t.Fatalf("expected max_leases to cap worker-a at [0 1 2], got %s", got)
+	}
+
+	_ = store.RegisterWorker(ctx, "worker-b")
+	b := newScheduler(p, "worker-b")
+	defer b.releaseAll("shutdown")
+	b.rebalance(ctx, segments)
+	if got := fmt.Sprint(heldPartitions(b)); got != "[3]" {
+		t.Fatalf("expected worker-b to claim only the unowned partition, got %s", got)
+	}
+
+	a.rebalance(ctx, segments)
+	if got := fmt.Sprint(heldPartitions(a)); got != "[0 1]" {
+		t.Fatalf("expected worker-a to release down to its share, got %s", got)
+	}
+	if atomic.LoadInt32(&store.releaseCalls) != 1 {
+		t.Fatalf("expected 1 lease release, got %d", atomic.LoadInt32(&store.releaseCalls))
+	}
+
+	b.rebalance(ctx, segments)
+	if got := fmt.Sprint(heldPartitions(b)); got != "[2 3]" {
+		t.Fatalf("expected worker-b to pick up the released partition, got %s", got)
+	}
+
+	_ = store.UnregisterWorker(ctx, "worker-b")
+	b.releaseAll("shutdown")
+	a.rebalance(ctx, segments)
+	if got := fmt.Sprint(heldPartitions(a)); got != "[0 1 2]" {
+		t.Fatalf("expected worker-a to take over after worker-b left, got %s", got)
+	}
+}
+
 func offsetKey(topic string, partition int32) string {
 	return fmt.Sprintf("%s:%d", topic, partition)
 }
+
+// signalingDecoder reports each decoded segment on decoded.
+type signalingDecoder struct {
+	testDecoder
+	decoded chan string
+}
+
+func (d *signalingDecoder) Decode(ctx context.Context, segmentKey string, indexKey string, topic string, partition int32) ([]decoder.Record, error) {
+	records, err := d.testDecoder.Decode(ctx, segmentKey, indexKey, topic, partition)
+	d.decoded <- segmentKey
+	return records, err
+}
+
+func TestSchedulerBuffersSegmentsUntilStop(t *testing.T) {
+	segments := []discovery.SegmentRef{
+		{Topic: "orders", Partition: 0, SegmentKey: "segment-0"},
+		{Topic: "orders", Partition: 0, SegmentKey: "segment-1"},
+	}
+	records := map[string][]decoder.Record{
+		"segment-0": {{Topic: "orders", Partition: 0, Offset: 0, Value: []byte("a")}, {Topic: "orders", Partition: 0, Offset: 1, Value: []byte("b")}},
+		"segment-1": {{Topic: "orders", Partition: 0, Offset: 2, Value: []byte("c")}},
+	}
+	store := &testStore{}
+	sinkWriter := &testSink{writes: make(chan struct{}, 4)}
+	decode := &signalingDecoder{testDecoder: testDecoder{records: records}, decoded: make(chan string, 4)}
+	p := &Processor{
+		cfg: config.Config{
+			Processor: config.ProcessorConfig{MaxLeases: 1, FlushIntervalSeconds: 60},
+			Mappings:  []config.Mapping{{Topic: "orders", Write: config.WriteConfig{TargetFileSizeBytes: 1 << 20}}},
+		},
+		decode: decode,
+		store:  store,
+		sink:   sinkWriter,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	sched := newScheduler(p, "worker-a")
+	sched.rebalance(ctx, segments)
+	for i := 0; i < len(segments); i++ {
+		select {
+		case <-decode.decoded:
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for decode")
+		}
+	}
+	if len(sinkWriter.writes) != 0 {
+		t.Fatalf("expected records to stay buffered below the target size")
+	}
+	sched.releaseAll("shutdown")
+
+	if len(sinkWriter.writes) != 1 {
+		t.Fatalf("expected a single flush on stop, got %d writes", len(sinkWriter.writes))
+	}
+	sinkWriter.mu.Lock()
+	defer sinkWriter.mu.Unlock()
+	if len(sinkWriter.all) != 3 {
+		t.Fatalf("expected all buffered records to be written, got %+v", sinkWriter.all)
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.offsets[offsetKey("orders", 0)] != 2 {
+		t.Fatalf("expected offset 2 to be committed, got %v", store.offsets)
+	}
+}
+
+type maintainingSink struct {
+	testSink
+	topics []string
+}
+
+func (s *maintainingSink) Maintain(ctx context.Context, topic string) (sink.MaintenanceResult, error) {
+	s.mu.Lock()
+	s.topics = append(s.topics, topic)
+	s.mu.Unlock()
+	return sink.MaintenanceResult{RewrittenFiles: 4, AddedFiles: 1}, nil
+}
+
+func TestMaintainTablesSkipsLeasedTables(t *testing.T) {
+	store := &testStore{tables: map[string]string{"prod.payments": "worker-b"}}
+	sinkWriter := &maintainingSink{}
+	p := &Processor{
+		cfg: config.Config{Mappings: []config.Mapping{
+			{Topic: "orders", Table: "prod.orders"},
+			{Topic: "orders-replay", Table: "prod.orders"},
+			{Topic: "payments", Table: "prod.payments"},
+		}},
+		store: store,
+		sink:  sinkWriter,
+	}
+
+	p.maintainTables(context.Background(), sinkWriter, "worker-a")
+
+	if len(sinkWriter.topics) != 1 || sinkWriter.topics[0] != "orders" {
+		t.Fatalf("expected only prod.orders to be maintained once, got %v", sinkWriter.topics)
+	}
+	if _, ok := store.tables["prod.orders"]; ok {
+		t.Fatalf("expected maintenance lease to be released")
+	}
+	if store.tables["prod.payments"] != "worker-b" {
+		t.Fatalf("expected lease held by another worker to be left alone")
+	}
+}
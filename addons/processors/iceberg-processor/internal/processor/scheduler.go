@@ -0,0 +1,376 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package processor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/checkpoint"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/discovery"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/metrics"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/sink"
+)
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+// partitionWorker owns one leased partition and writes its segments in order.
+type partitionWorker struct {
+	lease     checkpoint.Lease
+	segments  chan []discovery.SegmentRef
+	stop      chan struct{}
+	stopOnce  sync.Once
+	cancel    context.CancelFunc
+	done      chan struct{}
+	stopRenew func()
+}
+
+// halt asks the worker to stop after the segment it is currently writing.
+func (w *partitionWorker) halt() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+// partitionBuffer holds the records read from a partition's segments until
+// they are written as one batch. last is the last record read, including
+// records that were rejected or filtered by validation, so the committed
+// offset moves past them.
+type partitionBuffer struct {
+	records []sink.Record
+	bytes   int64
+	last    sink.Record
+	pending bool
+	since   time.Time
+}
+
+func (b *partitionBuffer) add(records []sink.Record, last sink.Record) {
+	if !b.pending {
+		b.since = time.Now()
+	}
+	for _, record := range records {
+		b.bytes += int64(len(record.Key) + len(record.Value))
+		for _, h := range record.Headers {
+			b.bytes += int64(len(h.Key) + len(h.Value))
+		}
+	}
+	b.records = append(b.records, records...)
+	b.last = last
+	b.pending = true
+}
+
+func (b *partitionBuffer) empty() bool {
+	return !b.pending
+}
+
+func (b *partitionBuffer) reset() {
+	*b = partitionBuffer{}
+}
+
+// scheduler tracks the partitions leased by this worker. On every poll it
+// sizes its fair share as ceil(partitions / live workers), capped by
+// processor.max_leases, releases leases above that share and claims unowned
+// partitions below it. Released partitions are picked up by other workers on
+// their next poll.
+type scheduler struct {
+	p         *Processor
+	ownerID   string
+	maxLeases int
+	workers   map[partitionKey]*partitionWorker
+	lost      chan partitionKey
+	failed    chan error
+}
+
+func newScheduler(p *Processor, ownerID string) *scheduler {
+	maxLeases := p.cfg.Processor.MaxLeases
+	if maxLeases <= 0 {
+		maxLeases = 1
+	}
+	return &scheduler{
+		p:         p,
+		ownerID:   ownerID,
+		maxLeases: maxLeases,
+		workers:   make(map[partitionKey]*partitionWorker),
+		lost:      make(chan partitionKey),
+		failed:    make(chan error),
+	}
+}
+
+func (s *scheduler) rebalance(ctx context.Context, segments []discovery.SegmentRef) {
+	byPartition := make(map[partitionKey][]discovery.SegmentRef)
+	keys := make([]partitionKey, 0)
+	for _, seg := range segments {
+		key := partitionKey{topic: seg.Topic, partition: seg.Partition}
+		if _, ok := byPartition[key]; !ok {
+			keys = append(keys, key)
+		}
+		byPartition[key] = append(byPartition[key], seg)
+	}
+	sortPartitionKeys(keys)
+
+	for _, key := range s.heldKeys() {
+		if _, ok := byPartition[key]; !ok {
+			s.drop(key, "rebalance")
+		}
+	}
+	target := s.share(ctx, len(keys))
+	if excess := len(s.workers) - target; excess > 0 {
+		held := s.heldKeys()
+		for _, key := range held[len(held)-excess:] {
+			s.drop(key, "rebalance")
+		}
+	}
+	if len(s.workers) < target {
+		s.claim(ctx, keys, target)
+	}
+
+	for key, w := range s.workers {
+		select {
+		case w.segments <- byPartition[key]:
+		default:
+			// The worker is still busy with an earlier batch; the next poll
+			// lists these segments again.
+		}
+	}
+	metrics.AssignedPartitions.WithLabelValues(s.ownerID).Set(float64(len(s.workers)))
+}
+
+// share returns how many partitions this worker should hold.
+func (s *scheduler) share(ctx context.Context, partitions int) int {
+	live := 1
+	workers, err := s.p.store.ListWorkers(ctx)
+	if err != nil {
+		metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
+	} else {
+		live = len(workers)
+		if !containsString(workers, s.ownerID) {
+			live++
+		}
+	}
+	metrics.LiveWorkers.Set(float64(live))
+
+	target := (partitions + live - 1) / live
+	if target > s.maxLeases {
+		target = s.maxLeases
+	}
+	return target
+}
+
+func (s *scheduler) claim(ctx context.Context, keys []partitionKey, target int) {
+	owned := make(map[partitionKey]bool)
+	leases, err := s.p.store.ListLeases(ctx)
+	if err != nil {
+		metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
+	}
+	for _, lease := range leases {
+		if lease.OwnerID != s.ownerID {
+			owned[partitionKey{topic: lease.Topic, partition: lease.Partition}] = true
+		}
+	}
+
+	for _, key := range keys {
+		if len(s.workers) >= target {
+			return
+		}
+		if _, held := s.workers[key]; held || owned[key] {
+			continue
+		}
+		lease, err := s.p.store.ClaimLease(ctx, key.topic, key.partition, s.ownerID)
+		if err != nil {
+			metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
+			continue
+		}
+		s.start(ctx, key, lease)
+	}
+}
+
+func (s *scheduler) start(ctx context.Context, key partitionKey, lease checkpoint.Lease) {
+	workerCtx, cancel := context.WithCancel(ctx)
+	leaseLost := make(chan error, 1)
+	w := &partitionWorker{
+		lease:    lease,
+		segments: make(chan []discovery.SegmentRef, 1),
+		stop:     make(chan struct{}),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	w.stopRenew = s.p.startLeaseRenewal(workerCtx, lease, leaseLost)
+	s.workers[key] = w
+	go s.run(workerCtx, key, w, leaseLost)
+}
+
+func (s *scheduler) run(ctx context.Context, key partitionKey, w *partitionWorker, leaseLost <-chan error) {
+	defer close(w.done)
+
+	// The committed offset is recovered once per lease and then tracked
+	// locally, since no other worker writes this partition while we hold it.
+	// read runs ahead of committed while records sit in the buffer.
+	var (
+		committed int64
+		read      int64
+		recovered bool
+		buf       partitionBuffer
+	)
+	target := s.p.targetBytes(key.topic)
+	interval := time.Duration(s.p.cfg.Processor.FlushIntervalSeconds) * time.Second
+
+	onLost := func(err error) {
+		log.Printf("lease renewal failed topic=%s partition=%d: %v", key.topic, key.partition, err)
+		metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
+		select {
+		case s.lost <- key:
+		case <-w.stop:
+		case <-ctx.Done():
+		}
+	}
+	// flush commits the buffer. On failure the buffered records are read
+	// again from their segments on the next poll.
+	flush := func() bool {
+		if buf.empty() {
+			return true
+		}
+		offset, err := s.p.flush(ctx, &buf, committed)
+		committed, read = offset, offset
+		return err == nil
+	}
+
+	for {
+		var flushDue <-chan time.Time
+		if !buf.empty() {
+			flushDue = time.After(time.Until(buf.since.Add(interval)))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stop:
+			flush()
+			return
+		case err := <-leaseLost:
+			onLost(err)
+			return
+		case <-flushDue:
+			flush()
+		case batch := <-w.segments:
+			if !recovered {
+				offset, err := s.p.recoverOffset(ctx, key.topic, key.partition)
+				if err != nil {
+					log.Printf("offset recovery failed topic=%s partition=%d: %v", key.topic, key.partition, err)
+					continue
+				}
+				committed, read, recovered = offset, offset, true
+			}
+			for _, seg := range batch {
+				select {
+				case <-ctx.Done():
+					return
+				case <-w.stop:
+					flush()
+					return
+				case err := <-leaseLost:
+					onLost(err)
+					return
+				default:
+				}
+				err := s.p.readSegment(ctx, seg, read, &buf)
+				if errors.Is(err, errRetrySegment) {
+					break
+				}
+				if err != nil {
+					select {
+					case s.failed <- err:
+					case <-w.stop:
+					case <-ctx.Done():
+					}
+					return
+				}
+				if !buf.empty() {
+					read = buf.last.Offset
+				}
+				if target <= 0 || buf.bytes >= target {
+					if !flush() {
+						break
+					}
+				}
+			}
+			if interval <= 0 || (!buf.empty() && time.Since(buf.since) >= interval) {
+				flush()
+			}
+		}
+	}
+}
+
+// drop stops the worker for key once its in-flight segment is written and
+// releases the lease so another worker can claim it.
+func (s *scheduler) drop(key partitionKey, reason string) {
+	w, ok := s.workers[key]
+	if !ok {
+		return
+	}
+	delete(s.workers, key)
+	w.halt()
+	<-w.done
+	w.stopRenew()
+	w.cancel()
+
+	releaseCtx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if err := s.p.store.ReleaseLease(releaseCtx, w.lease); err != nil {
+		metrics.ErrorsTotal.WithLabelValues("checkpoint").Inc()
+	}
+	metrics.LeaseHandoffs.WithLabelValues(reason).Inc()
+	metrics.AssignedPartitions.WithLabelValues(s.ownerID).Set(float64(len(s.workers)))
+}
+
+func (s *scheduler) releaseAll(reason string) {
+	for _, w := range s.workers {
+		w.halt()
+	}
+	for _, key := range s.heldKeys() {
+		s.drop(key, reason)
+	}
+}
+
+func (s *scheduler) heldKeys() []partitionKey {
+	keys := make([]partitionKey, 0, len(s.workers))
+	for key := range s.workers {
+		keys = append(keys, key)
+	}
+	sortPartitionKeys(keys)
+	return keys
+}
+
+func sortPartitionKeys(keys []partitionKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].topic != keys[j].topic {
+			return keys[i].topic < keys[j].topic
+		}
+		return keys[i].partition < keys[j].partition
+	})
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -40,6 +40,10 @@ const (
 	ModeStrict  Mode = "strict"
 )
 
+// ErrInvalidPayload marks payloads rejected by the schema, as opposed to
+// failures reaching the registry.
+var ErrInvalidPayload = errors.New("invalid payload")
+
 // Validator validates payloads by topic.
 type Validator interface {
 	Mode() Mode
@@ -110,11 +114,11 @@ func (r *jsonRegistry) Validate(ctx context.Context, topic string, payload []byt
 
 	var value interface{}
 	if err := json.Unmarshal(payload, &value); err != nil {
-		return fmt.Errorf("invalid json payload: %w", err)
+		return fmt.Errorf("%w: invalid json: %v", ErrInvalidPayload, err)
 	}
 
 	if err := schema.Validate(value); err != nil {
-		return err
+		return fmt.Errorf("%w: %v", ErrInvalidPayload, err)
 	}
 	return nil
 }
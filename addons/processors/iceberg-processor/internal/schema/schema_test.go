@@ -18,6 +18,7 @@ package schema
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"testing"
@@ -52,8 +53,8 @@ func TestJSONSchemaValidation(t *testing.T) {
 		t.Fatalf("expected valid payload: %v", err)
 	}
 
-	if err := validator.Validate(context.Background(), "orders", []byte(`{"id":"bad"}`)); err == nil {
-		t.Fatalf("expected invalid payload")
+	if err := validator.Validate(context.Background(), "orders", []byte(`{"id":"bad"}`)); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("expected invalid payload, got %v", err)
 	}
 }
 
@@ -0,0 +1,170 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hamba/avro/v2"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+// avroColumns maps the fields of an Avro record schema to columns. Records
+// become structs, arrays lists and maps string-keyed maps. Columns are
+// optional so that fields added by later versions can be added to the table;
+// fields of types without a column mapping, such as decimals or unions of
+// several types, are skipped.
+func avroColumns(schema avro.Schema) ([]config.Column, error) {
+	record, ok := schema.(*avro.RecordSchema)
+	if !ok {
+		return nil, fmt.Errorf("avro schema must be a record, got %s", schema.Type())
+	}
+	return avroFields(record, map[string]bool{}), nil
+}
+
+func avroFields(record *avro.RecordSchema, visiting map[string]bool) []config.Column {
+	visiting[record.FullName()] = true
+	defer delete(visiting, record.FullName())
+
+	columns := make([]config.Column, 0, len(record.Fields()))
+	for _, field := range record.Fields() {
+		col, ok := avroColumn(field.Type(), visiting)
+		if !ok {
+			continue
+		}
+		col.Name = field.Name()
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+func avroColumn(schema avro.Schema, visiting map[string]bool) (config.Column, bool) {
+	switch s := schema.(type) {
+	case *avro.RefSchema:
+		return avroColumn(s.Schema(), visiting)
+	case *avro.UnionSchema:
+		var inner avro.Schema
+		for _, typ := range s.Types() {
+			if typ.Type() == avro.Null {
+				continue
+			}
+			if inner != nil {
+				return config.Column{}, false
+			}
+			inner = typ
+		}
+		if inner == nil {
+			return config.Column{}, false
+		}
+		return avroColumn(inner, visiting)
+	case *avro.RecordSchema:
+		// Recursive records have no finite table type.
+		if visiting[s.FullName()] {
+			return config.Column{}, false
+		}
+		fields := avroFields(s, visiting)
+		if len(fields) == 0 {
+			return config.Column{}, false
+		}
+		return config.Column{Type: "struct", Fields: fields}, true
+	case *avro.ArraySchema:
+		element, ok := avroColumn(s.Items(), visiting)
+		if !ok {
+			return config.Column{}, false
+		}
+		return config.Column{Type: "list", Element: &element}, true
+	case *avro.MapSchema:
+		value, ok := avroColumn(s.Values(), visiting)
+		if !ok {
+			return config.Column{}, false
+		}
+		return config.Column{Type: "map", Element: &value}, true
+	case *avro.EnumSchema:
+		return config.Column{Type: "string"}, true
+	case *avro.FixedSchema:
+		if s.Logical() != nil {
+			return config.Column{}, false
+		}
+		return config.Column{Type: "binary"}, true
+	case *avro.PrimitiveSchema:
+		if logical := s.Logical(); logical != nil {
+			switch logical.Type() {
+			case avro.Date:
+				return config.Column{Type: "date"}, true
+			case avro.TimestampMillis, avro.TimestampMicros, avro.LocalTimestampMillis, avro.LocalTimestampMicros:
+				return config.Column{Type: "timestamp"}, true
+			case avro.UUID:
+				return config.Column{Type: "string"}, true
+			default:
+				return config.Column{}, false
+			}
+		}
+		switch s.Type() {
+		case avro.Boolean:
+			return config.Column{Type: "boolean"}, true
+		case avro.Int:
+			return config.Column{Type: "int"}, true
+		case avro.Long:
+			return config.Column{Type: "long"}, true
+		case avro.Float:
+			return config.Column{Type: "float"}, true
+		case avro.Double:
+			return config.Column{Type: "double"}, true
+		case avro.String:
+			return config.Column{Type: "string"}, true
+		case avro.Bytes:
+			return config.Column{Type: "binary"}, true
+		}
+	}
+	return config.Column{}, false
+}
+
+func decodeAvro(schema avro.Schema, body []byte) (map[string]interface{}, error) {
+	var decoded interface{}
+	if err := avro.Unmarshal(schema, body, &decoded); err != nil {
+		return nil, err
+	}
+	values, ok := normalizeAvro(decoded).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("avro payload is not a record")
+	}
+	return values, nil
+}
+
+// normalizeAvro turns fixed values, which decode as byte arrays, into byte
+// slices.
+func normalizeAvro(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeAvro(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeAvro(item)
+		}
+		return v
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		out := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(out), rv)
+		return out
+	}
+	return value
+}
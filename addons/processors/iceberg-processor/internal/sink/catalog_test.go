@@ -0,0 +1,185 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	iceberg "github.com/apache/iceberg-go"
+	gluecatalog "github.com/apache/iceberg-go/catalog/glue"
+	sqlcatalog "github.com/apache/iceberg-go/catalog/sql"
+	"github.com/apache/iceberg-go/table"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+func catalogTestConfig(catalogCfg config.CatalogConfig, warehouse string) config.Config {
+	return config.Config{
+		Iceberg: config.IcebergConfig{Catalog: catalogCfg, Warehouse: warehouse},
+		Mappings: []config.Mapping{{
+			Topic:               "orders",
+			Table:               "prod.orders",
+			Mode:                "append",
+			CreateTableIfAbsent: true,
+			Schema:              config.MappingSchemaConfig{Source: "none"},
+		}},
+	}
+}
+
+// writeAndReload writes two batches through a fresh writer, then checks a
+// second writer on the same catalog sees both commits.
+func writeAndReload(t *testing.T, cfg config.Config) {
+	t.Helper()
+	ctx := context.Background()
+	for batch := 0; batch < 2; batch++ {
+		writer, err := New(cfg)
+		if err != nil {
+			t.Fatalf("New writer: %v", err)
+		}
+		records := make([]Record, 0, 3)
+		for i := 0; i < 3; i++ {
+			offset := int64(batch*3 + i)
+			records = append(records, Record{Topic: "orders", Offset: offset, Value: []byte(fmt.Sprintf(`{"id":%d}`, offset))})
+		}
+		if err := writer.Write(ctx, records); err != nil {
+			t.Fatalf("write batch %d: %v", batch, err)
+		}
+		if err := writer.Close(ctx); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+	}
+
+	writer, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New writer: %v", err)
+	}
+	iw := writer.(*icebergWriter)
+	offset, ok, err := iw.CommittedOffset(ctx, "orders", 0)
+	if err != nil || !ok || offset != 5 {
+		t.Fatalf("expected committed offset 5, got %d ok=%v err=%v", offset, ok, err)
+	}
+	tbl, err := iw.catalog.LoadTable(ctx, table.Identifier{"prod", "orders"})
+	if err != nil {
+		t.Fatalf("load table: %v", err)
+	}
+	scan, err := tbl.Scan().ToArrowTable(ctx)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	defer scan.Release()
+	if scan.NumRows() != 6 {
+		t.Fatalf("expected 6 rows, got %d", scan.NumRows())
+	}
+}
+
+func TestSQLCatalogSQLiteRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	cfg := catalogTestConfig(config.CatalogConfig{
+		Type: "sql",
+		URI:  "file:" + filepath.Join(dir, "catalog.db"),
+		SQL:  config.SQLCatalogConfig{Dialect: "sqlite"},
+	}, "file://"+filepath.Join(dir, "warehouse"))
+	writeAndReload(t, cfg)
+
+	writer, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New writer: %v", err)
+	}
+	if _, ok := writer.(*icebergWriter).catalog.(*sqlcatalog.Catalog); !ok {
+		t.Fatalf("expected a SQL catalog, got %T", writer.(*icebergWriter).catalog)
+	}
+}
+
+func TestHadoopCatalogRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	cfg := catalogTestConfig(config.CatalogConfig{Type: "hadoop"}, dir)
+	writeAndReload(t, cfg)
+
+	cat, err := newHadoopCatalog(dir, iceberg.Properties{})
+	if err != nil {
+		t.Fatalf("hadoop catalog: %v", err)
+	}
+	version, err := cat.currentVersion(cat.location(table.Identifier{"prod", "orders"}))
+	if err != nil || version < 3 {
+		t.Fatalf("expected at least 3 metadata versions, got %d err=%v", version, err)
+	}
+	namespaces, err := cat.ListNamespaces(context.Background(), nil)
+	if err != nil || len(namespaces) != 1 || namespaces[0][0] != "prod" {
+		t.Fatalf("unexpected namespaces %v err=%v", namespaces, err)
+	}
+	for ident, err := range cat.ListTables(context.Background(), table.Identifier{"prod"}) {
+		if err != nil || len(ident) != 2 || ident[1] != "orders" {
+			t.Fatalf("unexpected table %v err=%v", ident, err)
+		}
+	}
+}
+
+func TestHadoopCatalogRejectsConcurrentCommit(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	cat, err := newHadoopCatalog(dir, iceberg.Properties{})
+	if err != nil {
+		t.Fatalf("hadoop catalog: %v", err)
+	}
+	if err := cat.CreateNamespace(ctx, table.Identifier{"prod"}, nil); err != nil {
+		t.Fatalf("create namespace: %v", err)
+	}
+	ident := table.Identifier{"prod", "orders"}
+	schema := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	if _, err := cat.CreateTable(ctx, ident, schema); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	// Both writers start from version 1; only the first commit may win.
+	base, _, err := cat.loadTable(ident)
+	if err != nil {
+		t.Fatalf("load table: %v", err)
+	}
+	update := []table.Update{table.NewSetPropertiesUpdate(iceberg.Properties{"owner": "a"})}
+	if _, _, err := cat.CommitTable(ctx, ident, nil, update); err != nil {
+		t.Fatalf("first commit: %v", err)
+	}
+	updated, err := table.UpdateTableMetadata(base.Metadata(), update, base.MetadataLocation())
+	if err != nil {
+		t.Fatalf("update metadata: %v", err)
+	}
+	if _, err := cat.writeVersion(cat.location(ident), 2, updated); !errors.Is(err, errHadoopCommitConflict) {
+		t.Fatalf("expected commit conflict, got %v", err)
+	}
+	if !isCommitConflict(errHadoopCommitConflict) {
+		t.Fatalf("expected hadoop conflicts to be retried")
+	}
+}
+
+func TestGlueCatalogFromConfig(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	cfg := catalogTestConfig(config.CatalogConfig{
+		Type: "glue",
+		Glue: config.GlueCatalogConfig{Region: "eu-west-1", CatalogID: "123456789012"},
+	}, "s3://warehouse/prod")
+
+	writer, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New writer: %v", err)
+	}
+	if _, ok := writer.(*icebergWriter).catalog.(*gluecatalog.Catalog); !ok {
+		t.Fatalf("expected a Glue catalog, got %T", writer.(*icebergWriter).catalog)
+	}
+}
@@ -0,0 +1,797 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	iceberg "github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/google/uuid"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+const (
+	deltaLogDir          = "_delta_log"
+	deltaLastCheckpoint  = "_last_checkpoint"
+	deltaEngineInfo      = "kafscale-iceberg-processor"
+	deltaTimestampNtz    = "timestampNtz"
+	deltaAppIDPrefix     = "kafscale"
+	deltaCommitAttempts  = 3
+	deltaSupportedWriter = 2
+)
+
+// deltaLegacyWriterFeatures are the features implied by writer versions
+// below 7, which must be listed when a table is upgraded to table features.
+var deltaLegacyWriterFeatures = map[string]bool{"appendOnly": true, "invariants": true}
+
+// deltaWriter appends records to Delta Lake tables. Each batch is one Parquet
+// data file and one _delta_log commit, which also carries a txn action per
+// Kafka partition holding the last offset written. Commits race on creating
+// the next log file; the loser reloads the log and retries.
+type deltaWriter struct {
+	store    deltaStore
+	mappings map[string]deltaMapping
+	registry *schemaRegistry
+
+	mu     sync.Mutex
+	tables map[string]*deltaSnapshot
+}
+
+type deltaMapping struct {
+	location   string
+	autoCreate bool
+	schema     config.MappingSchemaConfig
+	sortOrder  []config.SortField
+	write      config.WriteConfig
+}
+
+// deltaSnapshot is the table state this writer needs, replayed from the log.
+type deltaSnapshot struct {
+	version  int64
+	protocol *deltaProtocol
+	metadata *deltaMetadata
+	txns     map[string]int64
+}
+
+type deltaAction struct {
+	Protocol   *deltaProtocol `json:"protocol,omitempty"`
+	MetaData   *deltaMetadata `json:"metaData,omitempty"`
+	Add        *deltaAdd      `json:"add,omitempty"`
+	Txn        *deltaTxn      `json:"txn,omitempty"`
+	CommitInfo map[string]any `json:"commitInfo,omitempty"`
+}
+
+type deltaProtocol struct {
+	MinReaderVersion int      `json:"minReaderVersion"`
+	MinWriterVersion int      `json:"minWriterVersion"`
+	ReaderFeatures   []string `json:"readerFeatures,omitempty"`
+	WriterFeatures   []string `json:"writerFeatures,omitempty"`
+}
+
+type deltaMetadata struct {
+	ID               string      `json:"id"`
+	Format           deltaFormat `json:"format"`
+	SchemaString     string      `json:"schemaString"`
+	PartitionColumns []string    `json:"partitionColumns"`
+	Configuration    stringMap   `json:"configuration"`
+	CreatedTime      int64       `json:"createdTime,omitempty"`
+}
+
+type deltaFormat struct {
+	Provider string    `json:"provider"`
+	Options  stringMap `json:"options"`
+}
+
+type deltaAdd struct {
+	Path             string    `json:"path"`
+	PartitionValues  stringMap `json:"partitionValues"`
+	Size             int64     `json:"size"`
+	ModificationTime int64     `json:"modificationTime"`
+	DataChange       bool      `json:"dataChange"`
+	Stats            string    `json:"stats,omitempty"`
+}
+
+type deltaTxn struct {
+	AppID       string `json:"appId"`
+	Version     int64  `json:"version"`
+	LastUpdated int64  `json:"lastUpdated,omitempty"`
+}
+
+type deltaSchema struct {
+	Type   string       `json:"type"`
+	Fields []deltaField `json:"fields"`
+}
+
+type deltaField struct {
+	Name     string          `json:"name"`
+	Type     json.RawMessage `json:"type"`
+	Nullable bool            `json:"nullable"`
+	Metadata json.RawMessage `json:"metadata"`
+}
+
+// stringMap is a Delta string map. It marshals nil as {} and also accepts
+// the key/value list form maps take when checkpoints are read through Arrow.
+type stringMap map[string]string
+
+func (m stringMap) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]string(m))
+}
+
+func (m *stringMap) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*m = nil
+		return nil
+	}
+	var object map[string]string
+	if err := json.Unmarshal(data, &object); err == nil {
+		*m = object
+		return nil
+	}
+	var entries []struct {
+		Key   string  `json:"key"`
+		Value *string `json:"value"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	out := make(stringMap, len(entries))
+	for _, entry := range entries {
+		if entry.Value != nil {
+			out[entry.Key] = *entry.Value
+		}
+	}
+	*m = out
+	return nil
+}
+
+func newDeltaWriter(cfg config.Config) (Writer, error) {
+	store, err := newDeltaStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	warehouse := strings.TrimRight(cfg.Sink.Delta.Warehouse, "/")
+	mappings := make(map[string]deltaMapping, len(cfg.Mappings))
+	for _, mapping := range cfg.Mappings {
+		mappings[mapping.Topic] = deltaMapping{
+			location:   warehouse + "/" + strings.ReplaceAll(mapping.Table, ".", "/"),
+			autoCreate: mapping.CreateTableIfAbsent,
+			schema:     mapping.Schema,
+			sortOrder:  mapping.SortOrder,
+			write:      mapping.Write,
+		}
+	}
+	return &deltaWriter{
+		store:    store,
+		mappings: mappings,
+		registry: newSchemaRegistry(cfg.Schema, cfg.Mappings),
+		tables:   make(map[string]*deltaSnapshot),
+	}, nil
+}
+
+func (w *deltaWriter) Write(ctx context.Context, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	records, err := w.registry.decodeRecords(ctx, records)
+	if err != nil {
+		return err
+	}
+	for topic, topicRecords := range groupByTopic(records) {
+		mapping, ok := w.mappings[topic]
+		if !ok {
+			return fmt.Errorf("no table mapping for topic %q", topic)
+		}
+		if err := w.writeTopic(ctx, topic, mapping, topicRecords); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *deltaWriter) writeTopic(ctx context.Context, topic string, mapping deltaMapping, records []Record) error {
+	w.mu.Lock()
+	snap := w.tables[topic]
+	w.mu.Unlock()
+
+	var lastErr error
+	for attempt := 0; attempt < deltaCommitAttempts; attempt++ {
+		if snap == nil || attempt > 0 {
+			loaded, err := loadDeltaSnapshot(ctx, w.store, mapping.location)
+			if err != nil {
+				return err
+			}
+			snap = loaded
+		}
+		if snap.version < 0 && !mapping.autoCreate {
+			return fmt.Errorf("delta table %s does not exist and create_table_if_missing is false", mapping.location)
+		}
+
+		// Offsets travel in txn actions of the same commit as the data, so
+		// anything the table already has is skipped.
+		pending := filterCommitted(records, snap.offsets(topic))
+		if len(pending) == 0 {
+			lastErr = nil
+			break
+		}
+		next, err := w.commit(ctx, topic, mapping, snap, pending)
+		if err == nil {
+			w.mu.Lock()
+			w.tables[topic] = next
+			w.mu.Unlock()
+			lastErr = nil
+			break
+		}
+		lastErr = err
+		if !errors.Is(err, errDeltaCommitConflict) {
+			return err
+		}
+		log.Printf("delta: commit of version %d to %s conflicted, retrying", snap.version+1, mapping.location)
+		if err := sleepWithContext(ctx, time.Duration(attempt+1)*200*time.Millisecond); err != nil {
+			return err
+		}
+	}
+	return lastErr
+}
+
+// commit writes records as one data file and commits it as the version after
+// snap. It returns the snapshot the commit produced.
+func (w *deltaWriter) commit(ctx context.Context, topic string, mapping deltaMapping, snap *deltaSnapshot, records []Record) (*deltaSnapshot, error) {
+	columns, err := resolveColumns(ctx, w.registry, mapping.schema, topic)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := deltaIcebergSchema(columns)
+	if err != nil {
+		return nil, err
+	}
+	metadata, protocol, err := evolveDeltaTable(snap, schema)
+	if err != nil {
+		return nil, fmt.Errorf("delta table %s: %w", mapping.location, err)
+	}
+
+	arrowSchema, err := table.SchemaToArrowSchema(schema, nil, false, false)
+	if err != nil {
+		return nil, err
+	}
+	data, err := deltaParquet(arrowSchema, columns, sortRecords(records, mapping.sortOrder), mapping.write)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	name := fmt.Sprintf("part-00000-%s-c000.snappy.parquet", uuid.New().String())
+	dataLocation := mapping.location + "/" + name
+	if err := w.store.Write(ctx, dataLocation, data); err != nil {
+		return nil, fmt.Errorf("write delta data file: %w", err)
+	}
+
+	actions := make([]deltaAction, 0, 4)
+	actions = append(actions, deltaAction{CommitInfo: map[string]any{
+		"timestamp":           now,
+		"operation":           "WRITE",
+		"operationParameters": map[string]string{"mode": "Append"},
+		"isBlindAppend":       true,
+		"engineInfo":          deltaEngineInfo,
+	}})
+	if protocol != nil {
+		actions = append(actions, deltaAction{Protocol: protocol})
+	}
+	if metadata != nil {
+		actions = append(actions, deltaAction{MetaData: metadata})
+	}
+	stats, _ := json.Marshal(map[string]int{"numRecords": len(records)})
+	actions = append(actions, deltaAction{Add: &deltaAdd{
+		Path:             name,
+		Size:             int64(len(data)),
+		ModificationTime: now,
+		DataChange:       true,
+		Stats:            string(stats),
+	}})
+	offsets := lastOffsets(records)
+	partitions := make([]int32, 0, len(offsets))
+	for partition := range offsets {
+		partitions = append(partitions, partition)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	for _, partition := range partitions {
+		actions = append(actions, deltaAction{Txn: &deltaTxn{
+			AppID:       deltaAppID(topic, partition),
+			Version:     offsets[partition],
+			LastUpdated: now,
+		}})
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, action := range actions {
+		if err := enc.Encode(action); err != nil {
+			return nil, err
+		}
+	}
+	version := snap.version + 1
+	if err := w.store.PutIfAbsent(ctx, deltaLogLocation(mapping.location, version), body.Bytes()); err != nil {
+		if errors.Is(err, errDeltaCommitConflict) {
+			_ = w.store.Delete(ctx, dataLocation)
+		}
+		return nil, err
+	}
+
+	next := &deltaSnapshot{
+		version:  version,
+		protocol: snap.protocol,
+		metadata: snap.metadata,
+		txns:     make(map[string]int64, len(snap.txns)+len(offsets)),
+	}
+	for appID, v := range snap.txns {
+		next.txns[appID] = v
+	}
+	next.apply(actions)
+	return next, nil
+}
+
+// CommittedOffset reads the last offset committed for a partition from the
+// txn actions in the table log, bypassing the cached snapshot.
+func (w *deltaWriter) CommittedOffset(ctx context.Context, topic string, partition int32) (int64, bool, error) {
+	mapping, ok := w.mappings[topic]
+	if !ok {
+		return 0, false, fmt.Errorf("no table mapping for topic %q", topic)
+	}
+	snap, err := loadDeltaSnapshot(ctx, w.store, mapping.location)
+	if err != nil {
+		return 0, false, err
+	}
+	w.mu.Lock()
+	if w.tables[topic] != nil {
+		w.tables[topic] = snap
+	}
+	w.mu.Unlock()
+	offset, ok := snap.txns[deltaAppID(topic, partition)]
+	return offset, ok, nil
+}
+
+func (w *deltaWriter) Close(ctx context.Context) error {
+	return nil
+}
+
+func deltaAppID(topic string, partition int32) string {
+	return fmt.Sprintf("%s.%s.%d", deltaAppIDPrefix, topic, partition)
+}
+
+func deltaLogLocation(tableLocation string, version int64) string {
+	return fmt.Sprintf("%s/%s/%020d.json", tableLocation, deltaLogDir, version)
+}
+
+// offsets returns the committed offset per partition of topic.
+func (s *deltaSnapshot) offsets(topic string) map[int32]int64 {
+	prefix := deltaAppIDPrefix + "." + topic + "."
+	out := make(map[int32]int64)
+	for appID, version := range s.txns {
+		if !strings.HasPrefix(appID, prefix) {
+			continue
+		}
+		partition, err := strconv.ParseInt(strings.TrimPrefix(appID, prefix), 10, 32)
+		if err != nil {
+			continue
+		}
+		out[int32(partition)] = version
+	}
+	return out
+}
+
+func (s *deltaSnapshot) apply(actions []deltaAction) {
+	for _, action := range actions {
+		switch {
+		case action.Protocol != nil:
+			s.protocol = action.Protocol
+		case action.MetaData != nil:
+			s.metadata = action.MetaData
+		case action.Txn != nil:
+			s.txns[action.Txn.AppID] = action.Txn.Version
+		}
+	}
+}
+
+// loadDeltaSnapshot replays the table log from its last checkpoint. A table
+// without a log has version -1.
+func loadDeltaSnapshot(ctx context.Context, store deltaStore, location string) (*deltaSnapshot, error) {
+	snap := &deltaSnapshot{version: -1, txns: make(map[string]int64)}
+	logDir := location + "/" + deltaLogDir
+
+	startAfter := ""
+	if raw, err := store.Read(ctx, logDir+"/"+deltaLastCheckpoint); err == nil {
+		var last struct {
+			Version int64 `json:"version"`
+			Parts   int   `json:"parts"`
+		}
+		if err := json.Unmarshal(raw, &last); err != nil {
+			return nil, fmt.Errorf("parse %s/%s: %w", logDir, deltaLastCheckpoint, err)
+		}
+		actions, err := readDeltaCheckpoint(ctx, store, logDir, last.Version, last.Parts)
+		if err != nil {
+			return nil, err
+		}
+		snap.apply(actions)
+		snap.version = last.Version
+		startAfter = fmt.Sprintf("%020d.checkpoint", last.Version)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	names, err := store.List(ctx, logDir, startAfter)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		stem, ok := strings.CutSuffix(name, ".json")
+		if !ok || len(stem) != 20 {
+			continue
+		}
+		version, err := strconv.ParseInt(stem, 10, 64)
+		if err != nil || version <= snap.version {
+			continue
+		}
+		if version != snap.version+1 {
+			return nil, fmt.Errorf("delta log %s is missing version %d", logDir, snap.version+1)
+		}
+		raw, err := store.Read(ctx, logDir+"/"+name)
+		if err != nil {
+			return nil, err
+		}
+		actions, err := decodeDeltaActions(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s/%s: %w", logDir, name, err)
+		}
+		snap.apply(actions)
+		snap.version = version
+	}
+	if snap.version >= 0 && (snap.protocol == nil || snap.metadata == nil) {
+		return nil, fmt.Errorf("delta log %s has no protocol or metadata", logDir)
+	}
+	return snap, nil
+}
+
+func decodeDeltaActions(raw []byte) ([]deltaAction, error) {
+	var actions []deltaAction
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	for dec.More() {
+		var action deltaAction
+		if err := dec.Decode(&action); err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// readDeltaCheckpoint reads a classic single or multi-part Parquet
+// checkpoint by converting its rows to the JSON action form.
+func readDeltaCheckpoint(ctx context.Context, store deltaStore, logDir string, version int64, parts int) ([]deltaAction, error) {
+	names := []string{fmt.Sprintf("%020d.checkpoint.parquet", version)}
+	if parts > 1 {
+		names = names[:0]
+		for part := 1; part <= parts; part++ {
+			names = append(names, fmt.Sprintf("%020d.checkpoint.%010d.%010d.parquet", version, part, parts))
+		}
+	}
+	var actions []deltaAction
+	for _, name := range names {
+		raw, err := store.Read(ctx, logDir+"/"+name)
+		if err != nil {
+			return nil, fmt.Errorf("read delta checkpoint %s: %w", name, err)
+		}
+		pf, err := file.NewParquetReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("open delta checkpoint %s: %w", name, err)
+		}
+		fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: 64 << 10}, memory.DefaultAllocator)
+		if err != nil {
+			pf.Close()
+			return nil, err
+		}
+		data, err := fr.ReadTable(ctx)
+		pf.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read delta checkpoint %s: %w", name, err)
+		}
+		var rows bytes.Buffer
+		rdr := array.NewTableReader(data, 0)
+		for rdr.Next() {
+			if err := array.RecordToJSON(rdr.RecordBatch(), &rows); err != nil {
+				rdr.Release()
+				data.Release()
+				return nil, err
+			}
+		}
+		rdr.Release()
+		data.Release()
+		decoded, err := decodeDeltaActions(rows.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("decode delta checkpoint %s: %w", name, err)
+		}
+		actions = append(actions, decoded...)
+	}
+	return actions, nil
+}
+
+// deltaIcebergSchema builds the base fields plus mapped columns, in the
+// order recordsToArrow fills them.
+func deltaIcebergSchema(columns []config.Column) (*iceberg.Schema, error) {
+	fields := baseFields()
+	maxID := len(fields)
+	nextID := func() int {
+		maxID++
+		return maxID
+	}
+	for _, col := range columns {
+		id := nextID()
+		fieldType, err := columnType(col, nil, nextID)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, iceberg.NestedField{
+			ID:       id,
+			Name:     col.Name,
+			Type:     fieldType,
+			Required: col.Required,
+		})
+	}
+	return iceberg.NewSchema(0, fields...), nil
+}
+
+// deltaType encodes an Iceberg type as a Delta schema type: a name for
+// primitives and an object for structs, arrays and maps.
+func deltaType(t iceberg.Type) (json.RawMessage, error) {
+	switch t := t.(type) {
+	case *iceberg.StructType:
+		fields := make([]deltaField, 0, len(t.FieldList))
+		for _, field := range t.FieldList {
+			typ, err := deltaType(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, deltaField{Name: field.Name, Type: typ, Nullable: !field.Required, Metadata: json.RawMessage("{}")})
+		}
+		return json.Marshal(deltaSchema{Type: "struct", Fields: fields})
+	case *iceberg.ListType:
+		element, err := deltaType(t.Element)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]any{"type": "array", "elementType": element, "containsNull": !t.ElementRequired})
+	case *iceberg.MapType:
+		key, err := deltaType(t.KeyType)
+		if err != nil {
+			return nil, err
+		}
+		value, err := deltaType(t.ValueType)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]any{"type": "map", "keyType": key, "valueType": value, "valueContainsNull": !t.ValueRequired})
+	}
+	name, err := deltaPrimitiveType(t)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(name)
+}
+
+func deltaPrimitiveType(t iceberg.Type) (string, error) {
+	switch t {
+	case iceberg.PrimitiveTypes.Bool:
+		return "boolean", nil
+	case iceberg.PrimitiveTypes.Int32:
+		return "integer", nil
+	case iceberg.PrimitiveTypes.Int64:
+		return "long", nil
+	case iceberg.PrimitiveTypes.Float32:
+		return "float", nil
+	case iceberg.PrimitiveTypes.Float64:
+		return "double", nil
+	case iceberg.PrimitiveTypes.String:
+		return "string", nil
+	case iceberg.PrimitiveTypes.Binary:
+		return "binary", nil
+	case iceberg.PrimitiveTypes.TimestampTz:
+		return "timestamp", nil
+	case iceberg.PrimitiveTypes.Timestamp:
+		return "timestamp_ntz", nil
+	case iceberg.PrimitiveTypes.Date:
+		return "date", nil
+	default:
+		return "", fmt.Errorf("unsupported delta type %s", t)
+	}
+}
+
+// sameDeltaType compares two encoded types regardless of key order.
+func sameDeltaType(a, b json.RawMessage) bool {
+	var left, right any
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// evolveDeltaTable returns the metadata and protocol actions needed to write
+// schema to the table, or nil for those that are unchanged. Columns are only
+// ever added; a changed column type is rejected.
+func evolveDeltaTable(snap *deltaSnapshot, schema *iceberg.Schema) (*deltaMetadata, *deltaProtocol, error) {
+	var current deltaSchema
+	if snap.metadata != nil {
+		if err := json.Unmarshal([]byte(snap.metadata.SchemaString), &current); err != nil {
+			return nil, nil, fmt.Errorf("parse schema: %w", err)
+		}
+	}
+	existing := make(map[string]deltaField, len(current.Fields))
+	for _, field := range current.Fields {
+		existing[field.Name] = field
+	}
+
+	merged := deltaSchema{Type: "struct", Fields: append([]deltaField(nil), current.Fields...)}
+	desired := make(map[string]bool, len(schema.Fields()))
+	needsNtz := false
+	for _, field := range schema.Fields() {
+		desired[field.Name] = true
+		encoded, err := deltaType(field.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		needsNtz = needsNtz || bytes.Contains(encoded, []byte(`"timestamp_ntz"`))
+		if have, ok := existing[field.Name]; ok {
+			if !sameDeltaType(have.Type, encoded) {
+				return nil, nil, fmt.Errorf("column %q has type %s, cannot write %s", field.Name, have.Type, encoded)
+			}
+			continue
+		}
+		merged.Fields = append(merged.Fields, deltaField{
+			Name: field.Name,
+			Type: encoded,
+			// Columns added to an existing table must be nullable.
+			Nullable: !field.Required || snap.metadata != nil,
+			Metadata: json.RawMessage("{}"),
+		})
+	}
+	for _, field := range current.Fields {
+		if !desired[field.Name] && !field.Nullable {
+			return nil, nil, fmt.Errorf("required column %q is not in the mapping", field.Name)
+		}
+	}
+
+	protocol, err := deltaProtocolFor(snap.protocol, needsNtz)
+	if err != nil {
+		return nil, nil, err
+	}
+	if snap.metadata != nil && len(merged.Fields) == len(current.Fields) {
+		return nil, protocol, nil
+	}
+	schemaString, err := json.Marshal(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata := &deltaMetadata{
+		ID:               uuid.New().String(),
+		Format:           deltaFormat{Provider: "parquet"},
+		PartitionColumns: []string{},
+		CreatedTime:      time.Now().UnixMilli(),
+	}
+	if snap.metadata != nil {
+		copied := *snap.metadata
+		metadata = &copied
+		if len(metadata.PartitionColumns) > 0 {
+			return nil, nil, errors.New("partitioned delta tables are not supported")
+		}
+	}
+	metadata.SchemaString = string(schemaString)
+	return metadata, protocol, nil
+}
+
+// deltaProtocolFor checks this writer can write a table with the current
+// protocol and returns the protocol to commit when it has to change: for a
+// new table, or to add timestamp_ntz support.
+func deltaProtocolFor(current *deltaProtocol, needsNtz bool) (*deltaProtocol, error) {
+	if current == nil {
+		if needsNtz {
+			return &deltaProtocol{
+				MinReaderVersion: 3,
+				MinWriterVersion: 7,
+				ReaderFeatures:   []string{deltaTimestampNtz},
+				WriterFeatures:   []string{deltaTimestampNtz},
+			}, nil
+		}
+		return &deltaProtocol{MinReaderVersion: 1, MinWriterVersion: deltaSupportedWriter}, nil
+	}
+	if current.MinWriterVersion > deltaSupportedWriter && current.MinWriterVersion < 7 {
+		return nil, fmt.Errorf("delta writer version %d is not supported", current.MinWriterVersion)
+	}
+	hasNtz := false
+	for _, feature := range current.WriterFeatures {
+		if feature == deltaTimestampNtz {
+			hasNtz = true
+			continue
+		}
+		if !deltaLegacyWriterFeatures[feature] {
+			return nil, fmt.Errorf("delta writer feature %q is not supported", feature)
+		}
+	}
+	if !needsNtz || hasNtz {
+		return nil, nil
+	}
+
+	upgraded := &deltaProtocol{MinReaderVersion: 3, MinWriterVersion: 7}
+	if current.MinReaderVersion >= 3 {
+		upgraded.ReaderFeatures = append(upgraded.ReaderFeatures, current.ReaderFeatures...)
+	}
+	upgraded.ReaderFeatures = append(upgraded.ReaderFeatures, deltaTimestampNtz)
+	if current.MinWriterVersion >= 7 {
+		upgraded.WriterFeatures = append(upgraded.WriterFeatures, current.WriterFeatures...)
+	} else {
+		upgraded.WriterFeatures = append(upgraded.WriterFeatures, "appendOnly", "invariants")
+	}
+	upgraded.WriterFeatures = append(upgraded.WriterFeatures, deltaTimestampNtz)
+	return upgraded, nil
+}
+
+// deltaParquet encodes records as one Snappy-compressed Parquet file.
+func deltaParquet(schema *arrow.Schema, columns []config.Column, records []Record, write config.WriteConfig) ([]byte, error) {
+	rdr, err := recordsToArrow(schema, columns, records)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Release()
+
+	opts := []parquet.WriterProperty{parquet.WithCompression(compress.Codecs.Snappy)}
+	if write.RowGroupLimit > 0 {
+		opts = append(opts, parquet.WithMaxRowGroupLength(write.RowGroupLimit))
+	}
+	var buf bytes.Buffer
+	fw, err := pqarrow.NewFileWriter(schema, &buf, parquet.NewWriterProperties(opts...), pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+	for rdr.Next() {
+		if err := fw.WriteBuffered(rdr.RecordBatch()); err != nil {
+			_ = fw.Close()
+			return nil, err
+		}
+	}
+	if err := fw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
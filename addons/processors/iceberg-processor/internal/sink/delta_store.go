@@ -0,0 +1,237 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+var errDeltaCommitConflict = errors.New("delta: log version already exists")
+
+// deltaStore is the object storage a Delta table lives on. Locations are
+// absolute: s3://bucket/key, file:///path or a plain path.
+type deltaStore interface {
+	Read(ctx context.Context, location string) ([]byte, error)
+	Write(ctx context.Context, location string, data []byte) error
+	// PutIfAbsent writes data only if location does not exist yet and
+	// returns errDeltaCommitConflict otherwise.
+	PutIfAbsent(ctx context.Context, location string, data []byte) error
+	// List returns the sorted names of the files directly under dir that
+	// sort after startAfter.
+	List(ctx context.Context, dir string, startAfter string) ([]string, error)
+	Delete(ctx context.Context, location string) error
+}
+
+func newDeltaStore(cfg config.Config) (deltaStore, error) {
+	if strings.HasPrefix(cfg.Sink.Delta.Warehouse, "s3://") {
+		return newS3DeltaStore(cfg)
+	}
+	return localDeltaStore{}, nil
+}
+
+// localDeltaStore keeps tables on a local or mounted filesystem. Commits
+// hard-link the log file into place, which fails if it already exists.
+type localDeltaStore struct{}
+
+func (localDeltaStore) Read(ctx context.Context, location string) ([]byte, error) {
+	return os.ReadFile(localPath(location))
+}
+
+func (localDeltaStore) Write(ctx context.Context, location string, data []byte) error {
+	path := localPath(location)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := writeTemp(filepath.Dir(path), data)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (localDeltaStore) PutIfAbsent(ctx context.Context, location string, data []byte) error {
+	path := localPath(location)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := writeTemp(filepath.Dir(path), data)
+	if err != nil {
+		return err
+	}
+	err = os.Link(tmp, path)
+	_ = os.Remove(tmp)
+	if errors.Is(err, fs.ErrExist) {
+		return errDeltaCommitConflict
+	}
+	return err
+}
+
+func (localDeltaStore) List(ctx context.Context, dir string, startAfter string) ([]string, error) {
+	entries, err := os.ReadDir(localPath(dir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && entry.Name() > startAfter && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (localDeltaStore) Delete(ctx context.Context, location string) error {
+	return os.Remove(localPath(location))
+}
+
+type deltaObjectAPI interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+}
+
+// s3DeltaStore keeps tables on S3. Commits use conditional writes
+// (If-None-Match), so the endpoint must support them.
+type s3DeltaStore struct {
+	client deltaObjectAPI
+}
+
+func newS3DeltaStore(cfg config.Config) (*s3DeltaStore, error) {
+	loadOptions := []func(*awsconfig.LoadOptions) error{}
+	if cfg.S3.Region != "" {
+		loadOptions = append(loadOptions, awsconfig.WithRegion(cfg.S3.Region))
+	}
+	if cfg.S3.Endpoint != "" {
+		resolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, _ ...interface{}) (aws.Endpoint, error) {
+			if service == s3.ServiceID {
+				return aws.Endpoint{URL: cfg.S3.Endpoint, SigningRegion: region}, nil
+			}
+			return aws.Endpoint{}, &aws.EndpointNotFoundError{}
+		})
+		loadOptions = append(loadOptions, awsconfig.WithEndpointResolverWithOptions(resolver))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	client := s3.NewFromConfig(awsCfg, func(opts *s3.Options) {
+		if cfg.S3.PathStyle {
+			opts.UsePathStyle = true
+		}
+	})
+	return &s3DeltaStore{client: client}, nil
+}
+
+func splitS3Location(location string) (string, string) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(location, "s3://"), "/")
+	return bucket, key
+}
+
+func (s *s3DeltaStore) Read(ctx context.Context, location string) ([]byte, error) {
+	bucket, key := splitS3Location(location)
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		var noKey *types.NoSuchKey
+		if errors.As(err, &noKey) {
+			return nil, fmt.Errorf("%s: %w", location, fs.ErrNotExist)
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (s *s3DeltaStore) Write(ctx context.Context, location string, data []byte) error {
+	bucket, key := splitS3Location(location)
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s *s3DeltaStore) PutIfAbsent(ctx context.Context, location string, data []byte) error {
+	bucket, key := splitS3Location(location)
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		IfNoneMatch: aws.String("*"),
+	})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "PreconditionFailed" || apiErr.ErrorCode() == "ConditionalRequestConflict") {
+		return errDeltaCommitConflict
+	}
+	return err
+}
+
+func (s *s3DeltaStore) List(ctx context.Context, dir string, startAfter string) ([]string, error) {
+	bucket, prefix := splitS3Location(strings.TrimRight(dir, "/") + "/")
+	input := &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}
+	if startAfter != "" {
+		input.StartAfter = aws.String(prefix + startAfter)
+	}
+	var names []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			names = append(names, strings.TrimPrefix(aws.ToString(obj.Key), prefix))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *s3DeltaStore) Delete(ctx context.Context, location string) error {
+	bucket, key := splitS3Location(location)
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	return err
+}
@@ -0,0 +1,307 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+func deltaTestConfig(warehouse string) config.Config {
+	return config.Config{
+		Sink: config.SinkConfig{Type: "delta", Delta: config.DeltaConfig{Warehouse: warehouse}},
+		Mappings: []config.Mapping{{
+			Topic:               "orders",
+			Table:               "prod.orders",
+			Mode:                "append",
+			CreateTableIfAbsent: true,
+			Schema: config.MappingSchemaConfig{Source: "mapping", Columns: []config.Column{
+				{Name: "order_id", Type: "long"},
+				{Name: "placed_at", Type: "timestamp"},
+			}},
+		}},
+	}
+}
+
+func deltaRecords(partition int32, offsets ...int64) []Record {
+	records := make([]Record, 0, len(offsets))
+	for _, offset := range offsets {
+		records = append(records, Record{
+			Topic:     "orders",
+			Partition: partition,
+			Offset:    offset,
+			Value:     []byte(fmt.Sprintf(`{"order_id":%d}`, offset)),
+		})
+	}
+	return records
+}
+
+func readDeltaLog(t *testing.T, dir string, version int64) []deltaAction {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join(dir, deltaLogDir, fmt.Sprintf("%020d.json", version)))
+	if err != nil {
+		t.Fatalf("read log version %d: %v", version, err)
+	}
+	actions, err := decodeDeltaActions(raw)
+	if err != nil {
+		t.Fatalf("decode log version %d: %v", version, err)
+	}
+	return actions
+}
+
+func countParquetRows(t *testing.T, dir string) int64 {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "*.parquet"))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	var rows int64
+	for _, match := range matches {
+		rdr, err := file.OpenParquetFile(match, false)
+		if err != nil {
+			t.Fatalf("open %s: %v", match, err)
+		}
+		rows += rdr.NumRows()
+		rdr.Close()
+	}
+	return rows
+}
+
+func TestDeltaWriterCommitsDataWithTxnOffsets(t *testing.T) {
+	ctx := context.Background()
+	warehouse := t.TempDir()
+	writer, err := New(deltaTestConfig(warehouse))
+	if err != nil {
+		t.Fatalf("New writer: %v", err)
+	}
+	if err := writer.Write(ctx, deltaRecords(0, 0, 1, 2)); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+	// Offsets 1 and 2 are already in the table and must not be written again.
+	if err := writer.Write(ctx, deltaRecords(0, 1, 2, 3, 4)); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+
+	tableDir := filepath.Join(warehouse, "prod", "orders")
+	first := readDeltaLog(t, tableDir, 0)
+	var protocol *deltaProtocol
+	var metadata *deltaMetadata
+	var txn *deltaTxn
+	for _, action := range first {
+		switch {
+		case action.Protocol != nil:
+			protocol = action.Protocol
+		case action.MetaData != nil:
+			metadata = action.MetaData
+		case action.Txn != nil:
+			txn = action.Txn
+		}
+	}
+	if protocol == nil || protocol.MinWriterVersion != 7 || len(protocol.WriterFeatures) != 1 || protocol.WriterFeatures[0] != deltaTimestampNtz {
+		t.Fatalf("expected a timestampNtz protocol, got %+v", protocol)
+	}
+	if metadata == nil || !strings.Contains(metadata.SchemaString, `"name":"placed_at","type":"timestamp_ntz"`) {
+		t.Fatalf("unexpected metadata %+v", metadata)
+	}
+	if txn == nil || txn.AppID != "kafscale.orders.0" || txn.Version != 2 {
+		t.Fatalf("unexpected txn %+v", txn)
+	}
+	for _, action := range readDeltaLog(t, tableDir, 1) {
+		if action.Protocol != nil || action.MetaData != nil {
+			t.Fatalf("expected the second commit to keep the table metadata, got %+v", action)
+		}
+		if action.Add != nil && action.Add.Stats != `{"numRecords":2}` {
+			t.Fatalf("unexpected add stats %q", action.Add.Stats)
+		}
+	}
+	if rows := countParquetRows(t, tableDir); rows != 5 {
+		t.Fatalf("expected 5 rows across data files, got %d", rows)
+	}
+
+	reopened, err := New(deltaTestConfig(warehouse))
+	if err != nil {
+		t.Fatalf("New writer: %v", err)
+	}
+	offset, ok, err := reopened.(OffsetTracker).CommittedOffset(ctx, "orders", 0)
+	if err != nil || !ok || offset != 4 {
+		t.Fatalf("expected committed offset 4, got %d ok=%v err=%v", offset, ok, err)
+	}
+	if _, ok, _ := reopened.(OffsetTracker).CommittedOffset(ctx, "orders", 1); ok {
+		t.Fatalf("expected no offset for an unwritten partition")
+	}
+}
+
+func TestDeltaWriterRetriesOnConflict(t *testing.T) {
+	ctx := context.Background()
+	warehouse := t.TempDir()
+	a, err := New(deltaTestConfig(warehouse))
+	if err != nil {
+		t.Fatalf("New writer: %v", err)
+	}
+	b, err := New(deltaTestConfig(warehouse))
+	if err != nil {
+		t.Fatalf("New writer: %v", err)
+	}
+	if err := b.Write(ctx, deltaRecords(1, 0)); err != nil {
+		t.Fatalf("b write: %v", err)
+	}
+	if err := a.Write(ctx, deltaRecords(0, 0, 1)); err != nil {
+		t.Fatalf("a write: %v", err)
+	}
+	// b still caches version 0, so its next commit races a's version 1.
+	if err := b.Write(ctx, deltaRecords(1, 1)); err != nil {
+		t.Fatalf("b second write: %v", err)
+	}
+
+	tableDir := filepath.Join(warehouse, "prod", "orders")
+	readDeltaLog(t, tableDir, 2)
+	snap, err := loadDeltaSnapshot(ctx, localDeltaStore{}, tableDir)
+	if err != nil {
+		t.Fatalf("load snapshot: %v", err)
+	}
+	if snap.version != 2 || snap.txns["kafscale.orders.0"] != 1 || snap.txns["kafscale.orders.1"] != 1 {
+		t.Fatalf("unexpected snapshot version=%d txns=%v", snap.version, snap.txns)
+	}
+	if rows := countParquetRows(t, tableDir); rows != 4 {
+		t.Fatalf("expected the conflicting data file to be removed, got %d rows", rows)
+	}
+}
+
+func TestLoadDeltaSnapshotFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	tableDir := t.TempDir()
+	logDir := filepath.Join(tableDir, deltaLogDir)
+	if err := os.MkdirAll(logDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	stringMapType := arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "txn", Type: arrow.StructOf(
+			arrow.Field{Name: "appId", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "version", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		), Nullable: true},
+		{Name: "metaData", Type: arrow.StructOf(
+			arrow.Field{Name: "id", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "schemaString", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "partitionColumns", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+			arrow.Field{Name: "configuration", Type: stringMapType, Nullable: true},
+			arrow.Field{Name: "format", Type: arrow.StructOf(
+				arrow.Field{Name: "provider", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "options", Type: stringMapType, Nullable: true},
+			), Nullable: true},
+		), Nullable: true},
+		{Name: "protocol", Type: arrow.StructOf(
+			arrow.Field{Name: "minReaderVersion", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+			arrow.Field{Name: "minWriterVersion", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		), Nullable: true},
+	}, nil)
+	rows := `[
+		{"protocol": {"minReaderVersion": 1, "minWriterVersion": 2}},
+		{"metaData": {"id": "t1", "schemaString": "{\"type\":\"struct\",\"fields\":[]}", "partitionColumns": [],
+			"configuration": [{"key": "delta.appendOnly", "value": "true"}], "format": {"provider": "parquet", "options": []}}},
+		{"txn": {"appId": "kafscale.orders.0", "version": 41}}
+	]`
+	rec, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, strings.NewReader(rows))
+	if err != nil {
+		t.Fatalf("checkpoint rows: %v", err)
+	}
+	defer rec.Release()
+	var checkpoint bytes.Buffer
+	tbl := array.NewTableFromRecords(schema, []arrow.RecordBatch{rec})
+	defer tbl.Release()
+	if err := pqarrow.WriteTable(tbl, &checkpoint, 1024, nil, pqarrow.DefaultWriterProps()); err != nil {
+		t.Fatalf("write checkpoint: %v", err)
+	}
+
+	files := map[string][]byte{
+		"00000000000000000003.json":               []byte("not json"),
+		"00000000000000000010.checkpoint.parquet": checkpoint.Bytes(),
+		"00000000000000000011.json":               []byte(`{"txn":{"appId":"kafscale.orders.0","version":45}}` + "\n"),
+		deltaLastCheckpoint:                       []byte(`{"version":10,"size":3}`),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(logDir, name), data, 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	snap, err := loadDeltaSnapshot(ctx, localDeltaStore{}, tableDir)
+	if err != nil {
+		t.Fatalf("load snapshot: %v", err)
+	}
+	if snap.version != 11 || snap.txns["kafscale.orders.0"] != 45 {
+		t.Fatalf("unexpected snapshot version=%d txns=%v", snap.version, snap.txns)
+	}
+	if snap.metadata == nil || snap.metadata.Configuration["delta.appendOnly"] != "true" {
+		t.Fatalf("expected checkpoint metadata configuration, got %+v", snap.metadata)
+	}
+	if snap.protocol == nil || snap.protocol.MinWriterVersion != 2 {
+		t.Fatalf("expected checkpoint protocol, got %+v", snap.protocol)
+	}
+}
+
+func TestEvolveDeltaTable(t *testing.T) {
+	longSchema, err := deltaIcebergSchema([]config.Column{{Name: "order_id", Type: "long"}})
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	metadata, protocol, err := evolveDeltaTable(&deltaSnapshot{version: -1}, longSchema)
+	if err != nil {
+		t.Fatalf("evolve new table: %v", err)
+	}
+	if protocol == nil || protocol.MinWriterVersion != 2 || metadata == nil {
+		t.Fatalf("expected protocol and metadata for a new table, got %+v %+v", protocol, metadata)
+	}
+	snap := &deltaSnapshot{version: 0, protocol: protocol, metadata: metadata}
+
+	if m, p, err := evolveDeltaTable(snap, longSchema); err != nil || m != nil || p != nil {
+		t.Fatalf("expected no changes for the same schema, got %+v %+v %v", m, p, err)
+	}
+
+	wider, _ := deltaIcebergSchema([]config.Column{{Name: "order_id", Type: "long"}, {Name: "note", Type: "string", Required: true}})
+	m, _, err := evolveDeltaTable(snap, wider)
+	if err != nil || m == nil || m.ID != metadata.ID {
+		t.Fatalf("expected evolved metadata with the same table id, got %+v %v", m, err)
+	}
+	var evolved deltaSchema
+	if err := json.Unmarshal([]byte(m.SchemaString), &evolved); err != nil {
+		t.Fatalf("parse schema: %v", err)
+	}
+	last := evolved.Fields[len(evolved.Fields)-1]
+	if last.Name != "note" || !last.Nullable {
+		t.Fatalf("expected note to be added as nullable, got %+v", last)
+	}
+
+	changed, _ := deltaIcebergSchema([]config.Column{{Name: "order_id", Type: "string"}})
+	if _, _, err := evolveDeltaTable(snap, changed); err == nil {
+		t.Fatalf("expected a type change to be rejected")
+	}
+}
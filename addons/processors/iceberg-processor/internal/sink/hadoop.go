@@ -0,0 +1,382 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	iceberg "github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+)
+
+const (
+	hadoopCatalogType catalog.Type = "hadoop"
+	versionHintFile                = "version-hint.text"
+)
+
+var (
+	errHadoopCommitConflict = errors.New("hadoop catalog: table metadata was committed concurrently")
+	hadoopMetadataFile      = regexp.MustCompile(`^v(\d+)\.metadata\.json$`)
+)
+
+// hadoopCatalog is a filesystem catalog using the Hadoop table layout:
+// <warehouse>/<namespace>/<table>/metadata/v<N>.metadata.json, with the
+// current version in metadata/version-hint.text. A commit hard-links its
+// metadata file into place, which fails if another writer got there first,
+// so the warehouse must be a local or mounted filesystem.
+type hadoopCatalog struct {
+	warehouse string
+	props     iceberg.Properties
+}
+
+var _ catalog.Catalog = (*hadoopCatalog)(nil)
+
+func newHadoopCatalog(warehouse string, props iceberg.Properties) (*hadoopCatalog, error) {
+	warehouse = strings.TrimRight(warehouse, "/")
+	if err := os.MkdirAll(localPath(warehouse), 0o755); err != nil {
+		return nil, fmt.Errorf("create hadoop warehouse: %w", err)
+	}
+	return &hadoopCatalog{warehouse: warehouse, props: props}, nil
+}
+
+func localPath(location string) string {
+	return strings.TrimPrefix(location, "file://")
+}
+
+func (c *hadoopCatalog) location(ident table.Identifier) string {
+	if len(ident) == 0 {
+		return c.warehouse
+	}
+	return c.warehouse + "/" + strings.Join(ident, "/")
+}
+
+func metadataFileLocation(tableLocation string, version int) string {
+	return fmt.Sprintf("%s/metadata/v%d.metadata.json", tableLocation, version)
+}
+
+// currentVersion returns the latest committed metadata version of the table
+// at location. The version hint is written after the metadata file, so it may
+// lag behind; newer files are picked up by probing forward.
+func (c *hadoopCatalog) currentVersion(location string) (int, error) {
+	metadataDir := localPath(location + "/metadata")
+	version := 0
+	if hint, err := os.ReadFile(filepath.Join(metadataDir, versionHintFile)); err == nil {
+		version, _ = strconv.Atoi(strings.TrimSpace(string(hint)))
+	}
+	if version == 0 {
+		entries, err := os.ReadDir(metadataDir)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return 0, err
+		}
+		for _, entry := range entries {
+			if m := hadoopMetadataFile.FindStringSubmatch(entry.Name()); m != nil {
+				if v, _ := strconv.Atoi(m[1]); v > version {
+					version = v
+				}
+			}
+		}
+	}
+	if version == 0 {
+		return 0, catalog.ErrNoSuchTable
+	}
+	for {
+		if _, err := os.Stat(localPath(metadataFileLocation(location, version+1))); err != nil {
+			break
+		}
+		version++
+	}
+	return version, nil
+}
+
+func (c *hadoopCatalog) isTable(location string) bool {
+	_, err := c.currentVersion(location)
+	return err == nil
+}
+
+func (c *hadoopCatalog) loadTable(ident table.Identifier) (*table.Table, int, error) {
+	location := c.location(ident)
+	version, err := c.currentVersion(location)
+	if err != nil {
+		return nil, 0, err
+	}
+	metadataLoc := metadataFileLocation(location, version)
+	data, err := os.ReadFile(localPath(metadataLoc))
+	if err != nil {
+		return nil, 0, err
+	}
+	meta, err := table.ParseMetadataBytes(data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse %s: %w", metadataLoc, err)
+	}
+	return table.New(ident, meta, metadataLoc, iceio.LoadFSFunc(c.props, metadataLoc), c), version, nil
+}
+
+// writeVersion stores meta as the given version of the table at location.
+func (c *hadoopCatalog) writeVersion(location string, version int, meta table.Metadata) (string, error) {
+	metadataLoc := metadataFileLocation(location, version)
+	metadataDir := localPath(location + "/metadata")
+	if err := os.MkdirAll(metadataDir, 0o755); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+	tmp, err := writeTemp(metadataDir, data)
+	if err != nil {
+		return "", err
+	}
+	err = os.Link(tmp, localPath(metadataLoc))
+	_ = os.Remove(tmp)
+	if errors.Is(err, fs.ErrExist) {
+		return "", errHadoopCommitConflict
+	}
+	if err != nil {
+		return "", err
+	}
+
+	hint, err := writeTemp(metadataDir, []byte(strconv.Itoa(version)))
+	if err == nil {
+		err = os.Rename(hint, filepath.Join(metadataDir, versionHintFile))
+	}
+	if err != nil {
+		// The commit is durable; readers probe past a stale hint.
+		_ = os.Remove(hint)
+	}
+	return metadataLoc, nil
+}
+
+func writeTemp(dir string, data []byte) (string, error) {
+	f, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+func (c *hadoopCatalog) CatalogType() catalog.Type {
+	return hadoopCatalogType
+}
+
+func (c *hadoopCatalog) CreateTable(ctx context.Context, ident table.Identifier, schema *iceberg.Schema, opts ...catalog.CreateTableOpt) (*table.Table, error) {
+	cfg := catalog.NewCreateTableCfg()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if len(ident) < 2 {
+		return nil, fmt.Errorf("hadoop catalog: table %v needs a namespace", ident)
+	}
+	exists, err := c.CheckNamespaceExists(ctx, catalog.NamespaceFromIdent(ident))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", catalog.ErrNoSuchNamespace, strings.Join(catalog.NamespaceFromIdent(ident), "."))
+	}
+	location := c.location(ident)
+	if cfg.Location != "" && strings.TrimRight(cfg.Location, "/") != location {
+		return nil, fmt.Errorf("hadoop catalog: table %v must be located at %s", ident, location)
+	}
+	if c.isTable(location) {
+		return nil, fmt.Errorf("%w: %s", catalog.ErrTableAlreadyExists, strings.Join(ident, "."))
+	}
+
+	meta, err := table.NewMetadata(schema, cfg.PartitionSpec, cfg.SortOrder, location, cfg.Properties)
+	if err != nil {
+		return nil, err
+	}
+	metadataLoc, err := c.writeVersion(location, 1, meta)
+	if errors.Is(err, errHadoopCommitConflict) {
+		return nil, fmt.Errorf("%w: %s", catalog.ErrTableAlreadyExists, strings.Join(ident, "."))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return table.New(ident, meta, metadataLoc, iceio.LoadFSFunc(c.props, metadataLoc), c), nil
+}
+
+func (c *hadoopCatalog) CommitTable(ctx context.Context, ident table.Identifier, requirements []table.Requirement, updates []table.Update) (table.Metadata, string, error) {
+	current, version, err := c.loadTable(ident)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, req := range requirements {
+		if err := req.Validate(current.Metadata()); err != nil {
+			return nil, "", err
+		}
+	}
+	updated, err := table.UpdateTableMetadata(current.Metadata(), updates, current.MetadataLocation())
+	if err != nil {
+		return nil, "", err
+	}
+	metadataLoc, err := c.writeVersion(c.location(ident), version+1, updated)
+	if err != nil {
+		return nil, "", err
+	}
+	return updated, metadataLoc, nil
+}
+
+func (c *hadoopCatalog) ListTables(ctx context.Context, namespace table.Identifier) iter.Seq2[table.Identifier, error] {
+	return func(yield func(table.Identifier, error) bool) {
+		children, err := c.children(namespace)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, child := range children {
+			if !c.isTable(c.location(child)) {
+				continue
+			}
+			if !yield(child, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (c *hadoopCatalog) LoadTable(ctx context.Context, ident table.Identifier) (*table.Table, error) {
+	tbl, _, err := c.loadTable(ident)
+	return tbl, err
+}
+
+func (c *hadoopCatalog) DropTable(ctx context.Context, ident table.Identifier) error {
+	location := c.location(ident)
+	if !c.isTable(location) {
+		return fmt.Errorf("%w: %s", catalog.ErrNoSuchTable, strings.Join(ident, "."))
+	}
+	return os.RemoveAll(localPath(location))
+}
+
+func (c *hadoopCatalog) RenameTable(ctx context.Context, from, to table.Identifier) (*table.Table, error) {
+	return nil, errors.New("hadoop catalog does not support renaming tables")
+}
+
+func (c *hadoopCatalog) CheckTableExists(ctx context.Context, ident table.Identifier) (bool, error) {
+	return c.isTable(c.location(ident)), nil
+}
+
+// children lists the directories directly under namespace.
+func (c *hadoopCatalog) children(namespace table.Identifier) ([]table.Identifier, error) {
+	entries, err := os.ReadDir(localPath(c.location(namespace)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", catalog.ErrNoSuchNamespace, strings.Join(namespace, "."))
+	}
+	if err != nil {
+		return nil, err
+	}
+	children := make([]table.Identifier, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		children = append(children, append(slices.Clone(namespace), entry.Name()))
+	}
+	return children, nil
+}
+
+func (c *hadoopCatalog) ListNamespaces(ctx context.Context, parent table.Identifier) ([]table.Identifier, error) {
+	children, err := c.children(parent)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := children[:0]
+	for _, child := range children {
+		if !c.isTable(c.location(child)) {
+			namespaces = append(namespaces, child)
+		}
+	}
+	return namespaces, nil
+}
+
+func (c *hadoopCatalog) CreateNamespace(ctx context.Context, namespace table.Identifier, props iceberg.Properties) error {
+	if len(props) > 0 {
+		return errors.New("hadoop catalog does not support namespace properties")
+	}
+	exists, err := c.CheckNamespaceExists(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", catalog.ErrNamespaceAlreadyExists, strings.Join(namespace, "."))
+	}
+	return os.MkdirAll(localPath(c.location(namespace)), 0o755)
+}
+
+func (c *hadoopCatalog) DropNamespace(ctx context.Context, namespace table.Identifier) error {
+	exists, err := c.CheckNamespaceExists(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", catalog.ErrNoSuchNamespace, strings.Join(namespace, "."))
+	}
+	children, err := c.children(namespace)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		return fmt.Errorf("%w: %s", catalog.ErrNamespaceNotEmpty, strings.Join(namespace, "."))
+	}
+	return os.Remove(localPath(c.location(namespace)))
+}
+
+func (c *hadoopCatalog) CheckNamespaceExists(ctx context.Context, namespace table.Identifier) (bool, error) {
+	location := c.location(namespace)
+	info, err := os.Stat(localPath(location))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return info.IsDir() && !c.isTable(location), nil
+}
+
+func (c *hadoopCatalog) LoadNamespaceProperties(ctx context.Context, namespace table.Identifier) (iceberg.Properties, error) {
+	exists, err := c.CheckNamespaceExists(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", catalog.ErrNoSuchNamespace, strings.Join(namespace, "."))
+	}
+	return iceberg.Properties{"location": c.location(namespace)}, nil
+}
+
+func (c *hadoopCatalog) UpdateNamespaceProperties(ctx context.Context, namespace table.Identifier, removals []string, updates iceberg.Properties) (catalog.PropertiesUpdateSummary, error) {
+	return catalog.PropertiesUpdateSummary{}, errors.New("hadoop catalog does not support namespace properties")
+}
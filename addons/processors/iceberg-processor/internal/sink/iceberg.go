@@ -36,17 +36,25 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	iceberg "github.com/apache/iceberg-go"
 	"github.com/apache/iceberg-go/catalog"
+	gluecatalog "github.com/apache/iceberg-go/catalog/glue"
 	restcatalog "github.com/apache/iceberg-go/catalog/rest"
+	sqlcatalog "github.com/apache/iceberg-go/catalog/sql"
 	iceio "github.com/apache/iceberg-go/io"
 	"github.com/apache/iceberg-go/table"
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
 	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/decoder"
+	_ "github.com/uptrace/bun/driver/pgdriver"
+	"github.com/uptrace/bun/driver/sqliteshim"
 )
 
 const defaultTableSchemaID = 1
 
-// New returns an Iceberg writer wired to the config mappings.
+// New returns the Iceberg or Delta Lake writer selected by sink.type, wired
+// to the config mappings.
 func New(cfg config.Config) (Writer, error) {
+	if cfg.Sink.Type == "delta" {
+		return newDeltaWriter(cfg)
+	}
 	props := iceberg.Properties{
 		"type": cfg.Iceberg.Catalog.Type,
 		"uri":  cfg.Iceberg.Catalog.URI,
@@ -76,9 +84,27 @@ func New(cfg config.Config) (Writer, error) {
 		props[iceio.S3ForceVirtualAddressing] = "true"
 	}
 
+	switch cfg.Iceberg.Catalog.Type {
+	case "sql":
+		props[sqlcatalog.DialectKey] = cfg.Iceberg.Catalog.SQL.Dialect
+		props[sqlcatalog.DriverKey] = sqliteshim.ShimName
+		if cfg.Iceberg.Catalog.SQL.Dialect == string(sqlcatalog.Postgres) {
+			props[sqlcatalog.DriverKey] = "pg"
+		}
+	case "glue":
+		if cfg.Iceberg.Catalog.Glue.Region != "" {
+			props[gluecatalog.Region] = cfg.Iceberg.Catalog.Glue.Region
+		}
+		if cfg.Iceberg.Catalog.Glue.CatalogID != "" {
+			props[gluecatalog.CatalogIdKey] = cfg.Iceberg.Catalog.Glue.CatalogID
+		}
+	}
+
 	var cat catalog.Catalog
 	var err error
-	if cfg.Iceberg.Catalog.Type == "rest" && os.Getenv("ICEBERG_PROCESSOR_REST_DEBUG") != "" {
+	if cfg.Iceberg.Catalog.Type == "hadoop" {
+		cat, err = newHadoopCatalog(cfg.Iceberg.Warehouse, props)
+	} else if cfg.Iceberg.Catalog.Type == "rest" && os.Getenv("ICEBERG_PROCESSOR_REST_DEBUG") != "" {
 		transport := &loggingTransport{base: http.DefaultTransport}
 		opts := []restcatalog.Option{
 			restcatalog.WithWarehouseLocation(cfg.Iceberg.Warehouse),
@@ -102,7 +128,12 @@ func New(cfg config.Config) (Writer, error) {
 			identifier: catalog.ToIdentifier(mapping.Table),
 			autoCreate: mapping.CreateTableIfAbsent,
 			mode:       mapping.Mode,
+			keyColumns: mapping.KeyColumns,
+			envelope:   mapping.Envelope,
 			schema:     mapping.Schema,
+			partition:  mapping.Partition,
+			sortOrder:  mapping.SortOrder,
+			write:      mapping.Write,
 		}
 	}
 
@@ -110,9 +141,11 @@ func New(cfg config.Config) (Writer, error) {
 		catalog:   cat,
 		warehouse: strings.TrimRight(cfg.Iceberg.Warehouse, "/"),
 		mappings:  mappings,
-		registry:  cfg.Schema,
+		registry:  newSchemaRegistry(cfg.Schema, cfg.Mappings),
 		schemas:   make(map[string]*topicSchema),
 		tables:    make(map[string]*table.Table),
+
+		maintenance: cfg.Maintenance,
 	}, nil
 }
 
@@ -120,16 +153,23 @@ type tableMapping struct {
 	identifier table.Identifier
 	autoCreate bool
 	mode       string
+	keyColumns []string
+	envelope   string
 	schema     config.MappingSchemaConfig
+	partition  []config.PartitionField
+	sortOrder  []config.SortField
+	write      config.WriteConfig
 }
 
 type icebergWriter struct {
 	catalog   catalog.Catalog
 	warehouse string
 	mappings  map[string]tableMapping
-	registry  config.SchemaConfig
+	registry  *schemaRegistry
 	schemas   map[string]*topicSchema
 
+	maintenance config.MaintenanceConfig
+
 	mu        sync.Mutex
 	tables    map[string]*table.Table
 	initLocks map[string]*sync.Mutex
@@ -145,6 +185,12 @@ func (w *icebergWriter) Write(ctx context.Context, records []Record) error {
 	if len(records) == 0 {
 		return nil
 	}
+	// Decoding first lets a record with a new schema version refresh the
+	// registry columns before the schema check in loadTable.
+	records, err := w.registry.decodeRecords(ctx, records)
+	if err != nil {
+		return err
+	}
 
 	buckets := groupByTopic(records)
 	for topic, topicRecords := range buckets {
@@ -155,25 +201,33 @@ func (w *icebergWriter) Write(ctx context.Context, records []Record) error {
 
 		var lastErr error
 		for attempt := 0; attempt < 3; attempt++ {
-			recordReader, err := recordsToArrow(schema.arrow, schema.columns, topicRecords)
-			if err != nil {
-				return err
+			// Offsets travel in the snapshot summary of the same commit as the
+			// data, so anything the table already has is skipped, including
+			// an earlier attempt whose commit succeeded but was reported lost.
+			props := offsetProperties(tbl)
+			pending := filterCommitted(topicRecords, parseOffsets(props, topic))
+			if len(pending) == 0 {
+				lastErr = nil
+				break
+			}
+			for partition, offset := range lastOffsets(pending) {
+				props[offsetProperty(topic, partition)] = strconv.FormatInt(offset, 10)
 			}
-			updated, err := tbl.Append(ctx, recordReader, iceberg.Properties{
-				"kafscale.commit.attempt": fmt.Sprintf("%d", attempt+1),
-			})
-			recordReader.Release()
+			props["kafscale.commit.attempt"] = fmt.Sprintf("%d", attempt+1)
+
+			mapping := w.mappings[topic]
+			updated, err := w.commit(ctx, tbl, schema, mapping, pending, props)
 			if err == nil {
 				tbl = updated
-				if hasSnapshotDataFiles(tbl) == false {
+				if mapping.mode != modeUpsert && !hasSnapshotDataFiles(tbl) {
 					return fmt.Errorf("iceberg commit produced no data files for topic %q", topic)
 				}
 				if reloaded, loadErr := loadTableWithRetry(ctx, w.catalog, w.mappings[topic].identifier, 3, 150*time.Millisecond); loadErr == nil {
 					tbl = reloaded
-					w.mu.Lock()
-					w.tables[topic] = tbl
-					w.mu.Unlock()
 				}
+				w.mu.Lock()
+				w.tables[topic] = tbl
+				w.mu.Unlock()
 				lastErr = nil
 				break
 			}
@@ -199,6 +253,48 @@ func (w *icebergWriter) Write(ctx context.Context, records []Record) error {
 	return nil
 }
 
+// commit writes one batch of a topic as a single snapshot. Append mode hands
+// the rows to the table; upsert mode writes the snapshot itself.
+func (w *icebergWriter) commit(ctx context.Context, tbl *table.Table, schema *topicSchema, mapping tableMapping, records []Record, props iceberg.Properties) (*table.Table, error) {
+	if mapping.mode == modeUpsert {
+		batch, err := collapseUpserts(records, mapping)
+		if err != nil {
+			return nil, err
+		}
+		return w.commitUpsert(ctx, tbl, schema, mapping, batch, props)
+	}
+	recordReader, err := recordsToArrow(schema.arrow, schema.columns, sortRecords(records, mapping.sortOrder))
+	if err != nil {
+		return nil, err
+	}
+	defer recordReader.Release()
+	return tbl.Append(ctx, recordReader, props)
+}
+
+// CommittedOffset reads the last offset committed for a partition from the
+// table's snapshot history, bypassing the cached table.
+func (w *icebergWriter) CommittedOffset(ctx context.Context, topic string, partition int32) (int64, bool, error) {
+	mapping, ok := w.mappings[topic]
+	if !ok {
+		return 0, false, fmt.Errorf("no table mapping for topic %q", topic)
+	}
+	tbl, err := loadTableWithRetry(ctx, w.catalog, mapping.identifier, 3, 150*time.Millisecond)
+	if err != nil {
+		if errors.Is(err, catalog.ErrNoSuchTable) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	w.mu.Lock()
+	if w.tables[topic] != nil {
+		w.tables[topic] = tbl
+	}
+	w.mu.Unlock()
+
+	offset, ok := parseOffsets(offsetProperties(tbl), topic)[partition]
+	return offset, ok, nil
+}
+
 func (w *icebergWriter) Close(ctx context.Context) error {
 	return nil
 }
@@ -208,7 +304,7 @@ func (w *icebergWriter) loadTable(ctx context.Context, topic string) (*table.Tab
 	if !ok {
 		return nil, nil, fmt.Errorf("no table mapping for topic %q", topic)
 	}
-	if mapping.mode != "append" {
+	if mapping.mode != modeAppend && mapping.mode != modeUpsert {
 		return nil, nil, fmt.Errorf("unsupported mapping mode %q for topic %q", mapping.mode, topic)
 	}
 
@@ -289,7 +385,8 @@ func (w *icebergWriter) topicLock(topic string) *sync.Mutex {
 	return lock
 }
 
-func (w *icebergWriter) createTable(ctx context.Context, ident table.Identifier, schemaCfg config.MappingSchemaConfig, topic string) (*table.Table, error) {
+func (w *icebergWriter) createTable(ctx context.Context, mapping tableMapping, topic string) (*table.Table, error) {
+	ident := mapping.identifier
 	if len(ident) > 1 {
 		namespace := ident[:len(ident)-1]
 		if _, isRest := w.catalog.(*restcatalog.Catalog); isRest {
@@ -316,6 +413,9 @@ func (w *icebergWriter) createTable(ctx context.Context, ident table.Identifier,
 	props := iceberg.Properties{
 		"write.format.default": "parquet",
 	}
+	for k, v := range writeProperties(mapping.write) {
+		props[k] = v
+	}
 	if location != "" {
 		props[table.WriteDataPathKey] = location + "/data"
 		props[table.WriteMetadataPathKey] = location + "/metadata"
@@ -327,10 +427,24 @@ func (w *icebergWriter) createTable(ctx context.Context, ident table.Identifier,
 		opts = append(opts, catalog.WithLocation(location))
 	}
 
-	desired, _, err := w.buildDesiredSchema(ctx, schemaCfg, topic, nil)
+	desired, _, err := w.buildDesiredSchema(ctx, mapping.schema, topic, nil)
 	if err != nil {
 		return nil, err
 	}
+	if len(mapping.partition) > 0 {
+		spec, err := partitionSpec(desired, mapping.partition)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, catalog.WithPartitionSpec(&spec))
+	}
+	if len(mapping.sortOrder) > 0 {
+		order, err := sortOrder(desired, mapping.sortOrder)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, catalog.WithSortOrder(order))
+	}
 	return w.catalog.CreateTable(ctx, ident, desired, opts...)
 }
 
@@ -341,11 +455,12 @@ func (w *icebergWriter) createTableWithRetry(ctx context.Context, mapping tableM
 			return nil, err
 		}
 		log.Printf("iceberg: create table attempt %d/%d for %v", i+1, attempts, mapping.identifier)
-		tbl, err := w.createTable(ctx, mapping.identifier, mapping.schema, topic)
+		tbl, err := w.createTable(ctx, mapping, topic)
 		if err == nil {
 			log.Printf("iceberg: create table %v succeeded", mapping.identifier)
 			return tbl, nil
 		}
+		log.Printf("iceberg: create table %v attempt %d failed: %v", mapping.identifier, i+1, err)
 		if _, isRest := w.catalog.(*restcatalog.Catalog); isRest {
 			if isCreateConflict(err) || errors.Is(err, catalog.ErrTableAlreadyExists) {
 				log.Printf("iceberg: create table %v conflicted, retrying load", mapping.identifier)
@@ -374,10 +489,14 @@ func isCreateConflict(err error) bool {
 }
 
 func isCommitConflict(err error) bool {
-	if errors.Is(err, restcatalog.ErrCommitFailed) {
+	if errors.Is(err, restcatalog.ErrCommitFailed) || errors.Is(err, errHadoopCommitConflict) {
 		return true
 	}
-	return strings.Contains(err.Error(), "branch main was created concurrently")
+	msg := err.Error()
+	return strings.Contains(msg, "branch main was created concurrently") ||
+		strings.Contains(msg, "branch main has changed") ||
+		strings.Contains(msg, "table has been updated by another process") ||
+		strings.Contains(msg, "ConcurrentModificationException")
 }
 
 func hasSnapshotDataFiles(tbl *table.Table) bool {
@@ -400,6 +519,80 @@ func hasSnapshotDataFiles(tbl *table.Table) bool {
 	return true
 }
 
+const offsetPropertyPrefix = "kafscale.offset."
+
+func offsetProperty(topic string, partition int32) string {
+	return fmt.Sprintf("%s%s.%d", offsetPropertyPrefix, topic, partition)
+}
+
+// offsetProperties returns the offset watermarks of the newest snapshot in the
+// current lineage that carries them. Each commit copies them forward, so
+// snapshots written by other tools (compaction, deletes) are skipped over.
+func offsetProperties(tbl *table.Table) iceberg.Properties {
+	props := iceberg.Properties{}
+	if tbl == nil {
+		return props
+	}
+	for snap := tbl.CurrentSnapshot(); snap != nil; {
+		if snap.Summary != nil {
+			for key, value := range snap.Summary.Properties {
+				if strings.HasPrefix(key, offsetPropertyPrefix) {
+					props[key] = value
+				}
+			}
+		}
+		if len(props) > 0 || snap.ParentSnapshotID == nil {
+			break
+		}
+		snap = tbl.SnapshotByID(*snap.ParentSnapshotID)
+	}
+	return props
+}
+
+func parseOffsets(props iceberg.Properties, topic string) map[int32]int64 {
+	prefix := offsetPropertyPrefix + topic + "."
+	offsets := make(map[int32]int64)
+	for key, value := range props {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		partition, err := strconv.ParseInt(strings.TrimPrefix(key, prefix), 10, 32)
+		if err != nil {
+			continue
+		}
+		offset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		offsets[int32(partition)] = offset
+	}
+	return offsets
+}
+
+func filterCommitted(records []Record, committed map[int32]int64) []Record {
+	if len(committed) == 0 {
+		return records
+	}
+	out := make([]Record, 0, len(records))
+	for _, record := range records {
+		if offset, ok := committed[record.Partition]; ok && record.Offset <= offset {
+			continue
+		}
+		out = append(out, record)
+	}
+	return out
+}
+
+func lastOffsets(records []Record) map[int32]int64 {
+	offsets := make(map[int32]int64)
+	for _, record := range records {
+		if offset, ok := offsets[record.Partition]; !ok || record.Offset > offset {
+			offsets[record.Partition] = record.Offset
+		}
+	}
+	return offsets
+}
+
 func summaryCount(props iceberg.Properties, key string) (int, bool) {
 	raw := props[key]
 	if raw == "" {
@@ -482,12 +675,18 @@ func (w *icebergWriter) ensureSchema(ctx context.Context, tbl *table.Table, mapp
 		tbl = updated
 		current = tbl.Schema()
 	}
+	if updated, err := w.ensureWriteProperties(ctx, tbl, mapping); err != nil {
+		return nil, nil, err
+	} else if updated != nil {
+		tbl = updated
+		current = tbl.Schema()
+	}
 	desired, columns, err := w.buildDesiredSchema(ctx, mapping.schema, topic, current)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	needsUpdate, err := schemaNeedsUpdate(current, desired, mapping.schema.AllowTypeWidening)
+	needsUpdate, err := schemaNeedsUpdate(current, desired, w.registry.allowWidening(mapping.schema))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -514,7 +713,7 @@ func (w *icebergWriter) ensureSchema(ctx context.Context, tbl *table.Table, mapp
 				if err != nil {
 					return nil, nil, err
 				}
-				needsUpdate, err = schemaNeedsUpdate(current, desired, mapping.schema.AllowTypeWidening)
+				needsUpdate, err = schemaNeedsUpdate(current, desired, w.registry.allowWidening(mapping.schema))
 				if err != nil {
 					return nil, nil, err
 				}
@@ -611,7 +810,7 @@ func (w *icebergWriter) buildDesiredSchema(ctx context.Context, schemaCfg config
 		return nil, nil, err
 	}
 
-	fieldIDs := map[string]int{}
+	existingFields := map[string]iceberg.NestedField{}
 	maxID := 0
 	if existing != nil {
 		for _, id := range existing.FieldIDs() {
@@ -620,14 +819,18 @@ func (w *icebergWriter) buildDesiredSchema(ctx context.Context, schemaCfg config
 			}
 		}
 		for _, field := range existing.Fields() {
-			fieldIDs[field.Name] = field.ID
+			existingFields[field.Name] = field
 		}
 	}
+	nextID := func() int {
+		maxID++
+		return maxID
+	}
 
 	fields := make([]iceberg.NestedField, 0, len(baseFields())+len(columns))
 	for _, base := range baseFields() {
-		if id, ok := fieldIDs[base.Name]; ok {
-			base.ID = id
+		if field, ok := existingFields[base.Name]; ok {
+			base.ID = field.ID
 		} else if base.ID <= maxID {
 			maxID++
 			base.ID = maxID
@@ -639,17 +842,16 @@ func (w *icebergWriter) buildDesiredSchema(ctx context.Context, schemaCfg config
 	}
 
 	for _, col := range columns {
-		id, ok := fieldIDs[col.Name]
+		field, ok := existingFields[col.Name]
 		if !ok {
-			maxID++
-			id = maxID
+			field.ID = nextID()
 		}
-		fieldType, err := icebergTypeForColumn(col.Type)
+		fieldType, err := columnType(col, field.Type, nextID)
 		if err != nil {
 			return nil, nil, err
 		}
 		fields = append(fields, iceberg.NestedField{
-			ID:       id,
+			ID:       field.ID,
 			Name:     col.Name,
 			Type:     fieldType,
 			Required: col.Required,
@@ -663,12 +865,12 @@ func (w *icebergWriter) buildDesiredSchema(ctx context.Context, schemaCfg config
 	return iceberg.NewSchema(schemaID, fields...), columns, nil
 }
 
-func resolveColumns(ctx context.Context, registry config.SchemaConfig, schemaCfg config.MappingSchemaConfig, topic string) ([]config.Column, error) {
+func resolveColumns(ctx context.Context, registry *schemaRegistry, schemaCfg config.MappingSchemaConfig, topic string) ([]config.Column, error) {
 	switch schemaCfg.Source {
 	case "mapping":
 		return schemaCfg.Columns, nil
 	case "registry":
-		return columnsFromRegistry(ctx, registry, topic)
+		return registry.columns(ctx, topic)
 	case "none":
 		return nil, nil
 	default:
@@ -804,21 +1006,131 @@ func icebergTypeForColumn(value string) (iceberg.Type, error) {
 	}
 }
 
+// columnType builds the Iceberg type of a column. Nested fields, list
+// elements and map keys and values reuse their IDs from existing, the
+// column's current type, and take new IDs from nextID. Fields added to an
+// existing struct are optional.
+func columnType(col config.Column, existing iceberg.Type, nextID func() int) (iceberg.Type, error) {
+	switch strings.ToLower(col.Type) {
+	case "struct":
+		current, _ := existing.(*iceberg.StructType)
+		have := map[string]iceberg.NestedField{}
+		if current != nil {
+			for _, field := range current.FieldList {
+				have[field.Name] = field
+			}
+		}
+		fields := make([]iceberg.NestedField, 0, len(col.Fields))
+		for _, child := range col.Fields {
+			field, ok := have[child.Name]
+			if !ok {
+				field = iceberg.NestedField{ID: nextID(), Name: child.Name, Required: child.Required && current == nil}
+			}
+			typ, err := columnType(child, field.Type, nextID)
+			if err != nil {
+				return nil, err
+			}
+			field.Type = typ
+			fields = append(fields, field)
+		}
+		return &iceberg.StructType{FieldList: fields}, nil
+	case "list":
+		current, _ := existing.(*iceberg.ListType)
+		list := &iceberg.ListType{ElementRequired: col.Element.Required}
+		var currentElement iceberg.Type
+		if current != nil {
+			list.ElementID, list.ElementRequired, currentElement = current.ElementID, current.ElementRequired, current.Element
+		} else {
+			list.ElementID = nextID()
+		}
+		element, err := columnType(*col.Element, currentElement, nextID)
+		if err != nil {
+			return nil, err
+		}
+		list.Element = element
+		return list, nil
+	case "map":
+		current, _ := existing.(*iceberg.MapType)
+		m := &iceberg.MapType{KeyType: iceberg.PrimitiveTypes.String, ValueRequired: col.Element.Required}
+		var currentValue iceberg.Type
+		if current != nil {
+			m.KeyID, m.ValueID, m.ValueRequired, currentValue = current.KeyID, current.ValueID, current.ValueRequired, current.ValueType
+		} else {
+			m.KeyID, m.ValueID = nextID(), nextID()
+		}
+		value, err := columnType(*col.Element, currentValue, nextID)
+		if err != nil {
+			return nil, err
+		}
+		m.ValueType = value
+		return m, nil
+	default:
+		return icebergTypeForColumn(col.Type)
+	}
+}
+
 func schemaNeedsUpdate(current *iceberg.Schema, desired *iceberg.Schema, allowWiden bool) (bool, error) {
+	needsUpdate := false
 	for _, field := range desired.Fields() {
 		existing, ok := current.FindFieldByName(field.Name)
 		if !ok {
-			return true, nil
-		}
-		if existing.Type.Equals(field.Type) {
+			needsUpdate = true
 			continue
 		}
-		if allowWiden && isWidening(existing.Type, field.Type) {
+		changed, err := typeNeedsUpdate(field.Name, existing.Type, field.Type, allowWiden)
+		if err != nil {
+			return false, err
+		}
+		needsUpdate = needsUpdate || changed
+	}
+	return needsUpdate, nil
+}
+
+// typeNeedsUpdate compares a column's current and desired types. Fields may
+// be added to structs at any depth, and primitives widened if allowed; any
+// other change is incompatible.
+func typeNeedsUpdate(name string, current, desired iceberg.Type, allowWiden bool) (bool, error) {
+	if current.Equals(desired) {
+		return false, nil
+	}
+	switch d := desired.(type) {
+	case *iceberg.StructType:
+		c, ok := current.(*iceberg.StructType)
+		if !ok {
+			break
+		}
+		have := map[string]iceberg.NestedField{}
+		for _, field := range c.FieldList {
+			have[field.Name] = field
+		}
+		needsUpdate := false
+		for _, field := range d.FieldList {
+			existing, ok := have[field.Name]
+			if !ok {
+				needsUpdate = true
+				continue
+			}
+			changed, err := typeNeedsUpdate(name+"."+field.Name, existing.Type, field.Type, allowWiden)
+			if err != nil {
+				return false, err
+			}
+			needsUpdate = needsUpdate || changed
+		}
+		return needsUpdate, nil
+	case *iceberg.ListType:
+		if c, ok := current.(*iceberg.ListType); ok {
+			return typeNeedsUpdate(name+".element", c.Element, d.Element, allowWiden)
+		}
+	case *iceberg.MapType:
+		if c, ok := current.(*iceberg.MapType); ok && c.KeyType.Equals(d.KeyType) {
+			return typeNeedsUpdate(name+".value", c.ValueType, d.ValueType, allowWiden)
+		}
+	default:
+		if allowWiden && isWidening(current, desired) {
 			return true, nil
 		}
-		return false, fmt.Errorf("incompatible type change for %q: %s -> %s", field.Name, existing.Type, field.Type)
 	}
-	return false, nil
+	return false, fmt.Errorf("incompatible type change for %q: %s -> %s", name, current, desired)
 }
 
 func isWidening(from iceberg.Type, to iceberg.Type) bool {
@@ -848,6 +1160,7 @@ func baseFields() []iceberg.NestedField {
 		{ID: 6, Name: "key", Type: iceberg.PrimitiveTypes.Binary, Required: false},
 		{ID: 7, Name: "value", Type: iceberg.PrimitiveTypes.Binary, Required: false},
 		{ID: 8, Name: "headers", Type: iceberg.PrimitiveTypes.String, Required: false},
+		{ID: 9, Name: "_ts", Type: iceberg.PrimitiveTypes.TimestampTz, Required: false},
 	}
 }
 
@@ -864,6 +1177,7 @@ func recordsToArrow(schema *arrow.Schema, columns []config.Column, records []Rec
 	keyBuilder := builder.Field(5).(*array.BinaryBuilder)
 	valueBuilder := builder.Field(6).(*array.BinaryBuilder)
 	headersBuilder := builder.Field(7).(*array.StringBuilder)
+	tsBuilder := builder.Field(8).(*array.TimestampBuilder)
 	columnBuilders := buildColumnBuilders(builder, columns)
 
 	for _, record := range records {
@@ -883,8 +1197,9 @@ func recordsToArrow(schema *arrow.Schema, columns []config.Column, records []Rec
 			valueBuilder.Append(record.Value)
 		}
 		headersBuilder.Append(serializeHeaders(record.Headers))
+		tsBuilder.Append(arrow.Timestamp(record.Timestamp * 1000))
 		if len(columnBuilders) > 0 {
-			values := extractJSONValues(record.Value)
+			values := recordValues(record)
 			appendColumnValues(columnBuilders, values)
 		}
 	}
@@ -930,7 +1245,6 @@ func groupByTopic(records []Record) map[string][]Record {
 
 type columnBuilder struct {
 	name       string
-	typ        string
 	append     func(interface{})
 	appendNull func()
 }
@@ -942,148 +1256,167 @@ func buildColumnBuilders(builder *array.RecordBuilder, columns []config.Column)
 
 	builders := make([]columnBuilder, 0, len(columns))
 	for i, col := range columns {
-		field := builder.Field(8 + i)
-		switch strings.ToLower(col.Type) {
-		case "boolean":
-			b := field.(*array.BooleanBuilder)
-			builders = append(builders, columnBuilder{
-				name: col.Name,
-				typ:  col.Type,
-				append: func(value interface{}) {
-					v, ok := asBool(value)
-					if !ok {
-						b.AppendNull()
-						return
-					}
-					b.Append(v)
-				},
-				appendNull: b.AppendNull,
-			})
-		case "int":
-			b := field.(*array.Int32Builder)
-			builders = append(builders, columnBuilder{
-				name: col.Name,
-				typ:  col.Type,
-				append: func(value interface{}) {
-					v, ok := asInt64(value)
-					if !ok {
-						b.AppendNull()
-						return
-					}
-					b.Append(int32(v))
-				},
-				appendNull: b.AppendNull,
-			})
-		case "long":
-			b := field.(*array.Int64Builder)
-			builders = append(builders, columnBuilder{
-				name: col.Name,
-				typ:  col.Type,
-				append: func(value interface{}) {
-					v, ok := asInt64(value)
-					if !ok {
-						b.AppendNull()
-						return
-					}
-					b.Append(v)
-				},
-				appendNull: b.AppendNull,
-			})
-		case "float":
-			b := field.(*array.Float32Builder)
-			builders = append(builders, columnBuilder{
-				name: col.Name,
-				typ:  col.Type,
-				append: func(value interface{}) {
-					v, ok := asFloat64(value)
-					if !ok {
-						b.AppendNull()
-						return
-					}
-					b.Append(float32(v))
-				},
-				appendNull: b.AppendNull,
-			})
-		case "double":
-			b := field.(*array.Float64Builder)
-			builders = append(builders, columnBuilder{
-				name: col.Name,
-				typ:  col.Type,
-				append: func(value interface{}) {
-					v, ok := asFloat64(value)
-					if !ok {
-						b.AppendNull()
-						return
-					}
-					b.Append(v)
-				},
-				appendNull: b.AppendNull,
-			})
-		case "string":
-			b := field.(*array.StringBuilder)
-			builders = append(builders, columnBuilder{
-				name: col.Name,
-				typ:  col.Type,
-				append: func(value interface{}) {
-					v, ok := asString(value)
-					if !ok {
-						b.AppendNull()
-						return
-					}
-					b.Append(v)
-				},
-				appendNull: b.AppendNull,
-			})
-		case "binary":
-			b := field.(*array.BinaryBuilder)
-			builders = append(builders, columnBuilder{
-				name: col.Name,
-				typ:  col.Type,
-				append: func(value interface{}) {
-					v, ok := asBytes(value)
-					if !ok {
-						b.AppendNull()
-						return
-					}
-					b.Append(v)
-				},
-				appendNull: b.AppendNull,
-			})
-		case "timestamp":
-			b := field.(*array.TimestampBuilder)
-			builders = append(builders, columnBuilder{
-				name: col.Name,
-				typ:  col.Type,
-				append: func(value interface{}) {
-					v, ok := asTimestamp(value)
-					if !ok {
-						b.AppendNull()
-						return
-					}
-					b.Append(v)
-				},
-				appendNull: b.AppendNull,
-			})
-		case "date":
-			b := field.(*array.Date32Builder)
-			builders = append(builders, columnBuilder{
-				name: col.Name,
-				typ:  col.Type,
-				append: func(value interface{}) {
-					v, ok := asDate(value)
-					if !ok {
-						b.AppendNull()
-						return
-					}
-					b.Append(v)
-				},
-				appendNull: b.AppendNull,
-			})
-		}
+		field := builder.Field(len(baseFields()) + i)
+		builders = append(builders, columnBuilder{
+			name:       col.Name,
+			append:     valueAppender(field),
+			appendNull: field.AppendNull,
+		})
 	}
 	return builders
 }
 
+// valueAppender returns a function that converts a decoded value to the
+// builder's type and appends it. Structs take maps, lists take slices and
+// maps take string-keyed maps. Values that do not convert are appended as
+// nulls.
+func valueAppender(field array.Builder) func(interface{}) {
+	switch b := field.(type) {
+	case *array.BooleanBuilder:
+		return func(value interface{}) {
+			if v, ok := asBool(value); ok {
+				b.Append(v)
+				return
+			}
+			b.AppendNull()
+		}
+	case *array.Int32Builder:
+		return func(value interface{}) {
+			if v, ok := asInt64(value); ok {
+				b.Append(int32(v))
+				return
+			}
+			b.AppendNull()
+		}
+	case *array.Int64Builder:
+		return func(value interface{}) {
+			if v, ok := asInt64(value); ok {
+				b.Append(v)
+				return
+			}
+			b.AppendNull()
+		}
+	case *array.Float32Builder:
+		return func(value interface{}) {
+			if v, ok := asFloat64(value); ok {
+				b.Append(float32(v))
+				return
+			}
+			b.AppendNull()
+		}
+	case *array.Float64Builder:
+		return func(value interface{}) {
+			if v, ok := asFloat64(value); ok {
+				b.Append(v)
+				return
+			}
+			b.AppendNull()
+		}
+	case *array.StringBuilder:
+		return func(value interface{}) {
+			if v, ok := asString(value); ok {
+				b.Append(v)
+				return
+			}
+			b.AppendNull()
+		}
+	case *array.BinaryBuilder:
+		return func(value interface{}) {
+			if v, ok := asBytes(value); ok {
+				b.Append(v)
+				return
+			}
+			b.AppendNull()
+		}
+	case *array.TimestampBuilder:
+		unit := b.Type().(*arrow.TimestampType).Unit
+		return func(value interface{}) {
+			if t, ok := value.(time.Time); ok {
+				if ts, err := arrow.TimestampFromTime(t, unit); err == nil {
+					b.Append(ts)
+					return
+				}
+			}
+			if v, ok := asTimestamp(value); ok {
+				b.Append(v)
+				return
+			}
+			b.AppendNull()
+		}
+	case *array.Date32Builder:
+		return func(value interface{}) {
+			if v, ok := asDate(value); ok {
+				b.Append(v)
+				return
+			}
+			b.AppendNull()
+		}
+	case *array.StructBuilder:
+		fields := b.Type().(*arrow.StructType).Fields()
+		children := make([]func(interface{}), len(fields))
+		for i := range fields {
+			children[i] = valueAppender(b.FieldBuilder(i))
+		}
+		return func(value interface{}) {
+			values, ok := value.(map[string]interface{})
+			if !ok {
+				b.AppendNull()
+				return
+			}
+			b.Append(true)
+			for i, field := range fields {
+				children[i](values[field.Name])
+			}
+		}
+	case *array.MapBuilder:
+		appendKey := valueAppender(b.KeyBuilder())
+		appendItem := valueAppender(b.ItemBuilder())
+		return func(value interface{}) {
+			entries, ok := value.(map[string]interface{})
+			if !ok {
+				b.AppendNull()
+				return
+			}
+			keys := make([]string, 0, len(entries))
+			for key := range entries {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			b.Append(true)
+			for _, key := range keys {
+				appendKey(key)
+				appendItem(entries[key])
+			}
+		}
+	case *array.ListBuilder:
+		appendElement := valueAppender(b.ValueBuilder())
+		return func(value interface{}) {
+			items, ok := value.([]interface{})
+			if !ok {
+				b.AppendNull()
+				return
+			}
+			b.Append(true)
+			for _, item := range items {
+				appendElement(item)
+			}
+		}
+	default:
+		return func(interface{}) {
+			field.AppendNull()
+		}
+	}
+}
+
+// recordValues returns the row values of a record: those decoded from a
+// registry-framed payload, or else the fields of the JSON payload.
+func recordValues(record Record) map[string]interface{} {
+	if record.values != nil {
+		return record.values
+	}
+	return extractJSONValues(record.Value)
+}
+
 func extractJSONValues(payload []byte) map[string]interface{} {
 	if len(payload) == 0 {
 		return nil
@@ -1190,6 +1523,9 @@ func asTimestamp(value interface{}) (arrow.Timestamp, bool) {
 }
 
 func asDate(value interface{}) (arrow.Date32, bool) {
+	if t, ok := value.(time.Time); ok {
+		return arrow.Date32FromTime(t), true
+	}
 	if v, ok := asInt64(value); ok {
 		return arrow.Date32(v), true
 	}
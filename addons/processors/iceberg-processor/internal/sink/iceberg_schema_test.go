@@ -27,7 +27,7 @@ func TestSchemaNeedsUpdateForNewColumn(t *testing.T) {
 	current := iceberg.NewSchema(1, baseFields()...)
 	desired := iceberg.NewSchema(2,
 		append(baseFields(),
-			iceberg.NestedField{ID: 9, Name: "order_id", Type: iceberg.PrimitiveTypes.Int64},
+			iceberg.NestedField{ID: 10, Name: "order_id", Type: iceberg.PrimitiveTypes.Int64},
 		)...)
 
 	needsUpdate, err := schemaNeedsUpdate(current, desired, false)
@@ -42,11 +42,11 @@ func TestSchemaNeedsUpdateForNewColumn(t *testing.T) {
 func TestSchemaNeedsUpdateRejectsIncompatibleType(t *testing.T) {
 	current := iceberg.NewSchema(1,
 		append(baseFields(),
-			iceberg.NestedField{ID: 9, Name: "status", Type: iceberg.PrimitiveTypes.String},
+			iceberg.NestedField{ID: 10, Name: "status", Type: iceberg.PrimitiveTypes.String},
 		)...)
 	desired := iceberg.NewSchema(2,
 		append(baseFields(),
-			iceberg.NestedField{ID: 9, Name: "status", Type: iceberg.PrimitiveTypes.Int64},
+			iceberg.NestedField{ID: 10, Name: "status", Type: iceberg.PrimitiveTypes.Int64},
 		)...)
 
 	_, err := schemaNeedsUpdate(current, desired, false)
@@ -58,11 +58,11 @@ func TestSchemaNeedsUpdateRejectsIncompatibleType(t *testing.T) {
 func TestSchemaNeedsUpdateAllowsWidening(t *testing.T) {
 	current := iceberg.NewSchema(1,
 		append(baseFields(),
-			iceberg.NestedField{ID: 9, Name: "count", Type: iceberg.PrimitiveTypes.Int32},
+			iceberg.NestedField{ID: 10, Name: "count", Type: iceberg.PrimitiveTypes.Int32},
 		)...)
 	desired := iceberg.NewSchema(2,
 		append(baseFields(),
-			iceberg.NestedField{ID: 9, Name: "count", Type: iceberg.PrimitiveTypes.Int64},
+			iceberg.NestedField{ID: 10, Name: "count", Type: iceberg.PrimitiveTypes.Int64},
 		)...)
 
 	needsUpdate, err := schemaNeedsUpdate(current, desired, true)
@@ -78,7 +78,7 @@ func TestResolveColumnsFromMapping(t *testing.T) {
 	columns := []config.Column{
 		{Name: "order_id", Type: "long"},
 	}
-	out, err := resolveColumns(context.Background(), config.SchemaConfig{}, config.MappingSchemaConfig{
+	out, err := resolveColumns(context.Background(), nil, config.MappingSchemaConfig{
 		Source:  "mapping",
 		Columns: columns,
 	}, "orders")
@@ -75,8 +75,8 @@ func TestRecordsToArrow(t *testing.T) {
 func TestRecordsToArrowWithColumns(t *testing.T) {
 	iceSchema := iceberg.NewSchema(1,
 		append(baseFields(),
-			iceberg.NestedField{ID: 9, Name: "order_id", Type: iceberg.PrimitiveTypes.Int64},
-			iceberg.NestedField{ID: 10, Name: "status", Type: iceberg.PrimitiveTypes.String},
+			iceberg.NestedField{ID: 10, Name: "order_id", Type: iceberg.PrimitiveTypes.Int64},
+			iceberg.NestedField{ID: 11, Name: "status", Type: iceberg.PrimitiveTypes.String},
 		)...)
 	arrowSchema, err := tableSchemaToArrow(iceSchema)
 	if err != nil {
@@ -107,11 +107,11 @@ func TestRecordsToArrowWithColumns(t *testing.T) {
 	}
 
 	batch := rdr.RecordBatch()
-	orderID := batch.Column(8).(*array.Int64)
+	orderID := batch.Column(9).(*array.Int64)
 	if orderID.Value(0) != 42 {
 		t.Fatalf("unexpected order_id: %d", orderID.Value(0))
 	}
-	status := batch.Column(9).(*array.String)
+	status := batch.Column(10).(*array.String)
 	if status.Value(0) != "new" {
 		t.Fatalf("unexpected status: %s", status.Value(0))
 	}
@@ -210,6 +210,64 @@ func TestHasSnapshotDataFilesMissingSummary(t *testing.T) {
 	}
 }
 
+func TestOffsetPropertiesSkipForeignSnapshots(t *testing.T) {
+	tbl := tableWithLineage(t,
+		&table.Summary{Operation: table.OpAppend, Properties: iceberg.Properties{
+			offsetProperty("orders", 0):    "41",
+			offsetProperty("orders", 1):    "7",
+			offsetProperty("orders.eu", 0): "3",
+		}},
+		&table.Summary{Operation: table.OpReplace, Properties: iceberg.Properties{"added-data-files": "1"}},
+	)
+
+	offsets := parseOffsets(offsetProperties(tbl), "orders")
+	if len(offsets) != 2 || offsets[0] != 41 || offsets[1] != 7 {
+		t.Fatalf("unexpected offsets: %v", offsets)
+	}
+	if eu := parseOffsets(offsetProperties(tbl), "orders.eu"); len(eu) != 1 || eu[0] != 3 {
+		t.Fatalf("unexpected offsets for dotted topic: %v", eu)
+	}
+}
+
+func TestFilterCommittedRecords(t *testing.T) {
+	records := []Record{
+		{Partition: 0, Offset: 40},
+		{Partition: 0, Offset: 42},
+		{Partition: 1, Offset: 5},
+		{Partition: 2, Offset: 0},
+	}
+	pending := filterCommitted(records, map[int32]int64{0: 41, 1: 7})
+	if len(pending) != 2 || pending[0].Offset != 42 || pending[1].Partition != 2 {
+		t.Fatalf("unexpected pending records: %+v", pending)
+	}
+	last := lastOffsets(records)
+	if last[0] != 42 || last[1] != 5 || last[2] != 0 {
+		t.Fatalf("unexpected last offsets: %v", last)
+	}
+}
+
+func TestCommittedOffsetFromTable(t *testing.T) {
+	tbl := tableWithLineage(t, &table.Summary{Operation: table.OpAppend, Properties: iceberg.Properties{
+		offsetProperty("demo-topic", 2): "99",
+	}})
+	writer := &icebergWriter{
+		catalog: &fakeCatalog{meta: tbl.Metadata()},
+		mappings: map[string]tableMapping{
+			"demo-topic": {identifier: table.Identifier{"demo", "demo_topic_1"}, mode: "append"},
+		},
+		schemas: make(map[string]*topicSchema),
+		tables:  make(map[string]*table.Table),
+	}
+
+	offset, found, err := writer.CommittedOffset(context.Background(), "demo-topic", 2)
+	if err != nil || !found || offset != 99 {
+		t.Fatalf("expected offset 99, got %d found=%v err=%v", offset, found, err)
+	}
+	if _, found, _ := writer.CommittedOffset(context.Background(), "demo-topic", 3); found {
+		t.Fatalf("expected no offset for uncommitted partition")
+	}
+}
+
 func TestLoadTableRefreshesSchema(t *testing.T) {
 	ident := table.Identifier{"demo", "demo_topic_1"}
 	meta, err := table.NewMetadata(defaultSchema(), iceberg.UnpartitionedSpec, table.UnsortedSortOrder, "s3://bucket/demo/demo_topic_1", iceberg.Properties{})
@@ -312,9 +370,51 @@ func tableWithSnapshot(t *testing.T, summary *table.Summary) *table.Table {
 	return table.New(ident, meta, "", func(context.Context) (io.IO, error) { return nil, nil }, &fakeCatalog{meta: meta})
 }
 
+// tableWithLineage builds a table whose snapshots form a parent chain, oldest
+// summary first.
+func tableWithLineage(t *testing.T, summaries ...*table.Summary) *table.Table {
+	t.Helper()
+
+	ident := table.Identifier{"demo", "demo_topic_1"}
+	meta, err := table.NewMetadata(defaultSchema(), iceberg.UnpartitionedSpec, table.UnsortedSortOrder, "s3://bucket/demo/demo_topic_1", iceberg.Properties{})
+	if err != nil {
+		t.Fatalf("metadata init: %v", err)
+	}
+	builder, err := table.MetadataBuilderFromBase(meta, "")
+	if err != nil {
+		t.Fatalf("metadata builder: %v", err)
+	}
+	var parent *int64
+	for i, summary := range summaries {
+		snap := table.Snapshot{
+			SnapshotID:       int64(i + 1),
+			ParentSnapshotID: parent,
+			SequenceNumber:   int64(i + 1),
+			TimestampMs:      time.Now().UnixMilli(),
+			Summary:          summary,
+		}
+		if err := builder.AddSnapshot(&snap); err != nil {
+			t.Fatalf("add snapshot: %v", err)
+		}
+		if err := builder.SetSnapshotRef(table.MainBranch, snap.SnapshotID, table.BranchRef); err != nil {
+			t.Fatalf("set snapshot ref: %v", err)
+		}
+		id := snap.SnapshotID
+		parent = &id
+	}
+	meta, err = builder.Build()
+	if err != nil {
+		t.Fatalf("build metadata: %v", err)
+	}
+
+	return table.New(ident, meta, "", func(context.Context) (io.IO, error) { return nil, nil }, &fakeCatalog{meta: meta})
+}
+
 type fakeCatalog struct {
 	meta        table.Metadata
 	commitCalls int
+	// fs backs the tables returned by LoadTable.
+	fs io.IO
 }
 
 func (f *fakeCatalog) CatalogType() catalog.Type { return catalog.REST }
@@ -347,7 +447,7 @@ func (f *fakeCatalog) ListTables(ctx context.Context, namespace table.Identifier
 }
 
 func (f *fakeCatalog) LoadTable(ctx context.Context, identifier table.Identifier) (*table.Table, error) {
-	return table.New(identifier, f.meta, "", func(context.Context) (io.IO, error) { return nil, nil }, f), nil
+	return table.New(identifier, f.meta, "", func(context.Context) (io.IO, error) { return f.fs, nil }, f), nil
 }
 
 func (f *fakeCatalog) DropTable(ctx context.Context, identifier table.Identifier) error {
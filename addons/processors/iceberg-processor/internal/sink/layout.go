@@ -0,0 +1,257 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	iceberg "github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+// partitionFieldIDStart is where Iceberg starts numbering partition fields.
+const partitionFieldIDStart = 1000
+
+func partitionSpec(schema *iceberg.Schema, fields []config.PartitionField) (iceberg.PartitionSpec, error) {
+	opts := make([]iceberg.PartitionOption, 0, len(fields))
+	for i, field := range fields {
+		source, ok := schema.FindFieldByName(field.Column)
+		if !ok {
+			return iceberg.PartitionSpec{}, fmt.Errorf("partition column %q is not in the table schema", field.Column)
+		}
+		transform, err := iceberg.ParseTransform(field.Transform)
+		if err != nil {
+			return iceberg.PartitionSpec{}, err
+		}
+		if !transform.CanTransform(source.Type) {
+			return iceberg.PartitionSpec{}, fmt.Errorf("partition transform %s cannot apply to %q of type %s", field.Transform, field.Column, source.Type)
+		}
+		fieldID := partitionFieldIDStart + i
+		opts = append(opts, iceberg.AddPartitionFieldByName(field.Column, partitionFieldName(field), transform, schema, &fieldID))
+	}
+	return iceberg.NewPartitionSpecOpts(opts...)
+}
+
+// partitionFieldName follows the Iceberg naming convention, e.g. _ts_day or
+// customer_id_bucket.
+func partitionFieldName(field config.PartitionField) string {
+	name := field.Transform
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	switch name {
+	case "identity":
+		return field.Column
+	case "truncate":
+		name = "trunc"
+	}
+	return field.Column + "_" + name
+}
+
+func sortOrder(schema *iceberg.Schema, fields []config.SortField) (table.SortOrder, error) {
+	out := make([]table.SortField, 0, len(fields))
+	for _, field := range fields {
+		source, ok := schema.FindFieldByName(field.Column)
+		if !ok {
+			return table.SortOrder{}, fmt.Errorf("sort column %q is not in the table schema", field.Column)
+		}
+		direction := table.SortASC
+		if field.Direction == "desc" {
+			direction = table.SortDESC
+		}
+		nullOrder := table.NullsFirst
+		if field.NullOrder == "last" {
+			nullOrder = table.NullsLast
+		}
+		out = append(out, table.SortField{
+			SourceID:  source.ID,
+			Transform: iceberg.IdentityTransform{},
+			Direction: direction,
+			NullOrder: nullOrder,
+		})
+	}
+	return table.NewSortOrder(table.InitialSortOrderID, out)
+}
+
+// writeProperties maps the write settings of a mapping to table properties,
+// which the Iceberg writer reads when it rolls data files and row groups.
+func writeProperties(write config.WriteConfig) iceberg.Properties {
+	props := iceberg.Properties{}
+	if write.TargetFileSizeBytes > 0 {
+		props[table.WriteTargetFileSizeBytesKey] = strconv.FormatInt(write.TargetFileSizeBytes, 10)
+	}
+	if write.RowGroupSizeBytes > 0 {
+		props[table.ParquetRowGroupSizeBytesKey] = strconv.FormatInt(write.RowGroupSizeBytes, 10)
+	}
+	if write.RowGroupLimit > 0 {
+		props[table.ParquetRowGroupLimitKey] = strconv.FormatInt(write.RowGroupLimit, 10)
+	}
+	return props
+}
+
+// ensureWriteProperties brings the write properties of an existing table in
+// line with its mapping. Partition specs and sort orders are only applied
+// when a table is created.
+func (w *icebergWriter) ensureWriteProperties(ctx context.Context, tbl *table.Table, mapping tableMapping) (*table.Table, error) {
+	desired := writeProperties(mapping.write)
+	for attempt := 0; attempt < 3; attempt++ {
+		props := iceberg.Properties{}
+		current := tbl.Properties()
+		for k, v := range desired {
+			if current[k] != v {
+				props[k] = v
+			}
+		}
+		if len(props) == 0 {
+			if attempt == 0 {
+				return nil, nil
+			}
+			return tbl, nil
+		}
+		if _, _, err := w.catalog.CommitTable(ctx, mapping.identifier, nil, []table.Update{table.NewSetPropertiesUpdate(props)}); err != nil {
+			if !isCommitConflict(err) {
+				return nil, err
+			}
+			reloaded, loadErr := loadTableWithRetry(ctx, w.catalog, mapping.identifier, 5, 200*time.Millisecond)
+			if loadErr != nil {
+				return nil, err
+			}
+			tbl = reloaded
+			continue
+		}
+		return loadTableWithRetry(ctx, w.catalog, mapping.identifier, 3, 150*time.Millisecond)
+	}
+	return nil, fmt.Errorf("failed to update write properties for %v", mapping.identifier)
+}
+
+// sortRecords orders records by the mapping's sort order so that each data
+// file is written sorted. The Iceberg writer does not sort on its own.
+func sortRecords(records []Record, fields []config.SortField) []Record {
+	if len(fields) == 0 || len(records) < 2 {
+		return records
+	}
+	keys := make([][]interface{}, len(records))
+	for i, record := range records {
+		var values map[string]interface{}
+		keys[i] = make([]interface{}, len(fields))
+		for j, field := range fields {
+			if value, ok := baseSortValue(record, field.Column); ok {
+				keys[i][j] = value
+				continue
+			}
+			if values == nil {
+				values = recordValues(record)
+			}
+			keys[i][j] = values[field.Column]
+		}
+	}
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		for j, field := range fields {
+			cmp := compareSortValues(keys[order[a]][j], keys[order[b]][j], field.NullOrder == "last")
+			if cmp == 0 {
+				continue
+			}
+			if field.Direction == "desc" && keys[order[a]][j] != nil && keys[order[b]][j] != nil {
+				cmp = -cmp
+			}
+			return cmp < 0
+		}
+		return false
+	})
+	sorted := make([]Record, len(records))
+	for i, idx := range order {
+		sorted[i] = records[idx]
+	}
+	return sorted
+}
+
+func baseSortValue(record Record, column string) (interface{}, bool) {
+	switch column {
+	case "record_id":
+		return fmt.Sprintf("%s:%d:%d", record.Topic, record.Partition, record.Offset), true
+	case "topic":
+		return record.Topic, true
+	case "partition":
+		return float64(record.Partition), true
+	case "offset":
+		return float64(record.Offset), true
+	case "timestamp_ms", "_ts":
+		return float64(record.Timestamp), true
+	case "key":
+		if record.Key == nil {
+			return nil, true
+		}
+		return string(record.Key), true
+	default:
+		return nil, false
+	}
+}
+
+// compareSortValues orders JSON-decoded values. Nulls sort first unless
+// nullsLast is set, regardless of direction.
+func compareSortValues(a, b interface{}, nullsLast bool) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		if nullsLast {
+			return 1
+		}
+		return -1
+	case b == nil:
+		if nullsLast {
+			return -1
+		}
+		return 1
+	}
+	switch av := a.(type) {
+	case float64:
+		if bv, ok := b.(float64); ok {
+			switch {
+			case av < bv:
+				return -1
+			case av > bv:
+				return 1
+			}
+			return 0
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv)
+		}
+	case bool:
+		if bv, ok := b.(bool); ok {
+			switch {
+			case av == bv:
+				return 0
+			case !av:
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+func TestPartitionSpecFromMapping(t *testing.T) {
+	columns := []config.Column{{Name: "customer_id", Type: "long"}, {Name: "region", Type: "string"}}
+	schema, _, err := (&icebergWriter{}).buildDesiredSchema(context.Background(), config.MappingSchemaConfig{Source: "mapping", Columns: columns}, "orders", nil)
+	if err != nil {
+		t.Fatalf("build schema: %v", err)
+	}
+
+	spec, err := partitionSpec(schema, []config.PartitionField{
+		{Column: "_ts", Transform: "day"},
+		{Column: "customer_id", Transform: "bucket[16]"},
+		{Column: "region", Transform: "identity"},
+	})
+	if err != nil {
+		t.Fatalf("partitionSpec: %v", err)
+	}
+	if spec.NumFields() != 3 {
+		t.Fatalf("expected 3 partition fields, got %d", spec.NumFields())
+	}
+	names := []string{"_ts_day", "customer_id_bucket", "region"}
+	for i, name := range names {
+		if field := spec.Field(i); field.Name != name || field.FieldID != partitionFieldIDStart+i {
+			t.Fatalf("unexpected partition field %d: %+v", i, field)
+		}
+	}
+
+	if _, err := partitionSpec(schema, []config.PartitionField{{Column: "region", Transform: "day"}}); err == nil {
+		t.Fatalf("expected error for day transform on a string column")
+	}
+	if _, err := partitionSpec(schema, []config.PartitionField{{Column: "missing", Transform: "identity"}}); err == nil {
+		t.Fatalf("expected error for unknown partition column")
+	}
+}
+
+func TestSortOrderFromMapping(t *testing.T) {
+	schema, _, err := (&icebergWriter{}).buildDesiredSchema(context.Background(), config.MappingSchemaConfig{Source: "none"}, "orders", nil)
+	if err != nil {
+		t.Fatalf("build schema: %v", err)
+	}
+	order, err := sortOrder(schema, []config.SortField{{Column: "offset", Direction: "desc", NullOrder: "last"}})
+	if err != nil {
+		t.Fatalf("sortOrder: %v", err)
+	}
+	if order.Len() != 1 {
+		t.Fatalf("expected 1 sort field, got %d", order.Len())
+	}
+	for field := range order.Fields() {
+		if field.Direction != table.SortDESC || field.NullOrder != table.NullsLast {
+			t.Fatalf("unexpected sort field: %+v", field)
+		}
+	}
+}
+
+func TestSortRecords(t *testing.T) {
+	records := []Record{
+		{Offset: 1, Value: []byte(`{"region":"eu","amount":5}`)},
+		{Offset: 2, Value: []byte(`{"region":"us","amount":7}`)},
+		{Offset: 3, Value: []byte(`{"amount":1}`)},
+		{Offset: 4, Value: []byte(`{"region":"eu","amount":9}`)},
+	}
+
+	sorted := sortRecords(records, []config.SortField{
+		{Column: "region", Direction: "asc", NullOrder: "last"},
+		{Column: "amount", Direction: "desc", NullOrder: "last"},
+	})
+	want := []int64{4, 1, 2, 3}
+	for i, offset := range want {
+		if sorted[i].Offset != offset {
+			t.Fatalf("unexpected order at %d: got offset %d, want %d", i, sorted[i].Offset, offset)
+		}
+	}
+	if records[0].Offset != 1 {
+		t.Fatalf("expected input slice to be left untouched")
+	}
+}
+
+func TestWriteProperties(t *testing.T) {
+	props := writeProperties(config.WriteConfig{TargetFileSizeBytes: 64 << 20, RowGroupLimit: 10000})
+	if props[table.WriteTargetFileSizeBytesKey] != "67108864" || props[table.ParquetRowGroupLimitKey] != "10000" {
+		t.Fatalf("unexpected write properties: %v", props)
+	}
+	if _, ok := props[table.ParquetRowGroupSizeBytesKey]; ok {
+		t.Fatalf("expected unset row group size to be omitted")
+	}
+}
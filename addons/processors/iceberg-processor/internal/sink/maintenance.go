@@ -0,0 +1,514 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	iceberg "github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+)
+
+const defaultTargetFileSizeBytes = 128 << 20
+
+// Maintain compacts small data files, expires old snapshots and deletes
+// orphaned files of the table mapped to topic. Tables that do not exist yet
+// are skipped.
+func (w *icebergWriter) Maintain(ctx context.Context, topic string) (MaintenanceResult, error) {
+	var result MaintenanceResult
+	mapping, ok := w.mappings[topic]
+	if !ok {
+		return result, fmt.Errorf("no table mapping for topic %q", topic)
+	}
+	tbl, err := w.catalog.LoadTable(ctx, mapping.identifier)
+	if err != nil {
+		if errors.Is(err, catalog.ErrNoSuchTable) {
+			return result, nil
+		}
+		return result, err
+	}
+
+	tbl, result.RewrittenFiles, result.AddedFiles, err = w.compact(ctx, tbl, mapping)
+	if err != nil {
+		return result, fmt.Errorf("compact %v: %w", mapping.identifier, err)
+	}
+	retention := time.Duration(w.maintenance.SnapshotRetentionHours) * time.Hour
+	tbl, result.ExpiredSnapshots, err = w.expireSnapshots(ctx, tbl, retention, w.maintenance.RetainLastSnapshots)
+	if err != nil {
+		return result, fmt.Errorf("expire snapshots of %v: %w", mapping.identifier, err)
+	}
+	w.mu.Lock()
+	if _, cached := w.tables[topic]; cached {
+		w.tables[topic] = tbl
+	}
+	w.mu.Unlock()
+
+	olderThan := time.Duration(w.maintenance.OrphanOlderThanHours) * time.Hour
+	cleanup, err := tbl.DeleteOrphanFiles(ctx,
+		table.WithFilesOlderThan(olderThan),
+		table.WithPrefixMismatchMode(table.PrefixMismatchIgnore))
+	if err != nil {
+		return result, fmt.Errorf("delete orphan files of %v: %w", mapping.identifier, err)
+	}
+	result.DeletedOrphans = len(cleanup.DeletedFiles)
+	return result, nil
+}
+
+// compact merges small data files of the current partition spec, one bin of
+// files per commit. Each commit appends the merged rows and removes the
+// inputs in a single transaction, and carries the offset watermarks forward
+// so that offset recovery never has to look past it.
+//
+// The rewritten rows get a newer sequence number than every existing delete
+// file, so equality deletes are applied while the rows are read. Tables with
+// position deletes or partitioned equality deletes are not compacted.
+func (w *icebergWriter) compact(ctx context.Context, tbl *table.Table, mapping tableMapping) (*table.Table, int, int, error) {
+	snap := tbl.CurrentSnapshot()
+	if snap == nil {
+		return tbl, 0, 0, nil
+	}
+	fsys, err := tbl.FS(ctx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	manifests, err := snap.Manifests(fsys)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	spec := tbl.Spec()
+	specID := int32(spec.ID())
+	var files []iceberg.DataFile
+	dataSeqs := make(map[string]int64)
+	var deleteEntries []iceberg.ManifestEntry
+	for _, manifest := range manifests {
+		entries, err := manifest.FetchEntries(fsys, true)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		for _, entry := range entries {
+			df := entry.DataFile()
+			if manifest.ManifestContent() == iceberg.ManifestContentDeletes {
+				if df.ContentType() != iceberg.EntryContentEqDeletes || !specUnpartitioned(tbl.Metadata(), int(df.SpecID())) {
+					log.Printf("iceberg: skipping compaction of %v, table has position or partitioned equality deletes", mapping.identifier)
+					return tbl, 0, 0, nil
+				}
+				deleteEntries = append(deleteEntries, entry)
+				continue
+			}
+			if df.SpecID() == specID {
+				files = append(files, df)
+				dataSeqs[df.FilePath()] = entry.SequenceNum()
+			}
+		}
+	}
+
+	target := mapping.write.TargetFileSizeBytes
+	if target <= 0 {
+		target = defaultTargetFileSizeBytes
+	}
+	small := w.maintenance.Compaction.SmallFileBytes
+	if small <= 0 {
+		small = target * 3 / 4
+	}
+	bins := planCompaction(files, small, target, w.maintenance.Compaction.MinInputFiles)
+	if len(bins) == 0 {
+		return tbl, 0, 0, nil
+	}
+
+	schema := tbl.Schema()
+	arrowSchema, err := table.SchemaToArrowSchema(schema, nil, true, false)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	deletes, err := loadEqualityDeletes(ctx, fsys, schema, deleteEntries)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("load equality deletes: %w", err)
+	}
+	var rewritten, added int
+	for _, bin := range bins {
+		updated, err := rewriteFiles(ctx, tbl, fsys, schema, arrowSchema, bin, dataSeqs, deletes)
+		if err != nil {
+			if isCommitConflict(err) {
+				log.Printf("iceberg: compaction of %v lost a commit race, retrying next run", mapping.identifier)
+				break
+			}
+			return nil, rewritten, added, err
+		}
+		tbl = updated
+		rewritten += len(bin)
+		added += addedDataFiles(tbl)
+	}
+	return tbl, rewritten, added, nil
+}
+
+func rewriteFiles(ctx context.Context, tbl *table.Table, fsys iceio.IO, schema *iceberg.Schema, arrowSchema *arrow.Schema, bin []iceberg.DataFile, dataSeqs map[string]int64, deletes *equalityDeletes) (*table.Table, error) {
+	var batches []arrow.RecordBatch
+	defer func() {
+		for _, batch := range batches {
+			batch.Release()
+		}
+	}()
+	paths := make([]string, 0, len(bin))
+	for _, df := range bin {
+		recs, err := readDataFile(ctx, fsys, df.FilePath(), schema)
+		for i := range recs {
+			if err != nil {
+				break
+			}
+			recs[i], err = deletes.filter(ctx, recs[i], dataSeqs[df.FilePath()])
+		}
+		batches = append(batches, recs...)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, df.FilePath())
+	}
+	rdr, err := array.NewRecordReader(arrowSchema, batches)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Release()
+
+	props := offsetProperties(tbl)
+	tx := tbl.NewTransaction()
+	if err := tx.Append(ctx, rdr, props); err != nil {
+		return nil, err
+	}
+	if err := tx.ReplaceDataFiles(ctx, paths, nil, props); err != nil {
+		return nil, err
+	}
+	return tx.Commit(ctx)
+}
+
+// addedDataFiles counts the files written by the append half of the last
+// compaction commit, which is the parent of the current snapshot.
+func addedDataFiles(tbl *table.Table) int {
+	snap := tbl.CurrentSnapshot()
+	if snap == nil || snap.ParentSnapshotID == nil {
+		return 0
+	}
+	parent := tbl.SnapshotByID(*snap.ParentSnapshotID)
+	if parent == nil || parent.Summary == nil {
+		return 0
+	}
+	count, _ := summaryCount(parent.Summary.Properties, "added-data-files")
+	return count
+}
+
+// planCompaction groups files below smallFileBytes by partition and packs
+// each group, smallest first, into bins of at most targetBytes. Bins with
+// fewer than minInputs files are left alone.
+func planCompaction(files []iceberg.DataFile, smallFileBytes, targetBytes int64, minInputs int) [][]iceberg.DataFile {
+	groups := make(map[string][]iceberg.DataFile)
+	for _, df := range files {
+		if df.FileSizeBytes() >= smallFileBytes {
+			continue
+		}
+		key := partitionKey(df)
+		groups[key] = append(groups[key], df)
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var bins [][]iceberg.DataFile
+	for _, key := range keys {
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].FileSizeBytes() < group[j].FileSizeBytes()
+		})
+		var bin []iceberg.DataFile
+		var size int64
+		for _, df := range group {
+			if len(bin) > 0 && size+df.FileSizeBytes() > targetBytes {
+				if len(bin) >= minInputs {
+					bins = append(bins, bin)
+				}
+				bin, size = nil, 0
+			}
+			bin = append(bin, df)
+			size += df.FileSizeBytes()
+		}
+		if len(bin) >= minInputs {
+			bins = append(bins, bin)
+		}
+	}
+	return bins
+}
+
+func partitionKey(df iceberg.DataFile) string {
+	values := df.Partition()
+	ids := make([]int, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d", df.SpecID())
+	for _, id := range ids {
+		fmt.Fprintf(&b, "/%d=%v", id, values[id])
+	}
+	return b.String()
+}
+
+// readDataFile reads a Parquet data file and projects it onto schema, so that
+// files written before a schema change line up with newer ones.
+func readDataFile(ctx context.Context, fsys iceio.IO, path string, schema *iceberg.Schema) ([]arrow.RecordBatch, error) {
+	var out []arrow.RecordBatch
+	var fileSchema *iceberg.Schema
+	err := readFileBatches(ctx, fsys, path, func(rec arrow.RecordBatch) error {
+		if fileSchema == nil {
+			var err error
+			if fileSchema, err = table.ArrowSchemaToIceberg(rec.Schema(), false, nil); err != nil {
+				return err
+			}
+		}
+		projected, err := table.ToRequestedSchema(ctx, schema, fileSchema, rec, false, true, false)
+		if err != nil {
+			return err
+		}
+		out = append(out, projected)
+		return nil
+	})
+	return out, err
+}
+
+// readFileBatches hands each record batch of a Parquet file to fn.
+func readFileBatches(ctx context.Context, fsys iceio.IO, path string, fn func(arrow.RecordBatch) error) error {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	pf, err := file.NewParquetReader(f)
+	if err != nil {
+		return err
+	}
+	defer pf.Close()
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{BatchSize: 64 << 10}, memory.DefaultAllocator)
+	if err != nil {
+		return err
+	}
+	data, err := fr.ReadTable(ctx)
+	if err != nil {
+		return err
+	}
+	defer data.Release()
+
+	rdr := array.NewTableReader(data, 0)
+	defer rdr.Release()
+	for rdr.Next() {
+		if err := fn(rdr.RecordBatch()); err != nil {
+			return err
+		}
+	}
+	return rdr.Err()
+}
+
+func specUnpartitioned(meta table.Metadata, id int) bool {
+	for _, spec := range meta.PartitionSpecs() {
+		if spec.ID() == id {
+			return spec.IsUnpartitioned()
+		}
+	}
+	return false
+}
+
+// equalityDeletes holds the keys removed by a table's equality delete files,
+// grouped by delete columns, with the newest sequence number that deleted
+// each key.
+type equalityDeletes struct {
+	groups []*deleteGroup
+}
+
+type deleteGroup struct {
+	columns []string
+	seqs    map[string]int64
+}
+
+func loadEqualityDeletes(ctx context.Context, fsys iceio.IO, schema *iceberg.Schema, entries []iceberg.ManifestEntry) (*equalityDeletes, error) {
+	deletes := &equalityDeletes{}
+	for _, entry := range entries {
+		df := entry.DataFile()
+		columns := make([]string, 0, len(df.EqualityFieldIDs()))
+		for _, id := range df.EqualityFieldIDs() {
+			name, ok := schema.FindColumnName(id)
+			if !ok {
+				return nil, fmt.Errorf("delete file %s references unknown field %d", df.FilePath(), id)
+			}
+			columns = append(columns, name)
+		}
+		group := deletes.group(columns)
+		err := readFileBatches(ctx, fsys, df.FilePath(), func(rec arrow.RecordBatch) error {
+			cols, err := keyColumns(rec, columns)
+			if err != nil {
+				return fmt.Errorf("delete file %s: %w", df.FilePath(), err)
+			}
+			for row := 0; row < int(rec.NumRows()); row++ {
+				key := rowKey(cols, row)
+				if seq, ok := group.seqs[key]; !ok || entry.SequenceNum() > seq {
+					group.seqs[key] = entry.SequenceNum()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return deletes, nil
+}
+
+func (d *equalityDeletes) group(columns []string) *deleteGroup {
+	for _, group := range d.groups {
+		if slices.Equal(group.columns, columns) {
+			return group
+		}
+	}
+	group := &deleteGroup{columns: columns, seqs: make(map[string]int64)}
+	d.groups = append(d.groups, group)
+	return group
+}
+
+// filter drops the rows of rec that a delete with a sequence number above
+// dataSeq removed, releasing rec when it returns a new batch.
+func (d *equalityDeletes) filter(ctx context.Context, rec arrow.RecordBatch, dataSeq int64) (arrow.RecordBatch, error) {
+	if d == nil || len(d.groups) == 0 {
+		return rec, nil
+	}
+	mask := array.NewBooleanBuilder(memory.DefaultAllocator)
+	defer mask.Release()
+	dropped := false
+	groupCols := make([][]arrow.Array, len(d.groups))
+	for i, group := range d.groups {
+		cols, err := keyColumns(rec, group.columns)
+		if err != nil {
+			return rec, err
+		}
+		groupCols[i] = cols
+	}
+	for row := 0; row < int(rec.NumRows()); row++ {
+		keep := true
+		for i, group := range d.groups {
+			if seq, ok := group.seqs[rowKey(groupCols[i], row)]; ok && seq > dataSeq {
+				keep = false
+				break
+			}
+		}
+		dropped = dropped || !keep
+		mask.Append(keep)
+	}
+	if !dropped {
+		return rec, nil
+	}
+	filter := mask.NewBooleanArray()
+	defer filter.Release()
+	out, err := compute.FilterRecordBatch(ctx, rec, filter, compute.DefaultFilterOptions())
+	if err != nil {
+		return rec, err
+	}
+	rec.Release()
+	return out, nil
+}
+
+func keyColumns(rec arrow.RecordBatch, columns []string) ([]arrow.Array, error) {
+	cols := make([]arrow.Array, 0, len(columns))
+	for _, name := range columns {
+		indices := rec.Schema().FieldIndices(name)
+		if len(indices) == 0 {
+			return nil, fmt.Errorf("missing delete column %q", name)
+		}
+		cols = append(cols, rec.Column(indices[0]))
+	}
+	return cols, nil
+}
+
+func rowKey(cols []arrow.Array, row int) string {
+	var b strings.Builder
+	for i, col := range cols {
+		if i > 0 {
+			b.WriteByte(0)
+		}
+		if col.IsNull(row) {
+			b.WriteByte(1)
+			continue
+		}
+		b.WriteString(col.ValueStr(row))
+	}
+	return b.String()
+}
+
+// expireSnapshots removes snapshots older than retention. Branch heads,
+// tags and the last retainLast snapshots of each branch are always kept.
+//
+// Expiry is computed here rather than with Transaction.ExpireSnapshots,
+// which fails once a branch's oldest kept snapshot points at a parent that
+// an earlier run expired.
+func (w *icebergWriter) expireSnapshots(ctx context.Context, tbl *table.Table, retention time.Duration, retainLast int) (*table.Table, int, error) {
+	meta := tbl.Metadata()
+	cutoff := time.Now().Add(-retention).UnixMilli()
+	keep := make(map[int64]bool)
+	for _, ref := range meta.Refs() {
+		keep[ref.SnapshotID] = true
+		if ref.SnapshotRefType != table.BranchRef {
+			continue
+		}
+		snap := meta.SnapshotByID(ref.SnapshotID)
+		for n := 0; snap != nil; n++ {
+			if n >= retainLast && snap.TimestampMs < cutoff {
+				break
+			}
+			keep[snap.SnapshotID] = true
+			if snap.ParentSnapshotID == nil {
+				break
+			}
+			snap = meta.SnapshotByID(*snap.ParentSnapshotID)
+		}
+	}
+	var expired []int64
+	for _, snap := range meta.Snapshots() {
+		if !keep[snap.SnapshotID] && snap.TimestampMs < cutoff {
+			expired = append(expired, snap.SnapshotID)
+		}
+	}
+	if len(expired) == 0 {
+		return tbl, 0, nil
+	}
+	reqs := []table.Requirement{table.AssertTableUUID(meta.TableUUID())}
+	updates := []table.Update{table.NewRemoveSnapshotsUpdate(expired)}
+	committed, location, err := w.catalog.CommitTable(ctx, tbl.Identifier(), reqs, updates)
+	if err != nil {
+		return nil, 0, err
+	}
+	return table.New(tbl.Identifier(), committed, location, tbl.FS, w.catalog), len(expired), nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	iceberg "github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+func TestPlanCompaction(t *testing.T) {
+	spec := iceberg.NewPartitionSpecID(1, iceberg.PartitionField{SourceID: 1, FieldID: 1000, Name: "region", Transform: iceberg.IdentityTransform{}})
+	dataFile := func(name, region string, size int64) iceberg.DataFile {
+		b, err := iceberg.NewDataFileBuilder(spec, iceberg.EntryContentData, name, iceberg.ParquetFile, map[int]any{1000: region}, nil, nil, 1, size)
+		if err != nil {
+			t.Fatalf("data file: %v", err)
+		}
+		return b.Build()
+	}
+	files := []iceberg.DataFile{
+		dataFile("eu-1", "eu", 10), dataFile("eu-2", "eu", 20), dataFile("eu-3", "eu", 30),
+		dataFile("eu-4", "eu", 60), dataFile("eu-big", "eu", 500),
+		dataFile("us-1", "us", 10), dataFile("us-2", "us", 10),
+	}
+
+	bins := planCompaction(files, 100, 70, 3)
+	if len(bins) != 1 {
+		t.Fatalf("expected one bin, got %d", len(bins))
+	}
+	var paths []string
+	for _, df := range bins[0] {
+		paths = append(paths, df.FilePath())
+	}
+	if fmt.Sprint(paths) != "[eu-1 eu-2 eu-3]" {
+		t.Fatalf("unexpected bin %v", paths)
+	}
+
+	if bins := planCompaction(files, 100, 70, 2); len(bins) != 2 {
+		t.Fatalf("expected the us partition to be compacted with min inputs 2, got %d bins", len(bins))
+	}
+	if bins := planCompaction(files, 100, 1000, 2); len(bins) != 2 || len(bins[0]) != 4 {
+		t.Fatalf("expected one bin per partition, got %v", bins)
+	}
+}
+
+func TestMaintainCompactsExpiresAndCleansUp(t *testing.T) {
+	ctx := context.Background()
+	columns := []config.Column{{Name: "order_id", Type: "long"}}
+	desired, _, err := (&icebergWriter{}).buildDesiredSchema(ctx, config.MappingSchemaConfig{Source: "mapping", Columns: columns}, "orders", nil)
+	if err != nil {
+		t.Fatalf("build schema: %v", err)
+	}
+	arrowSchema, err := tableSchemaToArrow(desired)
+	if err != nil {
+		t.Fatalf("arrow schema: %v", err)
+	}
+
+	location := t.TempDir()
+	meta, err := table.NewMetadata(desired, iceberg.UnpartitionedSpec, table.UnsortedSortOrder, location, iceberg.Properties{})
+	if err != nil {
+		t.Fatalf("metadata init: %v", err)
+	}
+	ident := table.Identifier{"demo", "orders"}
+	cat := &fakeCatalog{meta: meta, fs: io.LocalFS{}}
+	tbl, err := cat.LoadTable(ctx, ident)
+	if err != nil {
+		t.Fatalf("load table: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		rdr, err := recordsToArrow(arrowSchema, columns, []Record{
+			{Topic: "orders", Offset: int64(i), Value: []byte(fmt.Sprintf(`{"order_id":%d}`, i))},
+		})
+		if err != nil {
+			t.Fatalf("records: %v", err)
+		}
+		tbl, err = tbl.Append(ctx, rdr, iceberg.Properties{offsetProperty("orders", 0): fmt.Sprint(i)})
+		rdr.Release()
+		if err != nil {
+			t.Fatalf("append %d: %v", i, err)
+		}
+	}
+
+	orphan := filepath.Join(location, "data", "orphan.parquet")
+	if err := os.WriteFile(orphan, []byte("stale"), 0o644); err != nil {
+		t.Fatalf("write orphan: %v", err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(orphan, old, old); err != nil {
+		t.Fatalf("age orphan: %v", err)
+	}
+
+	writer := &icebergWriter{
+		catalog:  cat,
+		mappings: map[string]tableMapping{"orders": {identifier: ident, mode: modeAppend}},
+		tables:   map[string]*table.Table{},
+		maintenance: config.MaintenanceConfig{
+			Compaction:           config.CompactionConfig{MinInputFiles: 2},
+			RetainLastSnapshots:  1,
+			OrphanOlderThanHours: 24,
+		},
+	}
+	result, err := writer.Maintain(ctx, "orders")
+	if err != nil {
+		t.Fatalf("Maintain: %v", err)
+	}
+	if result.RewrittenFiles != 4 || result.AddedFiles != 1 {
+		t.Fatalf("expected 4 files rewritten into 1, got %+v", result)
+	}
+	if result.ExpiredSnapshots == 0 {
+		t.Fatalf("expected snapshots to expire, got %+v", result)
+	}
+	if result.DeletedOrphans != 1 {
+		t.Fatalf("expected the orphan to be deleted, got %+v", result)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Fatalf("expected orphan file to be removed, stat err=%v", err)
+	}
+
+	tbl, _ = cat.LoadTable(ctx, ident)
+	if offsets := parseOffsets(offsetProperties(tbl), "orders"); offsets[0] != 3 {
+		t.Fatalf("expected offsets to survive compaction and expiry, got %v", offsets)
+	}
+	if n := len(tbl.Metadata().Snapshots()); n != 1 {
+		t.Fatalf("expected one snapshot after expiry, got %d", n)
+	}
+	scan, err := tbl.Scan().ToArrowTable(ctx)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	defer scan.Release()
+	if scan.NumRows() != 4 {
+		t.Fatalf("expected 4 rows after compaction, got %d", scan.NumRows())
+	}
+	fsys, _ := tbl.FS(ctx)
+	manifests, err := tbl.CurrentSnapshot().Manifests(fsys)
+	if err != nil {
+		t.Fatalf("manifests: %v", err)
+	}
+	live := 0
+	for _, manifest := range manifests {
+		entries, err := manifest.FetchEntries(fsys, true)
+		if err != nil {
+			t.Fatalf("entries: %v", err)
+		}
+		live += len(entries)
+	}
+	if live != 1 {
+		t.Fatalf("expected one live data file, got %d", live)
+	}
+
+	// A second run finds nothing left to do.
+	result, err = writer.Maintain(ctx, "orders")
+	if err != nil {
+		t.Fatalf("second Maintain: %v", err)
+	}
+	if result.RewrittenFiles != 0 || result.ExpiredSnapshots != 0 {
+		t.Fatalf("expected an idle second run, got %+v", result)
+	}
+}
+
+func TestCompactAppliesEqualityDeletes(t *testing.T) {
+	ctx := context.Background()
+	columns := []config.Column{{Name: "order_id", Type: "long"}, {Name: "amount", Type: "long"}}
+	desired, _, err := (&icebergWriter{}).buildDesiredSchema(ctx, config.MappingSchemaConfig{Source: "mapping", Columns: columns}, "orders", nil)
+	if err != nil {
+		t.Fatalf("build schema: %v", err)
+	}
+	arrowSchema, err := tableSchemaToArrow(desired)
+	if err != nil {
+		t.Fatalf("arrow schema: %v", err)
+	}
+	schema := &topicSchema{iceberg: desired, arrow: arrowSchema, columns: columns}
+	meta, err := table.NewMetadata(desired, iceberg.UnpartitionedSpec, table.UnsortedSortOrder, t.TempDir(), iceberg.Properties{})
+	if err != nil {
+		t.Fatalf("metadata init: %v", err)
+	}
+	ident := table.Identifier{"demo", "orders"}
+	cat := &fakeCatalog{meta: meta, fs: io.LocalFS{}}
+	tbl, _ := cat.LoadTable(ctx, ident)
+	mapping := tableMapping{identifier: ident, mode: modeUpsert, keyColumns: []string{"order_id"}}
+	writer := &icebergWriter{catalog: cat, maintenance: config.MaintenanceConfig{Compaction: config.CompactionConfig{MinInputFiles: 2}}}
+	values := []string{`{"order_id":1,"amount":10}`, `{"order_id":2,"amount":20}`, `{"order_id":1,"amount":11}`}
+	for i, value := range values {
+		batch, err := collapseUpserts([]Record{{Topic: "orders", Offset: int64(i), Value: []byte(value)}}, mapping)
+		if err != nil {
+			t.Fatalf("collapseUpserts: %v", err)
+		}
+		if tbl, err = writer.commitUpsert(ctx, tbl, schema, mapping, batch, iceberg.Properties{}); err != nil {
+			t.Fatalf("commitUpsert: %v", err)
+		}
+	}
+
+	tbl, rewritten, added, err := writer.compact(ctx, tbl, mapping)
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if rewritten != 3 || added != 1 {
+		t.Fatalf("expected 3 files rewritten into 1, got %d and %d", rewritten, added)
+	}
+	fsys, err := tbl.FS(ctx)
+	if err != nil {
+		t.Fatalf("fs: %v", err)
+	}
+	manifests, err := tbl.CurrentSnapshot().Manifests(fsys)
+	if err != nil {
+		t.Fatalf("manifests: %v", err)
+	}
+	var rows []string
+	for _, manifest := range manifests {
+		if manifest.ManifestContent() != iceberg.ManifestContentData {
+			continue
+		}
+		entries, err := manifest.FetchEntries(fsys, true)
+		if err != nil {
+			t.Fatalf("entries: %v", err)
+		}
+		for _, entry := range entries {
+			recs, err := readDataFile(ctx, fsys, entry.DataFile().FilePath(), tbl.Schema())
+			if err != nil {
+				t.Fatalf("read data file: %v", err)
+			}
+			for _, rec := range recs {
+				orderIDs, _ := keyColumns(rec, []string{"order_id"})
+				amounts, _ := keyColumns(rec, []string{"amount"})
+				for row := 0; row < int(rec.NumRows()); row++ {
+					rows = append(rows, rowKey(orderIDs, row)+"="+rowKey(amounts, row))
+				}
+				rec.Release()
+			}
+		}
+	}
+	sort.Strings(rows)
+	if fmt.Sprint(rows) != "[1=11 2=20]" {
+		t.Fatalf("expected only the latest row per key, got %v", rows)
+	}
+}
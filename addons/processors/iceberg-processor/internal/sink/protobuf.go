@@ -0,0 +1,315 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+const protoSchemaFile = "kafscale-registry-schema.proto"
+
+// protoWrappers maps the well-known wrapper messages to their column type.
+var protoWrappers = map[protoreflect.FullName]string{
+	"google.protobuf.BoolValue":   "boolean",
+	"google.protobuf.Int32Value":  "int",
+	"google.protobuf.UInt32Value": "long",
+	"google.protobuf.Int64Value":  "long",
+	"google.protobuf.UInt64Value": "long",
+	"google.protobuf.FloatValue":  "float",
+	"google.protobuf.DoubleValue": "double",
+	"google.protobuf.StringValue": "string",
+	"google.protobuf.BytesValue":  "binary",
+}
+
+const protoTimestamp protoreflect.FullName = "google.protobuf.Timestamp"
+
+// compileProto compiles a registry .proto schema. Imports resolve to the
+// schema's references, then to the well-known types.
+func compileProto(ctx context.Context, source string, refs map[string]string) (protoreflect.FileDescriptor, error) {
+	sources := make(map[string]string, len(refs)+1)
+	for name, ref := range refs {
+		sources[name] = ref
+	}
+	sources[protoSchemaFile] = source
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	files, err := compiler.Compile(ctx, protoSchemaFile)
+	if err != nil {
+		return nil, err
+	}
+	return files[0], nil
+}
+
+// protoMessage finds a message by its Confluent message indexes: the index
+// of a top-level message followed by the indexes of nested messages.
+func protoMessage(fd protoreflect.FileDescriptor, indexes []int) (protoreflect.MessageDescriptor, error) {
+	messages := fd.Messages()
+	var md protoreflect.MessageDescriptor
+	for _, index := range indexes {
+		if index < 0 || index >= messages.Len() {
+			return nil, fmt.Errorf("message index %v not in schema", indexes)
+		}
+		md = messages.Get(index)
+		messages = md.Messages()
+	}
+	if md == nil {
+		return nil, fmt.Errorf("schema has no messages")
+	}
+	return md, nil
+}
+
+// protoMessageByName finds a message, including nested ones, by its full
+// name. Names without the schema's package are resolved within it.
+func protoMessageByName(fd protoreflect.FileDescriptor, name string) (protoreflect.MessageDescriptor, error) {
+	want := protoreflect.FullName(name)
+	if fd.Package() != "" && !strings.HasPrefix(name, string(fd.Package())+".") {
+		want = protoreflect.FullName(string(fd.Package()) + "." + name)
+	}
+	var find func(messages protoreflect.MessageDescriptors) protoreflect.MessageDescriptor
+	find = func(messages protoreflect.MessageDescriptors) protoreflect.MessageDescriptor {
+		for i := 0; i < messages.Len(); i++ {
+			md := messages.Get(i)
+			if md.FullName() == want {
+				return md
+			}
+			if nested := find(md.Messages()); nested != nil {
+				return nested
+			}
+		}
+		return nil
+	}
+	if md := find(fd.Messages()); md != nil {
+		return md, nil
+	}
+	return nil, fmt.Errorf("message %q not in schema", name)
+}
+
+// protoColumns maps the fields of a message to columns. Messages become
+// structs, repeated fields lists and map fields maps with string keys.
+// Timestamps and wrapper messages map to their primitive type. Recursive
+// messages are skipped.
+func protoColumns(md protoreflect.MessageDescriptor) []config.Column {
+	return protoFields(md, map[protoreflect.FullName]bool{})
+}
+
+func protoFields(md protoreflect.MessageDescriptor, visiting map[protoreflect.FullName]bool) []config.Column {
+	visiting[md.FullName()] = true
+	defer delete(visiting, md.FullName())
+
+	fields := md.Fields()
+	columns := make([]config.Column, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		var (
+			col config.Column
+			ok  bool
+		)
+		switch {
+		case fd.IsMap():
+			var value config.Column
+			if value, ok = protoColumn(fd.MapValue(), visiting); ok {
+				col = config.Column{Type: "map", Element: &value}
+			}
+		case fd.IsList():
+			var element config.Column
+			if element, ok = protoColumn(fd, visiting); ok {
+				col = config.Column{Type: "list", Element: &element}
+			}
+		default:
+			col, ok = protoColumn(fd, visiting)
+		}
+		if !ok {
+			continue
+		}
+		col.Name = string(fd.Name())
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// protoColumn maps the type of a single field value, ignoring cardinality.
+func protoColumn(fd protoreflect.FieldDescriptor, visiting map[protoreflect.FullName]bool) (config.Column, bool) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return config.Column{Type: "boolean"}, true
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return config.Column{Type: "int"}, true
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return config.Column{Type: "long"}, true
+	case protoreflect.FloatKind:
+		return config.Column{Type: "float"}, true
+	case protoreflect.DoubleKind:
+		return config.Column{Type: "double"}, true
+	case protoreflect.StringKind, protoreflect.EnumKind:
+		return config.Column{Type: "string"}, true
+	case protoreflect.BytesKind:
+		return config.Column{Type: "binary"}, true
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		md := fd.Message()
+		if md.FullName() == protoTimestamp {
+			return config.Column{Type: "timestamp"}, true
+		}
+		if typ, ok := protoWrappers[md.FullName()]; ok {
+			return config.Column{Type: typ}, true
+		}
+		if visiting[md.FullName()] {
+			return config.Column{}, false
+		}
+		fields := protoFields(md, visiting)
+		if len(fields) == 0 {
+			return config.Column{}, false
+		}
+		return config.Column{Type: "struct", Fields: fields}, true
+	}
+	return config.Column{}, false
+}
+
+// decodeProto reads the Confluent message indexes that precede a Protobuf
+// body and decodes the message they select.
+func decodeProto(fd protoreflect.FileDescriptor, body []byte) (map[string]interface{}, error) {
+	indexes, body, err := protoMessageIndexes(body)
+	if err != nil {
+		return nil, err
+	}
+	md, err := protoMessage(fd, indexes)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(body, msg); err != nil {
+		return nil, err
+	}
+	return protoValues(msg), nil
+}
+
+// protoMessageIndexes reads a zigzag varint count followed by that many
+// zigzag varint indexes. A count of zero is shorthand for the first message.
+func protoMessageIndexes(body []byte) ([]int, []byte, error) {
+	readInt := func() (int, error) {
+		v, n := protowire.ConsumeVarint(body)
+		if n < 0 {
+			return 0, fmt.Errorf("invalid protobuf message index")
+		}
+		body = body[n:]
+		return int(protowire.DecodeZigZag(v)), nil
+	}
+	count, err := readInt()
+	if err != nil {
+		return nil, nil, err
+	}
+	if count == 0 {
+		return []int{0}, body, nil
+	}
+	if count < 0 || count > len(body) {
+		return nil, nil, fmt.Errorf("invalid protobuf message index count %d", count)
+	}
+	indexes := make([]int, count)
+	for i := range indexes {
+		if indexes[i], err = readInt(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return indexes, body, nil
+}
+
+// protoValues converts a message to row values. Unset fields with presence
+// are left out; scalars without presence carry their default value.
+func protoValues(msg protoreflect.Message) map[string]interface{} {
+	md := msg.Descriptor()
+	fields := md.Fields()
+	out := make(map[string]interface{}, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.HasPresence() && !msg.Has(fd) {
+			continue
+		}
+		value := msg.Get(fd)
+		switch {
+		case fd.IsMap():
+			entries := make(map[string]interface{}, value.Map().Len())
+			value.Map().Range(func(key protoreflect.MapKey, item protoreflect.Value) bool {
+				entries[key.String()] = protoValue(fd.MapValue(), item)
+				return true
+			})
+			out[string(fd.Name())] = entries
+		case fd.IsList():
+			list := value.List()
+			items := make([]interface{}, list.Len())
+			for j := 0; j < list.Len(); j++ {
+				items[j] = protoValue(fd, list.Get(j))
+			}
+			out[string(fd.Name())] = items
+		default:
+			out[string(fd.Name())] = protoValue(fd, value)
+		}
+	}
+	return out
+}
+
+func protoValue(fd protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return value.Bool()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return value.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return int64(value.Uint())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return value.Float()
+	case protoreflect.StringKind:
+		return value.String()
+	case protoreflect.BytesKind:
+		return value.Bytes()
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(value.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(value.Enum()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		msg := value.Message()
+		name := msg.Descriptor().FullName()
+		if name == protoTimestamp {
+			fields := msg.Descriptor().Fields()
+			seconds := msg.Get(fields.ByName("seconds")).Int()
+			nanos := msg.Get(fields.ByName("nanos")).Int()
+			return time.Unix(seconds, nanos).UTC()
+		}
+		if _, ok := protoWrappers[name]; ok {
+			inner := msg.Descriptor().Fields().ByName("value")
+			return protoValue(inner, msg.Get(inner))
+		}
+		return protoValues(msg)
+	}
+	return nil
+}
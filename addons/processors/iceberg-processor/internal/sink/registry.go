@@ -0,0 +1,423 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+const (
+	registryStatic    = "static"
+	registryConfluent = "confluent"
+
+	schemaTypeAvro     = "AVRO"
+	schemaTypeProtobuf = "PROTOBUF"
+	schemaTypeJSON     = "JSON"
+)
+
+// schemaRegistry resolves registry columns and, for Confluent-compatible
+// registries, decodes payloads in the Confluent wire format: a zero magic
+// byte, a big-endian schema ID and the Avro, Protobuf or JSON body.
+type schemaRegistry struct {
+	cfg      config.SchemaConfig
+	subjects map[string]string
+	messages map[string]string
+	debezium map[string]bool
+	baseURL  string
+	client   *http.Client
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	byID   map[int]*registrySchema
+	latest map[string]latestSchema
+	// indexes holds the Protobuf message indexes of the last payload decoded
+	// per subject.
+	indexes map[string][]int
+}
+
+// registrySchema is one parsed schema version. Schemas are immutable per ID,
+// so they are cached for the life of the process.
+type registrySchema struct {
+	id         int
+	schemaType string
+	avro       avro.Schema
+	proto      protoreflect.FileDescriptor
+	json       []byte
+}
+
+type latestSchema struct {
+	schema  *registrySchema
+	fetched time.Time
+}
+
+type registryResponse struct {
+	ID         int                 `json:"id"`
+	Version    int                 `json:"version"`
+	Schema     string              `json:"schema"`
+	SchemaType string              `json:"schemaType"`
+	References []registryReference `json:"references"`
+}
+
+type registryReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+func newSchemaRegistry(cfg config.SchemaConfig, mappings []config.Mapping) *schemaRegistry {
+	if cfg.Registry.BaseURL == "" {
+		return nil
+	}
+	subjects := make(map[string]string, len(mappings))
+	messages := make(map[string]string)
+	debezium := make(map[string]bool)
+	for _, mapping := range mappings {
+		debezium[mapping.Topic] = mapping.Envelope == envelopeDebezium
+		messages[mapping.Topic] = mapping.Schema.Message
+		subjects[mapping.Topic] = mapping.Schema.Subject
+		if subjects[mapping.Topic] == "" {
+			subjects[mapping.Topic] = mapping.Topic + "-value"
+		}
+	}
+	timeout := time.Duration(cfg.Registry.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	cacheTTL := time.Duration(cfg.Registry.CacheSeconds) * time.Second
+	if cacheTTL == 0 {
+		cacheTTL = 5 * time.Minute
+	}
+	return &schemaRegistry{
+		cfg:      cfg,
+		subjects: subjects,
+		messages: messages,
+		debezium: debezium,
+		baseURL:  strings.TrimRight(cfg.Registry.BaseURL, "/"),
+		client:   &http.Client{Timeout: timeout},
+		cacheTTL: cacheTTL,
+		byID:     make(map[int]*registrySchema),
+		latest:   make(map[string]latestSchema),
+		indexes:  make(map[string][]int),
+	}
+}
+
+func (r *schemaRegistry) confluent() bool {
+	return r != nil && r.cfg.Registry.Type == registryConfluent
+}
+
+// allowWidening reports whether type widening is applied for a mapping. A
+// Confluent registry only accepts compatible versions, so its promotions are
+// applied without opting in.
+func (r *schemaRegistry) allowWidening(schemaCfg config.MappingSchemaConfig) bool {
+	return schemaCfg.AllowTypeWidening || (schemaCfg.Source == "registry" && r.confluent())
+}
+
+// columns returns the columns of the topic's schema: the static JSON Schema
+// document, or the latest version of the topic's Confluent subject.
+func (r *schemaRegistry) columns(ctx context.Context, topic string) ([]config.Column, error) {
+	if r == nil {
+		return nil, fmt.Errorf("schema.registry.base_url is required for registry columns")
+	}
+	if !r.confluent() {
+		return columnsFromRegistry(ctx, r.cfg, topic)
+	}
+	subject := r.subjects[topic]
+	if subject == "" {
+		subject = topic + "-value"
+	}
+	schema, err := r.latestSchema(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := schema.columns(r.messages[topic], r.messageIndexes(subject))
+	if err != nil {
+		return nil, fmt.Errorf("schema %d of subject %q: %w", schema.id, subject, err)
+	}
+	if r.debezium[topic] {
+		columns = debeziumRowColumns(columns)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns resolved from schema registry for topic %q", topic)
+	}
+	return columns, nil
+}
+
+// debeziumRowColumns returns the row columns of a Debezium envelope: the
+// fields of its after image.
+func debeziumRowColumns(columns []config.Column) []config.Column {
+	for _, col := range columns {
+		if col.Name == "after" && col.Type == "struct" {
+			return col.Fields
+		}
+	}
+	return nil
+}
+
+// columns returns the columns of the schema. Protobuf schemas may hold several
+// messages; the one named by message is used, else the one at indexes, else
+// the first.
+func (s *registrySchema) columns(message string, indexes []int) ([]config.Column, error) {
+	switch s.schemaType {
+	case schemaTypeAvro:
+		return avroColumns(s.avro)
+	case schemaTypeProtobuf:
+		var (
+			md  protoreflect.MessageDescriptor
+			err error
+		)
+		switch {
+		case message != "":
+			md, err = protoMessageByName(s.proto, message)
+		case len(indexes) > 0:
+			md, err = protoMessage(s.proto, indexes)
+		default:
+			md, err = protoMessage(s.proto, []int{0})
+		}
+		if err != nil {
+			return nil, err
+		}
+		return protoColumns(md), nil
+	default:
+		return columnsFromSchemaBytes(s.json)
+	}
+}
+
+func (r *schemaRegistry) latestSchema(ctx context.Context, subject string) (*registrySchema, error) {
+	r.mu.Lock()
+	cached, ok := r.latest[subject]
+	r.mu.Unlock()
+	if ok && time.Since(cached.fetched) < r.cacheTTL {
+		return cached.schema, nil
+	}
+
+	var resp registryResponse
+	if err := r.get(ctx, "/subjects/"+url.PathEscape(subject)+"/versions/latest", &resp); err != nil {
+		return nil, err
+	}
+	schema, err := r.parse(ctx, resp)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema %d of subject %q: %w", resp.ID, subject, err)
+	}
+	r.mu.Lock()
+	r.byID[resp.ID] = schema
+	r.latest[subject] = latestSchema{schema: schema, fetched: time.Now()}
+	r.mu.Unlock()
+	return schema, nil
+}
+
+func (r *schemaRegistry) schemaByID(ctx context.Context, id int) (*registrySchema, error) {
+	r.mu.Lock()
+	cached := r.byID[id]
+	r.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var resp registryResponse
+	if err := r.get(ctx, fmt.Sprintf("/schemas/ids/%d", id), &resp); err != nil {
+		return nil, err
+	}
+	resp.ID = id
+	schema, err := r.parse(ctx, resp)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema %d: %w", id, err)
+	}
+	r.mu.Lock()
+	r.byID[id] = schema
+	r.mu.Unlock()
+	return schema, nil
+}
+
+func (r *schemaRegistry) parse(ctx context.Context, resp registryResponse) (*registrySchema, error) {
+	schema := &registrySchema{id: resp.ID, schemaType: strings.ToUpper(resp.SchemaType)}
+	if schema.schemaType == "" {
+		schema.schemaType = schemaTypeAvro
+	}
+	refs, err := r.references(ctx, resp.References, map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	switch schema.schemaType {
+	case schemaTypeAvro:
+		cache := &avro.SchemaCache{}
+		// References are returned dependencies first, so named types are
+		// known by the time a schema uses them.
+		for _, ref := range resp.References {
+			if _, err := avro.ParseWithCache(refs[ref.Name], "", cache); err != nil {
+				return nil, fmt.Errorf("reference %q: %w", ref.Name, err)
+			}
+		}
+		schema.avro, err = avro.ParseWithCache(resp.Schema, "", cache)
+	case schemaTypeProtobuf:
+		schema.proto, err = compileProto(ctx, resp.Schema, refs)
+	case schemaTypeJSON:
+		schema.json = []byte(resp.Schema)
+	default:
+		err = fmt.Errorf("unsupported schema type %q", resp.SchemaType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// references fetches referenced schemas, and theirs in turn, by name.
+func (r *schemaRegistry) references(ctx context.Context, refs []registryReference, out map[string]string) (map[string]string, error) {
+	for _, ref := range refs {
+		if _, ok := out[ref.Name]; ok {
+			continue
+		}
+		var resp registryResponse
+		if err := r.get(ctx, fmt.Sprintf("/subjects/%s/versions/%d", url.PathEscape(ref.Subject), ref.Version), &resp); err != nil {
+			return nil, fmt.Errorf("reference %q: %w", ref.Name, err)
+		}
+		out[ref.Name] = resp.Schema
+		if _, err := r.references(ctx, resp.References, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (r *schemaRegistry) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("schema registry %s status %d", path, resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// decodeRecords decodes the Confluent-framed keys and values of records into
+// row values. Payloads without the magic byte are left to the JSON path. A
+// value with a newer schema ID than the cached latest version of its subject
+// drops the cached version, so the next schema check picks up the change.
+func (r *schemaRegistry) decodeRecords(ctx context.Context, records []Record) ([]Record, error) {
+	if !r.confluent() {
+		return records, nil
+	}
+	out := make([]Record, len(records))
+	for i, record := range records {
+		if id, body, ok := confluentFrame(record.Value); ok {
+			schema, err := r.schemaByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			values, err := decodePayload(schema, body)
+			if err != nil {
+				return nil, &RecordError{Record: record, Err: fmt.Errorf("decode value %s:%d:%d: %w", record.Topic, record.Partition, record.Offset, err)}
+			}
+			record.values = values
+			r.noteSchemaID(r.subjects[record.Topic], id)
+			if schema.schemaType == schemaTypeProtobuf {
+				if indexes, _, err := protoMessageIndexes(body); err == nil {
+					r.noteMessageIndexes(r.subjects[record.Topic], indexes)
+				}
+			}
+		}
+		if id, body, ok := confluentFrame(record.Key); ok {
+			schema, err := r.schemaByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			values, err := decodePayload(schema, body)
+			if err != nil {
+				return nil, &RecordError{Record: record, Err: fmt.Errorf("decode key %s:%d:%d: %w", record.Topic, record.Partition, record.Offset, err)}
+			}
+			record.keyValues = values
+		}
+		out[i] = record
+	}
+	return out, nil
+}
+
+func (r *schemaRegistry) noteMessageIndexes(subject string, indexes []int) {
+	if subject == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.indexes[subject] = indexes
+}
+
+func (r *schemaRegistry) messageIndexes(subject string) []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.indexes[subject]
+}
+
+func (r *schemaRegistry) noteSchemaID(subject string, id int) {
+	if subject == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, ok := r.latest[subject]; ok && cached.schema.id < id {
+		delete(r.latest, subject)
+	}
+}
+
+// decodePayload decodes the body of a registry-framed payload with its
+// writer schema.
+func decodePayload(schema *registrySchema, body []byte) (map[string]interface{}, error) {
+	switch schema.schemaType {
+	case schemaTypeAvro:
+		return decodeAvro(schema.avro, body)
+	case schemaTypeProtobuf:
+		return decodeProto(schema.proto, body)
+	default:
+		values := extractJSONValues(body)
+		if values == nil {
+			return nil, fmt.Errorf("payload is not a JSON object")
+		}
+		return values, nil
+	}
+}
+
+// confluentFrame splits a Confluent wire format payload into its schema ID
+// and body.
+func confluentFrame(payload []byte) (int, []byte, bool) {
+	if len(payload) < 5 || payload[0] != 0 {
+		return 0, nil, false
+	}
+	return int(binary.BigEndian.Uint32(payload[1:5])), payload[5:], true
+}
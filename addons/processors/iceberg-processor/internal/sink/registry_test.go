@@ -0,0 +1,379 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	iceberg "github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+// fakeRegistry serves the Confluent schema registry endpoints used by the
+// sink from an in-memory list of versions of one subject.
+type fakeRegistry struct {
+	mu       sync.Mutex
+	versions []registryResponse
+}
+
+func (f *fakeRegistry) register(schemaType, schema string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := 100 + len(f.versions)
+	f.versions = append(f.versions, registryResponse{ID: id, Version: len(f.versions) + 1, Schema: schema, SchemaType: schemaType})
+	return id
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path == "/subjects/orders-value/versions/latest" && len(f.versions) > 0 {
+		_ = json.NewEncoder(w).Encode(f.versions[len(f.versions)-1])
+		return
+	}
+	for _, version := range f.versions {
+		if r.URL.Path == fmt.Sprintf("/schemas/ids/%d", version.ID) {
+			_ = json.NewEncoder(w).Encode(registryResponse{Schema: version.Schema, SchemaType: version.SchemaType})
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func newTestRegistry(t *testing.T, fake *fakeRegistry, mappings ...config.Mapping) *schemaRegistry {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return newSchemaRegistry(config.SchemaConfig{Registry: config.RegistryConfig{Type: registryConfluent, BaseURL: server.URL}}, mappings)
+}
+
+func confluentPayload(id int, body []byte) []byte {
+	out := []byte{0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(out[1:], uint32(id))
+	return append(out, body...)
+}
+
+const ordersAvroV1 = `{"type":"record","name":"Order","fields":[
+	{"name":"order_id","type":"int"},
+	{"name":"status","type":{"type":"enum","name":"Status","symbols":["NEW","PAID"]}},
+	{"name":"placed_at","type":{"type":"long","logicalType":"timestamp-millis"}},
+	{"name":"customer","type":{"type":"record","name":"Customer","fields":[{"name":"name","type":"string"}]}},
+	{"name":"tags","type":{"type":"array","items":"string"}},
+	{"name":"attrs","type":{"type":"map","values":"long"}},
+	{"name":"amount","type":{"type":"bytes","logicalType":"decimal","precision":9,"scale":2}}
+]}`
+
+const ordersAvroV2 = `{"type":"record","name":"Order","fields":[
+	{"name":"order_id","type":"long"},
+	{"name":"status","type":{"type":"enum","name":"Status","symbols":["NEW","PAID"]}},
+	{"name":"placed_at","type":{"type":"long","logicalType":"timestamp-millis"}},
+	{"name":"customer","type":{"type":"record","name":"Customer","fields":[
+		{"name":"name","type":"string"},
+		{"name":"email","type":["null","string"],"default":null}
+	]}},
+	{"name":"tags","type":{"type":"array","items":"string"}},
+	{"name":"attrs","type":{"type":"map","values":"long"}},
+	{"name":"amount","type":{"type":"bytes","logicalType":"decimal","precision":9,"scale":2}}
+]}`
+
+func TestAvroColumns(t *testing.T) {
+	columns, err := avroColumns(avro.MustParse(ordersAvroV1))
+	if err != nil {
+		t.Fatalf("avroColumns: %v", err)
+	}
+	types := make(map[string]string)
+	for _, col := range columns {
+		types[col.Name] = col.Type
+	}
+	want := map[string]string{
+		"order_id":  "int",
+		"status":    "string",
+		"placed_at": "timestamp",
+		"customer":  "struct",
+		"tags":      "list",
+		"attrs":     "map",
+	}
+	if fmt.Sprint(types) != fmt.Sprint(want) {
+		t.Fatalf("unexpected column types %v", types)
+	}
+	customer := columns[3]
+	if len(customer.Fields) != 1 || customer.Fields[0].Name != "name" || customer.Fields[0].Type != "string" {
+		t.Fatalf("unexpected customer fields %+v", customer.Fields)
+	}
+	if columns[4].Element == nil || columns[4].Element.Type != "string" || columns[5].Element == nil || columns[5].Element.Type != "long" {
+		t.Fatalf("unexpected collection elements %+v %+v", columns[4], columns[5])
+	}
+}
+
+func TestWriteEvolvesSchemaFromConfluentAvro(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeRegistry{}
+	v1 := fake.register("", ordersAvroV1)
+	mapping := config.Mapping{Topic: "orders", Mode: "append", Schema: config.MappingSchemaConfig{Source: "registry"}}
+	registry := newTestRegistry(t, fake, mapping)
+
+	ident := table.Identifier{"demo", "orders"}
+	meta, err := table.NewMetadata(defaultSchema(), iceberg.UnpartitionedSpec, table.UnsortedSortOrder, t.TempDir(), iceberg.Properties{})
+	if err != nil {
+		t.Fatalf("metadata init: %v", err)
+	}
+	cat := &fakeCatalog{meta: meta, fs: io.LocalFS{}}
+	writer := &icebergWriter{
+		catalog:  cat,
+		mappings: map[string]tableMapping{"orders": {identifier: ident, mode: modeAppend, schema: mapping.Schema}},
+		registry: registry,
+		schemas:  make(map[string]*topicSchema),
+		tables:   make(map[string]*table.Table),
+	}
+
+	placedAt := time.Date(2025, 3, 4, 10, 15, 0, 0, time.UTC)
+	encode := func(schema string, id int, row map[string]any) []byte {
+		body, err := avro.Marshal(avro.MustParse(schema), row)
+		if err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		return confluentPayload(id, body)
+	}
+	first := encode(ordersAvroV1, v1, map[string]any{
+		"order_id": 1, "status": "NEW", "placed_at": placedAt,
+		"customer": map[string]any{"name": "ada"}, "tags": []any{"a", "b"},
+		"attrs": map[string]any{"x": int64(7)}, "amount": []byte{0x01},
+	})
+	if err := writer.Write(ctx, []Record{{Topic: "orders", Offset: 0, Value: first}}); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+
+	// The second version widens order_id and adds a nested field. The new
+	// schema ID in the payload is enough to trigger evolution.
+	v2 := fake.register("AVRO", ordersAvroV2)
+	second := encode(ordersAvroV2, v2, map[string]any{
+		"order_id": int64(1) << 40, "status": "PAID", "placed_at": placedAt,
+		"customer": map[string]any{"name": "grace", "email": "grace@example.com"}, "tags": []any{},
+		"attrs": map[string]any{}, "amount": []byte{0x02},
+	})
+	if err := writer.Write(ctx, []Record{{Topic: "orders", Offset: 1, Value: second}}); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+
+	tbl, _ := cat.LoadTable(ctx, ident)
+	schema := tbl.Schema()
+	orderID, _ := schema.FindFieldByName("order_id")
+	if !orderID.Type.Equals(iceberg.PrimitiveTypes.Int64) {
+		t.Fatalf("expected order_id to be widened to long, got %s", orderID.Type)
+	}
+	customerName, _ := schema.FindFieldByName("customer.name")
+	if _, ok := schema.FindFieldByName("customer.email"); !ok {
+		t.Fatalf("expected customer.email to be added, schema %s", schema)
+	}
+	if _, ok := schema.FindFieldByName("amount"); ok {
+		t.Fatalf("expected decimal field to be skipped")
+	}
+	firstSchema := tbl.Metadata().Schemas()[1]
+	if field, ok := firstSchema.FindFieldByName("customer.name"); !ok || field.ID != customerName.ID {
+		t.Fatalf("expected nested field IDs to be reused, got %d and %d", field.ID, customerName.ID)
+	}
+
+	rows := scanRows(t, tbl)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	got, err := json.Marshal([]any{
+		rows[0]["order_id"], rows[0]["status"], rows[0]["customer"], rows[0]["tags"], rows[0]["attrs"],
+		rows[1]["order_id"], rows[1]["status"], rows[1]["customer"], rows[1]["tags"],
+	})
+	if err != nil {
+		t.Fatalf("marshal rows: %v", err)
+	}
+	want := `[1,"NEW",{"email":null,"name":"ada"},["a","b"],[{"key":"x","value":7}],` +
+		`1099511627776,"PAID",{"email":"grace@example.com","name":"grace"},[]]`
+	if string(got) != want {
+		t.Fatalf("unexpected rows\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestDecodeConfluentProtobuf(t *testing.T) {
+	ctx := context.Background()
+	const source = `syntax = "proto3";
+package shop;
+import "google/protobuf/timestamp.proto";
+message Envelope { string id = 1; }
+message Order {
+  enum Status { NEW = 0; PAID = 1; }
+  message Line { string sku = 1; int32 quantity = 2; }
+  int64 order_id = 1;
+  Status status = 2;
+  google.protobuf.Timestamp placed_at = 3;
+  repeated Line lines = 4;
+  map<string, string> labels = 5;
+}`
+	fake := &fakeRegistry{}
+	id := fake.register("PROTOBUF", source)
+	registry := newTestRegistry(t, fake, config.Mapping{Topic: "orders"})
+
+	fd, err := compileProto(ctx, source, nil)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	desc, err := protoMessage(fd, []int{1})
+	if err != nil {
+		t.Fatalf("message: %v", err)
+	}
+	msg := dynamicpb.NewMessage(desc)
+	fields := desc.Fields()
+	msg.Set(fields.ByName("order_id"), protoreflect.ValueOfInt64(42))
+	msg.Set(fields.ByName("status"), protoreflect.ValueOfEnum(1))
+	placed := dynamicpb.NewMessage(fields.ByName("placed_at").Message())
+	placed.Set(placed.Descriptor().Fields().ByName("seconds"), protoreflect.ValueOfInt64(1741083300))
+	msg.Set(fields.ByName("placed_at"), protoreflect.ValueOfMessage(placed))
+	lines := msg.Mutable(fields.ByName("lines")).List()
+	line := lines.NewElement()
+	line.Message().Set(fields.ByName("lines").Message().Fields().ByName("sku"), protoreflect.ValueOfString("sku-1"))
+	line.Message().Set(fields.ByName("lines").Message().Fields().ByName("quantity"), protoreflect.ValueOfInt32(3))
+	lines.Append(line)
+	msg.Mutable(fields.ByName("labels")).Map().Set(protoreflect.ValueOfString("channel").MapKey(), protoreflect.ValueOfString("web"))
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	indexes := protowire.AppendVarint(nil, protowire.EncodeZigZag(1))
+	indexes = protowire.AppendVarint(indexes, protowire.EncodeZigZag(1))
+	records, err := registry.decodeRecords(ctx, []Record{{Topic: "orders", Value: confluentPayload(id, append(indexes, body...))}})
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	values := recordValues(records[0])
+	if values["order_id"] != int64(42) || values["status"] != "PAID" {
+		t.Fatalf("unexpected scalar values %v", values)
+	}
+	if placedAt, ok := values["placed_at"].(time.Time); !ok || !placedAt.Equal(time.Unix(1741083300, 0)) {
+		t.Fatalf("unexpected timestamp %v", values["placed_at"])
+	}
+	got, err := json.Marshal([]any{values["lines"], values["labels"]})
+	if err != nil {
+		t.Fatalf("marshal values: %v", err)
+	}
+	if string(got) != `[[{"quantity":3,"sku":"sku-1"}],{"channel":"web"}]` {
+		t.Fatalf("unexpected nested values %s", got)
+	}
+
+	// The subject's first message is the envelope; the columns follow the
+	// message the payloads select.
+	columns, err := registry.columns(ctx, "orders")
+	if err != nil {
+		t.Fatalf("columns: %v", err)
+	}
+	assertOrderColumns(t, columns)
+}
+
+func TestProtobufColumnsFromConfiguredMessage(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeRegistry{}
+	fake.register("PROTOBUF", `syntax = "proto3";
+package shop;
+message Envelope { string id = 1; }
+message Order {
+  enum Status { NEW = 0; PAID = 1; }
+  message Line { string sku = 1; int32 quantity = 2; }
+  int64 order_id = 1;
+  Status status = 2;
+  int64 placed_at = 3;
+  repeated Line lines = 4;
+  map<string, string> labels = 5;
+}`)
+	registry := newTestRegistry(t, fake, config.Mapping{Topic: "orders", Schema: config.MappingSchemaConfig{Message: "shop.Order"}})
+
+	columns, err := registry.columns(ctx, "orders")
+	if err != nil {
+		t.Fatalf("columns: %v", err)
+	}
+	assertOrderColumns(t, columns)
+
+	registry.messages["orders"] = "Order.Line"
+	columns, err = registry.columns(ctx, "orders")
+	if err != nil {
+		t.Fatalf("columns: %v", err)
+	}
+	if len(columns) != 2 || columns[0].Name != "sku" || columns[1].Type != "int" {
+		t.Fatalf("unexpected nested message columns %+v", columns)
+	}
+
+	registry.messages["orders"] = "shop.Missing"
+	if _, err := registry.columns(ctx, "orders"); err == nil {
+		t.Fatalf("expected error for unknown message")
+	}
+}
+
+func assertOrderColumns(t *testing.T, columns []config.Column) {
+	t.Helper()
+	names := make([]string, 0, len(columns))
+	for _, col := range columns {
+		names = append(names, col.Name)
+	}
+	if strings.Join(names, ",") != "order_id,status,placed_at,lines,labels" {
+		t.Fatalf("expected Order columns, got %v", names)
+	}
+	if columns[0].Type != "long" || columns[1].Type != "string" || columns[3].Type != "list" || columns[3].Element.Type != "struct" || columns[4].Type != "map" {
+		t.Fatalf("unexpected Order column types %+v", columns)
+	}
+}
+
+// scanRows reads every row of the table as a JSON object, ordered by offset.
+func scanRows(t *testing.T, tbl *table.Table) []map[string]any {
+	t.Helper()
+	scan, err := tbl.Scan().ToArrowTable(context.Background())
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	defer scan.Release()
+	reader := array.NewTableReader(scan, -1)
+	defer reader.Release()
+	var rows []map[string]any
+	for reader.Next() {
+		data, err := reader.Record().MarshalJSON()
+		if err != nil {
+			t.Fatalf("record json: %v", err)
+		}
+		var batch []map[string]any
+		if err := json.Unmarshal(data, &batch); err != nil {
+			t.Fatalf("decode record json: %v", err)
+		}
+		rows = append(rows, batch...)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i]["offset"].(float64) < rows[j]["offset"].(float64)
+	})
+	return rows
+}
@@ -30,6 +30,11 @@ type Record struct {
 	Key       []byte
 	Value     []byte
 	Headers   []decoder.Header
+
+	// values and keyValues hold the row decoded from a registry-framed value
+	// and key. Without them, values come from the JSON payload.
+	values    map[string]interface{}
+	keyValues map[string]interface{}
 }
 
 // Writer writes records to a downstream system (Iceberg, OLAP, etc).
@@ -37,3 +42,35 @@ type Writer interface {
 	Write(ctx context.Context, records []Record) error
 	Close(ctx context.Context) error
 }
+
+// RecordError reports a record a writer could not decode or convert. Nothing
+// of the batch is committed, so it can be written again without the record.
+type RecordError struct {
+	Record Record
+	Err    error
+}
+
+func (e *RecordError) Error() string { return e.Err.Error() }
+
+func (e *RecordError) Unwrap() error { return e.Err }
+
+// OffsetTracker is implemented by writers that commit offsets atomically with
+// the data they write. Their committed position takes precedence over the
+// checkpoint store.
+type OffsetTracker interface {
+	CommittedOffset(ctx context.Context, topic string, partition int32) (int64, bool, error)
+}
+
+// Maintainer is implemented by writers that can compact and clean up the
+// tables they write.
+type Maintainer interface {
+	Maintain(ctx context.Context, topic string) (MaintenanceResult, error)
+}
+
+// MaintenanceResult reports what a maintenance run changed.
+type MaintenanceResult struct {
+	RewrittenFiles   int
+	AddedFiles       int
+	ExpiredSnapshots int
+	DeletedOrphans   int
+}
@@ -0,0 +1,539 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	iceberg "github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+	"github.com/google/uuid"
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+)
+
+const (
+	modeAppend = "append"
+	modeUpsert = "upsert"
+
+	envelopeDebezium = "debezium"
+)
+
+// upsertBatch is a batch collapsed to the latest change per key. Rows carry
+// the row image in Value; deletes hold one record per key the batch touched,
+// so that older versions of the row are removed by equality deletes.
+type upsertBatch struct {
+	rows    []Record
+	deletes []Record
+}
+
+// collapseUpserts resolves each record to a row image and key, and keeps only
+// the last change per key. Tombstones and Debezium deletes remove the key.
+func collapseUpserts(records []Record, mapping tableMapping) (upsertBatch, error) {
+	type change struct {
+		record  Record
+		deleted bool
+	}
+	index := make(map[string]int, len(records))
+	changes := make([]change, 0, len(records))
+	for n, record := range records {
+		var (
+			deleted, ok bool
+			err         error
+		)
+		if record.values != nil || (record.Value == nil && record.keyValues != nil) {
+			record, deleted, ok, err = changeValues(record, mapping)
+		} else {
+			var image []byte
+			image, deleted, ok, err = changeImage(record, mapping)
+			record.Value = image
+		}
+		if err != nil {
+			return upsertBatch{}, &RecordError{Record: records[n], Err: err}
+		}
+		if !ok {
+			continue
+		}
+		key, err := upsertKey(record, mapping.keyColumns)
+		if err != nil {
+			return upsertBatch{}, &RecordError{Record: records[n], Err: err}
+		}
+		if i, seen := index[key]; seen {
+			changes[i] = change{record: record, deleted: deleted}
+			continue
+		}
+		index[key] = len(changes)
+		changes = append(changes, change{record: record, deleted: deleted})
+	}
+
+	var batch upsertBatch
+	for _, c := range changes {
+		batch.deletes = append(batch.deletes, c.record)
+		if !c.deleted {
+			batch.rows = append(batch.rows, c.record)
+		}
+	}
+	return batch, nil
+}
+
+// changeImage returns the row image for a record and whether it deletes the
+// row. ok is false for events that do not change rows, such as Debezium
+// truncate or schema messages.
+func changeImage(record Record, mapping tableMapping) (image []byte, deleted bool, ok bool, err error) {
+	if record.Value == nil {
+		// A tombstone only carries the key. With key columns the Kafka key is
+		// the row key as JSON, which is how Debezium keys its topics.
+		if len(mapping.keyColumns) > 0 {
+			return unwrapPayload(record.Key), true, true, nil
+		}
+		return nil, true, true, nil
+	}
+	if mapping.envelope != envelopeDebezium {
+		return record.Value, false, true, nil
+	}
+
+	event, err := decodeDebezium(record.Value)
+	if err != nil {
+		return nil, false, false, fmt.Errorf("decode debezium event %s:%d:%d: %w", record.Topic, record.Partition, record.Offset, err)
+	}
+	switch event.Op {
+	case "c", "u", "r":
+		if isJSONNull(event.After) {
+			return nil, false, false, fmt.Errorf("debezium event %s:%d:%d op %q has no after image", record.Topic, record.Partition, record.Offset, event.Op)
+		}
+		return event.After, false, true, nil
+	case "d":
+		if isJSONNull(event.Before) {
+			return unwrapPayload(record.Key), true, true, nil
+		}
+		return event.Before, true, true, nil
+	default:
+		return nil, false, false, nil
+	}
+}
+
+// changeValues is changeImage for records decoded from registry-framed
+// payloads. The row image replaces the record's values; the encoded value
+// is kept as is.
+func changeValues(record Record, mapping tableMapping) (Record, bool, bool, error) {
+	if record.Value == nil {
+		if len(mapping.keyColumns) > 0 {
+			record.values = record.keyValues
+		}
+		return record, true, true, nil
+	}
+	if mapping.envelope != envelopeDebezium {
+		return record, false, true, nil
+	}
+
+	op, _ := record.values["op"].(string)
+	before, _ := record.values["before"].(map[string]interface{})
+	after, _ := record.values["after"].(map[string]interface{})
+	switch op {
+	case "c", "u", "r":
+		if after == nil {
+			return record, false, false, fmt.Errorf("debezium event %s:%d:%d op %q has no after image", record.Topic, record.Partition, record.Offset, op)
+		}
+		record.values = after
+		return record, false, true, nil
+	case "d":
+		record.values = before
+		if before == nil {
+			record.values = record.keyValues
+		}
+		return record, true, true, nil
+	case "":
+		return record, false, false, fmt.Errorf("decode debezium event %s:%d:%d: missing op field", record.Topic, record.Partition, record.Offset)
+	default:
+		return record, false, false, nil
+	}
+}
+
+type debeziumEvent struct {
+	Op     string          `json:"op"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// decodeDebezium accepts both the JSON converter form with a schema/payload
+// wrapper and the bare form without schemas.
+func decodeDebezium(value []byte) (debeziumEvent, error) {
+	var event debeziumEvent
+	if err := json.Unmarshal(unwrapPayload(value), &event); err != nil {
+		return debeziumEvent{}, err
+	}
+	if event.Op == "" {
+		return debeziumEvent{}, fmt.Errorf("missing op field")
+	}
+	return event, nil
+}
+
+// unwrapPayload strips the schema/payload wrapper that the Kafka Connect JSON
+// converter adds when schemas are enabled.
+func unwrapPayload(value []byte) []byte {
+	var wrapper struct {
+		Schema  json.RawMessage `json:"schema"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(value, &wrapper); err != nil || wrapper.Schema == nil || wrapper.Payload == nil {
+		return value
+	}
+	return wrapper.Payload
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
+
+// upsertKey identifies the row a record belongs to: the Kafka key, or the
+// values of the key columns in the row image.
+func upsertKey(record Record, keyColumns []string) (string, error) {
+	if len(keyColumns) == 0 {
+		if record.Key == nil {
+			return "", fmt.Errorf("record %s:%d:%d has no key for upsert", record.Topic, record.Partition, record.Offset)
+		}
+		return string(record.Key), nil
+	}
+	values := recordValues(record)
+	key := make([]interface{}, 0, len(keyColumns))
+	for _, name := range keyColumns {
+		value, ok := values[name]
+		if !ok || value == nil {
+			return "", fmt.Errorf("record %s:%d:%d has no value for key column %q", record.Topic, record.Partition, record.Offset, name)
+		}
+		key = append(key, value)
+	}
+	encoded, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+// commitUpsert writes the batch as a single snapshot holding a data file with
+// the new rows and an equality-delete file with every key in the batch. Both
+// share the snapshot's sequence number, so the deletes remove older versions
+// of the rows but not the rows added alongside them.
+//
+// iceberg-go has no row-delta API yet, so files, manifests and the snapshot
+// are written here and committed through the catalog directly.
+func (w *icebergWriter) commitUpsert(ctx context.Context, tbl *table.Table, schema *topicSchema, mapping tableMapping, batch upsertBatch, props iceberg.Properties) (*table.Table, error) {
+	meta := tbl.Metadata()
+	if meta.Version() != 2 {
+		return nil, fmt.Errorf("upsert mode requires a format-version 2 table, %v is version %d", mapping.identifier, meta.Version())
+	}
+	spec := tbl.Spec()
+	if !spec.IsUnpartitioned() {
+		return nil, fmt.Errorf("upsert mode requires an unpartitioned table, %v has spec %d", mapping.identifier, spec.ID())
+	}
+	equalityIDs, err := equalityFieldIDs(schema.iceberg, mapping.keyColumns)
+	if err != nil {
+		return nil, err
+	}
+	fsys, err := tbl.FS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wfs, ok := fsys.(iceio.WriteFileIO)
+	if !ok {
+		return nil, fmt.Errorf("table %v file system is not writable", mapping.identifier)
+	}
+	locations, err := tbl.LocationProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotID := newSnapshotID()
+	commitID := uuid.NewString()
+	var parentID *int64
+	var manifests []iceberg.ManifestFile
+	if parent := tbl.CurrentSnapshot(); parent != nil {
+		id := parent.SnapshotID
+		parentID = &id
+		existing, err := parent.Manifests(fsys)
+		if err != nil {
+			return nil, err
+		}
+		manifests = existing
+	}
+
+	summary := iceberg.Properties{}
+	for k, v := range props {
+		summary[k] = v
+	}
+	var added []iceberg.ManifestFile
+	if len(batch.rows) > 0 {
+		rec, err := arrowBatch(schema, sortRecords(batch.rows, mapping.sortOrder))
+		if err != nil {
+			return nil, err
+		}
+		path := locations.NewDataLocation(fmt.Sprintf("00000-0-%s.parquet", commitID))
+		size, err := writeParquet(wfs, path, rec)
+		rows := rec.NumRows()
+		rec.Release()
+		if err != nil {
+			return nil, err
+		}
+		df, err := iceberg.NewDataFileBuilder(spec, iceberg.EntryContentData, path, iceberg.ParquetFile, nil, nil, nil, rows, size)
+		if err != nil {
+			return nil, err
+		}
+		mf, err := writeUpsertManifest(wfs, locations.NewMetadataLocation(commitID+"-m0.avro"), spec, schema.iceberg, snapshotID, iceberg.ManifestContentData, df.Build())
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, mf)
+		summary["added-data-files"] = "1"
+		summary["added-records"] = strconv.FormatInt(rows, 10)
+	}
+	if len(batch.deletes) > 0 {
+		full, err := arrowBatch(schema, batch.deletes)
+		if err != nil {
+			return nil, err
+		}
+		rec, err := projectFields(full, schema.arrow, keyFieldNames(mapping.keyColumns))
+		full.Release()
+		if err != nil {
+			return nil, err
+		}
+		path := locations.NewDataLocation(fmt.Sprintf("00000-1-%s-deletes.parquet", commitID))
+		size, err := writeParquet(wfs, path, rec)
+		rows := rec.NumRows()
+		rec.Release()
+		if err != nil {
+			return nil, err
+		}
+		df, err := iceberg.NewDataFileBuilder(spec, iceberg.EntryContentEqDeletes, path, iceberg.ParquetFile, nil, nil, nil, rows, size)
+		if err != nil {
+			return nil, err
+		}
+		mf, err := writeUpsertManifest(wfs, locations.NewMetadataLocation(commitID+"-m1.avro"), spec, schema.iceberg, snapshotID, iceberg.ManifestContentDeletes, df.EqualityFieldIDs(equalityIDs).Build())
+		if err != nil {
+			return nil, err
+		}
+		added = append(added, mf)
+		summary["added-delete-files"] = "1"
+		summary["added-equality-delete-files"] = "1"
+		summary["added-equality-deletes"] = strconv.FormatInt(rows, 10)
+	}
+	manifests = append(added, manifests...)
+
+	seq := meta.LastSequenceNumber() + 1
+	listPath := locations.NewMetadataLocation(fmt.Sprintf("snap-%d-0-%s.avro", snapshotID, commitID))
+	out, err := wfs.Create(listPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := iceberg.WriteManifestList(meta.Version(), out, snapshotID, parentID, &seq, 0, manifests); err != nil {
+		out.Close()
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+
+	schemaID := schema.iceberg.ID
+	snap := table.Snapshot{
+		SnapshotID:       snapshotID,
+		ParentSnapshotID: parentID,
+		SequenceNumber:   seq,
+		TimestampMs:      time.Now().UnixMilli(),
+		ManifestList:     listPath,
+		Summary:          &table.Summary{Operation: table.OpOverwrite, Properties: summary},
+		SchemaID:         &schemaID,
+	}
+	reqs := []table.Requirement{table.AssertRefSnapshotID(table.MainBranch, parentID)}
+	updates := []table.Update{
+		table.NewAddSnapshotUpdate(&snap),
+		table.NewSetSnapshotRefUpdate(table.MainBranch, snapshotID, table.BranchRef, -1, -1, -1),
+	}
+	committed, location, err := w.catalog.CommitTable(ctx, tbl.Identifier(), reqs, updates)
+	if err != nil {
+		return nil, err
+	}
+	return table.New(tbl.Identifier(), committed, location, tbl.FS, w.catalog), nil
+}
+
+func arrowBatch(schema *topicSchema, records []Record) (arrow.RecordBatch, error) {
+	rdr, err := recordsToArrow(schema.arrow, schema.columns, records)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Release()
+	if !rdr.Next() {
+		return nil, fmt.Errorf("no arrow batch built for %d records", len(records))
+	}
+	rec := rdr.RecordBatch()
+	rec.Retain()
+	return rec, nil
+}
+
+// keyFieldNames maps the configured key columns to table fields. Without key
+// columns the Kafka key column is the row key.
+func keyFieldNames(keyColumns []string) []string {
+	if len(keyColumns) == 0 {
+		return []string{"key"}
+	}
+	return keyColumns
+}
+
+func equalityFieldIDs(schema *iceberg.Schema, keyColumns []string) ([]int, error) {
+	names := keyFieldNames(keyColumns)
+	ids := make([]int, 0, len(names))
+	for _, name := range names {
+		field, ok := schema.FindFieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("key column %q is not in the table schema", name)
+		}
+		ids = append(ids, field.ID)
+	}
+	return ids, nil
+}
+
+// projectFields keeps the named columns, along with their field IDs, which
+// readers use to match equality deletes to table columns.
+func projectFields(rec arrow.RecordBatch, schema *arrow.Schema, names []string) (arrow.RecordBatch, error) {
+	fields := make([]arrow.Field, 0, len(names))
+	cols := make([]arrow.Array, 0, len(names))
+	for _, name := range names {
+		indices := schema.FieldIndices(name)
+		if len(indices) == 0 {
+			return nil, fmt.Errorf("key column %q is not in the table schema", name)
+		}
+		fields = append(fields, schema.Field(indices[0]))
+		cols = append(cols, rec.Column(indices[0]))
+	}
+	return array.NewRecordBatch(arrow.NewSchema(fields, nil), cols, rec.NumRows()), nil
+}
+
+func writeParquet(fsys iceio.WriteFileIO, path string, rec arrow.RecordBatch) (int64, error) {
+	out, err := fsys.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	cnt := &countingWriter{w: out}
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Zstd))
+	writer, err := pqarrow.NewFileWriter(rec.Schema(), cnt, props, pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()))
+	if err != nil {
+		out.Close()
+		return 0, err
+	}
+	if err := writer.Write(rec); err != nil {
+		writer.Close()
+		out.Close()
+		return 0, err
+	}
+	if err := writer.Close(); err != nil {
+		out.Close()
+		return 0, err
+	}
+	if err := out.Close(); err != nil {
+		return 0, err
+	}
+	return cnt.n, nil
+}
+
+// writeUpsertManifest writes a single-file manifest. The manifest writer
+// always labels its output as a data manifest, so delete manifests have the
+// content key of their Avro header rewritten before they are stored.
+func writeUpsertManifest(fsys iceio.WriteFileIO, path string, spec iceberg.PartitionSpec, schema *iceberg.Schema, snapshotID int64, content iceberg.ManifestContent, df iceberg.DataFile) (iceberg.ManifestFile, error) {
+	var buf bytes.Buffer
+	writer, err := iceberg.NewManifestWriter(2, &buf, spec, schema, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.Add(iceberg.NewManifestEntry(iceberg.EntryStatusADDED, &snapshotID, nil, nil, df)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	if content != iceberg.ManifestContentData {
+		if data, err = setManifestContent(data, content); err != nil {
+			return nil, err
+		}
+	}
+	out, err := fsys.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := out.Write(data); err != nil {
+		out.Close()
+		return nil, err
+	}
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+	return iceberg.NewManifestFile(2, path, int64(len(data)), int32(spec.ID()), snapshotID).
+		Content(content).
+		AddedFiles(1).
+		AddedRows(df.Count()).
+		Partitions([]iceberg.FieldSummary{}).
+		Build(), nil
+}
+
+// setManifestContent replaces the content key in the header of an Avro
+// container file. Blocks follow the header unchanged, and the sync marker
+// they reference stays the same.
+func setManifestContent(data []byte, content iceberg.ManifestContent) ([]byte, error) {
+	var header ocf.Header
+	if err := avro.Unmarshal(ocf.HeaderSchema, data, &header); err != nil {
+		return nil, fmt.Errorf("decode manifest header: %w", err)
+	}
+	original, err := avro.Marshal(ocf.HeaderSchema, header)
+	if err != nil {
+		return nil, err
+	}
+	header.Meta["content"] = []byte(content.String())
+	updated, err := avro.Marshal(ocf.HeaderSchema, header)
+	if err != nil {
+		return nil, err
+	}
+	return append(updated, data[len(original):]...), nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func newSnapshotID() int64 {
+	for {
+		if id := rand.Int64(); id > 0 {
+			return id
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	iceberg "github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/io"
+	"github.com/apache/iceberg-go/table"
+	"github.com/KafScale/platform/addons/processors/iceberg-processor/internal/config"
+)
+
+func TestCollapseUpsertsKeepsLastChangePerKey(t *testing.T) {
+	mapping := tableMapping{mode: modeUpsert}
+	records := []Record{
+		{Topic: "orders", Offset: 1, Key: []byte("a"), Value: []byte(`{"v":1}`)},
+		{Topic: "orders", Offset: 2, Key: []byte("b"), Value: []byte(`{"v":2}`)},
+		{Topic: "orders", Offset: 3, Key: []byte("a"), Value: []byte(`{"v":3}`)},
+		{Topic: "orders", Offset: 4, Key: []byte("b"), Value: nil},
+	}
+
+	batch, err := collapseUpserts(records, mapping)
+	if err != nil {
+		t.Fatalf("collapseUpserts: %v", err)
+	}
+	if len(batch.rows) != 1 || batch.rows[0].Offset != 3 {
+		t.Fatalf("expected only the latest row for key a, got %+v", batch.rows)
+	}
+	if len(batch.deletes) != 2 {
+		t.Fatalf("expected deletes for both keys, got %+v", batch.deletes)
+	}
+}
+
+func TestCollapseUpsertsRequiresKey(t *testing.T) {
+	records := []Record{{Topic: "orders", Offset: 1, Value: []byte(`{"id":1}`)}}
+	if _, err := collapseUpserts(records, tableMapping{mode: modeUpsert}); err == nil {
+		t.Fatalf("expected error for record without key")
+	}
+	mapping := tableMapping{mode: modeUpsert, keyColumns: []string{"order_id"}}
+	_, err := collapseUpserts(records, mapping)
+	var recordErr *RecordError
+	if !errors.As(err, &recordErr) || recordErr.Record.Offset != 1 {
+		t.Fatalf("expected record error for record without key column, got %v", err)
+	}
+}
+
+func TestCollapseUpsertsDebezium(t *testing.T) {
+	mapping := tableMapping{mode: modeUpsert, keyColumns: []string{"id"}, envelope: envelopeDebezium}
+	records := []Record{
+		{Topic: "db.orders", Offset: 1, Value: []byte(`{"schema":{},"payload":{"op":"c","before":null,"after":{"id":1,"status":"new"}}}`)},
+		{Topic: "db.orders", Offset: 2, Value: []byte(`{"op":"u","before":{"id":1,"status":"new"},"after":{"id":1,"status":"paid"}}`)},
+		{Topic: "db.orders", Offset: 3, Value: []byte(`{"op":"r","after":{"id":2,"status":"new"}}`)},
+		{Topic: "db.orders", Offset: 4, Value: []byte(`{"op":"d","before":{"id":2,"status":"new"},"after":null}`)},
+		{Topic: "db.orders", Offset: 5, Key: []byte(`{"schema":{},"payload":{"id":3}}`), Value: nil},
+		{Topic: "db.orders", Offset: 6, Value: []byte(`{"op":"t"}`)},
+	}
+
+	batch, err := collapseUpserts(records, mapping)
+	if err != nil {
+		t.Fatalf("collapseUpserts: %v", err)
+	}
+	if len(batch.rows) != 1 || string(batch.rows[0].Value) != `{"id":1,"status":"paid"}` {
+		t.Fatalf("unexpected rows: %+v", batch.rows)
+	}
+	if len(batch.deletes) != 3 {
+		t.Fatalf("expected deletes for ids 1, 2 and 3, got %d", len(batch.deletes))
+	}
+	if string(batch.deletes[2].Value) != `{"id":3}` {
+		t.Fatalf("expected tombstone key as delete image, got %s", batch.deletes[2].Value)
+	}
+}
+
+func TestDecodeDebeziumRejectsMissingOp(t *testing.T) {
+	if _, err := decodeDebezium([]byte(`{"after":{"id":1}}`)); err == nil {
+		t.Fatalf("expected error for event without op")
+	}
+	if _, err := decodeDebezium([]byte(`not json`)); err == nil {
+		t.Fatalf("expected error for invalid json")
+	}
+}
+
+func TestCommitUpsertWritesEqualityDeletes(t *testing.T) {
+	ctx := context.Background()
+	columns := []config.Column{{Name: "order_id", Type: "long"}, {Name: "status", Type: "string"}}
+	desired, _, err := (&icebergWriter{}).buildDesiredSchema(ctx, config.MappingSchemaConfig{Source: "mapping", Columns: columns}, "orders", nil)
+	if err != nil {
+		t.Fatalf("build schema: %v", err)
+	}
+	arrowSchema, err := tableSchemaToArrow(desired)
+	if err != nil {
+		t.Fatalf("arrow schema: %v", err)
+	}
+	schema := &topicSchema{iceberg: desired, arrow: arrowSchema, columns: columns}
+
+	ident := table.Identifier{"demo", "orders"}
+	meta, err := table.NewMetadata(desired, iceberg.UnpartitionedSpec, table.UnsortedSortOrder, t.TempDir(), iceberg.Properties{})
+	if err != nil {
+		t.Fatalf("metadata init: %v", err)
+	}
+	cat := &fakeCatalog{meta: meta}
+	fsF := func(context.Context) (io.IO, error) { return io.LocalFS{}, nil }
+	tbl := table.New(ident, meta, "", fsF, cat)
+	writer := &icebergWriter{catalog: cat}
+	mapping := tableMapping{identifier: ident, mode: modeUpsert, keyColumns: []string{"order_id"}}
+
+	batch, err := collapseUpserts([]Record{
+		{Topic: "orders", Offset: 1, Value: []byte(`{"order_id":1,"status":"new"}`)},
+		{Topic: "orders", Offset: 2, Value: []byte(`{"order_id":2,"status":"new"}`)},
+		{Topic: "orders", Offset: 3, Value: []byte(`{"order_id":1,"status":"paid"}`)},
+	}, mapping)
+	if err != nil {
+		t.Fatalf("collapseUpserts: %v", err)
+	}
+	props := iceberg.Properties{offsetProperty("orders", 0): "3"}
+	tbl, err = writer.commitUpsert(ctx, tbl, schema, mapping, batch, props)
+	if err != nil {
+		t.Fatalf("commitUpsert: %v", err)
+	}
+
+	snap := tbl.CurrentSnapshot()
+	if snap == nil || snap.Summary == nil {
+		t.Fatalf("expected committed snapshot")
+	}
+	if snap.Summary.Properties[offsetProperty("orders", 0)] != "3" {
+		t.Fatalf("expected offsets in summary, got %v", snap.Summary.Properties)
+	}
+	if snap.Summary.Properties["added-records"] != "2" || snap.Summary.Properties["added-equality-deletes"] != "2" {
+		t.Fatalf("unexpected summary counts: %v", snap.Summary.Properties)
+	}
+	manifests, err := snap.Manifests(io.LocalFS{})
+	if err != nil {
+		t.Fatalf("manifests: %v", err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("expected data and delete manifests, got %d", len(manifests))
+	}
+	if manifests[1].ManifestContent() != iceberg.ManifestContentDeletes {
+		t.Fatalf("expected delete manifest, got %v", manifests[1].ManifestContent())
+	}
+	entries, err := manifests[1].FetchEntries(io.LocalFS{}, true)
+	if err != nil {
+		t.Fatalf("fetch delete entries: %v", err)
+	}
+	field, _ := desired.FindFieldByName("order_id")
+	df := entries[0].DataFile()
+	if df.ContentType() != iceberg.EntryContentEqDeletes || len(df.EqualityFieldIDs()) != 1 || df.EqualityFieldIDs()[0] != field.ID {
+		t.Fatalf("unexpected delete file: content=%v ids=%v", df.ContentType(), df.EqualityFieldIDs())
+	}
+
+	// A second commit keeps the earlier manifests.
+	batch, err = collapseUpserts([]Record{{Topic: "orders", Offset: 4, Value: []byte(`{"order_id":2,"status":"paid"}`)}}, mapping)
+	if err != nil {
+		t.Fatalf("collapseUpserts: %v", err)
+	}
+	tbl, err = writer.commitUpsert(ctx, tbl, schema, mapping, batch, props)
+	if err != nil {
+		t.Fatalf("second commitUpsert: %v", err)
+	}
+	if manifests, _ := tbl.CurrentSnapshot().Manifests(io.LocalFS{}); len(manifests) != 4 {
+		t.Fatalf("expected 4 manifests after second commit, got %d", len(manifests))
+	}
+}
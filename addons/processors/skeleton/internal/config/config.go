@@ -18,6 +18,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -25,6 +26,7 @@ import (
 // Config defines the processor configuration schema.
 type Config struct {
 	S3       S3Config     `yaml:"s3"`
+	Sink     SinkConfig   `yaml:"sink"`
 	Mappings []Mapping    `yaml:"mappings"`
 	Offsets  OffsetConfig `yaml:"offsets"`
 }
@@ -34,6 +36,23 @@ type S3Config struct {
 	Namespace string `yaml:"namespace"`
 	Endpoint  string `yaml:"endpoint"`
 	Region    string `yaml:"region"`
+	PathStyle bool   `yaml:"path_style"`
+}
+
+// SinkConfig selects the writer: "noop" (default) or "archive".
+type SinkConfig struct {
+	Type    string        `yaml:"type"`
+	Archive ArchiveConfig `yaml:"archive"`
+}
+
+// ArchiveConfig writes records as Parquet or gzipped NDJSON files under
+// <prefix>/topic=<topic>/dt=YYYY-MM-DD/hour=HH/ in Bucket.
+type ArchiveConfig struct {
+	Bucket              string `yaml:"bucket"`
+	Prefix              string `yaml:"prefix"`
+	Format              string `yaml:"format"`
+	RollBytes           int64  `yaml:"roll_bytes"`
+	RollIntervalSeconds int    `yaml:"roll_interval_seconds"`
 }
 
 type OffsetConfig struct {
@@ -62,5 +81,35 @@ func Load(path string) (Config, error) {
 		return Config{}, fmt.Errorf("s3.bucket is required")
 	}
 
+	if cfg.Sink.Type == "" {
+		cfg.Sink.Type = "noop"
+	}
+	switch cfg.Sink.Type {
+	case "noop":
+	case "archive":
+		archive := &cfg.Sink.Archive
+		if archive.Bucket == "" {
+			archive.Bucket = cfg.S3.Bucket
+		}
+		archive.Prefix = strings.Trim(archive.Prefix, "/")
+		if archive.Format == "" {
+			archive.Format = "parquet"
+		}
+		if archive.Format != "parquet" && archive.Format != "ndjson" {
+			return Config{}, fmt.Errorf("sink.archive.format must be parquet or ndjson")
+		}
+		if archive.RollBytes == 0 {
+			archive.RollBytes = 128 << 20
+		}
+		if archive.RollIntervalSeconds == 0 {
+			archive.RollIntervalSeconds = 3600
+		}
+		if archive.RollBytes < 0 || archive.RollIntervalSeconds < 0 {
+			return Config{}, fmt.Errorf("sink.archive.roll_bytes and roll_interval_seconds must be positive")
+		}
+	default:
+		return Config{}, fmt.Errorf("sink.type must be noop or archive")
+	}
+
 	return cfg, nil
 }
@@ -38,3 +38,37 @@ func TestLoad(t *testing.T) {
 		t.Fatalf("unexpected bucket: %s", cfg.S3.Bucket)
 	}
 }
+
+func TestLoadArchiveSinkDefaults(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\nsink:\n  type: archive\n  archive:\n    prefix: /raw/\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	archive := cfg.Sink.Archive
+	if archive.Bucket != "test-bucket" || archive.Prefix != "raw" || archive.Format != "parquet" {
+		t.Fatalf("unexpected archive config %+v", archive)
+	}
+	if archive.RollBytes != 128<<20 || archive.RollIntervalSeconds != 3600 {
+		t.Fatalf("unexpected roll defaults %+v", archive)
+	}
+}
+
+func TestLoadRejectsUnknownArchiveFormat(t *testing.T) {
+	data := []byte("s3:\n  bucket: test-bucket\nsink:\n  type: archive\n  archive:\n    format: avro\n")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
@@ -22,6 +22,7 @@ type Batch struct {
 	Topic     string
 	Partition int32
 	Offset    int64
+	Timestamp int64
 	Payload   []byte
 }
 
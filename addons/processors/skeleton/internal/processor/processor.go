@@ -40,12 +40,17 @@ type Processor struct {
 const leaseRenewInterval = 10 * time.Second
 
 func New(cfg config.Config) (*Processor, error) {
+	store := checkpoint.New()
+	writer, err := sink.New(cfg, store)
+	if err != nil {
+		return nil, err
+	}
 	return &Processor{
 		cfg:      cfg,
 		discover: discovery.New(),
 		decode:   decoder.New(),
-		store:    checkpoint.New(),
-		sink:     sink.New(),
+		store:    store,
+		sink:     writer,
 		locks:    newTopicLocker(),
 	}, nil
 }
@@ -144,6 +149,9 @@ func (p *Processor) Run(ctx context.Context) error {
 				if err != nil {
 					continue
 				}
+				if committer, ok := p.sink.(sink.OffsetCommitter); ok && committer.CommitsOffsets() {
+					continue
+				}
 
 				last := records[len(records)-1]
 				_ = p.store.CommitOffset(ctx, checkpoint.OffsetState{
@@ -164,6 +172,7 @@ func mapBatches(batches []decoder.Batch) []sink.Record {
 			Topic:     batch.Topic,
 			Partition: batch.Partition,
 			Offset:    batch.Offset,
+			Timestamp: batch.Timestamp,
 			Payload:   batch.Payload,
 		})
 	}
@@ -0,0 +1,436 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/KafScale/platform/addons/processors/skeleton/internal/checkpoint"
+	"github.com/KafScale/platform/addons/processors/skeleton/internal/config"
+)
+
+type objectClient interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+type fileKey struct {
+	partitionKey
+	hour int64
+}
+
+// archiveFile buffers the records of one partition and hour until it rolls.
+type archiveFile struct {
+	records []Record
+	bytes   int64
+	opened  time.Time
+}
+
+type partitionState struct {
+	accepted  int64
+	committed int64
+}
+
+// archiveWriter writes records to time-partitioned Parquet or gzipped NDJSON
+// files. Files roll by size or age; each is uploaded to a temporary key and
+// then copied to its final key, and only then is the partition's offset
+// committed to the checkpoint store.
+type archiveWriter struct {
+	client       objectClient
+	store        checkpoint.Store
+	bucket       string
+	prefix       string
+	format       string
+	rollBytes    int64
+	rollInterval time.Duration
+	now          func() time.Time
+
+	mu         sync.Mutex
+	files      map[fileKey]*archiveFile
+	partitions map[partitionKey]*partitionState
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+func newS3ArchiveWriter(cfg config.Config, store checkpoint.Store) (Writer, error) {
+	loadOptions := []func(*awsconfig.LoadOptions) error{}
+	if cfg.S3.Region != "" {
+		loadOptions = append(loadOptions, awsconfig.WithRegion(cfg.S3.Region))
+	}
+	if cfg.S3.Endpoint != "" {
+		resolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, _ ...interface{}) (aws.Endpoint, error) {
+			if service == s3.ServiceID {
+				return aws.Endpoint{URL: cfg.S3.Endpoint, SigningRegion: region}, nil
+			}
+			return aws.Endpoint{}, &aws.EndpointNotFoundError{}
+		})
+		loadOptions = append(loadOptions, awsconfig.WithEndpointResolverWithOptions(resolver))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	client := s3.NewFromConfig(awsCfg, func(opts *s3.Options) {
+		if cfg.S3.PathStyle {
+			opts.UsePathStyle = true
+		}
+	})
+	w := newArchiveWriter(cfg.Sink.Archive, client, store, time.Now)
+	w.startRoller()
+	return w, nil
+}
+
+func newArchiveWriter(cfg config.ArchiveConfig, client objectClient, store checkpoint.Store, now func() time.Time) *archiveWriter {
+	return &archiveWriter{
+		client:       client,
+		store:        store,
+		bucket:       cfg.Bucket,
+		prefix:       cfg.Prefix,
+		format:       cfg.Format,
+		rollBytes:    cfg.RollBytes,
+		rollInterval: time.Duration(cfg.RollIntervalSeconds) * time.Second,
+		now:          now,
+		files:        make(map[fileKey]*archiveFile),
+		partitions:   make(map[partitionKey]*partitionState),
+	}
+}
+
+func (w *archiveWriter) CommitsOffsets() bool {
+	return true
+}
+
+// startRoller finalizes files that reach their age while no records arrive.
+func (w *archiveWriter) startRoller() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+	tick := w.rollInterval / 4
+	if tick < time.Second {
+		tick = time.Second
+	}
+	if tick > time.Minute {
+		tick = time.Minute
+	}
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.mu.Lock()
+				_ = w.rollExpired(ctx)
+				w.mu.Unlock()
+			}
+		}
+	}()
+}
+
+func (w *archiveWriter) Write(ctx context.Context, records []Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var errs []error
+	for _, record := range records {
+		pk := partitionKey{topic: record.Topic, partition: record.Partition}
+		state := w.partitions[pk]
+		if state == nil {
+			state = &partitionState{accepted: -1, committed: -1}
+			w.partitions[pk] = state
+		}
+		// The processor replays segments until offsets are committed; skip
+		// records that are already buffered or archived.
+		if record.Offset <= state.accepted {
+			continue
+		}
+		key := fileKey{partitionKey: pk, hour: w.recordTime(record).Unix() / 3600}
+		file := w.files[key]
+		if file == nil {
+			file = &archiveFile{opened: w.now()}
+			w.files[key] = file
+		}
+		file.records = append(file.records, record)
+		file.bytes += int64(len(record.Payload))
+		state.accepted = record.Offset
+		if file.bytes >= w.rollBytes {
+			if err := w.finalize(ctx, key); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := w.rollExpired(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (w *archiveWriter) Close(ctx context.Context) error {
+	if w.cancel != nil {
+		w.cancel()
+		<-w.done
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var errs []error
+	for _, key := range w.sortedKeys() {
+		if err := w.finalize(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *archiveWriter) recordTime(record Record) time.Time {
+	if record.Timestamp > 0 {
+		return time.UnixMilli(record.Timestamp).UTC()
+	}
+	return w.now().UTC()
+}
+
+func (w *archiveWriter) rollExpired(ctx context.Context) error {
+	var errs []error
+	now := w.now()
+	for _, key := range w.sortedKeys() {
+		if now.Sub(w.files[key].opened) < w.rollInterval {
+			continue
+		}
+		if err := w.finalize(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *archiveWriter) sortedKeys() []fileKey {
+	keys := make([]fileKey, 0, len(w.files))
+	for key := range w.files {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].topic != keys[j].topic {
+			return keys[i].topic < keys[j].topic
+		}
+		if keys[i].partition != keys[j].partition {
+			return keys[i].partition < keys[j].partition
+		}
+		return keys[i].hour < keys[j].hour
+	})
+	return keys
+}
+
+// finalize uploads a file under a temporary key, copies it to its final key
+// and commits the highest offset below every file still open for the
+// partition. A failed file stays open and is retried on the next roll.
+func (w *archiveWriter) finalize(ctx context.Context, key fileKey) error {
+	file := w.files[key]
+	data, ext, contentType, err := w.encode(file.records)
+	if err != nil {
+		return fmt.Errorf("encode %s/%d: %w", key.topic, key.partition, err)
+	}
+
+	first := file.records[0].Offset
+	last := file.records[len(file.records)-1].Offset
+	hour := time.Unix(key.hour*3600, 0).UTC()
+	finalKey := path.Join(w.prefix,
+		"topic="+key.topic,
+		"dt="+hour.Format("2006-01-02"),
+		"hour="+hour.Format("15"),
+		fmt.Sprintf("%d-%020d-%020d%s", key.partition, first, last, ext))
+	tempKey := path.Join(w.prefix, "_tmp", "topic="+key.topic, fmt.Sprintf("%d-%s", w.now().UnixNano(), path.Base(finalKey)))
+
+	if _, err := w.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(w.bucket),
+		Key:         aws.String(tempKey),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	}); err != nil {
+		return fmt.Errorf("put %s: %w", tempKey, err)
+	}
+	if _, err := w.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(w.bucket),
+		Key:        aws.String(finalKey),
+		CopySource: aws.String(copySource(w.bucket, tempKey)),
+	}); err != nil {
+		return fmt.Errorf("copy %s to %s: %w", tempKey, finalKey, err)
+	}
+	// A leftover temporary object is harmless; readers only list the final
+	// layout and a bucket lifecycle rule on _tmp/ can expire it.
+	_, _ = w.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(w.bucket),
+		Key:    aws.String(tempKey),
+	})
+
+	delete(w.files, key)
+	return w.commit(ctx, key.partitionKey)
+}
+
+func (w *archiveWriter) commit(ctx context.Context, pk partitionKey) error {
+	state := w.partitions[pk]
+	offset := state.accepted
+	for key, file := range w.files {
+		if key.partitionKey == pk && file.records[0].Offset-1 < offset {
+			offset = file.records[0].Offset - 1
+		}
+	}
+	if offset <= state.committed {
+		return nil
+	}
+	if err := w.store.CommitOffset(ctx, checkpoint.OffsetState{
+		Topic:     pk.topic,
+		Partition: pk.partition,
+		Offset:    offset,
+		Timestamp: w.now().UnixMilli(),
+	}); err != nil {
+		return fmt.Errorf("commit offset %s/%d: %w", pk.topic, pk.partition, err)
+	}
+	state.committed = offset
+	return nil
+}
+
+func copySource(bucket, key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return bucket + "/" + strings.Join(parts, "/")
+}
+
+func (w *archiveWriter) encode(records []Record) ([]byte, string, string, error) {
+	if w.format == "ndjson" {
+		data, err := encodeNDJSON(records)
+		return data, ".ndjson.gz", "application/gzip", err
+	}
+	data, err := encodeParquet(records)
+	return data, ".parquet", "application/vnd.apache.parquet", err
+}
+
+type ndjsonRecord struct {
+	Topic         string          `json:"topic"`
+	Partition     int32           `json:"partition"`
+	Offset        int64           `json:"offset"`
+	Timestamp     int64           `json:"timestamp,omitempty"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	PayloadBase64 string          `json:"payload_base64,omitempty"`
+}
+
+// encodeNDJSON writes one JSON object per line. JSON payloads are embedded
+// as-is; anything else is base64 encoded.
+func encodeNDJSON(records []Record) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	for _, record := range records {
+		line := ndjsonRecord{
+			Topic:     record.Topic,
+			Partition: record.Partition,
+			Offset:    record.Offset,
+			Timestamp: record.Timestamp,
+		}
+		if json.Valid(record.Payload) {
+			line.Payload = record.Payload
+		} else {
+			line.PayloadBase64 = base64.StdEncoding.EncodeToString(record.Payload)
+		}
+		if err := enc.Encode(line); err != nil {
+			return nil, err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var parquetSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "topic", Type: arrow.BinaryTypes.String},
+	{Name: "partition", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "offset", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "timestamp", Type: &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}, Nullable: true},
+	{Name: "payload", Type: arrow.BinaryTypes.Binary, Nullable: true},
+}, nil)
+
+func encodeParquet(records []Record) ([]byte, error) {
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, parquetSchema)
+	defer builder.Release()
+	topics := builder.Field(0).(*array.StringBuilder)
+	partitions := builder.Field(1).(*array.Int32Builder)
+	offsets := builder.Field(2).(*array.Int64Builder)
+	timestamps := builder.Field(3).(*array.TimestampBuilder)
+	payloads := builder.Field(4).(*array.BinaryBuilder)
+	for _, record := range records {
+		topics.Append(record.Topic)
+		partitions.Append(record.Partition)
+		offsets.Append(record.Offset)
+		if record.Timestamp > 0 {
+			timestamps.Append(arrow.Timestamp(record.Timestamp))
+		} else {
+			timestamps.AppendNull()
+		}
+		if record.Payload == nil {
+			payloads.AppendNull()
+		} else {
+			payloads.Append(record.Payload)
+		}
+	}
+	rec := builder.NewRecordBatch()
+	defer rec.Release()
+
+	var buf bytes.Buffer
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	writer, err := pqarrow.NewFileWriter(parquetSchema, &buf, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.Write(rec); err != nil {
+		_ = writer.Close()
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
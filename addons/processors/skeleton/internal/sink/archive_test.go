@@ -0,0 +1,211 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/KafScale/platform/addons/processors/skeleton/internal/checkpoint"
+	"github.com/KafScale/platform/addons/processors/skeleton/internal/config"
+)
+
+type fakeObjects struct {
+	objects map[string][]byte
+}
+
+func (f *fakeObjects) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	data, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[*params.Key] = data
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeObjects) CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	source, err := url.PathUnescape(strings.TrimPrefix(*params.CopySource, *params.Bucket+"/"))
+	if err != nil {
+		return nil, err
+	}
+	f.objects[*params.Key] = f.objects[source]
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (f *fakeObjects) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	delete(f.objects, *params.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (f *fakeObjects) keys() []string {
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type recordingStore struct {
+	checkpoint.Store
+	commits []checkpoint.OffsetState
+}
+
+func (s *recordingStore) CommitOffset(ctx context.Context, state checkpoint.OffsetState) error {
+	s.commits = append(s.commits, state)
+	return nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// 2025-03-04 10:15 UTC and one hour later.
+const (
+	hour10 = int64(1741083300000)
+	hour11 = hour10 + 3600*1000
+)
+
+func newTestArchive(format string, rollBytes int64) (*archiveWriter, *fakeObjects, *recordingStore, *fakeClock) {
+	objects := &fakeObjects{objects: map[string][]byte{}}
+	store := &recordingStore{Store: checkpoint.New()}
+	clock := &fakeClock{now: time.UnixMilli(hour10)}
+	cfg := config.ArchiveConfig{Bucket: "archive", Prefix: "raw", Format: format, RollBytes: rollBytes, RollIntervalSeconds: 600}
+	return newArchiveWriter(cfg, objects, store, clock.Now), objects, store, clock
+}
+
+func TestArchiveRollsBySizeWithHourlyLayout(t *testing.T) {
+	ctx := context.Background()
+	w, objects, store, _ := newTestArchive("parquet", 8)
+	records := []Record{
+		{Topic: "orders", Partition: 0, Offset: 0, Timestamp: hour10, Payload: []byte(`{"a":1}`)},
+		{Topic: "orders", Partition: 0, Offset: 1, Timestamp: hour10, Payload: []byte(`{"a":2}`)},
+		{Topic: "orders", Partition: 0, Offset: 2, Timestamp: hour10, Payload: []byte(`{"a":3}`)},
+	}
+	if err := w.Write(ctx, records); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	keys := objects.keys()
+	if len(keys) != 1 || keys[0] != "raw/topic=orders/dt=2025-03-04/hour=10/0-00000000000000000000-00000000000000000001.parquet" {
+		t.Fatalf("unexpected objects %v", keys)
+	}
+	if len(store.commits) != 1 || store.commits[0].Offset != 1 {
+		t.Fatalf("expected offset 1 to be committed, got %+v", store.commits)
+	}
+
+	rdr, err := file.NewParquetReader(bytes.NewReader(objects.objects[keys[0]]))
+	if err != nil {
+		t.Fatalf("open parquet: %v", err)
+	}
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatalf("arrow reader: %v", err)
+	}
+	tbl, err := fr.ReadTable(ctx)
+	if err != nil {
+		t.Fatalf("read table: %v", err)
+	}
+	defer tbl.Release()
+	if tbl.NumRows() != 2 || tbl.NumCols() != 5 {
+		t.Fatalf("unexpected table shape %dx%d", tbl.NumRows(), tbl.NumCols())
+	}
+
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if len(objects.keys()) != 2 || store.commits[len(store.commits)-1].Offset != 2 {
+		t.Fatalf("expected close to finalize the last file, got %v %+v", objects.keys(), store.commits)
+	}
+}
+
+func TestArchiveRollsByTimeAndHoldsOffsetsForOpenFiles(t *testing.T) {
+	ctx := context.Background()
+	w, objects, store, clock := newTestArchive("ndjson", 1<<20)
+	if err := w.Write(ctx, []Record{
+		{Topic: "orders", Partition: 3, Offset: 10, Timestamp: hour10, Payload: []byte(`{"a":1}`)},
+	}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	clock.now = clock.now.Add(5 * time.Minute)
+	if err := w.Write(ctx, []Record{
+		{Topic: "orders", Partition: 3, Offset: 11, Timestamp: hour11, Payload: []byte("not json")},
+	}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if len(objects.objects) != 0 {
+		t.Fatalf("expected no files before the roll interval, got %v", objects.keys())
+	}
+
+	// Only the hour-10 file is old enough; offset 11 stays uncommitted.
+	clock.now = clock.now.Add(6 * time.Minute)
+	if err := w.Write(ctx, nil); err != nil {
+		t.Fatalf("roll: %v", err)
+	}
+	key := "raw/topic=orders/dt=2025-03-04/hour=10/3-00000000000000000010-00000000000000000010.ndjson.gz"
+	if keys := objects.keys(); len(keys) != 1 || keys[0] != key {
+		t.Fatalf("unexpected objects %v", keys)
+	}
+	if len(store.commits) != 1 || store.commits[0].Offset != 10 {
+		t.Fatalf("expected offset 10 to be committed, got %+v", store.commits)
+	}
+
+	// Replayed records are ignored.
+	if err := w.Write(ctx, []Record{{Topic: "orders", Partition: 3, Offset: 11, Timestamp: hour11, Payload: []byte("not json")}}); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	clock.now = clock.now.Add(10 * time.Minute)
+	if err := w.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	next := "raw/topic=orders/dt=2025-03-04/hour=11/3-00000000000000000011-00000000000000000011.ndjson.gz"
+	gz, err := gzip.NewReader(bytes.NewReader(objects.objects[next]))
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	data, _ := io.ReadAll(gz)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one line, got %q", data)
+	}
+	var line map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &line); err != nil {
+		t.Fatalf("parse line: %v", err)
+	}
+	if line["payload_base64"] != "bm90IGpzb24=" || line["offset"] != float64(11) {
+		t.Fatalf("unexpected line %v", line)
+	}
+	if store.commits[len(store.commits)-1].Offset != 11 {
+		t.Fatalf("expected offset 11 after close, got %+v", store.commits)
+	}
+}
@@ -15,13 +15,21 @@
 
 package sink
 
-import "context"
+import (
+	"context"
+	"fmt"
+
+	"github.com/KafScale/platform/addons/processors/skeleton/internal/checkpoint"
+	"github.com/KafScale/platform/addons/processors/skeleton/internal/config"
+)
 
 // Record models a decoded record payload passed to the sink.
 type Record struct {
 	Topic     string
 	Partition int32
 	Offset    int64
+	// Timestamp is the record time in unix milliseconds; zero if unknown.
+	Timestamp int64
 	Payload   []byte
 }
 
@@ -31,9 +39,23 @@ type Writer interface {
 	Close(ctx context.Context) error
 }
 
-// New returns a placeholder sink.
-func New() Writer {
-	return &noopSink{}
+// OffsetCommitter is implemented by writers that buffer records and commit
+// offsets to the checkpoint store themselves once the data is durable. The
+// processor does not commit offsets after Write for these writers.
+type OffsetCommitter interface {
+	CommitsOffsets() bool
+}
+
+// New returns the sink selected by sink.type.
+func New(cfg config.Config, store checkpoint.Store) (Writer, error) {
+	switch cfg.Sink.Type {
+	case "", "noop":
+		return &noopSink{}, nil
+	case "archive":
+		return newS3ArchiveWriter(cfg, store)
+	default:
+		return nil, fmt.Errorf("unsupported sink type %q", cfg.Sink.Type)
+	}
 }
 
 type noopSink struct{}
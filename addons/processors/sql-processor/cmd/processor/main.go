@@ -44,6 +44,22 @@ func main() {
 	metrics.Start(ctx, cfg.Server.MetricsListen)
 
 	srv := server.New(cfg, log.Default())
+	if cfg.Server.FlightListen != "" {
+		flightSrv := server.NewFlightServer(srv)
+		go func() {
+			if err := flightSrv.Run(ctx); err != nil {
+				log.Fatalf("flight server stopped with error: %v", err)
+			}
+		}()
+	}
+	if cfg.HTTP.Listen != "" {
+		httpSrv := server.NewHTTPServer(srv)
+		go func() {
+			if err := httpSrv.Run(ctx); err != nil {
+				log.Fatalf("http server stopped with error: %v", err)
+			}
+		}()
+	}
 	if err := srv.Run(ctx); err != nil {
 		log.Fatalf("server stopped with error: %v", err)
 	}
@@ -35,6 +35,8 @@ type Config struct {
 	TimeIndex      TimeIndexConfig      `yaml:"time_index"`
 	Proxy          ProxyConfig          `yaml:"proxy"`
 	ResultCache    ResultCacheConfig    `yaml:"result_cache"`
+	HTTP           HTTPConfig           `yaml:"http"`
+	Export         ExportConfig         `yaml:"export"`
 
 	Mappings []Mapping    `yaml:"mappings"`
 	Offsets  OffsetConfig `yaml:"offsets"`
@@ -60,11 +62,13 @@ type Mapping struct {
 }
 
 type ServerConfig struct {
-	Listen         string `yaml:"listen"`
-	MaxConnections int    `yaml:"max_connections"`
-	ServerVersion  string `yaml:"server_version"`
-	ClientEncoding string `yaml:"client_encoding"`
-	MetricsListen  string `yaml:"metrics_listen"`
+	Listen          string `yaml:"listen"`
+	MaxConnections  int    `yaml:"max_connections"`
+	ServerVersion   string `yaml:"server_version"`
+	ClientEncoding  string `yaml:"client_encoding"`
+	MetricsListen   string `yaml:"metrics_listen"`
+	FlightListen    string `yaml:"flight_listen"`
+	FlightBatchRows int    `yaml:"flight_batch_rows"`
 }
 
 type MetaConfig struct {
@@ -84,16 +88,20 @@ type SnapshotConfig struct {
 }
 
 type QueryConfig struct {
-	DefaultLimit     int   `yaml:"default_limit"`
-	RequireTimeBound bool  `yaml:"require_time_bound"`
-	MaxUnbounded     int   `yaml:"max_unbounded_scan"`
-	MaxScanBytes     int64 `yaml:"max_scan_bytes"`
-	MaxScanSegments  int   `yaml:"max_scan_segments"`
-	MaxRows          int   `yaml:"max_rows"`
-	TimeoutSeconds   int   `yaml:"timeout_seconds"`
-	MaxConcurrent    int   `yaml:"max_concurrent"`
-	QueueSize        int   `yaml:"queue_size"`
-	QueueTimeoutSec  int   `yaml:"queue_timeout_seconds"`
+	DefaultLimit     int    `yaml:"default_limit"`
+	RequireTimeBound bool   `yaml:"require_time_bound"`
+	MaxUnbounded     int    `yaml:"max_unbounded_scan"`
+	MaxScanBytes     int64  `yaml:"max_scan_bytes"`
+	MaxScanSegments  int    `yaml:"max_scan_segments"`
+	MaxDecodedBytes  int64  `yaml:"max_decoded_bytes"`
+	MaxRows          int    `yaml:"max_rows"`
+	TimeoutSeconds   int    `yaml:"timeout_seconds"`
+	MaxConcurrent    int    `yaml:"max_concurrent"`
+	QueueSize        int    `yaml:"queue_size"`
+	QueueTimeoutSec  int    `yaml:"queue_timeout_seconds"`
+	ScanParallelism  int    `yaml:"scan_parallelism"`
+	JoinMemoryBytes  int64  `yaml:"join_memory_bytes"`
+	SpillDir         string `yaml:"spill_dir"`
 }
 
 type DiscoveryCacheConfig struct {
@@ -124,6 +132,22 @@ type ResultCacheConfig struct {
 	MaxEntries int `yaml:"max_entries"`
 	MaxRows    int `yaml:"max_rows"`
 }
+
+type HTTPConfig struct {
+	Listen            string `yaml:"listen"`
+	MaxJobs           int    `yaml:"max_jobs"`
+	JobTTLSeconds     int    `yaml:"job_ttl_seconds"`
+	JobTimeoutSeconds int    `yaml:"job_timeout_seconds"`
+	PageSize          int    `yaml:"page_size"`
+	MaxPageSize       int    `yaml:"max_page_size"`
+}
+
+type ExportConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	AllowedTargets []string `yaml:"allowed_targets"`
+	MaxRowsPerFile int      `yaml:"max_rows_per_file"`
+}
+
 type ProxyConfig struct {
 	Listen          string         `yaml:"listen"`
 	Upstreams       []string       `yaml:"upstreams"`
@@ -178,6 +202,10 @@ func Load(path string) (Config, error) {
 	return cfg, nil
 }
 
+// DefaultFlightBatchRows is the number of rows per Arrow Flight record batch
+// when server.flight_batch_rows is unset.
+const DefaultFlightBatchRows = 4096
+
 func applyDefaults(cfg *Config) {
 	if cfg.Server.Listen == "" {
 		cfg.Server.Listen = ":5432"
@@ -194,6 +222,9 @@ func applyDefaults(cfg *Config) {
 	if cfg.Server.MetricsListen == "" {
 		cfg.Server.MetricsListen = ":9090"
 	}
+	if cfg.Server.FlightBatchRows == 0 {
+		cfg.Server.FlightBatchRows = DefaultFlightBatchRows
+	}
 	if cfg.Query.DefaultLimit == 0 {
 		cfg.Query.DefaultLimit = 1000
 	}
@@ -221,6 +252,12 @@ func applyDefaults(cfg *Config) {
 	if cfg.Query.QueueTimeoutSec == 0 {
 		cfg.Query.QueueTimeoutSec = 10
 	}
+	if cfg.Query.ScanParallelism == 0 {
+		cfg.Query.ScanParallelism = 4
+	}
+	if cfg.Query.JoinMemoryBytes == 0 {
+		cfg.Query.JoinMemoryBytes = 256 * 1024 * 1024
+	}
 	if cfg.Metadata.Snapshot.Key == "" {
 		cfg.Metadata.Snapshot.Key = "/kafscale/metadata/snapshot"
 	}
@@ -257,6 +294,24 @@ func applyDefaults(cfg *Config) {
 	if cfg.ResultCache.MaxRows == 0 {
 		cfg.ResultCache.MaxRows = 10000
 	}
+	if cfg.HTTP.MaxJobs == 0 {
+		cfg.HTTP.MaxJobs = 100
+	}
+	if cfg.HTTP.JobTTLSeconds == 0 {
+		cfg.HTTP.JobTTLSeconds = 900
+	}
+	if cfg.HTTP.JobTimeoutSeconds == 0 {
+		cfg.HTTP.JobTimeoutSeconds = 600
+	}
+	if cfg.HTTP.PageSize == 0 {
+		cfg.HTTP.PageSize = 1000
+	}
+	if cfg.HTTP.MaxPageSize == 0 {
+		cfg.HTTP.MaxPageSize = 10000
+	}
+	if cfg.Export.MaxRowsPerFile == 0 {
+		cfg.Export.MaxRowsPerFile = 100000
+	}
 	if cfg.Proxy.Listen == "" {
 		cfg.Proxy.Listen = ":5432"
 	}
@@ -283,6 +338,8 @@ func applyEnvOverrides(cfg *Config) {
 	setString(&cfg.Server.ServerVersion, "KAFSQL_SERVER_VERSION")
 	setString(&cfg.Server.ClientEncoding, "KAFSQL_CLIENT_ENCODING")
 	setString(&cfg.Server.MetricsListen, "KAFSQL_METRICS_LISTEN")
+	setString(&cfg.Server.FlightListen, "KAFSQL_FLIGHT_LISTEN")
+	setInt(&cfg.Server.FlightBatchRows, "KAFSQL_FLIGHT_BATCH_ROWS")
 
 	setString(&cfg.Metadata.Discovery, "KAFSQL_METADATA_DISCOVERY")
 	setCSV(&cfg.Metadata.Etcd.Endpoints, "KAFSQL_METADATA_ETCD_ENDPOINTS")
@@ -294,11 +351,15 @@ func applyEnvOverrides(cfg *Config) {
 	setInt(&cfg.Query.MaxUnbounded, "KAFSQL_QUERY_MAX_UNBOUNDED")
 	setInt64(&cfg.Query.MaxScanBytes, "KAFSQL_QUERY_MAX_SCAN_BYTES")
 	setInt(&cfg.Query.MaxScanSegments, "KAFSQL_QUERY_MAX_SCAN_SEGMENTS")
+	setInt64(&cfg.Query.MaxDecodedBytes, "KAFSQL_QUERY_MAX_DECODED_BYTES")
 	setInt(&cfg.Query.MaxRows, "KAFSQL_QUERY_MAX_ROWS")
 	setInt(&cfg.Query.TimeoutSeconds, "KAFSQL_QUERY_TIMEOUT_SECONDS")
 	setInt(&cfg.Query.MaxConcurrent, "KAFSQL_QUERY_MAX_CONCURRENT")
 	setInt(&cfg.Query.QueueSize, "KAFSQL_QUERY_QUEUE_SIZE")
 	setInt(&cfg.Query.QueueTimeoutSec, "KAFSQL_QUERY_QUEUE_TIMEOUT_SECONDS")
+	setInt(&cfg.Query.ScanParallelism, "KAFSQL_QUERY_SCAN_PARALLELISM")
+	setInt64(&cfg.Query.JoinMemoryBytes, "KAFSQL_QUERY_JOIN_MEMORY_BYTES")
+	setString(&cfg.Query.SpillDir, "KAFSQL_QUERY_SPILL_DIR")
 
 	setInt(&cfg.DiscoveryCache.TTLSeconds, "KAFSQL_DISCOVERY_CACHE_TTL_SECONDS")
 	setInt(&cfg.DiscoveryCache.MaxEntries, "KAFSQL_DISCOVERY_CACHE_MAX_ENTRIES")
@@ -320,6 +381,17 @@ func applyEnvOverrides(cfg *Config) {
 	setInt(&cfg.ResultCache.MaxEntries, "KAFSQL_RESULT_CACHE_MAX_ENTRIES")
 	setInt(&cfg.ResultCache.MaxRows, "KAFSQL_RESULT_CACHE_MAX_ROWS")
 
+	setString(&cfg.HTTP.Listen, "KAFSQL_HTTP_LISTEN")
+	setInt(&cfg.HTTP.MaxJobs, "KAFSQL_HTTP_MAX_JOBS")
+	setInt(&cfg.HTTP.JobTTLSeconds, "KAFSQL_HTTP_JOB_TTL_SECONDS")
+	setInt(&cfg.HTTP.JobTimeoutSeconds, "KAFSQL_HTTP_JOB_TIMEOUT_SECONDS")
+	setInt(&cfg.HTTP.PageSize, "KAFSQL_HTTP_PAGE_SIZE")
+	setInt(&cfg.HTTP.MaxPageSize, "KAFSQL_HTTP_MAX_PAGE_SIZE")
+
+	setBool(&cfg.Export.Enabled, "KAFSQL_EXPORT_ENABLED")
+	setCSV(&cfg.Export.AllowedTargets, "KAFSQL_EXPORT_ALLOWED_TARGETS")
+	setInt(&cfg.Export.MaxRowsPerFile, "KAFSQL_EXPORT_MAX_ROWS_PER_FILE")
+
 	setString(&cfg.Proxy.Listen, "KAFSQL_PROXY_LISTEN")
 	setCSV(&cfg.Proxy.Upstreams, "KAFSQL_PROXY_UPSTREAMS")
 	setInt(&cfg.Proxy.MaxConnections, "KAFSQL_PROXY_MAX_CONNECTIONS")
@@ -37,7 +37,7 @@ func TestLoadDefaults(t *testing.T) {
 	if cfg.Query.MaxScanBytes == 0 || cfg.Query.MaxScanSegments == 0 || cfg.Query.MaxRows == 0 || cfg.Query.TimeoutSeconds == 0 {
 		t.Fatalf("expected query guardrail defaults")
 	}
-	if cfg.Query.MaxConcurrent == 0 || cfg.Query.QueueSize == 0 || cfg.Query.QueueTimeoutSec == 0 {
+	if cfg.Query.MaxConcurrent == 0 || cfg.Query.QueueSize == 0 || cfg.Query.QueueTimeoutSec == 0 || cfg.Query.ScanParallelism == 0 || cfg.Query.JoinMemoryBytes == 0 {
 		t.Fatalf("expected query queue defaults")
 	}
 	if cfg.Metadata.Snapshot.Key == "" {
@@ -61,6 +61,12 @@ func TestLoadDefaults(t *testing.T) {
 	if cfg.ResultCache.TTLSeconds == 0 || cfg.ResultCache.MaxEntries == 0 || cfg.ResultCache.MaxRows == 0 {
 		t.Fatalf("expected result cache defaults")
 	}
+	if cfg.HTTP.MaxJobs == 0 || cfg.HTTP.JobTTLSeconds == 0 || cfg.HTTP.PageSize == 0 || cfg.HTTP.MaxPageSize == 0 {
+		t.Fatalf("expected http defaults")
+	}
+	if cfg.Export.Enabled || cfg.Export.MaxRowsPerFile == 0 {
+		t.Fatalf("expected export defaults")
+	}
 	if cfg.Proxy.Listen == "" || cfg.Proxy.MaxConnections == 0 {
 		t.Fatalf("expected proxy defaults")
 	}
@@ -91,11 +97,13 @@ func TestLoadEnvOverrides(t *testing.T) {
 	t.Setenv("KAFSQL_TIME_INDEX_BUILD_LEASE_TTL_SECONDS", "180")
 	t.Setenv("KAFSQL_QUERY_MAX_SCAN_BYTES", "2048")
 	t.Setenv("KAFSQL_QUERY_MAX_SCAN_SEGMENTS", "12")
+	t.Setenv("KAFSQL_QUERY_MAX_DECODED_BYTES", "4096")
 	t.Setenv("KAFSQL_QUERY_MAX_ROWS", "345")
 	t.Setenv("KAFSQL_QUERY_TIMEOUT_SECONDS", "17")
 	t.Setenv("KAFSQL_QUERY_MAX_CONCURRENT", "7")
 	t.Setenv("KAFSQL_QUERY_QUEUE_SIZE", "9")
 	t.Setenv("KAFSQL_QUERY_QUEUE_TIMEOUT_SECONDS", "3")
+	t.Setenv("KAFSQL_QUERY_SCAN_PARALLELISM", "6")
 	t.Setenv("KAFSQL_PROXY_LISTEN", ":6432")
 	t.Setenv("KAFSQL_PROXY_UPSTREAMS", "kafsql-0:5432,kafsql-1:5432")
 	t.Setenv("KAFSQL_PROXY_MAX_CONNECTIONS", "55")
@@ -141,10 +149,10 @@ func TestLoadEnvOverrides(t *testing.T) {
 	if cfg.TimeIndex.BuildLeaseTTLSeconds != 180 {
 		t.Fatalf("expected time index lease override, got %+v", cfg.TimeIndex)
 	}
-	if cfg.Query.MaxScanBytes != 2048 || cfg.Query.MaxScanSegments != 12 || cfg.Query.MaxRows != 345 || cfg.Query.TimeoutSeconds != 17 {
+	if cfg.Query.MaxScanBytes != 2048 || cfg.Query.MaxScanSegments != 12 || cfg.Query.MaxDecodedBytes != 4096 || cfg.Query.MaxRows != 345 || cfg.Query.TimeoutSeconds != 17 {
 		t.Fatalf("expected query guardrail overrides, got %+v", cfg.Query)
 	}
-	if cfg.Query.MaxConcurrent != 7 || cfg.Query.QueueSize != 9 || cfg.Query.QueueTimeoutSec != 3 {
+	if cfg.Query.MaxConcurrent != 7 || cfg.Query.QueueSize != 9 || cfg.Query.QueueTimeoutSec != 3 || cfg.Query.ScanParallelism != 6 {
 		t.Fatalf("expected query queue overrides, got %+v", cfg.Query)
 	}
 	if cfg.Proxy.Listen != ":6432" || cfg.Proxy.MaxConnections != 55 {
@@ -59,7 +59,34 @@ type Decoder interface {
 	Decode(ctx context.Context, segmentKey, indexKey string, topic string, partition int32) ([]Record, error)
 }
 
+// ObjectWriter uploads objects to S3 with the decoder's client settings.
+type ObjectWriter interface {
+	PutObject(ctx context.Context, bucket, key string, body []byte, contentType string) error
+}
+
 func New(cfg config.Config) (Decoder, error) {
+	client, err := newS3Client(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Decoder{
+		client:  client,
+		bucket:  cfg.S3.Bucket,
+		metrics: newS3Metrics(),
+	}, nil
+}
+
+// NewObjectWriter returns an ObjectWriter that shares the decoder's AWS
+// configuration (region, endpoint, path style).
+func NewObjectWriter(cfg config.Config) (ObjectWriter, error) {
+	client, err := newS3Client(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &s3ObjectWriter{client: client, metrics: newS3Metrics()}, nil
+}
+
+func newS3Client(cfg config.Config) (*s3.Client, error) {
 	loadOptions := []func(*awsconfig.LoadOptions) error{}
 	if cfg.S3.Region != "" {
 		loadOptions = append(loadOptions, awsconfig.WithRegion(cfg.S3.Region))
@@ -79,17 +106,11 @@ func New(cfg config.Config) (Decoder, error) {
 		return nil, fmt.Errorf("load aws config: %w", err)
 	}
 
-	client := s3.NewFromConfig(awsCfg, func(opts *s3.Options) {
+	return s3.NewFromConfig(awsCfg, func(opts *s3.Options) {
 		if cfg.S3.PathStyle {
 			opts.UsePathStyle = true
 		}
-	})
-
-	return &s3Decoder{
-		client:  client,
-		bucket:  cfg.S3.Bucket,
-		metrics: newS3Metrics(),
-	}, nil
+	}), nil
 }
 
 type s3Decoder struct {
@@ -158,6 +179,28 @@ func (d *s3Decoder) getObject(ctx context.Context, op, key string) ([]byte, erro
 	return data, nil
 }
 
+type s3ObjectWriter struct {
+	client  *s3.Client
+	metrics s3Metrics
+}
+
+func (w *s3ObjectWriter) PutObject(ctx context.Context, bucket, key string, body []byte, contentType string) error {
+	start := time.Now()
+	w.metrics.requests.WithLabelValues("put").Inc()
+	_, err := w.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String(contentType),
+	})
+	w.metrics.duration.WithLabelValues("put").Observe(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		w.metrics.errors.WithLabelValues("put").Inc()
+		return err
+	}
+	return nil
+}
+
 func decodeSegment(segment []byte, topic string, partition int32) ([]Record, error) {
 	if len(segment) < segmentHeaderLen+segmentFooterLen {
 		return nil, fmt.Errorf("segment too small")
@@ -66,6 +66,50 @@ var (
 			Help:      "Total unbounded queries rejected.",
 		},
 	)
+	QueryScanParallelism = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "query_scan_parallelism",
+			Help:      "Concurrent segment decodes used per scan.",
+			Buckets:   []float64{1, 2, 4, 8, 16, 32},
+		},
+	)
+	ScanSegmentsInFlight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "scan_segments_in_flight",
+			Help:      "Segments currently being fetched and decoded.",
+		},
+	)
+	JoinSpills = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "join_spills_total",
+			Help:      "Joins whose build side exceeded the memory budget and spilled to disk.",
+		},
+	)
+	JoinSpillBytes = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "join_spill_bytes_total",
+			Help:      "Approximate bytes of join build records written to spill files.",
+		},
+	)
+	ExportFiles = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "export_files_total",
+			Help:      "Total files written by COPY TO exports by format.",
+		},
+		[]string{"format"},
+	)
+	ExportBytes = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "export_bytes_written_total",
+			Help:      "Total bytes written by COPY TO exports.",
+		},
+	)
 	S3Requests = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: namespace,
@@ -184,6 +228,21 @@ var (
 			Buckets:   prometheus.DefBuckets,
 		},
 	)
+	HTTPJobsActive = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_jobs_active",
+			Help:      "HTTP query jobs queued or running.",
+		},
+	)
+	HTTPJobsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_jobs_total",
+			Help:      "HTTP query jobs by final status.",
+		},
+		[]string{"status"},
+	)
 )
 
 func init() {
@@ -194,6 +253,12 @@ func init() {
 		QueryBytes,
 		QuerySegments,
 		QueryUnboundedRejected,
+		QueryScanParallelism,
+		ScanSegmentsInFlight,
+		JoinSpills,
+		JoinSpillBytes,
+		ExportFiles,
+		ExportBytes,
 		S3Requests,
 		S3Bytes,
 		S3Duration,
@@ -210,5 +275,7 @@ func init() {
 		QueryQueueRejected,
 		QueryQueueTimeout,
 		QueryQueueWait,
+		HTTPJobsActive,
+		HTTPJobsTotal,
 	)
 }
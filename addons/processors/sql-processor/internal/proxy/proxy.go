@@ -304,6 +304,11 @@ func queryTopics(parsed kafsql.Query) ([]string, bool) {
 			return queryTopics(*parsed.Explain)
 		}
 		return nil, false
+	case kafsql.QueryCopy:
+		if parsed.Copy != nil {
+			return queryTopics(parsed.Copy.Source)
+		}
+		return nil, false
 	case kafsql.QuerySelect:
 		topics := []string{parsed.Topic}
 		if parsed.JoinTopic != "" {
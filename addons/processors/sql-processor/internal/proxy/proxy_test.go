@@ -370,6 +370,17 @@ func TestQueryTopicsExplain(t *testing.T) {
 	}
 }
 
+func TestQueryTopicsCopy(t *testing.T) {
+	parsed, err := kafsql.Parse("COPY (SELECT * FROM orders LAST 1h) TO 's3://exports/orders';")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	topics, showTopics := queryTopics(parsed)
+	if showTopics || len(topics) != 1 || topics[0] != "orders" {
+		t.Fatalf("unexpected topics: %v show=%t", topics, showTopics)
+	}
+}
+
 func TestTrimQuery(t *testing.T) {
 	query := strings.Repeat("a", 600)
 	trimmed := trimQuery(query)
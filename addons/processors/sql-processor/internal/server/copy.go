@@ -0,0 +1,481 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/jackc/pgproto3/v2"
+
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/decoder"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/metrics"
+	kafsql "github.com/kafscale/platform/addons/processors/sql-processor/internal/sql"
+)
+
+const (
+	copyPartitionColumn = "__copy_partition"
+	copyOffsetColumn    = "__copy_offset"
+	copyManifestName    = "_manifest.json"
+)
+
+// copyManifest is written next to the exported files once every file has been
+// uploaded, so readers can treat its presence as a completed snapshot.
+type copyManifest struct {
+	Query      string                  `json:"query"`
+	Topic      string                  `json:"topic"`
+	Format     string                  `json:"format"`
+	CreatedAt  time.Time               `json:"created_at"`
+	Rows       int64                   `json:"rows"`
+	Truncated  bool                    `json:"truncated,omitempty"`
+	RowLimit   int                     `json:"row_limit,omitempty"`
+	Columns    []string                `json:"columns"`
+	Partitions []copyManifestPartition `json:"partitions,omitempty"`
+	Files      []copyManifestFile      `json:"files"`
+}
+
+type copyManifestPartition struct {
+	Partition int32 `json:"partition"`
+	Rows      int64 `json:"rows"`
+	MinOffset int64 `json:"min_offset"`
+	MaxOffset int64 `json:"max_offset"`
+}
+
+type copyManifestFile struct {
+	Key       string `json:"key"`
+	Partition *int32 `json:"partition,omitempty"`
+	Rows      int    `json:"rows"`
+	Bytes     int    `json:"bytes"`
+	MinOffset *int64 `json:"min_offset,omitempty"`
+	MaxOffset *int64 `json:"max_offset,omitempty"`
+}
+
+func copyResultFields() []pgproto3.FieldDescription {
+	return []pgproto3.FieldDescription{
+		{Name: []byte("rows"), DataTypeOID: 20, DataTypeSize: 8, TypeModifier: -1, Format: 0},
+		{Name: []byte("files"), DataTypeOID: 23, DataTypeSize: 4, TypeModifier: -1, Format: 0},
+		{Name: []byte("manifest"), DataTypeOID: 25, DataTypeSize: -1, TypeModifier: -1, Format: 0},
+		{Name: []byte("truncated"), DataTypeOID: 16, DataTypeSize: 1, TypeModifier: -1, Format: 0},
+	}
+}
+
+func (s *Server) handleCopy(ctx context.Context, backend messageWriter, parsed kafsql.Query) (queryResult, error) {
+	if parsed.Copy == nil {
+		return queryResult{}, errors.New("copy requires a source query")
+	}
+	if !s.cfg.Export.Enabled {
+		return queryResult{}, errors.New("copy to is disabled (export.enabled=false)")
+	}
+	stmt := parsed.Copy
+	bucket, prefix, err := parseS3Target(stmt.Target)
+	if err != nil {
+		return queryResult{}, err
+	}
+	if len(s.cfg.Export.AllowedTargets) == 0 {
+		return queryResult{}, errors.New("copy to requires export.allowed_targets")
+	}
+	if !s.exportAllowed(bucket, prefix) {
+		return queryResult{}, fmt.Errorf("copy target %s is not in export.allowed_targets", stmt.Target)
+	}
+	writer, err := s.getObjectWriter()
+	if err != nil {
+		return queryResult{}, err
+	}
+
+	source := stmt.Source
+	source.Select = append([]kafsql.SelectColumn(nil), source.Select...)
+	partitioned := source.JoinTopic == "" && !hasAggregates(source.Select)
+	hidden := 0
+	if partitioned && !isStarSelect(source.Select) {
+		source.Select = append(source.Select,
+			kafsql.SelectColumn{Kind: kafsql.SelectColumnField, Column: "_partition", Alias: copyPartitionColumn},
+			kafsql.SelectColumn{Kind: kafsql.SelectColumnField, Column: "_offset", Alias: copyOffsetColumn},
+		)
+		hidden = 2
+	}
+	rowLimit := 0
+	if source.Limit == "" && source.Tail == "" {
+		rowLimit = s.copyRowLimit(source)
+		source.Limit = strconv.Itoa(rowLimit)
+	}
+
+	maxRows := stmt.MaxRowsPerFile
+	if maxRows <= 0 {
+		maxRows = s.cfg.Export.MaxRowsPerFile
+	}
+	exporter := &copyExporter{
+		ctx:         ctx,
+		writer:      writer,
+		bucket:      bucket,
+		prefix:      prefix,
+		format:      stmt.Format,
+		maxRows:     maxRows,
+		partitioned: partitioned,
+		hidden:      hidden,
+		files:       make(map[int32]*copyFile),
+		offsets:     make(map[int32]*copyManifestPartition),
+	}
+	result, err := s.handleSelect(ctx, exporter, source, nil)
+	if err != nil {
+		return result, err
+	}
+	if err := exporter.flushAll(); err != nil {
+		return result, err
+	}
+	// An export that fills the implicit cap may have left rows behind.
+	truncated := rowLimit > 0 && exporter.rows >= int64(rowLimit)
+	manifestKey, err := exporter.writeManifest(parsed, stmt.Format, truncated, rowLimit)
+	if err != nil {
+		return result, err
+	}
+
+	manifestURL := fmt.Sprintf("s3://%s/%s", bucket, manifestKey)
+	if err := backend.Send(&pgproto3.RowDescription{Fields: copyResultFields()}); err != nil {
+		return result, err
+	}
+	row := [][]byte{
+		[]byte(strconv.FormatInt(exporter.rows, 10)),
+		[]byte(strconv.Itoa(len(exporter.manifestFiles))),
+		[]byte(manifestURL),
+		[]byte(strconv.FormatBool(truncated)),
+	}
+	if err := backend.Send(&pgproto3.DataRow{Values: row}); err != nil {
+		return result, err
+	}
+	if err := backend.Send(&pgproto3.CommandComplete{CommandTag: []byte(fmt.Sprintf("COPY %d", exporter.rows))}); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// copyRowLimit bounds exports that have no explicit LIMIT by the same
+// governance limits as interactive queries.
+func (s *Server) copyRowLimit(parsed kafsql.Query) int {
+	limit := s.cfg.Query.MaxRows
+	if parsed.ScanFull && s.cfg.Query.MaxUnbounded > 0 && (limit <= 0 || s.cfg.Query.MaxUnbounded < limit) {
+		limit = s.cfg.Query.MaxUnbounded
+	}
+	if limit <= 0 {
+		limit = s.cfg.Query.DefaultLimit
+	}
+	return limit
+}
+
+// exportAllowed reports whether bucket and prefix fall under one of
+// export.allowed_targets. Prefixes match whole path segments, so
+// s3://exports/team does not allow s3://exports/team-b.
+func (s *Server) exportAllowed(bucket, prefix string) bool {
+	for _, allowed := range s.cfg.Export.AllowedTargets {
+		allowedBucket, allowedPrefix, err := parseS3Target(strings.TrimSpace(allowed))
+		if err != nil || allowedBucket != bucket {
+			continue
+		}
+		if allowedPrefix == "" || prefix == allowedPrefix || strings.HasPrefix(prefix, allowedPrefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func isStarSelect(cols []kafsql.SelectColumn) bool {
+	return len(cols) == 0 || (len(cols) == 1 && cols[0].Kind == kafsql.SelectColumnStar)
+}
+
+func parseS3Target(target string) (string, string, error) {
+	if !strings.HasPrefix(strings.ToLower(target), "s3://") {
+		return "", "", fmt.Errorf("copy target must be an s3:// url")
+	}
+	rest := target[len("s3://"):]
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("copy target %s has no bucket", target)
+	}
+	// Keys are built with path.Join, so resolve dot segments up front and
+	// check the prefix that is actually written to.
+	return bucket, strings.Trim(path.Clean("/"+prefix), "/"), nil
+}
+
+// copyFile buffers the rows of the file currently open for one partition.
+type copyFile struct {
+	seq       int
+	rows      [][][]byte
+	minOffset *int64
+	maxOffset *int64
+}
+
+// copyExporter receives the rows of the source select and writes them to S3,
+// one file sequence per Kafka partition.
+type copyExporter struct {
+	ctx         context.Context
+	writer      decoder.ObjectWriter
+	bucket      string
+	prefix      string
+	format      string
+	maxRows     int
+	partitioned bool
+	hidden      int
+
+	fields       []pgproto3.FieldDescription
+	partitionIdx int
+	offsetIdx    int
+
+	files         map[int32]*copyFile
+	offsets       map[int32]*copyManifestPartition
+	manifestFiles []copyManifestFile
+	rows          int64
+}
+
+func (e *copyExporter) Send(msg pgproto3.BackendMessage) error {
+	switch m := msg.(type) {
+	case *pgproto3.RowDescription:
+		return e.describe(m.Fields)
+	case *pgproto3.DataRow:
+		return e.appendRow(m.Values)
+	case *pgproto3.ErrorResponse:
+		return errors.New(m.Message)
+	}
+	return nil
+}
+
+func (e *copyExporter) describe(fields []pgproto3.FieldDescription) error {
+	e.fields = fields[:len(fields)-e.hidden]
+	e.partitionIdx, e.offsetIdx = -1, -1
+	if !e.partitioned {
+		return nil
+	}
+	partitionName, offsetName := "_partition", "_offset"
+	if e.hidden > 0 {
+		partitionName, offsetName = copyPartitionColumn, copyOffsetColumn
+	}
+	for i, field := range fields {
+		switch string(field.Name) {
+		case partitionName:
+			e.partitionIdx = i
+		case offsetName:
+			e.offsetIdx = i
+		}
+	}
+	if e.partitionIdx == -1 || e.offsetIdx == -1 {
+		return errors.New("copy source is missing partition columns")
+	}
+	return nil
+}
+
+func (e *copyExporter) appendRow(values [][]byte) error {
+	partition := int32(-1)
+	var offset *int64
+	if e.partitioned {
+		value, err := strconv.ParseInt(string(values[e.partitionIdx]), 10, 32)
+		if err != nil {
+			return fmt.Errorf("copy: invalid partition value: %w", err)
+		}
+		partition = int32(value)
+		parsed, err := strconv.ParseInt(string(values[e.offsetIdx]), 10, 64)
+		if err != nil {
+			return fmt.Errorf("copy: invalid offset value: %w", err)
+		}
+		offset = &parsed
+	}
+
+	file := e.files[partition]
+	if file == nil {
+		file = &copyFile{}
+		e.files[partition] = file
+	}
+	file.rows = append(file.rows, values[:len(values)-e.hidden])
+	if offset != nil {
+		file.minOffset = minInt64Ptr(file.minOffset, *offset)
+		file.maxOffset = maxInt64Ptr(file.maxOffset, *offset)
+		summary := e.offsets[partition]
+		if summary == nil {
+			summary = &copyManifestPartition{Partition: partition, MinOffset: *offset, MaxOffset: *offset}
+			e.offsets[partition] = summary
+		}
+		summary.Rows++
+		if *offset < summary.MinOffset {
+			summary.MinOffset = *offset
+		}
+		if *offset > summary.MaxOffset {
+			summary.MaxOffset = *offset
+		}
+	}
+	e.rows++
+	if len(file.rows) >= e.maxRows {
+		return e.flush(partition, file)
+	}
+	return nil
+}
+
+func (e *copyExporter) flushAll() error {
+	partitions := make([]int32, 0, len(e.files))
+	for partition := range e.files {
+		partitions = append(partitions, partition)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	for _, partition := range partitions {
+		if err := e.flush(partition, e.files[partition]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *copyExporter) flush(partition int32, file *copyFile) error {
+	if len(file.rows) == 0 {
+		return nil
+	}
+	body, contentType, err := encodeCopyFile(e.format, e.fields, file.rows)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("part-%05d.%s", file.seq, e.format)
+	if e.partitioned {
+		name = path.Join(fmt.Sprintf("partition=%d", partition), name)
+	}
+	key := path.Join(e.prefix, name)
+	if err := e.writer.PutObject(e.ctx, e.bucket, key, body, contentType); err != nil {
+		return fmt.Errorf("copy: write %s: %w", key, err)
+	}
+	metrics.ExportFiles.WithLabelValues(e.format).Inc()
+	metrics.ExportBytes.Add(float64(len(body)))
+
+	entry := copyManifestFile{
+		Key:       key,
+		Rows:      len(file.rows),
+		Bytes:     len(body),
+		MinOffset: file.minOffset,
+		MaxOffset: file.maxOffset,
+	}
+	if e.partitioned {
+		p := partition
+		entry.Partition = &p
+	}
+	e.manifestFiles = append(e.manifestFiles, entry)
+	file.seq++
+	file.rows = nil
+	file.minOffset = nil
+	file.maxOffset = nil
+	return nil
+}
+
+func (e *copyExporter) writeManifest(parsed kafsql.Query, format string, truncated bool, rowLimit int) (string, error) {
+	manifest := copyManifest{
+		Topic:     parsed.Topic,
+		Format:    format,
+		CreatedAt: time.Now().UTC(),
+		Rows:      e.rows,
+		Truncated: truncated,
+		Files:     e.manifestFiles,
+	}
+	if truncated {
+		manifest.RowLimit = rowLimit
+	}
+	if parsed.Copy != nil {
+		manifest.Query = parsed.Copy.SourceSQL
+	}
+	for _, field := range e.fields {
+		manifest.Columns = append(manifest.Columns, string(field.Name))
+	}
+	for _, summary := range e.offsets {
+		manifest.Partitions = append(manifest.Partitions, *summary)
+	}
+	sort.Slice(manifest.Partitions, func(i, j int) bool {
+		return manifest.Partitions[i].Partition < manifest.Partitions[j].Partition
+	})
+	if manifest.Files == nil {
+		manifest.Files = []copyManifestFile{}
+	}
+	body, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	key := path.Join(e.prefix, copyManifestName)
+	if err := e.writer.PutObject(e.ctx, e.bucket, key, body, "application/json"); err != nil {
+		return "", fmt.Errorf("copy: write manifest: %w", err)
+	}
+	return key, nil
+}
+
+func encodeCopyFile(format string, fields []pgproto3.FieldDescription, rows [][][]byte) ([]byte, string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "csv":
+		if err := writeCSV(&buf, fields, rows); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "text/csv", nil
+	case "ndjson":
+		if err := writeNDJSON(&buf, fields, rows); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "application/x-ndjson", nil
+	case "parquet":
+		if err := writeParquet(&buf, fields, rows); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "application/vnd.apache.parquet", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported copy format %q", format)
+	}
+}
+
+func writeParquet(buf *bytes.Buffer, fields []pgproto3.FieldDescription, rows [][][]byte) error {
+	schema := arrowSchema(fields)
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+	for _, row := range rows {
+		for i := range fields {
+			var value []byte
+			if i < len(row) {
+				value = row[i]
+			}
+			appendArrowValue(builder.Field(i), value)
+		}
+	}
+	record := builder.NewRecordBatch()
+	defer record.Release()
+
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	writer, err := pqarrow.NewFileWriter(schema, buf, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return err
+	}
+	if err := writer.Write(record); err != nil {
+		_ = writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+func minInt64Ptr(current *int64, value int64) *int64 {
+	if current == nil || value < *current {
+		return &value
+	}
+	return current
+}
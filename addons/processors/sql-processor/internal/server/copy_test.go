@@ -0,0 +1,233 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/jackc/pgproto3/v2"
+
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/decoder"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/discovery"
+	kafsql "github.com/kafscale/platform/addons/processors/sql-processor/internal/sql"
+)
+
+type memoryObjectWriter struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (w *memoryObjectWriter) PutObject(ctx context.Context, bucket, key string, body []byte, contentType string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.objects == nil {
+		w.objects = make(map[string][]byte)
+	}
+	w.objects[bucket+"/"+key] = append([]byte(nil), body...)
+	return nil
+}
+
+type recordingWriter struct {
+	messages []pgproto3.BackendMessage
+}
+
+func (w *recordingWriter) Send(msg pgproto3.BackendMessage) error {
+	w.messages = append(w.messages, msg)
+	return nil
+}
+
+func newCopyTestServer(objects *memoryObjectWriter) *Server {
+	now := time.Now().UTC().UnixMilli()
+	segments := []discovery.SegmentRef{
+		{Topic: "orders", Partition: 0, SegmentKey: "seg-0"},
+		{Topic: "orders", Partition: 1, SegmentKey: "seg-1"},
+	}
+	srv := newTestServer(&mockLister{segments: segments}, &mockDecoder{records: map[string][]decoder.Record{
+		"seg-0": {
+			{Topic: "orders", Partition: 0, Offset: 10, Timestamp: now - 3000, Value: []byte(`{"a":1}`)},
+			{Topic: "orders", Partition: 0, Offset: 11, Timestamp: now - 2000, Value: []byte(`{"a":2}`)},
+			{Topic: "orders", Partition: 0, Offset: 12, Timestamp: now - 1000, Value: []byte(`{"a":3}`)},
+		},
+		"seg-1": {
+			{Topic: "orders", Partition: 1, Offset: 4, Timestamp: now - 1500, Value: []byte(`{"a":4}`)},
+		},
+	}})
+	srv.cfg.Export.Enabled = true
+	srv.cfg.Export.MaxRowsPerFile = 100
+	srv.cfg.Export.AllowedTargets = []string{"s3://exports/"}
+	srv.objectWriter = objects
+	srv.objectWriterInit = true
+	return srv
+}
+
+func runCopy(t *testing.T, srv *Server, query string) (*recordingWriter, error) {
+	t.Helper()
+	parsed, err := kafsql.Parse(query)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	out := &recordingWriter{}
+	_, err = srv.executeQuery(context.Background(), out, parsed)
+	return out, err
+}
+
+func TestCopyToNDJSONPartitioned(t *testing.T) {
+	objects := &memoryObjectWriter{}
+	srv := newCopyTestServer(objects)
+
+	out, err := runCopy(t, srv, "COPY (SELECT json_value(_value, '$.a') AS a FROM orders LAST 1h) TO 's3://exports/orders/run1' (FORMAT ndjson, MAX_ROWS_PER_FILE 2)")
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	complete, ok := out.messages[len(out.messages)-1].(*pgproto3.CommandComplete)
+	if !ok || string(complete.CommandTag) != "COPY 4" {
+		t.Fatalf("unexpected completion: %+v", out.messages[len(out.messages)-1])
+	}
+
+	first := objects.objects["exports/orders/run1/partition=0/part-00000.ndjson"]
+	if string(first) != "{\"a\":\"1\"}\n{\"a\":\"2\"}\n" {
+		t.Fatalf("unexpected first file: %q", first)
+	}
+	if _, ok := objects.objects["exports/orders/run1/partition=0/part-00001.ndjson"]; !ok {
+		t.Fatalf("expected rolled file, got %v", keys(objects.objects))
+	}
+	if _, ok := objects.objects["exports/orders/run1/partition=1/part-00000.ndjson"]; !ok {
+		t.Fatalf("expected partition 1 file, got %v", keys(objects.objects))
+	}
+
+	var manifest copyManifest
+	if err := json.Unmarshal(objects.objects["exports/orders/run1/_manifest.json"], &manifest); err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	if manifest.Rows != 4 || len(manifest.Files) != 3 || len(manifest.Columns) != 1 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	if len(manifest.Partitions) != 2 || manifest.Partitions[0].MinOffset != 10 || manifest.Partitions[0].MaxOffset != 12 || manifest.Partitions[1].Rows != 1 {
+		t.Fatalf("unexpected partition offsets: %+v", manifest.Partitions)
+	}
+	if !strings.HasPrefix(manifest.Query, "SELECT json_value") {
+		t.Fatalf("unexpected manifest query: %q", manifest.Query)
+	}
+	if manifest.Truncated || manifest.RowLimit != 0 {
+		t.Fatalf("expected complete export, got truncated=%v row_limit=%d", manifest.Truncated, manifest.RowLimit)
+	}
+}
+
+func TestCopyMarksCappedExportTruncated(t *testing.T) {
+	objects := &memoryObjectWriter{}
+	srv := newCopyTestServer(objects)
+	srv.cfg.Query.MaxRows = 3
+
+	out, err := runCopy(t, srv, "COPY (SELECT * FROM orders LAST 1h) TO 's3://exports/orders' (FORMAT ndjson)")
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	var manifest copyManifest
+	if err := json.Unmarshal(objects.objects["exports/orders/_manifest.json"], &manifest); err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	if manifest.Rows != 3 || !manifest.Truncated || manifest.RowLimit != 3 {
+		t.Fatalf("expected truncated manifest at 3 rows, got %+v", manifest)
+	}
+	var row *pgproto3.DataRow
+	for _, msg := range out.messages {
+		if m, ok := msg.(*pgproto3.DataRow); ok {
+			row = m
+		}
+	}
+	if row == nil || string(row.Values[3]) != "true" {
+		t.Fatalf("expected truncated result row, got %+v", row)
+	}
+}
+
+func TestCopyToParquet(t *testing.T) {
+	objects := &memoryObjectWriter{}
+	srv := newCopyTestServer(objects)
+
+	if _, err := runCopy(t, srv, "COPY (SELECT * FROM orders LAST 1h) TO 's3://exports/orders'"); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	body := objects.objects["exports/orders/partition=0/part-00000.parquet"]
+	reader, err := file.NewParquetReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("parquet reader: %v", err)
+	}
+	defer reader.Close()
+	if reader.NumRows() != 3 {
+		t.Fatalf("expected 3 rows, got %d", reader.NumRows())
+	}
+	arrowReader, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatalf("arrow reader: %v", err)
+	}
+	schema, err := arrowReader.Schema()
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	if schema.Field(2).Name != "_offset" {
+		t.Fatalf("unexpected schema: %s", schema)
+	}
+}
+
+func TestCopyRejectsDisabledAndDisallowedTargets(t *testing.T) {
+	objects := &memoryObjectWriter{}
+	srv := newCopyTestServer(objects)
+	srv.cfg.Export.Enabled = false
+	if _, err := runCopy(t, srv, "COPY (SELECT * FROM orders LAST 1h) TO 's3://exports/orders'"); err == nil {
+		t.Fatalf("expected disabled export error")
+	}
+
+	srv.cfg.Export.Enabled = true
+	srv.cfg.Export.AllowedTargets = nil
+	if _, err := runCopy(t, srv, "COPY (SELECT * FROM orders LAST 1h) TO 's3://exports/orders'"); err == nil {
+		t.Fatalf("expected empty allow-list to deny exports")
+	}
+
+	srv.cfg.Export.AllowedTargets = []string{"s3://exports/allowed/"}
+	for _, target := range []string{
+		"s3://exports/orders",
+		"s3://exports/allowed-b/run1",
+		"s3://exports/allowed/../orders",
+		"s3://exports-b/allowed/run1",
+	} {
+		if _, err := runCopy(t, srv, "COPY (SELECT * FROM orders LAST 1h) TO '"+target+"'"); err == nil {
+			t.Fatalf("expected disallowed target error for %s", target)
+		}
+	}
+	if len(objects.objects) != 0 {
+		t.Fatalf("expected no objects written")
+	}
+	if _, err := runCopy(t, srv, "COPY (SELECT * FROM orders LAST 1h) TO 's3://exports/allowed/run1'"); err != nil {
+		t.Fatalf("expected allowed target, got %v", err)
+	}
+}
+
+func keys(m map[string][]byte) []string {
+	out := make([]string, 0, len(m))
+	for key := range m {
+		out = append(out, key)
+	}
+	return out
+}
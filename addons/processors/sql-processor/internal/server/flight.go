@@ -0,0 +1,509 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/flight/flightsql"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/jackc/pgproto3/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/config"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/metrics"
+	kafsql "github.com/kafscale/platform/addons/processors/sql-processor/internal/sql"
+)
+
+// FlightServer exposes the KafSQL engine over Arrow Flight SQL. It shares the
+// parser, planner, lister and decoder with the Postgres front end and streams
+// results as columnar record batches.
+type FlightServer struct {
+	flightsql.BaseServer
+
+	srv       *Server
+	listen    string
+	batchRows int
+}
+
+// flightTicket is the statement handle embedded in each FlightEndpoint ticket.
+type flightTicket struct {
+	Query     string `json:"query"`
+	Partition *int32 `json:"partition,omitempty"`
+}
+
+func NewFlightServer(srv *Server) *FlightServer {
+	batchRows := srv.cfg.Server.FlightBatchRows
+	if batchRows <= 0 {
+		batchRows = config.DefaultFlightBatchRows
+	}
+	f := &FlightServer{
+		srv:       srv,
+		listen:    srv.cfg.Server.FlightListen,
+		batchRows: batchRows,
+	}
+	f.Alloc = memory.DefaultAllocator
+	_ = f.RegisterSqlInfo(flightsql.SqlInfoFlightSqlServerName, "kafsql")
+	_ = f.RegisterSqlInfo(flightsql.SqlInfoFlightSqlServerVersion, srv.serverVersion)
+	_ = f.RegisterSqlInfo(flightsql.SqlInfoFlightSqlServerReadOnly, true)
+	return f
+}
+
+func (f *FlightServer) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", f.listen)
+	if err != nil {
+		return err
+	}
+	return f.Serve(ctx, ln)
+}
+
+// Serve handles Flight SQL requests on ln until ctx is cancelled.
+func (f *FlightServer) Serve(ctx context.Context, ln net.Listener) error {
+	server := flight.NewServerWithMiddleware(nil)
+	server.RegisterFlightService(flightsql.NewFlightServer(f))
+	server.InitListener(ln)
+
+	go func() {
+		<-ctx.Done()
+		server.Shutdown()
+	}()
+	return server.Serve()
+}
+
+func (f *FlightServer) GetFlightInfoStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	query := strings.TrimSpace(cmd.GetQuery())
+	parsed, schema, err := f.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions, err := f.endpointPartitions(ctx, parsed)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	tickets := make([]flightTicket, 0, len(partitions))
+	for i := range partitions {
+		tickets = append(tickets, flightTicket{Query: query, Partition: &partitions[i]})
+	}
+	if len(tickets) == 0 {
+		tickets = append(tickets, flightTicket{Query: query})
+	}
+
+	endpoints := make([]*flight.FlightEndpoint, 0, len(tickets))
+	for _, ticket := range tickets {
+		handle, err := json.Marshal(ticket)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		encoded, err := flightsql.CreateStatementQueryTicket(handle)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		endpoints = append(endpoints, &flight.FlightEndpoint{Ticket: &flight.Ticket{Ticket: encoded}})
+	}
+
+	return &flight.FlightInfo{
+		Schema:           flight.SerializeSchema(schema, f.Alloc),
+		FlightDescriptor: desc,
+		Endpoint:         endpoints,
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+func (f *FlightServer) GetSchemaStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.SchemaResult, error) {
+	_, schema, err := f.prepare(strings.TrimSpace(cmd.GetQuery()))
+	if err != nil {
+		return nil, err
+	}
+	return &flight.SchemaResult{Schema: flight.SerializeSchema(schema, f.Alloc)}, nil
+}
+
+func (f *FlightServer) DoGetStatement(ctx context.Context, cmd flightsql.StatementQueryTicket) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	var ticket flightTicket
+	if err := json.Unmarshal(cmd.GetStatementHandle(), &ticket); err != nil {
+		return nil, nil, status.Error(codes.InvalidArgument, "invalid statement handle")
+	}
+	parsed, schema, err := f.prepare(ticket.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ticket.Partition != nil {
+		parsed.Partition = ticket.Partition
+	}
+
+	ch := make(chan flight.StreamChunk, 2)
+	go func() {
+		defer close(ch)
+		writer := newArrowBatchWriter(ctx, f.Alloc, schema, f.batchRows, ch)
+		defer writer.release()
+		stmt := preparedStatement{query: ticket.Query, parsed: parsed}
+		if err := f.srv.handlePreparedQuery(ctx, writer, stmt); err != nil {
+			writer.fail(err)
+			return
+		}
+		if err := writer.flush(); err != nil {
+			writer.fail(err)
+		}
+	}()
+	return schema, ch, nil
+}
+
+// prepare parses a statement and derives its Arrow result schema.
+func (f *FlightServer) prepare(query string) (kafsql.Query, *arrow.Schema, error) {
+	if query == "" {
+		return kafsql.Query{}, nil, status.Error(codes.InvalidArgument, "empty query")
+	}
+	parsed, err := kafsql.Parse(query)
+	if err != nil {
+		metrics.QueriesTotal.WithLabelValues("parse", "error").Inc()
+		return kafsql.Query{}, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	fields, err := f.srv.describeFields(parsed)
+	if err != nil {
+		return kafsql.Query{}, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return parsed, arrowSchema(fields), nil
+}
+
+// endpointPartitions returns the partitions a query can be split across, one
+// FlightEndpoint each. Queries whose result depends on seeing every row
+// (joins, aggregates, ORDER BY, TAIL, LIMIT) are served from a single
+// endpoint and return nil.
+func (f *FlightServer) endpointPartitions(ctx context.Context, parsed kafsql.Query) ([]int32, error) {
+	if parsed.Type != kafsql.QuerySelect || parsed.Topic == "" {
+		return nil, nil
+	}
+	if parsed.JoinTopic != "" || hasAggregates(parsed.Select) || parsed.OrderBy != "" || parsed.Tail != "" || parsed.Limit != "" || parsed.Partition != nil {
+		return nil, nil
+	}
+	timeMin, timeMax, err := resolveTimeBounds(parsed)
+	if err != nil {
+		// Leave the error to DoGet so it is reported with the query.
+		return nil, nil
+	}
+	lister, err := f.srv.getLister()
+	if err != nil {
+		return nil, err
+	}
+	segments, err := lister.ListCompleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int32]struct{})
+	partitions := make([]int32, 0)
+	for _, segment := range filterSegments(parsed, segments, timeMin, timeMax) {
+		if _, ok := seen[segment.Partition]; ok {
+			continue
+		}
+		seen[segment.Partition] = struct{}{}
+		partitions = append(partitions, segment.Partition)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	return partitions, nil
+}
+
+func arrowSchema(fields []pgproto3.FieldDescription) *arrow.Schema {
+	out := make([]arrow.Field, 0, len(fields))
+	for _, field := range fields {
+		out = append(out, arrow.Field{Name: string(field.Name), Type: arrowType(field.DataTypeOID), Nullable: true})
+	}
+	return arrow.NewSchema(out, nil)
+}
+
+func arrowType(oid uint32) arrow.DataType {
+	switch oid {
+	case 16:
+		return arrow.FixedWidthTypes.Boolean
+	case 20:
+		return arrow.PrimitiveTypes.Int64
+	case 23:
+		return arrow.PrimitiveTypes.Int32
+	case 701:
+		return arrow.PrimitiveTypes.Float64
+	case 1114:
+		return arrow.FixedWidthTypes.Timestamp_ms
+	case 17:
+		return arrow.BinaryTypes.Binary
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// arrowBatchWriter builds record batches of up to batchRows rows. Scanned
+// rows arrive through WriteRow and are appended from the records themselves;
+// DataRows, used for aggregates and cached results, are parsed from text.
+type arrowBatchWriter struct {
+	ctx       context.Context
+	schema    *arrow.Schema
+	builder   *array.RecordBuilder
+	batchRows int
+	rows      int
+	out       chan<- flight.StreamChunk
+}
+
+func newArrowBatchWriter(ctx context.Context, alloc memory.Allocator, schema *arrow.Schema, batchRows int, out chan<- flight.StreamChunk) *arrowBatchWriter {
+	return &arrowBatchWriter{
+		ctx:       ctx,
+		schema:    schema,
+		builder:   array.NewRecordBuilder(alloc, schema),
+		batchRows: batchRows,
+		out:       out,
+	}
+}
+
+func (w *arrowBatchWriter) Send(msg pgproto3.BackendMessage) error {
+	switch m := msg.(type) {
+	case *pgproto3.DataRow:
+		if len(m.Values) != len(w.schema.Fields()) {
+			return errors.New("row does not match result schema")
+		}
+		for i, value := range m.Values {
+			appendArrowValue(w.builder.Field(i), value)
+		}
+		w.rows++
+		if w.rows >= w.batchRows {
+			return w.flush()
+		}
+	case *pgproto3.ErrorResponse:
+		return errors.New(m.Message)
+	}
+	return nil
+}
+
+func (w *arrowBatchWriter) WriteRow(cols []resolvedColumn, row rowContext) error {
+	if len(cols) != len(w.schema.Fields()) {
+		return errors.New("row does not match result schema")
+	}
+	for i, col := range cols {
+		appendColumnValue(w.builder.Field(i), row, col)
+	}
+	w.rows++
+	if w.rows >= w.batchRows {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *arrowBatchWriter) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+	record := w.builder.NewRecordBatch()
+	w.rows = 0
+	select {
+	case w.out <- flight.StreamChunk{Data: record}:
+		return nil
+	case <-w.ctx.Done():
+		record.Release()
+		return w.ctx.Err()
+	}
+}
+
+func (w *arrowBatchWriter) fail(err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		err = status.FromContextError(err).Err()
+	}
+	select {
+	case w.out <- flight.StreamChunk{Err: err}:
+	case <-w.ctx.Done():
+	}
+}
+
+func (w *arrowBatchWriter) release() {
+	w.builder.Release()
+}
+
+// appendColumnValue appends the value of col in row with the Arrow type
+// arrowType gives its column OID.
+func appendColumnValue(builder array.Builder, row rowContext, col resolvedColumn) {
+	record, seg, ok := selectRecord(row, col.Source)
+	if !ok {
+		builder.AppendNull()
+		return
+	}
+	switch col.Kind {
+	case columnSchema:
+		value, ok := jsonLookup(record.Value, col.Schema.Path, record.Topic)
+		if !ok {
+			builder.AppendNull()
+			return
+		}
+		appendJSONValue(builder, value)
+	case columnJSONExists:
+		root, ok := parseJSON(record.Value)
+		if !ok {
+			builder.AppendNull()
+			return
+		}
+		_, found := jsonPathValue(root, col.JSONPath, "")
+		builder.(*array.BooleanBuilder).Append(found)
+	case columnJSONValue, columnJSONQuery:
+		appendArrowValue(builder, columnValue(row, col))
+	default:
+		switch col.Column {
+		case "_topic":
+			builder.(*array.StringBuilder).Append(record.Topic)
+		case "_partition":
+			builder.(*array.Int32Builder).Append(record.Partition)
+		case "_offset":
+			builder.(*array.Int64Builder).Append(record.Offset)
+		case "_ts":
+			builder.(*array.TimestampBuilder).Append(arrow.Timestamp(record.Timestamp))
+		case "_key":
+			appendBinary(builder.(*array.BinaryBuilder), record.Key)
+		case "_value":
+			appendBinary(builder.(*array.BinaryBuilder), record.Value)
+		case "_headers":
+			builder.(*array.StringBuilder).Append(headersToJSON(record.Headers))
+		case "_segment":
+			builder.(*array.StringBuilder).Append(seg)
+		default:
+			builder.AppendNull()
+		}
+	}
+}
+
+// appendJSONValue appends a decoded JSON value to a schema column. Values of
+// the wrong JSON type are null, as in schemaValue.
+func appendJSONValue(builder array.Builder, value interface{}) {
+	switch b := builder.(type) {
+	case *array.BooleanBuilder:
+		if v, ok := value.(bool); ok {
+			b.Append(v)
+			return
+		}
+	case *array.Int32Builder:
+		if v, ok := value.(float64); ok {
+			b.Append(int32(v))
+			return
+		}
+	case *array.Int64Builder:
+		if v, ok := value.(float64); ok {
+			b.Append(int64(v))
+			return
+		}
+	case *array.Float64Builder:
+		if v, ok := value.(float64); ok {
+			b.Append(v)
+			return
+		}
+	case *array.TimestampBuilder:
+		switch v := value.(type) {
+		case float64:
+			b.Append(arrow.Timestamp(int64(v)))
+			return
+		case string:
+			if parsed, ok := parseTimestampText(v); ok {
+				b.Append(arrow.Timestamp(parsed))
+				return
+			}
+		}
+	case *array.StringBuilder:
+		if v, ok := value.(string); ok {
+			b.Append(v)
+			return
+		}
+	}
+	builder.AppendNull()
+}
+
+func appendBinary(b *array.BinaryBuilder, value []byte) {
+	if value == nil {
+		b.AppendNull()
+		return
+	}
+	b.Append(value)
+}
+
+func appendArrowValue(builder array.Builder, value []byte) {
+	if value == nil {
+		builder.AppendNull()
+		return
+	}
+	text := string(value)
+	switch b := builder.(type) {
+	case *array.BooleanBuilder:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			b.AppendNull()
+			return
+		}
+		b.Append(parsed)
+	case *array.Int32Builder:
+		parsed, err := strconv.ParseInt(text, 10, 32)
+		if err != nil {
+			b.AppendNull()
+			return
+		}
+		b.Append(int32(parsed))
+	case *array.Int64Builder:
+		parsed, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			b.AppendNull()
+			return
+		}
+		b.Append(parsed)
+	case *array.Float64Builder:
+		parsed, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			b.AppendNull()
+			return
+		}
+		b.Append(parsed)
+	case *array.TimestampBuilder:
+		parsed, ok := parseTimestampText(text)
+		if !ok {
+			b.AppendNull()
+			return
+		}
+		b.Append(arrow.Timestamp(parsed))
+	case *array.BinaryBuilder:
+		if strings.HasPrefix(text, `\x`) {
+			decoded, err := hex.DecodeString(text[2:])
+			if err == nil {
+				b.Append(decoded)
+				return
+			}
+		}
+		b.Append(value)
+	case *array.StringBuilder:
+		b.Append(text)
+	default:
+		builder.AppendNull()
+	}
+}
+
+func parseTimestampText(text string) (int64, bool) {
+	for _, layout := range []string{"2006-01-02 15:04:05.000", time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if ts, err := time.Parse(layout, text); err == nil {
+			return ts.UnixMilli(), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,238 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/flight/flightsql"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/jackc/pgproto3/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/config"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/decoder"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/discovery"
+)
+
+func startFlightServer(t *testing.T, srv *Server) *flightsql.Client {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = NewFlightServer(srv).Serve(ctx, ln)
+	}()
+	client, err := flightsql.NewClient(ln.Addr().String(), nil, nil, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		cancel()
+		t.Fatalf("client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+		cancel()
+		<-done
+	})
+	return client
+}
+
+func TestFlightStatementPerPartitionEndpoints(t *testing.T) {
+	now := time.Now().UTC().UnixMilli()
+	segments := []discovery.SegmentRef{
+		{Topic: "orders", Partition: 0, SegmentKey: "seg-0", IndexKey: "idx-0"},
+		{Topic: "orders", Partition: 1, SegmentKey: "seg-1", IndexKey: "idx-1"},
+	}
+	srv := newTestServer(&mockLister{segments: segments}, &mockDecoder{records: map[string][]decoder.Record{
+		"seg-0": {
+			{Topic: "orders", Partition: 0, Offset: 1, Timestamp: now - 2000, Key: []byte("a")},
+			{Topic: "orders", Partition: 0, Offset: 2, Timestamp: now - 1000, Key: []byte("b")},
+		},
+		"seg-1": {
+			{Topic: "orders", Partition: 1, Offset: 7, Timestamp: now - 1500, Key: []byte("c")},
+		},
+	}})
+	client := startFlightServer(t, srv)
+
+	ctx := context.Background()
+	info, err := client.Execute(ctx, "SELECT _partition, _offset, _ts, _key FROM orders LAST 1h;")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(info.Endpoint) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(info.Endpoint))
+	}
+
+	offsets := map[int32][]int64{}
+	for _, endpoint := range info.Endpoint {
+		reader, err := client.DoGet(ctx, endpoint.Ticket)
+		if err != nil {
+			t.Fatalf("do get: %v", err)
+		}
+		schema := reader.Schema()
+		if schema.Field(1).Type.ID() != arrow.INT64 || schema.Field(2).Type.ID() != arrow.TIMESTAMP || schema.Field(3).Type.ID() != arrow.BINARY {
+			t.Fatalf("unexpected schema: %s", schema)
+		}
+		for reader.Next() {
+			record := reader.RecordBatch()
+			partitions := record.Column(0).(*array.Int32)
+			values := record.Column(1).(*array.Int64)
+			for i := 0; i < int(record.NumRows()); i++ {
+				offsets[partitions.Value(i)] = append(offsets[partitions.Value(i)], values.Value(i))
+			}
+		}
+		if err := reader.Err(); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		reader.Release()
+	}
+	if len(offsets[0]) != 2 || len(offsets[1]) != 1 || offsets[1][0] != 7 {
+		t.Fatalf("unexpected offsets: %+v", offsets)
+	}
+}
+
+func TestFlightStatementSingleEndpointWithLimit(t *testing.T) {
+	segments := []discovery.SegmentRef{
+		{Topic: "orders", Partition: 0, SegmentKey: "seg-0"},
+		{Topic: "orders", Partition: 1, SegmentKey: "seg-1"},
+	}
+	srv := newTestServer(&mockLister{segments: segments}, &mockDecoder{})
+	client := startFlightServer(t, srv)
+
+	info, err := client.Execute(context.Background(), "SELECT * FROM orders LIMIT 10 LAST 1h;")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(info.Endpoint) != 1 {
+		t.Fatalf("expected 1 endpoint, got %d", len(info.Endpoint))
+	}
+}
+
+func TestFlightStatementError(t *testing.T) {
+	srv := newTestServer(&mockLister{}, &mockDecoder{})
+	client := startFlightServer(t, srv)
+
+	ctx := context.Background()
+	if _, err := client.Execute(ctx, "SELEKT nope"); err == nil {
+		t.Fatalf("expected parse error")
+	}
+	info, err := client.Execute(ctx, "SELECT * FROM orders;")
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	reader, err := client.DoGet(ctx, info.Endpoint[0].Ticket)
+	if err != nil {
+		t.Fatalf("do get: %v", err)
+	}
+	defer reader.Release()
+	for reader.Next() {
+	}
+	if reader.Err() == nil {
+		t.Fatalf("expected unbounded query error")
+	}
+}
+
+func TestArrowBatchWriterWriteRow(t *testing.T) {
+	cols := []resolvedColumn{
+		{Name: "_offset", Kind: columnImplicit, Column: "_offset"},
+		{Name: "_ts", Kind: columnImplicit, Column: "_ts"},
+		{Name: "_key", Kind: columnImplicit, Column: "_key"},
+		{Name: "amount", Kind: columnSchema, Column: "amount", Schema: config.SchemaColumn{Name: "amount", Type: "double", Path: "$.amount"}},
+		{Name: "qty", Kind: columnSchema, Column: "qty", Schema: config.SchemaColumn{Name: "qty", Type: "long", Path: "$.qty"}},
+		{Name: "paid", Kind: columnJSONExists, JSONPath: "$.paid_at"},
+	}
+	out := make(chan flight.StreamChunk, 1)
+	writer := newArrowBatchWriter(context.Background(), memory.NewGoAllocator(), arrowSchema(buildRowDescription(cols)), 2, out)
+	defer writer.release()
+
+	records := []decoder.Record{
+		{Offset: 9, Timestamp: 1700000000123, Key: []byte("k"), Value: []byte(`{"amount":2.5,"qty":3}`)},
+		{Offset: 10, Timestamp: 1700000000456, Value: []byte(`{"amount":"n/a","paid_at":1}`)},
+	}
+	for _, record := range records {
+		if err := writer.WriteRow(cols, rowContext{left: record}); err != nil {
+			t.Fatalf("write row: %v", err)
+		}
+	}
+	chunk := <-out
+	batch := chunk.Data
+	defer batch.Release()
+
+	if batch.NumRows() != 2 || batch.Column(0).(*array.Int64).Value(1) != 10 {
+		t.Fatalf("unexpected offsets: %v", batch.Column(0))
+	}
+	if int64(batch.Column(1).(*array.Timestamp).Value(0)) != 1700000000123 {
+		t.Fatalf("unexpected timestamp: %v", batch.Column(1))
+	}
+	if string(batch.Column(2).(*array.Binary).Value(0)) != "k" || !batch.Column(2).IsNull(1) {
+		t.Fatalf("unexpected keys: %v", batch.Column(2))
+	}
+	if batch.Column(3).(*array.Float64).Value(0) != 2.5 || !batch.Column(3).IsNull(1) {
+		t.Fatalf("unexpected amounts: %v", batch.Column(3))
+	}
+	if batch.Column(4).(*array.Int64).Value(0) != 3 || !batch.Column(4).IsNull(1) {
+		t.Fatalf("unexpected quantities: %v", batch.Column(4))
+	}
+	paid := batch.Column(5).(*array.Boolean)
+	if paid.Value(0) || !paid.Value(1) {
+		t.Fatalf("unexpected json_exists: %v", paid)
+	}
+}
+
+func TestAppendArrowValue(t *testing.T) {
+	schema := arrowSchema([]pgproto3.FieldDescription{
+		{Name: []byte("b"), DataTypeOID: 16},
+		{Name: []byte("f"), DataTypeOID: 701},
+		{Name: []byte("ts"), DataTypeOID: 1114},
+		{Name: []byte("k"), DataTypeOID: 17},
+		{Name: []byte("s"), DataTypeOID: 25},
+	})
+	builder := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer builder.Release()
+	row := [][]byte{[]byte("true"), []byte("1.5"), []byte("2024-01-02 03:04:05.006"), encodeBytea([]byte("key")), nil}
+	for i, value := range row {
+		appendArrowValue(builder.Field(i), value)
+	}
+	record := builder.NewRecordBatch()
+	defer record.Release()
+
+	if !record.Column(0).(*array.Boolean).Value(0) {
+		t.Fatalf("expected true")
+	}
+	if record.Column(1).(*array.Float64).Value(0) != 1.5 {
+		t.Fatalf("unexpected float")
+	}
+	want := time.Date(2024, 1, 2, 3, 4, 5, 6*int(time.Millisecond), time.UTC).UnixMilli()
+	if int64(record.Column(2).(*array.Timestamp).Value(0)) != want {
+		t.Fatalf("unexpected timestamp")
+	}
+	if string(record.Column(3).(*array.Binary).Value(0)) != "key" {
+		t.Fatalf("unexpected binary")
+	}
+	if !record.Column(4).IsNull(0) {
+		t.Fatalf("expected null string")
+	}
+}
@@ -0,0 +1,523 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgproto3/v2"
+
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/metrics"
+	kafsql "github.com/kafscale/platform/addons/processors/sql-processor/internal/sql"
+)
+
+var errTooManyJobs = errors.New("too many query jobs")
+
+type jobState string
+
+const (
+	jobQueued    jobState = "queued"
+	jobRunning   jobState = "running"
+	jobSucceeded jobState = "succeeded"
+	jobFailed    jobState = "failed"
+	jobCancelled jobState = "cancelled"
+)
+
+// HTTPServer exposes KafSQL over HTTP/JSON. Every query runs as a job that
+// goes through the same limiter, result cache and executor as the Postgres
+// front end; synchronous requests simply wait for their job to finish.
+type HTTPServer struct {
+	srv     *Server
+	listen  string
+	baseCtx context.Context
+
+	mu   sync.Mutex
+	jobs map[string]*queryJob
+}
+
+type queryJob struct {
+	id        string
+	query     string
+	parsed    kafsql.Query
+	cancel    context.CancelFunc
+	done      chan struct{}
+	submitted time.Time
+
+	mu        sync.Mutex
+	state     jobState
+	err       error
+	started   time.Time
+	finished  time.Time
+	collector *rowCollector
+}
+
+type jobStatus struct {
+	ID          string     `json:"id"`
+	Query       string     `json:"query"`
+	Status      jobState   `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	Rows        int        `json:"rows"`
+	Truncated   bool       `json:"truncated,omitempty"`
+	Columns     []string   `json:"columns,omitempty"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+type queryRequest struct {
+	Query string `json:"query"`
+	Async bool   `json:"async"`
+}
+
+func NewHTTPServer(srv *Server) *HTTPServer {
+	return &HTTPServer{
+		srv:     srv,
+		listen:  srv.cfg.HTTP.Listen,
+		baseCtx: context.Background(),
+		jobs:    make(map[string]*queryJob),
+	}
+}
+
+func (h *HTTPServer) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", h.listen)
+	if err != nil {
+		return err
+	}
+	return h.Serve(ctx, ln)
+}
+
+// Serve handles HTTP requests on ln until ctx is cancelled. Jobs still
+// running at that point are cancelled.
+func (h *HTTPServer) Serve(ctx context.Context, ln net.Listener) error {
+	h.baseCtx = ctx
+	httpSrv := &http.Server{Handler: h.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = httpSrv.Shutdown(context.Background())
+	}()
+	if err := httpSrv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Handler returns the HTTP routes of the query API.
+func (h *HTTPServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/query", h.handleSubmit)
+	mux.HandleFunc("GET /v1/jobs/{id}", h.handleStatus)
+	mux.HandleFunc("DELETE /v1/jobs/{id}", h.handleCancel)
+	mux.HandleFunc("GET /v1/jobs/{id}/results", h.handleResults)
+	return mux
+}
+
+func (h *HTTPServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req queryRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		writeJSONError(w, http.StatusBadRequest, "empty query")
+		return
+	}
+	parsed, err := kafsql.Parse(query)
+	if err != nil {
+		metrics.QueriesTotal.WithLabelValues("parse", "error").Inc()
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := h.srv.describeFields(parsed); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := h.submit(query, parsed)
+	if err != nil {
+		writeJSONError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if req.Async {
+		w.Header().Set("Location", "/v1/jobs/"+job.id)
+		writeJSON(w, http.StatusAccepted, job.status())
+		return
+	}
+
+	select {
+	case <-job.done:
+	case <-r.Context().Done():
+		job.cancel()
+		<-job.done
+		return
+	}
+	status := job.status()
+	if status.Status != jobSucceeded {
+		code := http.StatusBadRequest
+		if err := job.failure(); errors.Is(err, errQueueFull) || errors.Is(err, errQueueTimeout) {
+			code = http.StatusTooManyRequests
+		}
+		writeJSONError(w, code, status.Error)
+		return
+	}
+	h.writePage(w, r, job)
+}
+
+func (h *HTTPServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.lookup(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "unknown job")
+		return
+	}
+	writeJSON(w, http.StatusOK, job.status())
+}
+
+func (h *HTTPServer) handleCancel(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.lookup(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "unknown job")
+		return
+	}
+	job.cancel()
+	<-job.done
+	writeJSON(w, http.StatusOK, job.status())
+}
+
+func (h *HTTPServer) handleResults(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.lookup(r.PathValue("id"))
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, "unknown job")
+		return
+	}
+	status := job.status()
+	if status.Status != jobSucceeded {
+		writeJSON(w, http.StatusConflict, status)
+		return
+	}
+	h.writePage(w, r, job)
+}
+
+// submit registers a job and starts executing it in the background.
+func (h *HTTPServer) submit(query string, parsed kafsql.Query) (*queryJob, error) {
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(h.baseCtx)
+	job := &queryJob{
+		id:        id,
+		query:     query,
+		parsed:    parsed,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		submitted: time.Now().UTC(),
+		state:     jobQueued,
+	}
+
+	h.mu.Lock()
+	h.expireLocked(time.Now())
+	if h.srv.cfg.HTTP.MaxJobs > 0 && len(h.jobs) >= h.srv.cfg.HTTP.MaxJobs {
+		h.mu.Unlock()
+		cancel()
+		return nil, errTooManyJobs
+	}
+	h.jobs[id] = job
+	h.mu.Unlock()
+
+	metrics.HTTPJobsActive.Inc()
+	go h.run(ctx, job)
+	return job, nil
+}
+
+func (h *HTTPServer) run(ctx context.Context, job *queryJob) {
+	defer close(job.done)
+	defer job.cancel()
+	defer metrics.HTTPJobsActive.Dec()
+
+	if timeout := h.srv.cfg.HTTP.JobTimeoutSeconds; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+
+	collector := newRowCollector(h.srv.cfg.Query.MaxRows)
+	err := h.srv.runPreparedQuery(ctx, &jobWriter{job: job, collector: collector}, preparedStatement{query: job.query, parsed: job.parsed})
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	job.finished = time.Now().UTC()
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled):
+		job.state = jobCancelled
+		job.err = context.Canceled
+	case err == nil:
+		job.state = jobSucceeded
+		job.collector = collector
+	default:
+		job.state = jobFailed
+		job.err = err
+	}
+	metrics.HTTPJobsTotal.WithLabelValues(string(job.state)).Inc()
+}
+
+func (h *HTTPServer) lookup(id string) (*queryJob, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.expireLocked(time.Now())
+	job, ok := h.jobs[id]
+	return job, ok
+}
+
+// expireLocked drops finished jobs older than the configured TTL.
+func (h *HTTPServer) expireLocked(now time.Time) {
+	ttl := time.Duration(h.srv.cfg.HTTP.JobTTLSeconds) * time.Second
+	if ttl <= 0 {
+		return
+	}
+	for id, job := range h.jobs {
+		job.mu.Lock()
+		expired := !job.finished.IsZero() && now.Sub(job.finished) > ttl
+		job.mu.Unlock()
+		if expired {
+			delete(h.jobs, id)
+		}
+	}
+}
+
+func (h *HTTPServer) writePage(w http.ResponseWriter, r *http.Request, job *queryJob) {
+	job.mu.Lock()
+	collector := job.collector
+	job.mu.Unlock()
+
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeJSONError(w, http.StatusBadRequest, "invalid offset")
+		return
+	}
+	limit, err := queryInt(r, "limit", h.srv.cfg.HTTP.PageSize)
+	if err != nil || limit <= 0 {
+		writeJSONError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	if h.srv.cfg.HTTP.MaxPageSize > 0 && limit > h.srv.cfg.HTTP.MaxPageSize {
+		limit = h.srv.cfg.HTTP.MaxPageSize
+	}
+
+	rows := collector.rows
+	total := len(rows)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	page := rows[offset:end]
+
+	w.Header().Set("X-Kafsql-Job-Id", job.id)
+	w.Header().Set("X-Kafsql-Total-Rows", strconv.Itoa(total))
+	if end < total {
+		w.Header().Set("X-Kafsql-Next-Offset", strconv.Itoa(end))
+	}
+
+	switch resultFormat(r) {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.WriteHeader(http.StatusOK)
+		_ = writeCSV(w, collector.fields, page)
+	default:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		_ = writeNDJSON(w, collector.fields, page)
+	}
+}
+
+func (j *queryJob) status() jobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	out := jobStatus{
+		ID:          j.id,
+		Query:       trimQuery(j.query),
+		Status:      j.state,
+		SubmittedAt: j.submitted,
+	}
+	if j.err != nil {
+		out.Error = j.err.Error()
+	}
+	if !j.started.IsZero() {
+		started := j.started
+		out.StartedAt = &started
+	}
+	if !j.finished.IsZero() {
+		finished := j.finished
+		out.FinishedAt = &finished
+	}
+	if j.collector != nil {
+		out.Rows = len(j.collector.rows)
+		out.Truncated = j.collector.truncated
+		for _, field := range j.collector.fields {
+			out.Columns = append(out.Columns, string(field.Name))
+		}
+	}
+	return out
+}
+
+func (j *queryJob) failure() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+// jobWriter buffers the rows of a job for later pagination.
+type jobWriter struct {
+	job       *queryJob
+	collector *rowCollector
+}
+
+// queryStarted marks the job running once it has left the limiter queue.
+func (w *jobWriter) queryStarted() {
+	w.job.mu.Lock()
+	w.job.state = jobRunning
+	w.job.started = time.Now().UTC()
+	w.job.mu.Unlock()
+}
+
+func (w *jobWriter) Send(msg pgproto3.BackendMessage) error {
+	if m, ok := msg.(*pgproto3.ErrorResponse); ok {
+		return errors.New(m.Message)
+	}
+	w.collector.capture(msg)
+	return nil
+}
+
+func writeNDJSON(w io.Writer, fields []pgproto3.FieldDescription, rows [][][]byte) error {
+	enc := json.NewEncoder(w)
+	for _, row := range rows {
+		obj := make(map[string]interface{}, len(fields))
+		for i, field := range fields {
+			var value []byte
+			if i < len(row) {
+				value = row[i]
+			}
+			obj[string(field.Name)] = jsonCellValue(field.DataTypeOID, value)
+		}
+		if err := enc.Encode(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, fields []pgproto3.FieldDescription, rows [][][]byte) error {
+	writer := csv.NewWriter(w)
+	header := make([]string, 0, len(fields))
+	for _, field := range fields {
+		header = append(header, string(field.Name))
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(fields))
+	for _, row := range rows {
+		for i := range record {
+			record[i] = ""
+			if i < len(row) {
+				record[i] = string(row[i])
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonCellValue maps a text-encoded column value to its JSON representation.
+func jsonCellValue(oid uint32, value []byte) interface{} {
+	if value == nil {
+		return nil
+	}
+	text := string(value)
+	switch oid {
+	case 16:
+		if parsed, err := strconv.ParseBool(text); err == nil {
+			return parsed
+		}
+	case 20, 23:
+		if parsed, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return parsed
+		}
+	case 701:
+		if parsed, err := strconv.ParseFloat(text, 64); err == nil {
+			return parsed
+		}
+	case 114:
+		if json.Valid(value) {
+			return json.RawMessage(append([]byte(nil), value...))
+		}
+	}
+	return text
+}
+
+func resultFormat(r *http.Request) string {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" && strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		format = "csv"
+	}
+	if format == "csv" {
+		return "csv"
+	}
+	return "ndjson"
+}
+
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(raw)
+}
+
+func newJobID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeJSONError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
@@ -0,0 +1,240 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/decoder"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/discovery"
+)
+
+func newTestHTTPServer(t *testing.T, srv *Server) *httptest.Server {
+	t.Helper()
+	srv.cfg.HTTP.PageSize = 2
+	srv.cfg.HTTP.MaxPageSize = 10
+	srv.cfg.HTTP.MaxJobs = 10
+	srv.cfg.HTTP.JobTTLSeconds = 60
+	ts := httptest.NewServer(NewHTTPServer(srv).Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func ordersServer() *Server {
+	now := time.Now().UTC().UnixMilli()
+	segments := []discovery.SegmentRef{
+		{Topic: "orders", Partition: 0, SegmentKey: "seg-1", IndexKey: "idx-1"},
+	}
+	return newTestServer(&mockLister{segments: segments}, &mockDecoder{records: map[string][]decoder.Record{
+		"seg-1": {
+			{Topic: "orders", Partition: 0, Offset: 1, Timestamp: now - 3000, Value: []byte(`{"a":1}`)},
+			{Topic: "orders", Partition: 0, Offset: 2, Timestamp: now - 2000, Value: []byte(`{"a":2}`)},
+			{Topic: "orders", Partition: 0, Offset: 3, Timestamp: now - 1000, Value: []byte(`{"a":3}`)},
+		},
+	}})
+}
+
+func TestHTTPSyncQueryPagination(t *testing.T) {
+	ts := newTestHTTPServer(t, ordersServer())
+
+	resp, err := http.Post(ts.URL+"/v1/query", "application/json", strings.NewReader(`{"query":"SELECT _offset, json_value(_value, '$.a') AS a FROM orders LAST 1h"}`))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("X-Kafsql-Total-Rows") != "3" || resp.Header.Get("X-Kafsql-Next-Offset") != "2" {
+		t.Fatalf("unexpected pagination headers: %v", resp.Header)
+	}
+	var rows []map[string]interface{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var row map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatalf("decode row: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) != 2 || rows[0]["_offset"] != float64(1) || rows[1]["a"] != "2" {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+
+	jobID := resp.Header.Get("X-Kafsql-Job-Id")
+	page, err := http.Get(ts.URL + "/v1/jobs/" + jobID + "/results?offset=2&format=csv")
+	if err != nil {
+		t.Fatalf("get page: %v", err)
+	}
+	defer page.Body.Close()
+	body := new(strings.Builder)
+	if _, err := bufio.NewReader(page.Body).WriteTo(body); err != nil {
+		t.Fatalf("read page: %v", err)
+	}
+	if body.String() != "_offset,a\n3,3\n" {
+		t.Fatalf("unexpected csv page: %q", body.String())
+	}
+	if page.Header.Get("X-Kafsql-Next-Offset") != "" {
+		t.Fatalf("expected last page")
+	}
+}
+
+func TestHTTPAsyncJobLifecycle(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	segments := []discovery.SegmentRef{{Topic: "orders", Partition: 0, SegmentKey: "seg-1"}}
+	srv := newTestServer(&mockLister{segments: segments}, &blockingDecoder{startedCh: started, releaseCh: release})
+	ts := newTestHTTPServer(t, srv)
+	defer close(release)
+
+	resp, err := http.Post(ts.URL+"/v1/query", "application/json", strings.NewReader(`{"query":"SELECT * FROM orders LAST 1h","async":true}`))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	var status jobStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted || status.ID == "" {
+		t.Fatalf("unexpected submit response: %d %+v", resp.StatusCode, status)
+	}
+	waitForDecoder(t, started)
+
+	results, err := http.Get(ts.URL + "/v1/jobs/" + status.ID + "/results")
+	if err != nil {
+		t.Fatalf("get results: %v", err)
+	}
+	results.Body.Close()
+	if results.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 while running, got %d", results.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/v1/jobs/"+status.ID, nil)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		release <- struct{}{}
+	}()
+	cancelResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := json.NewDecoder(cancelResp.Body).Decode(&status); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	cancelResp.Body.Close()
+	if status.Status != jobCancelled {
+		t.Fatalf("expected cancelled job, got %+v", status)
+	}
+}
+
+func TestHTTPJobQueuedBehindLimiter(t *testing.T) {
+	srv := ordersServer()
+	srv.limiter = newQueryLimiter(1, 1)
+	srv.cfg.Query.QueueTimeoutSec = 5
+	ts := newTestHTTPServer(t, srv)
+
+	release, err := srv.limiter.acquire(time.Second)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	resp, err := http.Post(ts.URL+"/v1/query", "application/json", strings.NewReader(`{"query":"SELECT _offset FROM orders LAST 1h","async":true}`))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	var status jobStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	resp.Body.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(srv.limiter.queue) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	statusResp, err := http.Get(ts.URL + "/v1/jobs/" + status.ID)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if err := json.NewDecoder(statusResp.Body).Decode(&status); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	statusResp.Body.Close()
+	if status.Status != jobQueued || status.StartedAt != nil {
+		t.Fatalf("expected queued job, got %+v", status)
+	}
+
+	rejected, err := http.Post(ts.URL+"/v1/query", "application/json", strings.NewReader(`{"query":"SELECT _offset FROM orders LAST 1h"}`))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	rejected.Body.Close()
+	if rejected.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 with a full queue, got %d", rejected.StatusCode)
+	}
+
+	release()
+	results, err := http.Get(ts.URL + "/v1/jobs/" + status.ID + "/results")
+	for err == nil && results.StatusCode == http.StatusConflict && time.Now().Before(deadline.Add(2*time.Second)) {
+		results.Body.Close()
+		time.Sleep(5 * time.Millisecond)
+		results, err = http.Get(ts.URL + "/v1/jobs/" + status.ID + "/results")
+	}
+	if err != nil {
+		t.Fatalf("get results: %v", err)
+	}
+	results.Body.Close()
+	if results.StatusCode != http.StatusOK {
+		t.Fatalf("expected results once the limiter frees up, got %d", results.StatusCode)
+	}
+}
+
+func TestHTTPRejectsInvalidQuery(t *testing.T) {
+	ts := newTestHTTPServer(t, ordersServer())
+
+	resp, err := http.Post(ts.URL+"/v1/query", "application/json", strings.NewReader(`{"query":"DROP TABLE orders"}`))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(ts.URL+"/v1/query", "application/json", strings.NewReader(`{"query":"SELECT * FROM orders"}`))
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unbounded query, got %d", resp.StatusCode)
+	}
+
+	missing, err := http.Get(ts.URL + "/v1/jobs/nope")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	missing.Body.Close()
+	if missing.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", missing.StatusCode)
+	}
+}
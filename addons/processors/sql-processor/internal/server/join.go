@@ -0,0 +1,261 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"errors"
+	"hash/fnv"
+	"io"
+	"os"
+	"time"
+
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/decoder"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/discovery"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/metrics"
+	kafsql "github.com/kafscale/platform/addons/processors/sql-processor/internal/sql"
+)
+
+const joinSpillBuckets = 16
+
+// joinRecordOverhead approximates the per-record bookkeeping of the in-memory
+// build table on top of key and value bytes.
+const joinRecordOverhead = 96
+
+// joinRightBounds returns the timestamp range the right side must cover for
+// left records in [timeMin, timeMax] to find all of their matches.
+func joinRightBounds(timeMin, timeMax *int64, within time.Duration, interval *kafsql.JoinInterval) (*int64, *int64) {
+	lower, upper := -within.Milliseconds(), within.Milliseconds()
+	if interval != nil {
+		lower, upper = interval.LowerMs, interval.UpperMs
+	}
+	var rightMin, rightMax *int64
+	if timeMin != nil {
+		value := *timeMin + lower
+		rightMin = &value
+	}
+	if timeMax != nil {
+		value := *timeMax + upper
+		rightMax = &value
+	}
+	return rightMin, rightMax
+}
+
+// joinTimeMatches applies the join's time predicate: the interval bounds on
+// right._ts - left._ts when present, otherwise the symmetric WITHIN window.
+func joinTimeMatches(left, right int64, within time.Duration, interval *kafsql.JoinInterval) bool {
+	if interval != nil {
+		diff := right - left
+		return diff >= interval.LowerMs && diff <= interval.UpperMs
+	}
+	return withinWindow(left, right, within)
+}
+
+// buildJoinTable scans the right side of a join into a hash table keyed by the
+// join expression. Records outside [timeMin, timeMax] cannot match and are
+// skipped.
+func (s *Server) buildJoinTable(ctx context.Context, dec decoder.Decoder, segments []discovery.SegmentRef, topic string, expr kafsql.JoinExpr, timeMin, timeMax *int64, budget *scanBudget) (*joinTable, int64, error) {
+	table := newJoinTable(s.cfg.Query.JoinMemoryBytes, s.cfg.Query.SpillDir)
+	bytesScanned := int64(0)
+	topicSegments := make([]discovery.SegmentRef, 0, len(segments))
+	for _, segment := range segments {
+		if segment.Topic == topic {
+			topicSegments = append(topicSegments, segment)
+		}
+	}
+	err := s.scanSegments(ctx, dec, topicSegments, budget, func(segment discovery.SegmentRef, records []decoder.Record) error {
+		for _, record := range records {
+			bytesScanned += int64(len(record.Key) + len(record.Value))
+			if timeMin != nil && record.Timestamp < *timeMin {
+				continue
+			}
+			if timeMax != nil && record.Timestamp > *timeMax {
+				continue
+			}
+			key := joinKeyFromExpr(record, expr)
+			if key == "" {
+				continue
+			}
+			if err := table.add(key, joinRecord{Record: record, SegmentKey: segment.SegmentKey}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		table.close()
+		return nil, 0, err
+	}
+	return table, bytesScanned, nil
+}
+
+// joinTable is the build side of a hash join. It holds records in memory
+// until they exceed the memory budget, then hash-partitions them into spill
+// files and probes one partition at a time.
+type joinTable struct {
+	budget int64
+	dir    string
+	size   int64
+	rows   map[string][]joinRecord
+	spill  []*joinSpillFile
+}
+
+type joinSpillFile struct {
+	file   *os.File
+	writer *bufio.Writer
+	enc    *gob.Encoder
+}
+
+type joinSpillEntry struct {
+	Key    string
+	Record joinRecord
+}
+
+func newJoinTable(budget int64, dir string) *joinTable {
+	return &joinTable{budget: budget, dir: dir, rows: make(map[string][]joinRecord)}
+}
+
+func (t *joinTable) spilled() bool {
+	return t.spill != nil
+}
+
+func (t *joinTable) add(key string, record joinRecord) error {
+	if t.spilled() {
+		return t.write(key, record)
+	}
+	t.rows[key] = append(t.rows[key], record)
+	t.size += joinRecordSize(key, record)
+	if t.budget > 0 && t.size > t.budget {
+		return t.spillAll()
+	}
+	return nil
+}
+
+func (t *joinTable) spillAll() error {
+	t.spill = make([]*joinSpillFile, 0, joinSpillBuckets)
+	for i := 0; i < joinSpillBuckets; i++ {
+		file, err := os.CreateTemp(t.dir, "kafsql-join-*.spill")
+		if err != nil {
+			return err
+		}
+		writer := bufio.NewWriter(file)
+		t.spill = append(t.spill, &joinSpillFile{file: file, writer: writer, enc: gob.NewEncoder(writer)})
+	}
+	metrics.JoinSpills.Inc()
+	for key, records := range t.rows {
+		for _, record := range records {
+			if err := t.write(key, record); err != nil {
+				return err
+			}
+		}
+	}
+	t.rows = nil
+	t.size = 0
+	return nil
+}
+
+func (t *joinTable) write(key string, record joinRecord) error {
+	metrics.JoinSpillBytes.Add(float64(joinRecordSize(key, record)))
+	return t.spill[joinBucket(key)].enc.Encode(joinSpillEntry{Key: key, Record: record})
+}
+
+// probe calls fn for every left record with the build records sharing its
+// key. In memory, left records are visited in order; after a spill they are
+// visited one spill partition at a time.
+func (t *joinTable) probe(lefts []joinRecord, expr kafsql.JoinExpr, fn func(left joinRecord, key string, matches []joinRecord) error) error {
+	if !t.spilled() {
+		for _, left := range lefts {
+			key := joinKeyFromExpr(left.Record, expr)
+			if err := fn(left, key, t.rows[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	buckets := make([][]joinRecord, len(t.spill))
+	for _, left := range lefts {
+		key := joinKeyFromExpr(left.Record, expr)
+		bucket := 0
+		if key != "" {
+			bucket = joinBucket(key)
+		}
+		buckets[bucket] = append(buckets[bucket], left)
+	}
+	for i, bucket := range buckets {
+		if len(bucket) == 0 {
+			continue
+		}
+		rows, err := t.load(i)
+		if err != nil {
+			return err
+		}
+		for _, left := range bucket {
+			key := joinKeyFromExpr(left.Record, expr)
+			if err := fn(left, key, rows[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *joinTable) load(bucket int) (map[string][]joinRecord, error) {
+	spill := t.spill[bucket]
+	if err := spill.writer.Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := spill.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	rows := make(map[string][]joinRecord)
+	dec := gob.NewDecoder(bufio.NewReader(spill.file))
+	for {
+		var entry joinSpillEntry
+		if err := dec.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return rows, nil
+			}
+			return nil, err
+		}
+		rows[entry.Key] = append(rows[entry.Key], entry.Record)
+	}
+}
+
+func (t *joinTable) close() {
+	for _, spill := range t.spill {
+		_ = spill.file.Close()
+		_ = os.Remove(spill.file.Name())
+	}
+	t.spill = nil
+	t.rows = nil
+}
+
+func joinBucket(key string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % joinSpillBuckets)
+}
+
+func joinRecordSize(key string, record joinRecord) int64 {
+	size := len(key) + len(record.Record.Key) + len(record.Record.Value) + len(record.SegmentKey) + joinRecordOverhead
+	for _, header := range record.Record.Headers {
+		size += len(header.Key) + len(header.Value)
+	}
+	return int64(size)
+}
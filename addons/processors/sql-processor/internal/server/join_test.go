@@ -0,0 +1,177 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgproto3/v2"
+
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/decoder"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/discovery"
+	kafsql "github.com/kafscale/platform/addons/processors/sql-processor/internal/sql"
+)
+
+func intervalJoinServer(now int64) (*Server, *mockDecoder) {
+	oldMin, oldMax := now-10*3600*1000, now-9*3600*1000
+	segments := []discovery.SegmentRef{
+		{Topic: "orders", Partition: 0, SegmentKey: "orders-0"},
+		{Topic: "payments", Partition: 0, SegmentKey: "payments-old", MinTimestamp: &oldMin, MaxTimestamp: &oldMax},
+		{Topic: "payments", Partition: 0, SegmentKey: "payments-0"},
+	}
+	var orders, payments []decoder.Record
+	for i := 0; i < 20; i++ {
+		ts := now - int64(30-i)*60*1000
+		key := []byte(fmt.Sprintf("k%d", i))
+		orders = append(orders, decoder.Record{Topic: "orders", Offset: int64(i), Timestamp: ts, Key: key})
+		// Payment 2 minutes before the order matches; 2 minutes after does not.
+		payments = append(payments,
+			decoder.Record{Topic: "payments", Offset: int64(100 + i), Timestamp: ts - 2*60*1000, Key: key},
+			decoder.Record{Topic: "payments", Offset: int64(200 + i), Timestamp: ts + 2*60*1000, Key: key},
+		)
+	}
+	dec := &mockDecoder{records: map[string][]decoder.Record{
+		"orders-0":     orders,
+		"payments-0":   payments,
+		"payments-old": {{Topic: "payments", Offset: 1, Timestamp: oldMin, Key: []byte("k0")}},
+	}}
+	return newTestServer(&mockLister{segments: segments}, dec), dec
+}
+
+func runJoin(t *testing.T, srv *Server, query string) [][][]byte {
+	t.Helper()
+	parsed, err := kafsql.Parse(query)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	writer := &recordingWriter{}
+	if _, err := srv.handleSelect(context.Background(), writer, parsed, nil); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	var rows [][][]byte
+	for _, msg := range writer.messages {
+		if row, ok := msg.(*pgproto3.DataRow); ok {
+			rows = append(rows, row.Values)
+		}
+	}
+	return rows
+}
+
+func rowStrings(rows [][][]byte) []string {
+	out := make([]string, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fmt.Sprintf("%s/%s", row[0], row[1]))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestHandleIntervalJoin(t *testing.T) {
+	now := time.Now().UTC().Add(-time.Minute).UnixMilli()
+	srv, dec := intervalJoinServer(now)
+
+	rows := runJoin(t, srv, "SELECT o._offset, p._offset FROM orders o JOIN payments p ON o._key = p._key AND p._ts BETWEEN o._ts - INTERVAL '5m' AND o._ts LAST 1h;")
+	if len(rows) != 20 {
+		t.Fatalf("expected 20 rows, got %d", len(rows))
+	}
+	for _, row := range rows {
+		if string(row[1]) < "100" || string(row[1]) >= "200" {
+			t.Fatalf("unexpected match outside interval: %q", row)
+		}
+	}
+	for _, key := range dec.keys {
+		if key == "payments-old" {
+			t.Fatalf("expected right segment outside the interval to be pruned")
+		}
+	}
+}
+
+func TestHandleIntervalJoinSpillsToDisk(t *testing.T) {
+	now := time.Now().UTC().Add(-time.Minute).UnixMilli()
+	query := "SELECT o._offset, p._offset FROM orders o LEFT JOIN payments p ON o._key = p._key AND p._ts BETWEEN o._ts AND o._ts + INTERVAL '5m' LAST 1h;"
+
+	memSrv, _ := intervalJoinServer(now)
+	expected := rowStrings(runJoin(t, memSrv, query))
+
+	spillDir := t.TempDir()
+	spillSrv, _ := intervalJoinServer(now)
+	spillSrv.cfg.Query.JoinMemoryBytes = 512
+	spillSrv.cfg.Query.SpillDir = spillDir
+	got := rowStrings(runJoin(t, spillSrv, query))
+
+	if len(expected) != 20 || fmt.Sprint(expected) != fmt.Sprint(got) {
+		t.Fatalf("spilled join differs:\nexpected %v\ngot      %v", expected, got)
+	}
+	entries, err := os.ReadDir(spillDir)
+	if err != nil {
+		t.Fatalf("read spill dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected spill files to be removed, found %d", len(entries))
+	}
+}
+
+func TestJoinTableSpill(t *testing.T) {
+	table := newJoinTable(1, t.TempDir())
+	defer table.close()
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("k%d", i%5)
+		record := joinRecord{Record: decoder.Record{Offset: int64(i), Key: []byte(key), Headers: []decoder.Header{{Key: "h", Value: []byte("v")}}}, SegmentKey: "seg"}
+		if err := table.add(key, record); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+	if !table.spilled() {
+		t.Fatalf("expected table to spill")
+	}
+	lefts := []joinRecord{{Record: decoder.Record{Key: []byte("k3")}}, {Record: decoder.Record{}}}
+	seen := map[string]int{}
+	err := table.probe(lefts, kafsql.JoinExpr{Kind: kafsql.JoinExprKey}, func(left joinRecord, key string, matches []joinRecord) error {
+		seen[key] = len(matches)
+		for _, match := range matches {
+			if string(match.Record.Headers[0].Value) != "v" {
+				t.Fatalf("headers not restored")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if seen["k3"] != 10 || seen[""] != 0 || len(seen) != 2 {
+		t.Fatalf("unexpected probe results: %v", seen)
+	}
+}
+
+func TestJoinRightBounds(t *testing.T) {
+	min, max := int64(1000), int64(2000)
+	lo, hi := joinRightBounds(&min, &max, 0, &kafsql.JoinInterval{LowerMs: -300, UpperMs: 50})
+	if *lo != 700 || *hi != 2050 {
+		t.Fatalf("unexpected interval bounds: %d %d", *lo, *hi)
+	}
+	lo, hi = joinRightBounds(&min, &max, 100*time.Millisecond, nil)
+	if *lo != 900 || *hi != 2100 {
+		t.Fatalf("unexpected within bounds: %d %d", *lo, *hi)
+	}
+	if !joinTimeMatches(1000, 700, 0, &kafsql.JoinInterval{LowerMs: -300, UpperMs: 0}) || joinTimeMatches(1000, 1001, 0, &kafsql.JoinInterval{LowerMs: -300, UpperMs: 0}) {
+		t.Fatalf("unexpected interval match")
+	}
+}
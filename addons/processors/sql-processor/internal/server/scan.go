@@ -0,0 +1,129 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/decoder"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/discovery"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/metrics"
+)
+
+// errStopScan is returned by a scan callback to end the scan early, e.g. once
+// LIMIT rows have been sent.
+var errStopScan = errors.New("stop scan")
+
+// scanBudget counts the decoded key and value bytes of one query against
+// query.max_decoded_bytes. Every scan of the query, including both sides of a
+// join, shares the same budget.
+type scanBudget struct {
+	limit   int64
+	decoded int64
+}
+
+func (s *Server) newScanBudget() *scanBudget {
+	return &scanBudget{limit: s.cfg.Query.MaxDecodedBytes}
+}
+
+func (b *scanBudget) add(records []decoder.Record) error {
+	for _, record := range records {
+		b.decoded += int64(len(record.Key) + len(record.Value))
+	}
+	if b.limit > 0 && b.decoded > b.limit {
+		return fmt.Errorf("decoded bytes exceed max_decoded_bytes (%s)", formatBytes(b.limit))
+	}
+	return nil
+}
+
+type decodedSegment struct {
+	records []decoder.Record
+	err     error
+}
+
+// scanSegments decodes segments with up to query.scan_parallelism downloads in
+// flight and hands each segment's records to fn in the order of segments, so
+// callers see the same row order as a sequential scan. At most that many
+// decoded segments are buffered ahead of fn.
+func (s *Server) scanSegments(ctx context.Context, dec decoder.Decoder, segments []discovery.SegmentRef, budget *scanBudget, fn func(discovery.SegmentRef, []decoder.Record) error) error {
+	workers := s.cfg.Query.ScanParallelism
+	if workers > len(segments) {
+		workers = len(segments)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	metrics.QueryScanParallelism.Observe(float64(workers))
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	results := make([]chan decodedSegment, len(segments))
+	for i := range results {
+		results[i] = make(chan decodedSegment, 1)
+	}
+	slots := make(chan struct{}, workers)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, segment := range segments {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(out chan<- decodedSegment, segment discovery.SegmentRef) {
+				defer wg.Done()
+				metrics.ScanSegmentsInFlight.Inc()
+				defer metrics.ScanSegmentsInFlight.Dec()
+				records, err := dec.Decode(ctx, segment.SegmentKey, segment.IndexKey, segment.Topic, segment.Partition)
+				out <- decodedSegment{records: records, err: err}
+			}(results[i], segment)
+		}
+	}()
+
+	for i, segment := range segments {
+		var result decodedSegment
+		select {
+		case result = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		<-slots
+		if result.err != nil {
+			return result.err
+		}
+		if err := budget.add(result.records); err != nil {
+			return err
+		}
+		if err := fn(segment, result.records); err != nil {
+			if errors.Is(err, errStopScan) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgproto3/v2"
+
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/decoder"
+	"github.com/kafscale/platform/addons/processors/sql-processor/internal/discovery"
+	kafsql "github.com/kafscale/platform/addons/processors/sql-processor/internal/sql"
+)
+
+// slowDecoder decodes earlier segments more slowly so that parallel scans
+// finish out of order, and tracks the peak number of concurrent decodes.
+type slowDecoder struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	calls    int
+	segments int
+}
+
+func (d *slowDecoder) Decode(ctx context.Context, segmentKey, indexKey string, topic string, partition int32) ([]decoder.Record, error) {
+	d.mu.Lock()
+	d.calls++
+	d.inFlight++
+	if d.inFlight > d.peak {
+		d.peak = d.inFlight
+	}
+	d.mu.Unlock()
+	defer func() {
+		d.mu.Lock()
+		d.inFlight--
+		d.mu.Unlock()
+	}()
+
+	var idx int
+	fmt.Sscanf(segmentKey, "seg-%d", &idx)
+	select {
+	case <-time.After(time.Duration(d.segments-idx) * 5 * time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	now := time.Now().UTC().Add(-time.Minute).UnixMilli()
+	return []decoder.Record{
+		{Topic: topic, Partition: partition, Offset: int64(idx * 10), Timestamp: now, Value: []byte("v")},
+		{Topic: topic, Partition: partition, Offset: int64(idx*10 + 1), Timestamp: now, Value: []byte("v")},
+	}, nil
+}
+
+func slowSegments(n int) []discovery.SegmentRef {
+	segments := make([]discovery.SegmentRef, 0, n)
+	for i := 0; i < n; i++ {
+		segments = append(segments, discovery.SegmentRef{Topic: "orders", Partition: 0, SegmentKey: fmt.Sprintf("seg-%d", i)})
+	}
+	return segments
+}
+
+func TestScanSegmentsPreservesOrder(t *testing.T) {
+	dec := &slowDecoder{segments: 8}
+	srv := newTestServer(&mockLister{}, dec)
+	srv.cfg.Query.ScanParallelism = 4
+
+	var offsets []int64
+	err := srv.scanSegments(context.Background(), dec, slowSegments(8), srv.newScanBudget(), func(segment discovery.SegmentRef, records []decoder.Record) error {
+		for _, record := range records {
+			offsets = append(offsets, record.Offset)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	for i := 1; i < len(offsets); i++ {
+		if offsets[i] <= offsets[i-1] {
+			t.Fatalf("offsets out of order: %v", offsets)
+		}
+	}
+	if len(offsets) != 16 {
+		t.Fatalf("expected 16 records, got %d", len(offsets))
+	}
+	if dec.peak < 2 || dec.peak > 4 {
+		t.Fatalf("expected bounded parallel decodes, peak %d", dec.peak)
+	}
+}
+
+func TestScanSegmentsStopsEarly(t *testing.T) {
+	dec := &slowDecoder{segments: 20}
+	srv := newTestServer(&mockLister{}, dec)
+	srv.cfg.Query.ScanParallelism = 2
+
+	seen := 0
+	err := srv.scanSegments(context.Background(), dec, slowSegments(20), srv.newScanBudget(), func(segment discovery.SegmentRef, records []decoder.Record) error {
+		seen++
+		return errStopScan
+	})
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	dec.mu.Lock()
+	calls := dec.calls
+	dec.mu.Unlock()
+	if seen != 1 || calls > 3 {
+		t.Fatalf("expected early stop, saw %d segments and %d decodes", seen, calls)
+	}
+}
+
+func TestScanSegmentsHonorsLimitsAndCancellation(t *testing.T) {
+	dec := &slowDecoder{segments: 4}
+	srv := newTestServer(&mockLister{}, dec)
+	srv.cfg.Query.ScanParallelism = 4
+	srv.cfg.Query.MaxDecodedBytes = 3
+
+	err := srv.scanSegments(context.Background(), dec, slowSegments(4), srv.newScanBudget(), func(discovery.SegmentRef, []decoder.Record) error { return nil })
+	if err == nil {
+		t.Fatalf("expected max decoded bytes error")
+	}
+
+	srv.cfg.Query.MaxDecodedBytes = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = srv.scanSegments(ctx, dec, slowSegments(4), srv.newScanBudget(), func(discovery.SegmentRef, []decoder.Record) error { return nil })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+}
+
+func TestJoinSidesShareDecodedBudget(t *testing.T) {
+	dec := &slowDecoder{segments: 1}
+	srv := newTestServer(&mockLister{}, dec)
+	srv.cfg.Query.MaxScanBytes = 1
+	srv.cfg.Query.MaxDecodedBytes = 3
+
+	budget := srv.newScanBudget()
+	if _, _, err := srv.loadRecords(context.Background(), dec, slowSegments(1), "orders", budget); err != nil {
+		t.Fatalf("left side within budget: %v", err)
+	}
+	_, _, err := srv.buildJoinTable(context.Background(), dec, slowSegments(1), "orders", kafsql.JoinExpr{}, nil, nil, budget)
+	if err == nil {
+		t.Fatalf("expected right side to exceed the shared max_decoded_bytes budget")
+	}
+}
+
+func TestHandleSelectParallelLimit(t *testing.T) {
+	dec := &slowDecoder{segments: 6}
+	srv := newTestServer(&mockLister{segments: slowSegments(6)}, dec)
+	srv.cfg.Query.ScanParallelism = 3
+
+	parsed, err := kafsql.Parse("SELECT _offset FROM orders LIMIT 3 LAST 1h")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	writer := &recordingWriter{}
+	if _, err := srv.handleSelect(context.Background(), writer, parsed, nil); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	var out [][][]byte
+	for _, msg := range writer.messages {
+		if row, ok := msg.(*pgproto3.DataRow); ok {
+			out = append(out, row.Values)
+		}
+	}
+	if len(out) != 3 || string(out[0][0]) != "0" || string(out[2][0]) != "10" {
+		t.Fatalf("unexpected rows: %q", out)
+	}
+}
@@ -63,6 +63,30 @@ type Server struct {
 	decoder     decoder.Decoder
 	decoderInit bool
 	decoderErr  error
+
+	objectWriterMu   sync.Mutex
+	objectWriter     decoder.ObjectWriter
+	objectWriterInit bool
+	objectWriterErr  error
+}
+
+// messageWriter receives the backend messages produced by query execution.
+// The Postgres front end writes them to the wire; other front ends convert
+// them into their own result formats.
+type messageWriter interface {
+	Send(msg pgproto3.BackendMessage) error
+}
+
+// rowWriter is implemented by writers that build result rows from the
+// scanned records instead of their Postgres text encoding.
+type rowWriter interface {
+	WriteRow(cols []resolvedColumn, row rowContext) error
+}
+
+// startNotifier is implemented by writers that track when their query leaves
+// the limiter queue and starts executing.
+type startNotifier interface {
+	queryStarted()
 }
 
 func New(cfg config.Config, logger *log.Logger) *Server {
@@ -231,7 +255,7 @@ type boundPortal struct {
 	statement string
 }
 
-func (s *Server) handleParse(state *connState, backend *pgproto3.Backend, msg *pgproto3.Parse) error {
+func (s *Server) handleParse(state *connState, backend messageWriter, msg *pgproto3.Parse) error {
 	query := strings.TrimSpace(msg.Query)
 	if query == "" {
 		return sendErrorResponse(backend, "empty query")
@@ -247,7 +271,7 @@ func (s *Server) handleParse(state *connState, backend *pgproto3.Backend, msg *p
 	return backend.Send(&pgproto3.ParseComplete{})
 }
 
-func (s *Server) handleBind(state *connState, backend *pgproto3.Backend, msg *pgproto3.Bind) error {
+func (s *Server) handleBind(state *connState, backend messageWriter, msg *pgproto3.Bind) error {
 	stmt, ok := state.statements[msg.PreparedStatement]
 	if !ok {
 		return sendErrorResponse(backend, "unknown prepared statement")
@@ -260,7 +284,7 @@ func (s *Server) handleBind(state *connState, backend *pgproto3.Backend, msg *pg
 	return backend.Send(&pgproto3.BindComplete{})
 }
 
-func (s *Server) handleDescribe(state *connState, backend *pgproto3.Backend, msg *pgproto3.Describe) error {
+func (s *Server) handleDescribe(state *connState, backend messageWriter, msg *pgproto3.Describe) error {
 	stmt, ok := s.lookupStatement(state, msg.ObjectType, msg.Name)
 	if !ok {
 		return sendErrorResponse(backend, "unknown prepared statement")
@@ -278,7 +302,7 @@ func (s *Server) handleDescribe(state *connState, backend *pgproto3.Backend, msg
 	return backend.Send(&pgproto3.RowDescription{Fields: fields})
 }
 
-func (s *Server) handleExecute(ctx context.Context, state *connState, backend *pgproto3.Backend, msg *pgproto3.Execute) error {
+func (s *Server) handleExecute(ctx context.Context, state *connState, backend messageWriter, msg *pgproto3.Execute) error {
 	stmt, ok := s.lookupStatement(state, 'P', msg.Portal)
 	if !ok {
 		return sendErrorResponse(backend, "unknown portal")
@@ -289,7 +313,7 @@ func (s *Server) handleExecute(ctx context.Context, state *connState, backend *p
 	return nil
 }
 
-func (s *Server) handleClose(state *connState, backend *pgproto3.Backend, msg *pgproto3.Close) error {
+func (s *Server) handleClose(state *connState, backend messageWriter, msg *pgproto3.Close) error {
 	switch msg.ObjectType {
 	case 'S':
 		delete(state.statements, msg.Name)
@@ -318,17 +342,26 @@ func (s *Server) lookupStatement(state *connState, objectType byte, name string)
 	}
 }
 
-func (s *Server) handlePreparedQuery(ctx context.Context, backend *pgproto3.Backend, stmt preparedStatement) error {
+func (s *Server) handlePreparedQuery(ctx context.Context, backend messageWriter, stmt preparedStatement) error {
+	ctx, cancel := s.withQueryTimeout(ctx)
+	defer cancel()
+	return s.runPreparedQuery(ctx, backend, stmt)
+}
+
+// runPreparedQuery executes a parsed statement under the query limiter. The
+// caller owns the execution deadline.
+func (s *Server) runPreparedQuery(ctx context.Context, backend messageWriter, stmt preparedStatement) error {
 	start := time.Now()
 	metrics.ActiveQueries.Inc()
 	defer metrics.ActiveQueries.Dec()
-	ctx, cancel := s.withQueryTimeout(ctx)
-	defer cancel()
 	release, err := s.limiter.acquire(time.Duration(s.cfg.Query.QueueTimeoutSec) * time.Second)
 	if err != nil {
 		return err
 	}
 	defer release()
+	if notifier, ok := backend.(startNotifier); ok {
+		notifier.queryStarted()
+	}
 
 	if result, handled, err := s.handleSetCommand(ctx, backend, stmt.query); handled {
 		status := "success"
@@ -398,6 +431,8 @@ func (s *Server) describeFields(parsed kafsql.Query) ([]pgproto3.FieldDescriptio
 			{Name: []byte("type"), DataTypeOID: 25, DataTypeSize: -1, TypeModifier: -1, Format: 0},
 			{Name: []byte("path"), DataTypeOID: 25, DataTypeSize: -1, TypeModifier: -1, Format: 0},
 		}, nil
+	case kafsql.QueryCopy:
+		return copyResultFields(), nil
 	case kafsql.QuerySelect:
 		if parsed.JoinTopic != "" {
 			cols, err := s.resolveJoinColumns(parsed)
@@ -432,21 +467,41 @@ func hasParameters(query string) bool {
 	return re.MatchString(query)
 }
 
-func sendErrorResponse(backend *pgproto3.Backend, message string) error {
+func sendErrorResponse(backend messageWriter, message string) error {
 	return backend.Send(&pgproto3.ErrorResponse{
 		Severity: "ERROR",
 		Message:  message,
 	})
 }
 
-func (s *Server) send(backend *pgproto3.Backend, collector *rowCollector, msg pgproto3.BackendMessage) error {
+func (s *Server) send(backend messageWriter, collector *rowCollector, msg pgproto3.BackendMessage) error {
 	if collector != nil {
 		collector.capture(msg)
 	}
 	return backend.Send(msg)
 }
 
-func sendCached(backend *pgproto3.Backend, entry *cacheEntry) error {
+// sendRow sends one result row, handing the scanned records to writers that
+// encode rows themselves. The collector always sees the text encoding.
+func (s *Server) sendRow(backend messageWriter, collector *rowCollector, cols []resolvedColumn, row rowContext) error {
+	writer, ok := backend.(rowWriter)
+	if !ok {
+		return s.send(backend, collector, &pgproto3.DataRow{Values: buildRowValues(cols, row)})
+	}
+	if collector != nil {
+		collector.capture(&pgproto3.DataRow{Values: buildRowValues(cols, row)})
+	}
+	return writer.WriteRow(cols, row)
+}
+
+func (s *Server) sendResult(backend messageWriter, collector *rowCollector, cols []resolvedColumn, row rowResult) error {
+	if row.row != nil {
+		return s.sendRow(backend, collector, cols, *row.row)
+	}
+	return s.send(backend, collector, &pgproto3.DataRow{Values: row.values})
+}
+
+func sendCached(backend messageWriter, entry *cacheEntry) error {
 	if entry == nil {
 		return nil
 	}
@@ -465,7 +520,7 @@ func sendCached(backend *pgproto3.Backend, entry *cacheEntry) error {
 	return backend.Send(&pgproto3.CommandComplete{CommandTag: tag})
 }
 
-func (s *Server) handleQuery(ctx context.Context, backend *pgproto3.Backend, query string) error {
+func (s *Server) handleQuery(ctx context.Context, backend messageWriter, query string) error {
 	start := time.Now()
 	metrics.ActiveQueries.Inc()
 	defer metrics.ActiveQueries.Dec()
@@ -554,7 +609,7 @@ func (s *Server) handleQuery(ctx context.Context, backend *pgproto3.Backend, que
 	return execErr
 }
 
-func (s *Server) handleSelectWithCache(ctx context.Context, backend *pgproto3.Backend, parsed kafsql.Query, query string) (queryResult, bool, error) {
+func (s *Server) handleSelectWithCache(ctx context.Context, backend messageWriter, parsed kafsql.Query, query string) (queryResult, bool, error) {
 	key, ok := s.cacheKey(parsed, query)
 	if ok && s.resultCache != nil {
 		if entry, hit := s.resultCache.Get(key); hit {
@@ -587,17 +642,21 @@ func (s *Server) cacheKey(parsed kafsql.Query, query string) (string, bool) {
 	if parsed.Tail != "" || parsed.ScanFull {
 		return "", false
 	}
+	partitionKey := ""
+	if parsed.Partition != nil {
+		partitionKey = fmt.Sprintf("|partition=%d", *parsed.Partition)
+	}
 	if parsed.Last != "" {
-		return cacheKey(query, "last="+parsed.Last), true
+		return cacheKey(query, "last="+parsed.Last+partitionKey), true
 	}
 	if parsed.TsMin == nil || parsed.TsMax == nil {
 		return "", false
 	}
 	timeKey := fmt.Sprintf("ts=%d-%d", *parsed.TsMin, *parsed.TsMax)
-	return cacheKey(query, timeKey), true
+	return cacheKey(query, timeKey+partitionKey), true
 }
 
-func (s *Server) handleCatalogQuery(ctx context.Context, backend *pgproto3.Backend, query string) (queryResult, bool, error) {
+func (s *Server) handleCatalogQuery(ctx context.Context, backend messageWriter, query string) (queryResult, bool, error) {
 	trimmed := strings.TrimSpace(query)
 	if trimmed == "" {
 		return queryResult{}, false, nil
@@ -634,7 +693,7 @@ func (s *Server) handleCatalogQuery(ctx context.Context, backend *pgproto3.Backe
 	}
 }
 
-func (s *Server) handleSetCommand(ctx context.Context, backend *pgproto3.Backend, query string) (queryResult, bool, error) {
+func (s *Server) handleSetCommand(ctx context.Context, backend messageWriter, query string) (queryResult, bool, error) {
 	_ = ctx
 	trimmed := strings.TrimSpace(query)
 	if trimmed == "" {
@@ -735,7 +794,7 @@ type groupState struct {
 	Aggs   []aggState
 }
 
-func (s *Server) executeQuery(ctx context.Context, backend *pgproto3.Backend, parsed kafsql.Query) (queryResult, error) {
+func (s *Server) executeQuery(ctx context.Context, backend messageWriter, parsed kafsql.Query) (queryResult, error) {
 	switch parsed.Type {
 	case kafsql.QueryShowTopics:
 		rows, err := s.handleShowTopics(ctx, backend)
@@ -750,12 +809,14 @@ func (s *Server) executeQuery(ctx context.Context, backend *pgproto3.Backend, pa
 		return s.handleSelect(ctx, backend, parsed, nil)
 	case kafsql.QueryExplain:
 		return s.handleExplain(ctx, backend, parsed)
+	case kafsql.QueryCopy:
+		return s.handleCopy(ctx, backend, parsed)
 	default:
 		return queryResult{}, errors.New("KAFSQL query execution not implemented")
 	}
 }
 
-func (s *Server) handleShowTopics(ctx context.Context, backend *pgproto3.Backend) (int, error) {
+func (s *Server) handleShowTopics(ctx context.Context, backend messageWriter) (int, error) {
 	resolver, err := s.getResolver()
 	if err != nil {
 		return 0, err
@@ -781,7 +842,7 @@ func (s *Server) handleShowTopics(ctx context.Context, backend *pgproto3.Backend
 	return len(topics), nil
 }
 
-func (s *Server) handleShowPartitions(ctx context.Context, backend *pgproto3.Backend, topic string) (int, error) {
+func (s *Server) handleShowPartitions(ctx context.Context, backend messageWriter, topic string) (int, error) {
 	if topic == "" {
 		return 0, errors.New("show partitions requires a topic")
 	}
@@ -810,7 +871,7 @@ func (s *Server) handleShowPartitions(ctx context.Context, backend *pgproto3.Bac
 	return len(partitions), nil
 }
 
-func (s *Server) handleDescribeTopic(ctx context.Context, backend *pgproto3.Backend, topic string) (int, error) {
+func (s *Server) handleDescribeTopic(ctx context.Context, backend messageWriter, topic string) (int, error) {
 	_ = ctx
 	if topic == "" {
 		return 0, errors.New("describe requires a topic")
@@ -860,7 +921,7 @@ func (s *Server) handleDescribeTopic(ctx context.Context, backend *pgproto3.Back
 	return rows, nil
 }
 
-func (s *Server) handleExplain(ctx context.Context, backend *pgproto3.Backend, parsed kafsql.Query) (queryResult, error) {
+func (s *Server) handleExplain(ctx context.Context, backend messageWriter, parsed kafsql.Query) (queryResult, error) {
 	if parsed.Explain == nil {
 		return queryResult{}, errors.New("explain requires query")
 	}
@@ -947,8 +1008,22 @@ func (s *Server) explainJoin(parsed kafsql.Query, segments []discovery.SegmentRe
 	right.OffsetMin = nil
 	right.OffsetMax = nil
 
+	var within time.Duration
+	if parsed.TimeWindow != "" {
+		value, err := parseDuration(parsed.TimeWindow)
+		if err != nil {
+			return nil, err
+		}
+		within = value
+	}
+	var interval *kafsql.JoinInterval
+	if parsed.JoinOn != nil {
+		interval = parsed.JoinOn.Interval
+	}
+	rightMin, rightMax := joinRightBounds(timeMin, timeMax, within, interval)
+
 	leftSegments := filterSegments(left, segments, timeMin, timeMax)
-	rightSegments := filterSegments(right, segments, nil, nil)
+	rightSegments := filterSegments(right, segments, rightMin, rightMax)
 	estBytes := estimateBytes(leftSegments) + estimateBytes(rightSegments)
 
 	lines := []string{
@@ -958,9 +1033,38 @@ func (s *Server) explainJoin(parsed kafsql.Query, segments []discovery.SegmentRe
 		fmt.Sprintf("  Right segments: %d", len(rightSegments)),
 		fmt.Sprintf("  Estimated bytes: %s", formatBytes(estBytes)),
 	}
+	if interval != nil {
+		lines = append(lines, fmt.Sprintf("  Interval: right._ts - left._ts between %dms and %dms", interval.LowerMs, interval.UpperMs))
+	}
+	if s.cfg.Query.JoinMemoryBytes > 0 && estimateBytes(rightSegments) > s.cfg.Query.JoinMemoryBytes {
+		lines = append(lines, "  Build side: may spill to disk")
+	}
 	return lines, nil
 }
 
+// resolveTimeBounds combines explicit _ts predicates with a LAST window into
+// the effective [min, max] timestamp range of a query.
+func resolveTimeBounds(parsed kafsql.Query) (*int64, *int64, error) {
+	timeMin := parsed.TsMin
+	timeMax := parsed.TsMax
+	if parsed.Last != "" {
+		window, err := parseDuration(parsed.Last)
+		if err != nil {
+			return nil, nil, err
+		}
+		now := time.Now().UTC().UnixMilli()
+		start := now - window.Milliseconds()
+		timeMin = maxInt64Ptr(timeMin, start)
+		if timeMax == nil {
+			timeMax = &now
+		}
+	}
+	if timeMin != nil && timeMax != nil && *timeMax < *timeMin {
+		return nil, nil, errors.New("time window is invalid")
+	}
+	return timeMin, timeMax, nil
+}
+
 func filterSegments(parsed kafsql.Query, segments []discovery.SegmentRef, timeMin *int64, timeMax *int64) []discovery.SegmentRef {
 	out := make([]discovery.SegmentRef, 0)
 	for _, segment := range segments {
@@ -1009,7 +1113,7 @@ func formatBytes(value int64) string {
 	}
 }
 
-func (s *Server) catalogTables(ctx context.Context, backend *pgproto3.Backend) (int, error) {
+func (s *Server) catalogTables(ctx context.Context, backend messageWriter) (int, error) {
 	tables, err := s.topicTables(ctx)
 	if err != nil {
 		return 0, err
@@ -1038,7 +1142,7 @@ func (s *Server) catalogTables(ctx context.Context, backend *pgproto3.Backend) (
 	return len(tables), nil
 }
 
-func (s *Server) catalogColumns(ctx context.Context, backend *pgproto3.Backend) (int, error) {
+func (s *Server) catalogColumns(ctx context.Context, backend messageWriter) (int, error) {
 	tables, err := s.topicTables(ctx)
 	if err != nil {
 		return 0, err
@@ -1077,7 +1181,7 @@ func (s *Server) catalogColumns(ctx context.Context, backend *pgproto3.Backend)
 	return rowCount, nil
 }
 
-func (s *Server) catalogPgTables(ctx context.Context, backend *pgproto3.Backend) (int, error) {
+func (s *Server) catalogPgTables(ctx context.Context, backend messageWriter) (int, error) {
 	tables, err := s.topicTables(ctx)
 	if err != nil {
 		return 0, err
@@ -1114,7 +1218,7 @@ func (s *Server) catalogPgTables(ctx context.Context, backend *pgproto3.Backend)
 	return len(tables), nil
 }
 
-func (s *Server) catalogPgNamespace(ctx context.Context, backend *pgproto3.Backend) (int, error) {
+func (s *Server) catalogPgNamespace(ctx context.Context, backend messageWriter) (int, error) {
 	fields := []pgproto3.FieldDescription{
 		{Name: []byte("oid"), DataTypeOID: 23, DataTypeSize: 4, TypeModifier: -1, Format: 0},
 		{Name: []byte("nspname"), DataTypeOID: 25, DataTypeSize: -1, TypeModifier: -1, Format: 0},
@@ -1135,7 +1239,7 @@ func (s *Server) catalogPgNamespace(ctx context.Context, backend *pgproto3.Backe
 	return 3, nil
 }
 
-func (s *Server) catalogPgType(ctx context.Context, backend *pgproto3.Backend) (int, error) {
+func (s *Server) catalogPgType(ctx context.Context, backend messageWriter) (int, error) {
 	fields := []pgproto3.FieldDescription{
 		{Name: []byte("oid"), DataTypeOID: 23, DataTypeSize: 4, TypeModifier: -1, Format: 0},
 		{Name: []byte("typname"), DataTypeOID: 25, DataTypeSize: -1, TypeModifier: -1, Format: 0},
@@ -1164,7 +1268,7 @@ func (s *Server) catalogPgType(ctx context.Context, backend *pgproto3.Backend) (
 	return rowCount, nil
 }
 
-func (s *Server) catalogPgDatabase(ctx context.Context, backend *pgproto3.Backend) (int, error) {
+func (s *Server) catalogPgDatabase(ctx context.Context, backend messageWriter) (int, error) {
 	fields := []pgproto3.FieldDescription{
 		{Name: []byte("oid"), DataTypeOID: 23, DataTypeSize: 4, TypeModifier: -1, Format: 0},
 		{Name: []byte("datname"), DataTypeOID: 25, DataTypeSize: -1, TypeModifier: -1, Format: 0},
@@ -1182,7 +1286,7 @@ func (s *Server) catalogPgDatabase(ctx context.Context, backend *pgproto3.Backen
 	return 1, nil
 }
 
-func (s *Server) catalogPgClass(ctx context.Context, backend *pgproto3.Backend) (int, error) {
+func (s *Server) catalogPgClass(ctx context.Context, backend messageWriter) (int, error) {
 	tables, err := s.topicTables(ctx)
 	if err != nil {
 		return 0, err
@@ -1305,7 +1409,19 @@ func (s *Server) getDecoder() (decoder.Decoder, error) {
 	return s.decoder, s.decoderErr
 }
 
-func (s *Server) handleSelect(ctx context.Context, backend *pgproto3.Backend, parsed kafsql.Query, collector *rowCollector) (queryResult, error) {
+func (s *Server) getObjectWriter() (decoder.ObjectWriter, error) {
+	s.objectWriterMu.Lock()
+	defer s.objectWriterMu.Unlock()
+
+	if s.objectWriterInit {
+		return s.objectWriter, s.objectWriterErr
+	}
+	s.objectWriterInit = true
+	s.objectWriter, s.objectWriterErr = decoder.NewObjectWriter(s.cfg)
+	return s.objectWriter, s.objectWriterErr
+}
+
+func (s *Server) handleSelect(ctx context.Context, backend messageWriter, parsed kafsql.Query, collector *rowCollector) (queryResult, error) {
 	if parsed.JoinTopic != "" {
 		return s.handleJoinSelect(ctx, backend, parsed, collector)
 	}
@@ -1365,22 +1481,9 @@ func (s *Server) handleSelect(ctx context.Context, backend *pgproto3.Backend, pa
 		return queryResult{}, err
 	}
 
-	timeMin := parsed.TsMin
-	timeMax := parsed.TsMax
-	if parsed.Last != "" {
-		window, err := parseDuration(parsed.Last)
-		if err != nil {
-			return queryResult{}, err
-		}
-		now := time.Now().UTC().UnixMilli()
-		start := now - window.Milliseconds()
-		timeMin = maxInt64Ptr(timeMin, start)
-		if timeMax == nil {
-			timeMax = &now
-		}
-	}
-	if timeMin != nil && timeMax != nil && *timeMax < *timeMin {
-		return queryResult{}, errors.New("time window is invalid")
+	timeMin, timeMax, err := resolveTimeBounds(parsed)
+	if err != nil {
+		return queryResult{}, err
 	}
 
 	candidates := filterSegments(parsed, segments, timeMin, timeMax)
@@ -1412,15 +1515,10 @@ func (s *Server) handleSelect(ctx context.Context, backend *pgproto3.Backend, pa
 	bytesScanned := int64(0)
 	var rows []rowResult
 	var tailRows []rowResult
-	for _, segment := range candidates {
-		if err := ctx.Err(); err != nil {
-			return queryResult{}, err
-		}
+	_, keepRecords := backend.(rowWriter)
+	limitReached := false
+	err = s.scanSegments(ctx, dec, candidates, s.newScanBudget(), func(segment discovery.SegmentRef, records []decoder.Record) error {
 		segmentsScanned++
-		records, err := dec.Decode(ctx, segment.SegmentKey, segment.IndexKey, segment.Topic, segment.Partition)
-		if err != nil {
-			return queryResult{}, err
-		}
 		for _, record := range records {
 			if timeMin != nil && record.Timestamp < *timeMin {
 				continue
@@ -1435,10 +1533,7 @@ func (s *Server) handleSelect(ctx context.Context, backend *pgproto3.Backend, pa
 				continue
 			}
 			bytesScanned += int64(len(record.Key) + len(record.Value))
-			row := rowResult{
-				values: buildRowValues(resolvedCols, rowContext{left: record, leftSeg: segment.SegmentKey}),
-				ts:     record.Timestamp,
-			}
+			row := newRowResult(resolvedCols, rowContext{left: record, leftSeg: segment.SegmentKey}, keepRecords)
 			if parsed.OrderBy != "" {
 				rows = append(rows, row)
 				continue
@@ -1447,15 +1542,23 @@ func (s *Server) handleSelect(ctx context.Context, backend *pgproto3.Backend, pa
 				tailRows = appendTailRow(tailRows, row, tailCount)
 				continue
 			}
-			if err := s.send(backend, collector, &pgproto3.DataRow{Values: row.values}); err != nil {
-				return queryResult{}, err
+			if err := s.sendResult(backend, collector, resolvedCols, row); err != nil {
+				return err
 			}
 			sent++
 			if sent >= limit {
-				_ = s.send(backend, collector, &pgproto3.CommandComplete{CommandTag: commandTag(sent)})
-				return queryResult{rows: sent, segments: segmentsScanned, bytes: bytesScanned}, nil
+				limitReached = true
+				return errStopScan
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return queryResult{}, err
+	}
+	if limitReached {
+		_ = s.send(backend, collector, &pgproto3.CommandComplete{CommandTag: commandTag(sent)})
+		return queryResult{rows: sent, segments: segmentsScanned, bytes: bytesScanned}, nil
 	}
 
 	if parsed.OrderBy != "" {
@@ -1469,14 +1572,14 @@ func (s *Server) handleSelect(ctx context.Context, backend *pgproto3.Backend, pa
 			rows = rows[:limit]
 		}
 		for _, row := range rows {
-			if err := s.send(backend, collector, &pgproto3.DataRow{Values: row.values}); err != nil {
+			if err := s.sendResult(backend, collector, resolvedCols, row); err != nil {
 				return queryResult{}, err
 			}
 			sent++
 		}
 	} else if tailCount > 0 {
 		for _, row := range tailRows {
-			if err := s.send(backend, collector, &pgproto3.DataRow{Values: row.values}); err != nil {
+			if err := s.sendResult(backend, collector, resolvedCols, row); err != nil {
 				return queryResult{}, err
 			}
 			sent++
@@ -1592,11 +1695,21 @@ func buildRowValues(cols []resolvedColumn, ctx rowContext) [][]byte {
 	return values
 }
 
+// rowResult is a row held back for ORDER BY or TAIL. It keeps the projected
+// values, or the scanned records when the writer builds rows itself.
 type rowResult struct {
 	values [][]byte
+	row    *rowContext
 	ts     int64
 }
 
+func newRowResult(cols []resolvedColumn, ctx rowContext, keepRecords bool) rowResult {
+	if keepRecords {
+		return rowResult{row: &ctx, ts: ctx.left.Timestamp}
+	}
+	return rowResult{values: buildRowValues(cols, ctx), ts: ctx.left.Timestamp}
+}
+
 func appendTailRow(rows []rowResult, row rowResult, limit int) []rowResult {
 	if limit <= 0 {
 		return rows
@@ -1759,7 +1872,7 @@ type aggregatePlan struct {
 	outputs   []outputColumn
 }
 
-func (s *Server) handleAggregateSelect(ctx context.Context, backend *pgproto3.Backend, parsed kafsql.Query, segments []discovery.SegmentRef, timeMin *int64, timeMax *int64, limit int, collector *rowCollector) (queryResult, error) {
+func (s *Server) handleAggregateSelect(ctx context.Context, backend messageWriter, parsed kafsql.Query, segments []discovery.SegmentRef, timeMin *int64, timeMax *int64, limit int, collector *rowCollector) (queryResult, error) {
 	plan, err := s.buildAggregatePlan(parsed)
 	if err != nil {
 		return queryResult{}, err
@@ -1781,15 +1894,8 @@ func (s *Server) handleAggregateSelect(ctx context.Context, backend *pgproto3.Ba
 	groups := make(map[string]*groupState)
 	segmentsScanned := 0
 	bytesScanned := int64(0)
-	for _, segment := range segments {
-		if err := ctx.Err(); err != nil {
-			return queryResult{}, err
-		}
+	err = s.scanSegments(ctx, dec, segments, s.newScanBudget(), func(segment discovery.SegmentRef, records []decoder.Record) error {
 		segmentsScanned++
-		records, err := dec.Decode(ctx, segment.SegmentKey, segment.IndexKey, segment.Topic, segment.Partition)
-		if err != nil {
-			return queryResult{}, err
-		}
 		for _, record := range records {
 			if timeMin != nil && record.Timestamp < *timeMin {
 				continue
@@ -1824,6 +1930,10 @@ func (s *Server) handleAggregateSelect(ctx context.Context, backend *pgproto3.Ba
 				updateAgg(&state.Aggs[i], spec, rowCtx)
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return queryResult{}, err
 	}
 
 	groupKeys := make([]string, 0, len(groups))
@@ -2278,7 +2388,7 @@ func aggMinMaxValue(state aggState, min bool) []byte {
 	}
 }
 
-func (s *Server) handleJoinSelect(ctx context.Context, backend *pgproto3.Backend, parsed kafsql.Query, collector *rowCollector) (queryResult, error) {
+func (s *Server) handleJoinSelect(ctx context.Context, backend messageWriter, parsed kafsql.Query, collector *rowCollector) (queryResult, error) {
 	if parsed.Topic == "" || parsed.JoinTopic == "" {
 		return queryResult{}, errors.New("join requires two topics")
 	}
@@ -2288,8 +2398,12 @@ func (s *Server) handleJoinSelect(ctx context.Context, backend *pgproto3.Backend
 	if parsed.ScanFull {
 		return queryResult{}, errors.New("join does not support scan full")
 	}
-	if parsed.TimeWindow == "" || parsed.Last == "" {
-		return queryResult{}, errors.New("join requires within <duration> and last <duration>")
+	interval := parsed.JoinOn != nil && parsed.JoinOn.Interval != nil
+	if parsed.Last == "" || (parsed.TimeWindow == "" && !interval) {
+		return queryResult{}, errors.New("join requires last <duration> and within <duration> or a _ts between interval")
+	}
+	if parsed.TimeWindow != "" && interval {
+		return queryResult{}, errors.New("join cannot combine within with a _ts between interval")
 	}
 	if parsed.Partition != nil || parsed.OffsetMin != nil || parsed.OffsetMax != nil {
 		return queryResult{}, errors.New("join does not support partition or offset filters")
@@ -2298,9 +2412,13 @@ func (s *Server) handleJoinSelect(ctx context.Context, backend *pgproto3.Backend
 		return queryResult{}, errors.New("join does not support aggregates")
 	}
 
-	within, err := parseDuration(parsed.TimeWindow)
-	if err != nil {
-		return queryResult{}, err
+	var within time.Duration
+	if parsed.TimeWindow != "" {
+		value, err := parseDuration(parsed.TimeWindow)
+		if err != nil {
+			return queryResult{}, err
+		}
+		within = value
 	}
 	last, err := parseDuration(parsed.Last)
 	if err != nil {
@@ -2355,22 +2473,9 @@ func (s *Server) handleJoinSelect(ctx context.Context, backend *pgproto3.Backend
 		return queryResult{}, err
 	}
 
-	timeMin := parsed.TsMin
-	timeMax := parsed.TsMax
-	if parsed.Last != "" {
-		window, err := parseDuration(parsed.Last)
-		if err != nil {
-			return queryResult{}, err
-		}
-		now := time.Now().UTC().UnixMilli()
-		start := now - window.Milliseconds()
-		timeMin = maxInt64Ptr(timeMin, start)
-		if timeMax == nil {
-			timeMax = &now
-		}
-	}
-	if timeMin != nil && timeMax != nil && *timeMax < *timeMin {
-		return queryResult{}, errors.New("time window is invalid")
+	timeMin, timeMax, err := resolveTimeBounds(parsed)
+	if err != nil {
+		return queryResult{}, err
 	}
 
 	leftParsed := parsed
@@ -2381,82 +2486,73 @@ func (s *Server) handleJoinSelect(ctx context.Context, backend *pgproto3.Backend
 	rightParsed.Partition = nil
 	rightParsed.OffsetMin = nil
 	rightParsed.OffsetMax = nil
-	rightSegments := filterSegments(rightParsed, segments, nil, nil)
+	rightMin, rightMax := joinRightBounds(timeMin, timeMax, within, joinOn.Interval)
+	rightSegments := filterSegments(rightParsed, segments, rightMin, rightMax)
 
 	if err := s.enforceScanLimits(len(leftSegments)+len(rightSegments), estimateBytes(leftSegments)+estimateBytes(rightSegments)); err != nil {
 		return queryResult{}, err
 	}
 
-	leftRecords, leftBytes, err := s.loadRecords(ctx, dec, leftSegments, parsed.Topic)
+	budget := s.newScanBudget()
+	leftRecords, leftBytes, err := s.loadRecords(ctx, dec, leftSegments, parsed.Topic, budget)
 	if err != nil {
 		return queryResult{}, err
 	}
-	rightRecords, rightBytes, err := s.loadRecords(ctx, dec, rightSegments, parsed.JoinTopic)
+	rightTable, rightBytes, err := s.buildJoinTable(ctx, dec, rightSegments, parsed.JoinTopic, joinOn.Right, rightMin, rightMax, budget)
 	if err != nil {
 		return queryResult{}, err
 	}
+	defer rightTable.close()
 
-	rightByKey := groupByJoinKey(rightRecords, joinOn.Right)
 	sent := 0
 	segmentsScanned := len(leftSegments) + len(rightSegments)
 	bytesScanned := leftBytes + rightBytes
-	for _, left := range leftRecords {
+	err = rightTable.probe(leftRecords, joinOn.Left, func(left joinRecord, key string, candidates []joinRecord) error {
 		if err := ctx.Err(); err != nil {
-			return queryResult{}, err
+			return err
 		}
 		if left.Record.Timestamp < windowStart.UnixMilli() {
-			continue
+			return nil
 		}
-		key := joinKeyFromExpr(left.Record, joinOn.Left)
-		if key == "" {
-			if parsed.JoinType == "left" {
-				values := buildRowValues(cols, rowContext{left: left.Record, leftSeg: left.SegmentKey})
-				if err := s.send(backend, collector, &pgproto3.DataRow{Values: values}); err != nil {
-					return queryResult{}, err
-				}
-				sent++
-			}
-			if sent >= limit {
-				_ = s.send(backend, collector, &pgproto3.CommandComplete{CommandTag: commandTag(sent)})
-				return queryResult{rows: sent, segments: segmentsScanned, bytes: bytesScanned}, nil
-			}
-			continue
+		if (timeMin != nil && left.Record.Timestamp < *timeMin) || (timeMax != nil && left.Record.Timestamp > *timeMax) {
+			return nil
 		}
-
-		candidates := rightByKey[key]
 		matched := false
-		for _, right := range candidates {
-			if !withinWindow(left.Record.Timestamp, right.Record.Timestamp, within) {
-				continue
-			}
-			values := buildRowValues(cols, rowContext{
-				left:     left.Record,
-				right:    &right.Record,
-				leftSeg:  left.SegmentKey,
-				rightSeg: right.SegmentKey,
-			})
-			if err := s.send(backend, collector, &pgproto3.DataRow{Values: values}); err != nil {
-				return queryResult{}, err
-			}
-			sent++
-			matched = true
-			if sent >= limit {
-				_ = s.send(backend, collector, &pgproto3.CommandComplete{CommandTag: commandTag(sent)})
-				return queryResult{rows: sent, segments: segmentsScanned, bytes: bytesScanned}, nil
+		if key != "" {
+			for _, right := range candidates {
+				if !joinTimeMatches(left.Record.Timestamp, right.Record.Timestamp, within, joinOn.Interval) {
+					continue
+				}
+				row := rowContext{
+					left:     left.Record,
+					right:    &right.Record,
+					leftSeg:  left.SegmentKey,
+					rightSeg: right.SegmentKey,
+				}
+				if err := s.sendRow(backend, collector, cols, row); err != nil {
+					return err
+				}
+				sent++
+				matched = true
+				if sent >= limit {
+					return errStopScan
+				}
 			}
 		}
 
 		if !matched && parsed.JoinType == "left" {
-			values := buildRowValues(cols, rowContext{left: left.Record, leftSeg: left.SegmentKey})
-			if err := s.send(backend, collector, &pgproto3.DataRow{Values: values}); err != nil {
-				return queryResult{}, err
+			if err := s.sendRow(backend, collector, cols, rowContext{left: left.Record, leftSeg: left.SegmentKey}); err != nil {
+				return err
 			}
 			sent++
 			if sent >= limit {
-				_ = s.send(backend, collector, &pgproto3.CommandComplete{CommandTag: commandTag(sent)})
-				return queryResult{rows: sent, segments: segmentsScanned, bytes: bytesScanned}, nil
+				return errStopScan
 			}
 		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStopScan) {
+		return queryResult{}, err
 	}
 
 	_ = s.send(backend, collector, &pgproto3.CommandComplete{CommandTag: commandTag(sent)})
@@ -2468,24 +2564,24 @@ type joinRecord struct {
 	SegmentKey string
 }
 
-func (s *Server) loadRecords(ctx context.Context, dec decoder.Decoder, segments []discovery.SegmentRef, topic string) ([]joinRecord, int64, error) {
+func (s *Server) loadRecords(ctx context.Context, dec decoder.Decoder, segments []discovery.SegmentRef, topic string, budget *scanBudget) ([]joinRecord, int64, error) {
 	out := make([]joinRecord, 0)
 	bytesScanned := int64(0)
+	topicSegments := make([]discovery.SegmentRef, 0, len(segments))
 	for _, segment := range segments {
-		if err := ctx.Err(); err != nil {
-			return nil, 0, err
-		}
-		if segment.Topic != topic {
-			continue
-		}
-		records, err := dec.Decode(ctx, segment.SegmentKey, segment.IndexKey, segment.Topic, segment.Partition)
-		if err != nil {
-			return nil, 0, err
+		if segment.Topic == topic {
+			topicSegments = append(topicSegments, segment)
 		}
+	}
+	err := s.scanSegments(ctx, dec, topicSegments, budget, func(segment discovery.SegmentRef, records []decoder.Record) error {
 		for _, record := range records {
 			bytesScanned += int64(len(record.Key) + len(record.Value))
 			out = append(out, joinRecord{Record: record, SegmentKey: segment.SegmentKey})
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 	return out, bytesScanned, nil
 }
@@ -2600,19 +2696,7 @@ func normalizeJoinCondition(cond *kafsql.JoinCondition) *kafsql.JoinCondition {
 	if left.Side == "right" && right.Side == "left" {
 		left, right = right, left
 	}
-	return &kafsql.JoinCondition{Left: left, Right: right}
-}
-
-func groupByJoinKey(records []joinRecord, expr kafsql.JoinExpr) map[string][]joinRecord {
-	out := make(map[string][]joinRecord)
-	for _, record := range records {
-		key := joinKeyFromExpr(record.Record, expr)
-		if key == "" {
-			continue
-		}
-		out[key] = append(out[key], record)
-	}
-	return out
+	return &kafsql.JoinCondition{Left: left, Right: right, Interval: cond.Interval}
 }
 
 func segmentsByTopic(segments []discovery.SegmentRef, topic string) int {
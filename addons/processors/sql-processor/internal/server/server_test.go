@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -40,11 +41,14 @@ func (m *mockLister) ListCompleted(ctx context.Context) ([]discovery.SegmentRef,
 }
 
 type mockDecoder struct {
+	mu      sync.Mutex
 	records map[string][]decoder.Record
 	keys    []string
 }
 
 func (m *mockDecoder) Decode(ctx context.Context, segmentKey, indexKey string, topic string, partition int32) ([]decoder.Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.keys = append(m.keys, segmentKey)
 	return m.records[segmentKey], nil
 }
@@ -838,6 +842,20 @@ func TestJoinHelpers(t *testing.T) {
 	}
 }
 
+func TestRowResultKeepsRecordsOnlyForRowWriters(t *testing.T) {
+	cols := []resolvedColumn{{Kind: columnImplicit, Column: "_offset"}}
+	ctx := rowContext{left: decoder.Record{Offset: 7, Timestamp: 42, Key: []byte("k"), Value: []byte("large payload")}}
+
+	row := newRowResult(cols, ctx, false)
+	if row.row != nil || len(row.values) != 1 || string(row.values[0]) != "7" || row.ts != 42 {
+		t.Fatalf("expected projected values only, got %+v", row)
+	}
+	row = newRowResult(cols, ctx, true)
+	if row.row == nil || row.values != nil || row.ts != 42 {
+		t.Fatalf("expected records kept for row writers, got %+v", row)
+	}
+}
+
 func TestCacheKeyRules(t *testing.T) {
 	srv := newTestServer(&mockLister{}, &mockDecoder{})
 	parsed := kafsql.Query{Type: kafsql.QuerySelect, Topic: "orders", Last: "1h"}
@@ -24,6 +24,7 @@ const (
 	QueryDescribe       QueryType = "describe"
 	QuerySelect         QueryType = "select"
 	QueryExplain        QueryType = "explain"
+	QueryCopy           QueryType = "copy"
 )
 
 type Query struct {
@@ -54,6 +55,16 @@ type Query struct {
 	ScanFull   bool
 
 	Explain *Query
+	Copy    *CopyStatement
+}
+
+// CopyStatement describes COPY (SELECT ...) TO '<target>' (FORMAT ...).
+type CopyStatement struct {
+	Source         Query
+	SourceSQL      string
+	Target         string
+	Format         string
+	MaxRowsPerFile int
 }
 
 type SelectColumnKind string
@@ -92,8 +103,15 @@ const (
 )
 
 type JoinCondition struct {
-	Left  JoinExpr
-	Right JoinExpr
+	Left     JoinExpr
+	Right    JoinExpr
+	Interval *JoinInterval
+}
+
+// JoinInterval bounds right._ts - left._ts to [LowerMs, UpperMs].
+type JoinInterval struct {
+	LowerMs int64
+	UpperMs int64
 }
 
 type JoinExpr struct {
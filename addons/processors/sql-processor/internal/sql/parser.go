@@ -45,6 +45,8 @@ func Parse(query string) (Query, error) {
 		return parseSelect(trimmed, lower, fields)
 	case "explain":
 		return parseExplain(trimmed)
+	case "copy":
+		return parseCopy(trimmed)
 	default:
 		return Query{Type: QueryUnknown}, fmt.Errorf("unsupported statement")
 	}
@@ -87,6 +89,103 @@ func parseExplain(raw string) (Query, error) {
 	return Query{Type: QueryExplain, Explain: &parsed}, nil
 }
 
+func parseCopy(raw string) (Query, error) {
+	rest := strings.TrimSpace(raw[len("copy"):])
+	if !strings.HasPrefix(rest, "(") {
+		return Query{Type: QueryUnknown}, fmt.Errorf("copy requires (select ...)")
+	}
+	end := closingParen(rest)
+	if end == -1 {
+		return Query{Type: QueryUnknown}, fmt.Errorf("copy query is missing )")
+	}
+	sourceSQL := strings.TrimSpace(rest[1:end])
+	source, err := Parse(sourceSQL)
+	if err != nil {
+		return Query{Type: QueryUnknown}, err
+	}
+	if source.Type != QuerySelect {
+		return Query{Type: QueryUnknown}, fmt.Errorf("copy supports select only")
+	}
+
+	rest = strings.TrimSpace(rest[end+1:])
+	if len(rest) < 2 || !strings.EqualFold(rest[:2], "to") {
+		return Query{Type: QueryUnknown}, fmt.Errorf("copy requires to '<target>'")
+	}
+	rest = strings.TrimSpace(rest[2:])
+	if !strings.HasPrefix(rest, "'") {
+		return Query{Type: QueryUnknown}, fmt.Errorf("copy target must be quoted")
+	}
+	quote := strings.Index(rest[1:], "'")
+	if quote == -1 {
+		return Query{Type: QueryUnknown}, fmt.Errorf("copy target must be quoted")
+	}
+	stmt := &CopyStatement{
+		Source:    source,
+		SourceSQL: sourceSQL,
+		Target:    rest[1 : quote+1],
+		Format:    "parquet",
+	}
+	if !strings.HasPrefix(strings.ToLower(stmt.Target), "s3://") {
+		return Query{Type: QueryUnknown}, fmt.Errorf("copy target must be an s3:// url")
+	}
+
+	rest = strings.TrimSpace(rest[quote+2:])
+	if len(rest) >= 4 && strings.EqualFold(rest[:4], "with") {
+		rest = strings.TrimSpace(rest[4:])
+	}
+	if rest != "" {
+		if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+			return Query{Type: QueryUnknown}, fmt.Errorf("copy options must be wrapped in ()")
+		}
+		for _, option := range strings.Split(rest[1:len(rest)-1], ",") {
+			parts := strings.Fields(strings.ToLower(option))
+			if len(parts) != 2 {
+				return Query{Type: QueryUnknown}, fmt.Errorf("invalid copy option %q", strings.TrimSpace(option))
+			}
+			switch parts[0] {
+			case "format":
+				switch parts[1] {
+				case "parquet", "csv", "ndjson":
+					stmt.Format = parts[1]
+				default:
+					return Query{Type: QueryUnknown}, fmt.Errorf("unsupported copy format %q", parts[1])
+				}
+			case "max_rows_per_file":
+				value, err := strconv.Atoi(parts[1])
+				if err != nil || value <= 0 {
+					return Query{Type: QueryUnknown}, fmt.Errorf("invalid max_rows_per_file")
+				}
+				stmt.MaxRowsPerFile = value
+			default:
+				return Query{Type: QueryUnknown}, fmt.Errorf("unsupported copy option %q", parts[0])
+			}
+		}
+	}
+	return Query{Type: QueryCopy, Topic: source.Topic, Copy: stmt}, nil
+}
+
+// closingParen returns the index of the parenthesis closing raw[0], skipping
+// quoted strings.
+func closingParen(raw string) int {
+	depth := 0
+	inQuote := false
+	for i, r := range raw {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
 func parseSelect(raw string, lower string, fields []string) (Query, error) {
 	selectCols, err := parseSelectColumns(raw, lower)
 	if err != nil {
@@ -613,6 +712,15 @@ func keywordIndex(lower, keyword string) int {
 	return loc[0]
 }
 
+func lastKeywordIndex(lower, keyword string) int {
+	re := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(keyword) + `\b`)
+	locs := re.FindAllStringIndex(lower, -1)
+	if len(locs) == 0 {
+		return -1
+	}
+	return locs[len(locs)-1][0]
+}
+
 func clauseEnd(lower string, stopKeywords []string) int {
 	end := len(lower)
 	for _, keyword := range stopKeywords {
@@ -653,6 +761,19 @@ func parseJoinCondition(raw string, lower string, topic string, alias string, jo
 	restLower := lower[onIdx+len("on"):]
 	end := clauseEnd(restLower, []string{"within", "last", "tail", "limit", "where", "group by", "order by", "scan"})
 	expr := strings.TrimSpace(rest[:end])
+	var interval *JoinInterval
+	if betweenIdx := keywordIndex(strings.ToLower(expr), "between"); betweenIdx != -1 {
+		andIdx := lastKeywordIndex(strings.ToLower(expr[:betweenIdx]), "and")
+		if andIdx == -1 {
+			return nil, fmt.Errorf("join interval must follow the equality predicate with and")
+		}
+		parsedInterval, err := parseJoinInterval(expr[andIdx+len("and"):], topic, alias, joinTopic, joinAlias)
+		if err != nil {
+			return nil, err
+		}
+		interval = parsedInterval
+		expr = strings.TrimSpace(expr[:andIdx])
+	}
 	parts := strings.Split(expr, "=")
 	if len(parts) != 2 {
 		return nil, fmt.Errorf("join requires equality predicate")
@@ -665,7 +786,106 @@ func parseJoinCondition(raw string, lower string, topic string, alias string, jo
 	if err != nil {
 		return nil, err
 	}
-	return &JoinCondition{Left: left, Right: right}, nil
+	return &JoinCondition{Left: left, Right: right, Interval: interval}, nil
+}
+
+// parseJoinInterval parses "x._ts BETWEEN y._ts [+|- INTERVAL '<d>'] AND
+// y._ts [+|- INTERVAL '<d>']" and normalizes it to bounds on
+// right._ts - left._ts.
+func parseJoinInterval(raw string, topic string, alias string, joinTopic string, joinAlias string) (*JoinInterval, error) {
+	lower := strings.ToLower(raw)
+	betweenIdx := keywordIndex(lower, "between")
+	subjectSource, subjectColumn := parseColumnRef(lower[:betweenIdx])
+	if subjectColumn != "_ts" {
+		return nil, fmt.Errorf("join interval supports _ts only")
+	}
+	bounds := raw[betweenIdx+len("between"):]
+	andIdx := keywordIndex(strings.ToLower(bounds), "and")
+	if andIdx == -1 {
+		return nil, fmt.Errorf("join interval requires between ... and ...")
+	}
+	lowSource, lowOffset, err := parseTsOffset(bounds[:andIdx])
+	if err != nil {
+		return nil, err
+	}
+	highSource, highOffset, err := parseTsOffset(bounds[andIdx+len("and"):])
+	if err != nil {
+		return nil, err
+	}
+	subjectSide := resolveJoinSide(subjectSource, topic, alias, joinTopic, joinAlias)
+	lowSide := resolveJoinSide(lowSource, topic, alias, joinTopic, joinAlias)
+	highSide := resolveJoinSide(highSource, topic, alias, joinTopic, joinAlias)
+	if lowSide != highSide || lowSide == subjectSide {
+		return nil, fmt.Errorf("join interval must compare _ts across both topics")
+	}
+	if lowOffset > highOffset {
+		return nil, fmt.Errorf("join interval lower bound exceeds upper bound")
+	}
+	if subjectSide == "right" {
+		return &JoinInterval{LowerMs: lowOffset, UpperMs: highOffset}, nil
+	}
+	// left._ts BETWEEN right._ts + low AND right._ts + high
+	// => right._ts - left._ts BETWEEN -high AND -low.
+	return &JoinInterval{LowerMs: -highOffset, UpperMs: -lowOffset}, nil
+}
+
+// parseTsOffset parses "src._ts [+|- INTERVAL '<duration>']".
+func parseTsOffset(raw string) (string, int64, error) {
+	expr := strings.TrimSpace(raw)
+	offset := int64(0)
+	re := regexp.MustCompile(`(?i)^(.*?)\s*([+-])\s*(interval\s*'[^']*')$`)
+	if match := re.FindStringSubmatch(expr); match != nil {
+		value, err := parseIntervalLiteral(match[3])
+		if err != nil {
+			return "", 0, err
+		}
+		offset = value
+		if match[2] == "-" {
+			offset = -value
+		}
+		expr = match[1]
+	}
+	source, column := parseColumnRef(strings.ToLower(expr))
+	if column != "_ts" {
+		return "", 0, fmt.Errorf("join interval supports _ts only")
+	}
+	return source, offset, nil
+}
+
+// parseIntervalLiteral parses INTERVAL '5m' or INTERVAL '5 minutes' into
+// milliseconds.
+func parseIntervalLiteral(raw string) (int64, error) {
+	lower := strings.ToLower(strings.TrimSpace(raw))
+	lower = strings.TrimSpace(strings.TrimPrefix(lower, "interval"))
+	if len(lower) < 2 || !strings.HasPrefix(lower, "'") || !strings.HasSuffix(lower, "'") {
+		return 0, fmt.Errorf("invalid interval %q", strings.TrimSpace(raw))
+	}
+	value := strings.TrimSpace(lower[1 : len(lower)-1])
+	re := regexp.MustCompile(`^(\d+)\s*([a-z]+)$`)
+	match := re.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("invalid interval %q", value)
+	}
+	amount, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %q", value)
+	}
+	var unit int64
+	switch match[2] {
+	case "ms", "millisecond", "milliseconds":
+		unit = 1
+	case "s", "sec", "second", "seconds":
+		unit = 1000
+	case "m", "min", "minute", "minutes":
+		unit = 60 * 1000
+	case "h", "hour", "hours":
+		unit = 60 * 60 * 1000
+	case "d", "day", "days":
+		unit = 24 * 60 * 60 * 1000
+	default:
+		return 0, fmt.Errorf("invalid interval unit %q", match[2])
+	}
+	return amount * unit, nil
 }
 
 func parseJoinExpr(raw string, topic string, alias string, joinTopic string, joinAlias string) (JoinExpr, error) {
@@ -85,6 +85,44 @@ func TestParseSelectJoin(t *testing.T) {
 	}
 }
 
+func TestParseSelectIntervalJoin(t *testing.T) {
+	q, err := Parse("SELECT * FROM orders o JOIN payments p ON o._key = p._key AND p._ts BETWEEN o._ts - INTERVAL '5m' AND o._ts LAST 1h;")
+	if err != nil {
+		t.Fatalf("expected interval join, got error: %v", err)
+	}
+	if q.JoinOn == nil || q.JoinOn.Interval == nil {
+		t.Fatalf("expected join interval: %+v", q.JoinOn)
+	}
+	if q.JoinOn.Interval.LowerMs != -5*60*1000 || q.JoinOn.Interval.UpperMs != 0 {
+		t.Fatalf("unexpected interval: %+v", q.JoinOn.Interval)
+	}
+	if q.JoinOn.Left.Side != "left" || q.JoinOn.Right.Side != "right" || q.Last != "1h" {
+		t.Fatalf("unexpected join: %+v", q)
+	}
+
+	q, err = Parse("SELECT * FROM orders o JOIN payments p ON o._key = p._key AND o._ts BETWEEN p._ts AND p._ts + INTERVAL '30 seconds' LAST 1h;")
+	if err != nil {
+		t.Fatalf("expected interval join, got error: %v", err)
+	}
+	if q.JoinOn.Interval.LowerMs != -30000 || q.JoinOn.Interval.UpperMs != 0 {
+		t.Fatalf("unexpected flipped interval: %+v", q.JoinOn.Interval)
+	}
+}
+
+func TestParseSelectIntervalJoinErrors(t *testing.T) {
+	queries := []string{
+		"SELECT * FROM orders o JOIN payments p ON o._key = p._key AND p._ts BETWEEN o._ts AND o._ts - INTERVAL '5m' LAST 1h;",
+		"SELECT * FROM orders o JOIN payments p ON o._key = p._key AND p._ts BETWEEN p._ts AND p._ts LAST 1h;",
+		"SELECT * FROM orders o JOIN payments p ON o._key = p._key AND p._offset BETWEEN o._ts AND o._ts LAST 1h;",
+		"SELECT * FROM orders o JOIN payments p ON o._key = p._key AND p._ts BETWEEN o._ts - INTERVAL '5 weeks' AND o._ts LAST 1h;",
+	}
+	for _, query := range queries {
+		if _, err := Parse(query); err == nil {
+			t.Fatalf("expected error for %q", query)
+		}
+	}
+}
+
 func TestParseOrderBy(t *testing.T) {
 	q, err := Parse("SELECT * FROM orders ORDER BY _ts DESC LIMIT 10;")
 	if err != nil {
@@ -162,3 +200,43 @@ func TestParseInvalid(t *testing.T) {
 		t.Fatalf("expected error for unsupported statement")
 	}
 }
+
+func TestParseCopy(t *testing.T) {
+	q, err := Parse("COPY (SELECT _offset, _value FROM orders WHERE _partition = 1 LAST 1h) TO 's3://Exports/orders/2024' (FORMAT csv, MAX_ROWS_PER_FILE 500);")
+	if err != nil {
+		t.Fatalf("expected copy, got error: %v", err)
+	}
+	if q.Type != QueryCopy || q.Copy == nil || q.Topic != "orders" {
+		t.Fatalf("unexpected query: %+v", q)
+	}
+	if q.Copy.Source.Type != QuerySelect || q.Copy.Source.Last != "1h" {
+		t.Fatalf("unexpected source: %+v", q.Copy.Source)
+	}
+	if q.Copy.Target != "s3://Exports/orders/2024" || q.Copy.Format != "csv" || q.Copy.MaxRowsPerFile != 500 {
+		t.Fatalf("unexpected copy statement: %+v", q.Copy)
+	}
+
+	q, err = Parse("COPY (SELECT * FROM orders LAST 1h) TO 's3://bucket/prefix'")
+	if err != nil {
+		t.Fatalf("expected copy, got error: %v", err)
+	}
+	if q.Copy.Format != "parquet" {
+		t.Fatalf("expected parquet default, got %q", q.Copy.Format)
+	}
+}
+
+func TestParseCopyErrors(t *testing.T) {
+	cases := []string{
+		"COPY orders TO 's3://bucket/prefix'",
+		"COPY (SELECT * FROM orders LAST 1h TO 's3://bucket/prefix'",
+		"COPY (SHOW TOPICS) TO 's3://bucket/prefix'",
+		"COPY (SELECT * FROM orders LAST 1h) TO '/tmp/out'",
+		"COPY (SELECT * FROM orders LAST 1h) TO 's3://bucket/prefix' (FORMAT avro)",
+		"COPY (SELECT * FROM orders LAST 1h) TO 's3://bucket/prefix' (COMPRESSION gzip)",
+	}
+	for _, query := range cases {
+		if _, err := Parse(query); err == nil {
+			t.Fatalf("expected error for %q", query)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import "sigs.k8s.io/controller-runtime/pkg/conversion"
+
+// v1alpha1 is the hub (storage) version. Later versions implement
+// conversion.Convertible against these types, and the conversion webhook is
+// registered by the same Setup*WebhookWithManager calls once they exist.
+
+var (
+	_ conversion.Hub = &KafscaleCluster{}
+	_ conversion.Hub = &KafscaleTopic{}
+)
+
+// Hub marks KafscaleCluster as the conversion hub.
+func (*KafscaleCluster) Hub() {}
+
+// Hub marks KafscaleTopic as the conversion hub.
+func (*KafscaleTopic) Hub() {}
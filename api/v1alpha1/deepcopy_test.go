@@ -17,6 +17,7 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -52,6 +53,9 @@ func TestKafscaleClusterDeepCopy(t *testing.T) {
 			Brokers: BrokerSpec{AdvertisedHost: "broker.local"},
 			S3:      S3Spec{Bucket: "bucket", Region: "us-east-1", CredentialsSecretRef: "secret"},
 			Etcd:    EtcdSpec{Endpoints: []string{"http://127.0.0.1:2379"}},
+			Proxy: &ProxySpec{Service: ComponentServiceSpec{
+				Annotations: map[string]string{"lb": "external"},
+			}},
 		},
 		Status: KafscaleClusterStatus{
 			Phase: "Ready",
@@ -59,6 +63,7 @@ func TestKafscaleClusterDeepCopy(t *testing.T) {
 				Type:   "EtcdSnapshotAccess",
 				Status: metav1.ConditionTrue,
 			}},
+			EtcdRestore: &EtcdRestoreStatus{RestoreFrom: "latest", Phase: "Restoring", StartedAt: &metav1.Time{}},
 		},
 	}
 	copy := orig.DeepCopy()
@@ -69,6 +74,15 @@ func TestKafscaleClusterDeepCopy(t *testing.T) {
 	if orig.Status.Conditions[0].Status == copy.Status.Conditions[0].Status {
 		t.Fatalf("expected deep copy of Conditions")
 	}
+	copy.Status.EtcdRestore.Phase = "Completed"
+	copy.Status.EtcdRestore.StartedAt.Time = copy.Status.EtcdRestore.StartedAt.Add(time.Minute)
+	if orig.Status.EtcdRestore.Phase != "Restoring" || !orig.Status.EtcdRestore.StartedAt.IsZero() {
+		t.Fatalf("expected deep copy of EtcdRestore")
+	}
+	copy.Spec.Proxy.Service.Annotations["lb"] = "internal"
+	if orig.Spec.Proxy.Service.Annotations["lb"] != "external" {
+		t.Fatalf("expected deep copy of Proxy")
+	}
 	if copy.Spec.Brokers.AdvertisedHost != orig.Spec.Brokers.AdvertisedHost {
 		t.Fatalf("expected broker host to match")
 	}
@@ -95,3 +109,49 @@ func TestKafscaleTopicDeepCopy(t *testing.T) {
 		t.Fatalf("expected deep copy of Partitions")
 	}
 }
+
+func TestKafscaleACLDeepCopy(t *testing.T) {
+	orig := &KafscaleACL{
+		Spec: KafscaleACLSpec{
+			ClusterRef: "kafscale",
+			User:       "alice",
+			Rules: []ACLRuleSpec{{
+				ResourceType: "Topic",
+				ResourceName: "orders",
+				Operations:   []string{"Read"},
+			}},
+		},
+	}
+	copy := orig.DeepCopy()
+	copy.Spec.Rules[0].Operations[0] = "Write"
+	if orig.Spec.Rules[0].Operations[0] != "Read" {
+		t.Fatalf("expected deep copy of Operations")
+	}
+}
+
+func TestKafscaleIcebergSinkDeepCopy(t *testing.T) {
+	replicas := int32(2)
+	orig := &KafscaleIcebergSink{
+		Spec: KafscaleIcebergSinkSpec{
+			ClusterRef:            "kafscale",
+			ProcessorWorkloadSpec: ProcessorWorkloadSpec{Replicas: &replicas},
+			IcebergProcessorConfig: IcebergProcessorConfig{
+				Mappings: []IcebergMapping{{
+					Topic: "orders",
+					Table: "prod.orders",
+					Schema: IcebergMappingSchema{Columns: []IcebergColumn{{
+						Name:    "tags",
+						Type:    "list",
+						Element: &IcebergColumn{Type: "string"},
+					}}},
+				}},
+			},
+		},
+	}
+	copy := orig.DeepCopy()
+	*copy.Spec.Replicas = 3
+	copy.Spec.Mappings[0].Schema.Columns[0].Element.Type = "long"
+	if *orig.Spec.Replicas != 2 || orig.Spec.Mappings[0].Schema.Columns[0].Element.Type != "string" {
+		t.Fatalf("expected deep copy of replicas and nested columns")
+	}
+}
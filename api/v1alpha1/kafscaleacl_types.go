@@ -0,0 +1,182 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// KafscaleACLSpec grants or denies operations on resources to one user.
+type KafscaleACLSpec struct {
+	ClusterRef string `json:"clusterRef"`
+	// User is the KafscaleUser name the rules apply to, or "*" for every user.
+	User  string        `json:"user"`
+	Rules []ACLRuleSpec `json:"rules"`
+}
+
+// ACLRuleSpec follows Kafka's ACL model; each operation becomes one binding.
+type ACLRuleSpec struct {
+	// ResourceType is Topic, Group, Cluster or TransactionalId.
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	// PatternType is Literal (default) or Prefixed.
+	PatternType string   `json:"patternType,omitempty"`
+	Operations  []string `json:"operations"`
+	// Permission is Allow (default) or Deny.
+	Permission string `json:"permission,omitempty"`
+	// Host restricts the rule to a client address. Defaults to "*".
+	Host string `json:"host,omitempty"`
+}
+
+// KafscaleACLStatus surfaces observed reconciliation details.
+type KafscaleACLStatus struct {
+	Phase      string             `json:"phase,omitempty"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Bindings   int32              `json:"bindings,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// KafscaleACL declares authorization rules the operator publishes to brokers.
+type KafscaleACL struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KafscaleACLSpec   `json:"spec,omitempty"`
+	Status KafscaleACLStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KafscaleACLList contains multiple ACLs.
+type KafscaleACLList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KafscaleACL `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KafscaleACL{}, &KafscaleACLList{})
+}
+
+func (in *ACLRuleSpec) DeepCopyInto(out *ACLRuleSpec) {
+	*out = *in
+	if in.Operations != nil {
+		out.Operations = make([]string, len(in.Operations))
+		copy(out.Operations, in.Operations)
+	}
+}
+
+func (in *ACLRuleSpec) DeepCopy() *ACLRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ACLRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleACLSpec) DeepCopyInto(out *KafscaleACLSpec) {
+	*out = *in
+	if in.Rules != nil {
+		out.Rules = make([]ACLRuleSpec, len(in.Rules))
+		for i := range in.Rules {
+			in.Rules[i].DeepCopyInto(&out.Rules[i])
+		}
+	}
+}
+
+func (in *KafscaleACLSpec) DeepCopy() *KafscaleACLSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleACLSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleACLStatus) DeepCopyInto(out *KafscaleACLStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(in.Conditions))
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
+
+func (in *KafscaleACLStatus) DeepCopy() *KafscaleACLStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleACLStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleACL) DeepCopyInto(out *KafscaleACL) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+func (in *KafscaleACL) DeepCopy() *KafscaleACL {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleACL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleACL) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func (in *KafscaleACLList) DeepCopyInto(out *KafscaleACLList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]KafscaleACL, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+func (in *KafscaleACLList) DeepCopy() *KafscaleACLList {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleACLList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleACLList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
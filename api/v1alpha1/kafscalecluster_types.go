@@ -27,6 +27,12 @@ type KafscaleClusterSpec struct {
 	S3      S3Spec            `json:"s3"`
 	Etcd    EtcdSpec          `json:"etcd"`
 	Config  ClusterConfigSpec `json:"config,omitempty"`
+	// Autoscaling scales brokers on throughput instead of CPU and memory.
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+	// Proxy runs the Kafka-aware proxy in front of the brokers.
+	Proxy *ProxySpec `json:"proxy,omitempty"`
+	// Console runs the web console against the cluster metadata.
+	Console *ConsoleSpec `json:"console,omitempty"`
 }
 
 type BrokerSpec struct {
@@ -35,6 +41,27 @@ type BrokerSpec struct {
 	AdvertisedHost string            `json:"advertisedHost,omitempty"`
 	AdvertisedPort *int32            `json:"advertisedPort,omitempty"`
 	Service        BrokerServiceSpec `json:"service,omitempty"`
+	// DrainTimeoutSeconds bounds how long a terminating broker pod waits for
+	// its partitions to be drained before it is released. Defaults to 60.
+	DrainTimeoutSeconds *int32 `json:"drainTimeoutSeconds,omitempty"`
+}
+
+// AutoscalingSpec configures the broker HPA. Targets are per-broker averages
+// of the broker Prometheus metrics, read through the custom metrics API.
+type AutoscalingSpec struct {
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+	// TargetProduceBytesPerSecond targets kafscale_produce_bytes_per_second.
+	TargetProduceBytesPerSecond *int64 `json:"targetProduceBytesPerSecond,omitempty"`
+	// TargetFetchRecordsPerSecond targets kafscale_fetch_rps.
+	TargetFetchRecordsPerSecond *int64 `json:"targetFetchRecordsPerSecond,omitempty"`
+	// TargetConnections targets kafscale_active_connections.
+	TargetConnections *int32 `json:"targetConnections,omitempty"`
+	// ConsumerLagThreshold targets kafscale_consumer_lag_max.
+	ConsumerLagThreshold *int64 `json:"consumerLagThreshold,omitempty"`
+	// ScaleDownStabilizationSeconds is how long load must stay low before
+	// brokers are removed. Defaults to 300.
+	ScaleDownStabilizationSeconds *int32 `json:"scaleDownStabilizationSeconds,omitempty"`
 }
 
 type BrokerResources struct {
@@ -52,6 +79,43 @@ type BrokerServiceSpec struct {
 	MetricsNodePort          *int32            `json:"metricsNodePort,omitempty"`
 }
 
+// ProxySpec configures the operator-managed Kafka proxy. Its Service is only
+// created once every broker is ready.
+type ProxySpec struct {
+	Replicas *int32 `json:"replicas,omitempty"`
+	Image    string `json:"image,omitempty"`
+	// AdvertisedHost and AdvertisedPort are what clients are told to connect
+	// to. The host defaults to the proxy Service DNS name; the port, 9092 by
+	// default, is also the port the proxy Service listens on.
+	AdvertisedHost string               `json:"advertisedHost,omitempty"`
+	AdvertisedPort *int32               `json:"advertisedPort,omitempty"`
+	Service        ComponentServiceSpec `json:"service,omitempty"`
+	Resources      BrokerResources      `json:"resources,omitempty"`
+	// AuthSecretRef names a Secret with KAFSCALE_PROXY_ETCD_USERNAME and
+	// KAFSCALE_PROXY_ETCD_PASSWORD keys.
+	AuthSecretRef string `json:"authSecretRef,omitempty"`
+}
+
+// ConsoleSpec configures the operator-managed web console.
+type ConsoleSpec struct {
+	Replicas  *int32               `json:"replicas,omitempty"`
+	Image     string               `json:"image,omitempty"`
+	Service   ComponentServiceSpec `json:"service,omitempty"`
+	Resources BrokerResources      `json:"resources,omitempty"`
+	// AuthSecretRef names a Secret with KAFSCALE_UI_USERNAME and
+	// KAFSCALE_UI_PASSWORD keys. The console refuses logins without them.
+	AuthSecretRef string `json:"authSecretRef,omitempty"`
+}
+
+// ComponentServiceSpec exposes a single-port proxy or console Service.
+type ComponentServiceSpec struct {
+	Type                     string            `json:"type,omitempty"`
+	Annotations              map[string]string `json:"annotations,omitempty"`
+	LoadBalancerIP           string            `json:"loadBalancerIP,omitempty"`
+	LoadBalancerSourceRanges []string          `json:"loadBalancerSourceRanges,omitempty"`
+	NodePort                 *int32            `json:"nodePort,omitempty"`
+}
+
 type S3Spec struct {
 	Bucket               string `json:"bucket"`
 	Region               string `json:"region"`
@@ -65,6 +129,9 @@ type S3Spec struct {
 
 type EtcdSpec struct {
 	Endpoints []string `json:"endpoints"`
+	// RestoreFrom bootstraps the operator-managed etcd from a snapshot in the
+	// snapshot bucket: a snapshot key, or "latest" for the newest one.
+	RestoreFrom string `json:"restoreFrom,omitempty"`
 }
 
 type ClusterConfigSpec struct {
@@ -75,8 +142,36 @@ type ClusterConfigSpec struct {
 
 // KafscaleClusterStatus captures observed state.
 type KafscaleClusterStatus struct {
-	Phase      string             `json:"phase,omitempty"`
-	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Phase       string             `json:"phase,omitempty"`
+	Conditions  []metav1.Condition `json:"conditions,omitempty"`
+	EtcdRestore *EtcdRestoreStatus `json:"etcdRestore,omitempty"`
+	// BrokerDrains holds the last drain of each broker pod, keyed by pod name.
+	BrokerDrains []BrokerDrainStatus `json:"brokerDrains,omitempty"`
+}
+
+// BrokerDrainStatus reports the drain of one terminating broker pod.
+type BrokerDrainStatus struct {
+	Pod string `json:"pod"`
+	// Reason is Draining, Drained, DrainTimedOut or DrainSkipped.
+	Reason             string      `json:"reason"`
+	Message            string      `json:"message,omitempty"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// EtcdRestoreStatus tracks the last spec.etcd.restoreFrom request.
+type EtcdRestoreStatus struct {
+	// RestoreFrom is the spec value this restore was started for.
+	RestoreFrom string `json:"restoreFrom"`
+	// Snapshot is the resolved snapshot object key.
+	Snapshot    string       `json:"snapshot,omitempty"`
+	Phase       string       `json:"phase,omitempty"`
+	Message     string       `json:"message,omitempty"`
+	StartedAt   *metav1.Time `json:"startedAt,omitempty"`
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+	// PartitionsChecked and OffsetsRepaired summarize the post-restore
+	// comparison of etcd next offsets against the segments in S3.
+	PartitionsChecked int32 `json:"partitionsChecked,omitempty"`
+	OffsetsRepaired   int32 `json:"offsetsRepaired,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -139,6 +234,10 @@ func (in *BrokerSpec) DeepCopyInto(out *BrokerSpec) {
 		out.AdvertisedPort = new(int32)
 		*out.AdvertisedPort = *in.AdvertisedPort
 	}
+	if in.DrainTimeoutSeconds != nil {
+		out.DrainTimeoutSeconds = new(int32)
+		*out.DrainTimeoutSeconds = *in.DrainTimeoutSeconds
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
 	in.Service.DeepCopyInto(&out.Service)
 }
@@ -152,6 +251,47 @@ func (in *BrokerSpec) DeepCopy() *BrokerSpec {
 	return out
 }
 
+func (in *AutoscalingSpec) DeepCopyInto(out *AutoscalingSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		out.MinReplicas = new(int32)
+		*out.MinReplicas = *in.MinReplicas
+	}
+	if in.MaxReplicas != nil {
+		out.MaxReplicas = new(int32)
+		*out.MaxReplicas = *in.MaxReplicas
+	}
+	if in.TargetProduceBytesPerSecond != nil {
+		out.TargetProduceBytesPerSecond = new(int64)
+		*out.TargetProduceBytesPerSecond = *in.TargetProduceBytesPerSecond
+	}
+	if in.TargetFetchRecordsPerSecond != nil {
+		out.TargetFetchRecordsPerSecond = new(int64)
+		*out.TargetFetchRecordsPerSecond = *in.TargetFetchRecordsPerSecond
+	}
+	if in.TargetConnections != nil {
+		out.TargetConnections = new(int32)
+		*out.TargetConnections = *in.TargetConnections
+	}
+	if in.ConsumerLagThreshold != nil {
+		out.ConsumerLagThreshold = new(int64)
+		*out.ConsumerLagThreshold = *in.ConsumerLagThreshold
+	}
+	if in.ScaleDownStabilizationSeconds != nil {
+		out.ScaleDownStabilizationSeconds = new(int32)
+		*out.ScaleDownStabilizationSeconds = *in.ScaleDownStabilizationSeconds
+	}
+}
+
+func (in *AutoscalingSpec) DeepCopy() *AutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 func (in *ClusterConfigSpec) DeepCopyInto(out *ClusterConfigSpec) {
 	*out = *in
 }
@@ -187,6 +327,75 @@ func (in *BrokerServiceSpec) DeepCopy() *BrokerServiceSpec {
 	return out
 }
 
+func (in *ComponentServiceSpec) DeepCopyInto(out *ComponentServiceSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		out.Annotations = make(map[string]string, len(in.Annotations))
+		for key, val := range in.Annotations {
+			out.Annotations[key] = val
+		}
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		out.LoadBalancerSourceRanges = make([]string, len(in.LoadBalancerSourceRanges))
+		copy(out.LoadBalancerSourceRanges, in.LoadBalancerSourceRanges)
+	}
+	if in.NodePort != nil {
+		out.NodePort = new(int32)
+		*out.NodePort = *in.NodePort
+	}
+}
+
+func (in *ComponentServiceSpec) DeepCopy() *ComponentServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *ProxySpec) DeepCopyInto(out *ProxySpec) {
+	*out = *in
+	if in.Replicas != nil {
+		out.Replicas = new(int32)
+		*out.Replicas = *in.Replicas
+	}
+	if in.AdvertisedPort != nil {
+		out.AdvertisedPort = new(int32)
+		*out.AdvertisedPort = *in.AdvertisedPort
+	}
+	in.Service.DeepCopyInto(&out.Service)
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+func (in *ProxySpec) DeepCopy() *ProxySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *ConsoleSpec) DeepCopyInto(out *ConsoleSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		out.Replicas = new(int32)
+		*out.Replicas = *in.Replicas
+	}
+	in.Service.DeepCopyInto(&out.Service)
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+func (in *ConsoleSpec) DeepCopy() *ConsoleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 func (in *ClusterConfigSpec) DeepCopy() *ClusterConfigSpec {
 	if in == nil {
 		return nil
@@ -202,6 +411,15 @@ func (in *KafscaleClusterSpec) DeepCopyInto(out *KafscaleClusterSpec) {
 	out.S3 = in.S3
 	out.Etcd = in.Etcd
 	out.Config = in.Config
+	if in.Autoscaling != nil {
+		out.Autoscaling = in.Autoscaling.DeepCopy()
+	}
+	if in.Proxy != nil {
+		out.Proxy = in.Proxy.DeepCopy()
+	}
+	if in.Console != nil {
+		out.Console = in.Console.DeepCopy()
+	}
 }
 
 func (in *KafscaleClusterSpec) DeepCopy() *KafscaleClusterSpec {
@@ -221,6 +439,39 @@ func (in *KafscaleClusterStatus) DeepCopyInto(out *KafscaleClusterStatus) {
 			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
 		}
 	}
+	if in.EtcdRestore != nil {
+		out.EtcdRestore = in.EtcdRestore.DeepCopy()
+	}
+	if in.BrokerDrains != nil {
+		out.BrokerDrains = make([]BrokerDrainStatus, len(in.BrokerDrains))
+		for i := range in.BrokerDrains {
+			in.BrokerDrains[i].DeepCopyInto(&out.BrokerDrains[i])
+		}
+	}
+}
+
+func (in *BrokerDrainStatus) DeepCopyInto(out *BrokerDrainStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+func (in *EtcdRestoreStatus) DeepCopyInto(out *EtcdRestoreStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		out.StartedAt = in.StartedAt.DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		out.CompletedAt = in.CompletedAt.DeepCopy()
+	}
+}
+
+func (in *EtcdRestoreStatus) DeepCopy() *EtcdRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 func (in *KafscaleClusterStatus) DeepCopy() *KafscaleClusterStatus {
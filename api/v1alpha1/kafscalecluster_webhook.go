@@ -0,0 +1,305 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// Defaults applied by the cluster webhook. They match what the operator
+// assumes when the fields are left empty.
+const (
+	DefaultBrokerReplicas                = 3
+	DefaultBrokerPort                    = 9092
+	DefaultDrainTimeoutSeconds           = 60
+	DefaultScaleDownStabilizationSeconds = 300
+	DefaultProxyReplicas                 = 2
+	DefaultConsoleReplicas               = 1
+)
+
+//+kubebuilder:webhook:path=/mutate-kafscale-io-v1alpha1-kafscalecluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=kafscale.io,resources=kafscaleclusters,verbs=create;update,versions=v1alpha1,name=mkafscalecluster.kafscale.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-kafscale-io-v1alpha1-kafscalecluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=kafscale.io,resources=kafscaleclusters,verbs=create;update,versions=v1alpha1,name=vkafscalecluster.kafscale.io,admissionReviewVersions=v1
+
+// SetupKafscaleClusterWebhookWithManager registers the defaulting and
+// validating webhooks for KafscaleCluster.
+func SetupKafscaleClusterWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &KafscaleCluster{}).
+		WithDefaulter(KafscaleClusterWebhook{}).
+		WithValidator(KafscaleClusterWebhook{}).
+		Complete()
+}
+
+// KafscaleClusterWebhook defaults and validates KafscaleCluster objects.
+type KafscaleClusterWebhook struct{}
+
+var (
+	_ admission.Defaulter[*KafscaleCluster] = KafscaleClusterWebhook{}
+	_ admission.Validator[*KafscaleCluster] = KafscaleClusterWebhook{}
+)
+
+// Default fills in the fields the operator would otherwise default at
+// reconcile time, so the stored spec shows what is actually deployed.
+func (KafscaleClusterWebhook) Default(_ context.Context, cluster *KafscaleCluster) error {
+	spec := &cluster.Spec
+	if spec.Brokers.Replicas == nil {
+		spec.Brokers.Replicas = int32Ptr(DefaultBrokerReplicas)
+	}
+	if spec.Brokers.AdvertisedPort == nil {
+		spec.Brokers.AdvertisedPort = int32Ptr(DefaultBrokerPort)
+	}
+	if spec.Brokers.DrainTimeoutSeconds == nil {
+		spec.Brokers.DrainTimeoutSeconds = int32Ptr(DefaultDrainTimeoutSeconds)
+	}
+	if spec.Brokers.Service.Type == "" {
+		spec.Brokers.Service.Type = string(corev1.ServiceTypeClusterIP)
+	}
+	spec.S3.Bucket = strings.TrimSpace(spec.S3.Bucket)
+	spec.S3.Region = strings.TrimSpace(spec.S3.Region)
+	if as := spec.Autoscaling; as != nil && as.ScaleDownStabilizationSeconds == nil {
+		as.ScaleDownStabilizationSeconds = int32Ptr(DefaultScaleDownStabilizationSeconds)
+	}
+	if proxy := spec.Proxy; proxy != nil {
+		if proxy.Replicas == nil {
+			proxy.Replicas = int32Ptr(DefaultProxyReplicas)
+		}
+		if proxy.AdvertisedPort == nil {
+			proxy.AdvertisedPort = int32Ptr(DefaultBrokerPort)
+		}
+		if proxy.Service.Type == "" {
+			proxy.Service.Type = string(corev1.ServiceTypeClusterIP)
+		}
+	}
+	if console := spec.Console; console != nil {
+		if console.Replicas == nil {
+			console.Replicas = int32Ptr(DefaultConsoleReplicas)
+		}
+		if console.Service.Type == "" {
+			console.Service.Type = string(corev1.ServiceTypeClusterIP)
+		}
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.Validator.
+func (KafscaleClusterWebhook) ValidateCreate(_ context.Context, cluster *KafscaleCluster) (admission.Warnings, error) {
+	return nil, clusterInvalid(cluster, validateKafscaleClusterSpec(&cluster.Spec))
+}
+
+// ValidateUpdate implements admission.Validator. Moving a cluster to another
+// bucket is allowed but leaves existing segments behind, so it only warns.
+func (KafscaleClusterWebhook) ValidateUpdate(_ context.Context, oldCluster, cluster *KafscaleCluster) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	if oldCluster.Spec.S3.Bucket != cluster.Spec.S3.Bucket {
+		warnings = append(warnings, fmt.Sprintf("spec.s3.bucket changed from %q to %q; existing segments are not moved", oldCluster.Spec.S3.Bucket, cluster.Spec.S3.Bucket))
+	}
+	return warnings, clusterInvalid(cluster, validateKafscaleClusterSpec(&cluster.Spec))
+}
+
+// ValidateDelete implements admission.Validator.
+func (KafscaleClusterWebhook) ValidateDelete(context.Context, *KafscaleCluster) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateKafscaleClusterSpec(spec *KafscaleClusterSpec) field.ErrorList {
+	var errs field.ErrorList
+	root := field.NewPath("spec")
+
+	brokers := root.Child("brokers")
+	if r := spec.Brokers.Replicas; r != nil && *r < 0 {
+		errs = append(errs, field.Invalid(brokers.Child("replicas"), *r, "must not be negative"))
+	}
+	if p := spec.Brokers.AdvertisedPort; p != nil && (*p < 1 || *p > 65535) {
+		errs = append(errs, field.Invalid(brokers.Child("advertisedPort"), *p, "must be between 1 and 65535"))
+	}
+	if d := spec.Brokers.DrainTimeoutSeconds; d != nil && *d < 0 {
+		errs = append(errs, field.Invalid(brokers.Child("drainTimeoutSeconds"), *d, "must not be negative"))
+	}
+	errs = append(errs, validateBrokerResources(&spec.Brokers.Resources, brokers.Child("resources"))...)
+	svc := brokers.Child("service")
+	errs = append(errs, validateServiceType(spec.Brokers.Service.Type, svc.Child("type"))...)
+	switch corev1.ServiceExternalTrafficPolicy(spec.Brokers.Service.ExternalTrafficPolicy) {
+	case "", corev1.ServiceExternalTrafficPolicyCluster, corev1.ServiceExternalTrafficPolicyLocal:
+	default:
+		errs = append(errs, field.NotSupported(svc.Child("externalTrafficPolicy"), spec.Brokers.Service.ExternalTrafficPolicy,
+			[]string{string(corev1.ServiceExternalTrafficPolicyCluster), string(corev1.ServiceExternalTrafficPolicyLocal)}))
+	}
+	if p := spec.Brokers.Service.KafkaNodePort; p != nil && (*p < 1 || *p > 65535) {
+		errs = append(errs, field.Invalid(svc.Child("kafkaNodePort"), *p, "must be between 1 and 65535"))
+	}
+	if p := spec.Brokers.Service.MetricsNodePort; p != nil && (*p < 1 || *p > 65535) {
+		errs = append(errs, field.Invalid(svc.Child("metricsNodePort"), *p, "must be between 1 and 65535"))
+	}
+
+	s3 := root.Child("s3")
+	if strings.TrimSpace(spec.S3.Bucket) == "" {
+		errs = append(errs, field.Required(s3.Child("bucket"), "an S3 bucket is required for segment storage"))
+	}
+	if strings.TrimSpace(spec.S3.Region) == "" {
+		errs = append(errs, field.Required(s3.Child("region"), "an S3 region is required"))
+	}
+	if msg := validateURL(spec.S3.Endpoint); msg != "" {
+		errs = append(errs, field.Invalid(s3.Child("endpoint"), spec.S3.Endpoint, msg))
+	}
+	if msg := validateURL(spec.S3.ReadEndpoint); msg != "" {
+		errs = append(errs, field.Invalid(s3.Child("readEndpoint"), spec.S3.ReadEndpoint, msg))
+	}
+
+	etcd := root.Child("etcd")
+	for i, endpoint := range spec.Etcd.Endpoints {
+		if strings.TrimSpace(endpoint) == "" {
+			errs = append(errs, field.Invalid(etcd.Child("endpoints").Index(i), endpoint, "must not be empty"))
+		}
+	}
+
+	config := root.Child("config")
+	if spec.Config.SegmentBytes < 0 {
+		errs = append(errs, field.Invalid(config.Child("segmentBytes"), spec.Config.SegmentBytes, "must not be negative"))
+	}
+	if spec.Config.FlushIntervalMs < 0 {
+		errs = append(errs, field.Invalid(config.Child("flushIntervalMs"), spec.Config.FlushIntervalMs, "must not be negative"))
+	}
+	if raw := spec.Config.CacheSize; raw != "" {
+		if q, err := resource.ParseQuantity(raw); err != nil {
+			errs = append(errs, field.Invalid(config.Child("cacheSize"), raw, "must be a byte quantity such as 512Mi or 2Gi"))
+		} else if q.Sign() <= 0 {
+			errs = append(errs, field.Invalid(config.Child("cacheSize"), raw, "must be positive"))
+		}
+	}
+
+	if as := spec.Autoscaling; as != nil {
+		errs = append(errs, validateAutoscaling(as, root.Child("autoscaling"))...)
+	}
+
+	if proxy := spec.Proxy; proxy != nil {
+		path := root.Child("proxy")
+		if r := proxy.Replicas; r != nil && *r < 0 {
+			errs = append(errs, field.Invalid(path.Child("replicas"), *r, "must not be negative"))
+		}
+		if p := proxy.AdvertisedPort; p != nil && (*p < 1 || *p > 65535) {
+			errs = append(errs, field.Invalid(path.Child("advertisedPort"), *p, "must be between 1 and 65535"))
+		}
+		errs = append(errs, validateBrokerResources(&proxy.Resources, path.Child("resources"))...)
+		errs = append(errs, validateComponentService(&proxy.Service, path.Child("service"))...)
+	}
+	if console := spec.Console; console != nil {
+		path := root.Child("console")
+		if r := console.Replicas; r != nil && *r < 0 {
+			errs = append(errs, field.Invalid(path.Child("replicas"), *r, "must not be negative"))
+		}
+		errs = append(errs, validateBrokerResources(&console.Resources, path.Child("resources"))...)
+		errs = append(errs, validateComponentService(&console.Service, path.Child("service"))...)
+	}
+	return errs
+}
+
+func validateServiceType(serviceType string, path *field.Path) field.ErrorList {
+	switch corev1.ServiceType(serviceType) {
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(path, serviceType,
+		[]string{string(corev1.ServiceTypeClusterIP), string(corev1.ServiceTypeNodePort), string(corev1.ServiceTypeLoadBalancer)})}
+}
+
+func validateComponentService(svc *ComponentServiceSpec, path *field.Path) field.ErrorList {
+	errs := validateServiceType(svc.Type, path.Child("type"))
+	if p := svc.NodePort; p != nil && (*p < 1 || *p > 65535) {
+		errs = append(errs, field.Invalid(path.Child("nodePort"), *p, "must be between 1 and 65535"))
+	}
+	return errs
+}
+
+func validateBrokerResources(res *BrokerResources, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	names := make([]string, 0, len(res.Limits))
+	for name := range res.Limits {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, key := range names {
+		name := corev1.ResourceName(key)
+		limit := res.Limits[name]
+		if request, ok := res.Requests[name]; ok && request.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(path.Child("requests").Key(string(name)), request.String(),
+				fmt.Sprintf("must not exceed the limit %s", limit.String())))
+		}
+	}
+	return errs
+}
+
+func validateAutoscaling(as *AutoscalingSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if as.MinReplicas != nil && *as.MinReplicas < 1 {
+		errs = append(errs, field.Invalid(path.Child("minReplicas"), *as.MinReplicas, "must be at least 1"))
+	}
+	if as.MaxReplicas != nil && *as.MaxReplicas < 1 {
+		errs = append(errs, field.Invalid(path.Child("maxReplicas"), *as.MaxReplicas, "must be at least 1"))
+	}
+	if as.MinReplicas != nil && as.MaxReplicas != nil && *as.MaxReplicas < *as.MinReplicas {
+		errs = append(errs, field.Invalid(path.Child("maxReplicas"), *as.MaxReplicas, "must not be less than minReplicas"))
+	}
+	if t := as.TargetProduceBytesPerSecond; t != nil && *t < 1 {
+		errs = append(errs, field.Invalid(path.Child("targetProduceBytesPerSecond"), *t, "must be at least 1"))
+	}
+	if t := as.TargetFetchRecordsPerSecond; t != nil && *t < 1 {
+		errs = append(errs, field.Invalid(path.Child("targetFetchRecordsPerSecond"), *t, "must be at least 1"))
+	}
+	if t := as.TargetConnections; t != nil && *t < 1 {
+		errs = append(errs, field.Invalid(path.Child("targetConnections"), *t, "must be at least 1"))
+	}
+	if t := as.ConsumerLagThreshold; t != nil && *t < 1 {
+		errs = append(errs, field.Invalid(path.Child("consumerLagThreshold"), *t, "must be at least 1"))
+	}
+	if s := as.ScaleDownStabilizationSeconds; s != nil && (*s < 0 || *s > 3600) {
+		errs = append(errs, field.Invalid(path.Child("scaleDownStabilizationSeconds"), *s, "must be between 0 and 3600"))
+	}
+	return errs
+}
+
+// validateURL returns a message when raw is set but is not an absolute
+// http(s) URL.
+func validateURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "must be an absolute http:// or https:// URL"
+	}
+	return ""
+}
+
+func clusterInvalid(cluster *KafscaleCluster, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("KafscaleCluster").GroupKind(), cluster.Name, errs)
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
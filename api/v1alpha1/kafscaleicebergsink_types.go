@@ -0,0 +1,355 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// KafscaleIcebergSinkSpec runs the Iceberg processor against a cluster. S3
+// and etcd settings come from the referenced KafscaleCluster; the remaining
+// fields mirror the processor's config file.
+type KafscaleIcebergSinkSpec struct {
+	ClusterRef             string `json:"clusterRef"`
+	ProcessorWorkloadSpec  `json:",inline"`
+	IcebergProcessorConfig `json:",inline"`
+}
+
+// IcebergProcessorConfig mirrors the iceberg-processor config.Config.
+type IcebergProcessorConfig struct {
+	Sink        IcebergSinkTargetConfig  `json:"sink,omitempty"`
+	Iceberg     IcebergConfig            `json:"iceberg,omitempty"`
+	Discovery   IcebergDiscoveryConfig   `json:"discovery,omitempty"`
+	Schema      IcebergSchemaConfig      `json:"schema,omitempty"`
+	Mappings    []IcebergMapping         `json:"mappings"`
+	Offsets     IcebergOffsetsConfig     `json:"offsets,omitempty"`
+	Processor   IcebergProcessorTuning   `json:"processor,omitempty"`
+	DLQ         IcebergDLQConfig         `json:"dlq,omitempty"`
+	Maintenance IcebergMaintenanceConfig `json:"maintenance,omitempty"`
+}
+
+// IcebergSinkTargetConfig selects the table format: iceberg (default) or delta.
+type IcebergSinkTargetConfig struct {
+	Type  string             `json:"type,omitempty"`
+	Delta IcebergDeltaConfig `json:"delta,omitempty"`
+}
+
+// IcebergDeltaConfig stores each table at <warehouse>/<table>.
+type IcebergDeltaConfig struct {
+	Warehouse string `json:"warehouse,omitempty"`
+}
+
+type IcebergConfig struct {
+	Catalog   IcebergCatalogConfig `json:"catalog,omitempty"`
+	Warehouse string               `json:"warehouse,omitempty"`
+}
+
+// IcebergCatalogConfig selects the catalog: rest, sql, glue or hadoop.
+type IcebergCatalogConfig struct {
+	Type string `json:"type,omitempty"`
+	URI  string `json:"uri,omitempty"`
+	// CredentialsSecretRef names a Secret whose token, username and password
+	// keys authenticate against the catalog.
+	CredentialsSecretRef string                   `json:"credentialsSecretRef,omitempty"`
+	SQL                  IcebergSQLCatalogConfig  `json:"sql,omitempty"`
+	Glue                 IcebergGlueCatalogConfig `json:"glue,omitempty"`
+}
+
+type IcebergSQLCatalogConfig struct {
+	Dialect string `json:"dialect,omitempty"`
+}
+
+type IcebergGlueCatalogConfig struct {
+	Region    string `json:"region,omitempty"`
+	CatalogID string `json:"catalogID,omitempty"`
+}
+
+type IcebergDiscoveryConfig struct {
+	Mode string `json:"mode,omitempty"`
+}
+
+type IcebergSchemaConfig struct {
+	Mode     string                `json:"mode,omitempty"`
+	Registry IcebergRegistryConfig `json:"registry,omitempty"`
+}
+
+// IcebergRegistryConfig points at a static (default) or confluent registry.
+type IcebergRegistryConfig struct {
+	Type           string `json:"type,omitempty"`
+	BaseURL        string `json:"baseURL,omitempty"`
+	TimeoutSeconds int32  `json:"timeoutSeconds,omitempty"`
+	CacheSeconds   int32  `json:"cacheSeconds,omitempty"`
+}
+
+// IcebergMapping routes a topic into a table.
+type IcebergMapping struct {
+	Topic                string               `json:"topic"`
+	Table                string               `json:"table"`
+	Mode                 string               `json:"mode,omitempty"`
+	CreateTableIfMissing bool                 `json:"createTableIfMissing,omitempty"`
+	Schema               IcebergMappingSchema `json:"schema,omitempty"`
+	KeyColumns           []string             `json:"keyColumns,omitempty"`
+	Envelope             string               `json:"envelope,omitempty"`
+	PartitionSpec        []IcebergPartition   `json:"partitionSpec,omitempty"`
+	SortOrder            []IcebergSortField   `json:"sortOrder,omitempty"`
+	Write                IcebergWriteConfig   `json:"write,omitempty"`
+}
+
+type IcebergMappingSchema struct {
+	Source            string          `json:"source,omitempty"`
+	Columns           []IcebergColumn `json:"columns,omitempty"`
+	Subject           string          `json:"subject,omitempty"`
+	Message           string          `json:"message,omitempty"`
+	AllowTypeWidening bool            `json:"allowTypeWidening,omitempty"`
+}
+
+// IcebergColumn is a table column; struct columns list Fields, list and map
+// columns describe their items in Element.
+type IcebergColumn struct {
+	Name     string          `json:"name,omitempty"`
+	Type     string          `json:"type"`
+	Required bool            `json:"required,omitempty"`
+	Fields   []IcebergColumn `json:"fields,omitempty"`
+	Element  *IcebergColumn  `json:"element,omitempty"`
+}
+
+type IcebergPartition struct {
+	Column    string `json:"column"`
+	Transform string `json:"transform"`
+}
+
+type IcebergSortField struct {
+	Column    string `json:"column"`
+	Direction string `json:"direction,omitempty"`
+	NullOrder string `json:"nullOrder,omitempty"`
+}
+
+type IcebergWriteConfig struct {
+	TargetFileSizeBytes int64 `json:"targetFileSizeBytes,omitempty"`
+	RowGroupSizeBytes   int64 `json:"rowGroupSizeBytes,omitempty"`
+	RowGroupLimit       int64 `json:"rowGroupLimit,omitempty"`
+}
+
+// IcebergOffsetsConfig tunes the etcd checkpoint store. KeyPrefix defaults
+// to processors/<sink name> so sinks sharing a cluster do not collide.
+type IcebergOffsetsConfig struct {
+	LeaseTTLSeconds int32  `json:"leaseTTLSeconds,omitempty"`
+	KeyPrefix       string `json:"keyPrefix,omitempty"`
+}
+
+type IcebergProcessorTuning struct {
+	PollIntervalSeconds  int32 `json:"pollIntervalSeconds,omitempty"`
+	MaxLeases            int32 `json:"maxLeases,omitempty"`
+	FlushIntervalSeconds int32 `json:"flushIntervalSeconds,omitempty"`
+}
+
+type IcebergDLQConfig struct {
+	Type  string                `json:"type,omitempty"`
+	Kafka IcebergDLQKafkaConfig `json:"kafka,omitempty"`
+	S3    IcebergDLQS3Config    `json:"s3,omitempty"`
+	Retry IcebergRetryConfig    `json:"retry,omitempty"`
+}
+
+type IcebergDLQKafkaConfig struct {
+	Brokers []string `json:"brokers,omitempty"`
+	Topic   string   `json:"topic,omitempty"`
+}
+
+type IcebergDLQS3Config struct {
+	Bucket string `json:"bucket,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// IcebergRetryConfig bounds sink write retries before records are dead-lettered.
+type IcebergRetryConfig struct {
+	MaxAttempts      int32 `json:"maxAttempts,omitempty"`
+	InitialBackoffMs int32 `json:"initialBackoffMs,omitempty"`
+	MaxBackoffMs     int32 `json:"maxBackoffMs,omitempty"`
+}
+
+type IcebergMaintenanceConfig struct {
+	Enabled                bool                    `json:"enabled,omitempty"`
+	IntervalSeconds        int32                   `json:"intervalSeconds,omitempty"`
+	Compaction             IcebergCompactionConfig `json:"compaction,omitempty"`
+	SnapshotRetentionHours int32                   `json:"snapshotRetentionHours,omitempty"`
+	RetainLastSnapshots    int32                   `json:"retainLastSnapshots,omitempty"`
+	OrphanOlderThanHours   int32                   `json:"orphanOlderThanHours,omitempty"`
+}
+
+type IcebergCompactionConfig struct {
+	MinInputFiles  int32 `json:"minInputFiles,omitempty"`
+	SmallFileBytes int64 `json:"smallFileBytes,omitempty"`
+}
+
+// KafscaleIcebergSinkStatus surfaces the workers and how far behind they are.
+type KafscaleIcebergSinkStatus struct {
+	Phase         string                 `json:"phase,omitempty"`
+	Conditions    []metav1.Condition     `json:"conditions,omitempty"`
+	Replicas      int32                  `json:"replicas,omitempty"`
+	ReadyReplicas int32                  `json:"readyReplicas,omitempty"`
+	TotalLag      int64                  `json:"totalLag,omitempty"`
+	Topics        []ProcessorTopicStatus `json:"topics,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// KafscaleIcebergSink declares an operator-managed Iceberg/Delta processor.
+type KafscaleIcebergSink struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KafscaleIcebergSinkSpec   `json:"spec,omitempty"`
+	Status KafscaleIcebergSinkStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KafscaleIcebergSinkList contains multiple Iceberg sinks.
+type KafscaleIcebergSinkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KafscaleIcebergSink `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KafscaleIcebergSink{}, &KafscaleIcebergSinkList{})
+}
+
+func (in *IcebergColumn) DeepCopyInto(out *IcebergColumn) {
+	*out = *in
+	if in.Fields != nil {
+		out.Fields = make([]IcebergColumn, len(in.Fields))
+		for i := range in.Fields {
+			in.Fields[i].DeepCopyInto(&out.Fields[i])
+		}
+	}
+	if in.Element != nil {
+		out.Element = new(IcebergColumn)
+		in.Element.DeepCopyInto(out.Element)
+	}
+}
+
+func (in *IcebergMapping) DeepCopyInto(out *IcebergMapping) {
+	*out = *in
+	if in.Schema.Columns != nil {
+		out.Schema.Columns = make([]IcebergColumn, len(in.Schema.Columns))
+		for i := range in.Schema.Columns {
+			in.Schema.Columns[i].DeepCopyInto(&out.Schema.Columns[i])
+		}
+	}
+	if in.KeyColumns != nil {
+		out.KeyColumns = make([]string, len(in.KeyColumns))
+		copy(out.KeyColumns, in.KeyColumns)
+	}
+	if in.PartitionSpec != nil {
+		out.PartitionSpec = make([]IcebergPartition, len(in.PartitionSpec))
+		copy(out.PartitionSpec, in.PartitionSpec)
+	}
+	if in.SortOrder != nil {
+		out.SortOrder = make([]IcebergSortField, len(in.SortOrder))
+		copy(out.SortOrder, in.SortOrder)
+	}
+}
+
+func (in *IcebergProcessorConfig) DeepCopyInto(out *IcebergProcessorConfig) {
+	*out = *in
+	if in.Mappings != nil {
+		out.Mappings = make([]IcebergMapping, len(in.Mappings))
+		for i := range in.Mappings {
+			in.Mappings[i].DeepCopyInto(&out.Mappings[i])
+		}
+	}
+	if in.DLQ.Kafka.Brokers != nil {
+		out.DLQ.Kafka.Brokers = make([]string, len(in.DLQ.Kafka.Brokers))
+		copy(out.DLQ.Kafka.Brokers, in.DLQ.Kafka.Brokers)
+	}
+}
+
+func (in *KafscaleIcebergSinkSpec) DeepCopyInto(out *KafscaleIcebergSinkSpec) {
+	*out = *in
+	in.ProcessorWorkloadSpec.DeepCopyInto(&out.ProcessorWorkloadSpec)
+	in.IcebergProcessorConfig.DeepCopyInto(&out.IcebergProcessorConfig)
+}
+
+func (in *KafscaleIcebergSinkStatus) DeepCopyInto(out *KafscaleIcebergSinkStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(in.Conditions))
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+	if in.Topics != nil {
+		out.Topics = make([]ProcessorTopicStatus, len(in.Topics))
+		for i := range in.Topics {
+			in.Topics[i].DeepCopyInto(&out.Topics[i])
+		}
+	}
+}
+
+func (in *KafscaleIcebergSink) DeepCopyInto(out *KafscaleIcebergSink) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+func (in *KafscaleIcebergSink) DeepCopy() *KafscaleIcebergSink {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleIcebergSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleIcebergSink) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func (in *KafscaleIcebergSinkList) DeepCopyInto(out *KafscaleIcebergSinkList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]KafscaleIcebergSink, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+func (in *KafscaleIcebergSinkList) DeepCopy() *KafscaleIcebergSinkList {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleIcebergSinkList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleIcebergSinkList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
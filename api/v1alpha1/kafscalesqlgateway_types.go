@@ -0,0 +1,271 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// KafscaleSQLGatewaySpec runs the SQL processor against a cluster. S3 and
+// etcd settings and listen addresses are set by the operator; the remaining
+// fields mirror the processor's config file.
+type KafscaleSQLGatewaySpec struct {
+	ClusterRef            string `json:"clusterRef"`
+	ProcessorWorkloadSpec `json:",inline"`
+	// ServiceType of the Service exposing the Postgres port. Defaults to ClusterIP.
+	ServiceType      string `json:"serviceType,omitempty"`
+	SQLGatewayConfig `json:",inline"`
+}
+
+// SQLGatewayConfig mirrors the sql-processor config.Config.
+type SQLGatewayConfig struct {
+	Server            SQLServerConfig      `json:"server,omitempty"`
+	Metadata          SQLMetadataConfig    `json:"metadata,omitempty"`
+	Query             SQLQueryConfig       `json:"query,omitempty"`
+	DiscoveryCache    SQLCacheConfig       `json:"discoveryCache,omitempty"`
+	DiscoveryManifest SQLManifestConfig    `json:"discoveryManifest,omitempty"`
+	TimeIndex         SQLTimeIndexConfig   `json:"timeIndex,omitempty"`
+	ResultCache       SQLResultCacheConfig `json:"resultCache,omitempty"`
+	HTTP              SQLHTTPConfig        `json:"http,omitempty"`
+	Export            SQLExportConfig      `json:"export,omitempty"`
+}
+
+type SQLServerConfig struct {
+	MaxConnections  int32  `json:"maxConnections,omitempty"`
+	ServerVersion   string `json:"serverVersion,omitempty"`
+	ClientEncoding  string `json:"clientEncoding,omitempty"`
+	FlightBatchRows int32  `json:"flightBatchRows,omitempty"`
+	// EnableFlight serves Arrow Flight SQL on port 8815.
+	EnableFlight bool `json:"enableFlight,omitempty"`
+}
+
+// SQLMetadataConfig adds static topic schemas on top of etcd discovery.
+type SQLMetadataConfig struct {
+	Snapshot SQLSnapshotConfig `json:"snapshot,omitempty"`
+	Topics   []SQLTopicConfig  `json:"topics,omitempty"`
+}
+
+type SQLSnapshotConfig struct {
+	TTLSeconds int32 `json:"ttlSeconds,omitempty"`
+}
+
+type SQLTopicConfig struct {
+	Name       string          `json:"name"`
+	Partitions []int32         `json:"partitions,omitempty"`
+	Schema     SQLSchemaConfig `json:"schema,omitempty"`
+}
+
+type SQLSchemaConfig struct {
+	Columns []SQLSchemaColumn `json:"columns,omitempty"`
+}
+
+type SQLSchemaColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Path string `json:"path,omitempty"`
+}
+
+// SQLQueryConfig bounds what a single query may scan and how many run at once.
+type SQLQueryConfig struct {
+	DefaultLimit        int32  `json:"defaultLimit,omitempty"`
+	RequireTimeBound    bool   `json:"requireTimeBound,omitempty"`
+	MaxUnboundedScan    int32  `json:"maxUnboundedScan,omitempty"`
+	MaxScanBytes        int64  `json:"maxScanBytes,omitempty"`
+	MaxScanSegments     int32  `json:"maxScanSegments,omitempty"`
+	MaxDecodedBytes     int64  `json:"maxDecodedBytes,omitempty"`
+	MaxRows             int32  `json:"maxRows,omitempty"`
+	TimeoutSeconds      int32  `json:"timeoutSeconds,omitempty"`
+	MaxConcurrent       int32  `json:"maxConcurrent,omitempty"`
+	QueueSize           int32  `json:"queueSize,omitempty"`
+	QueueTimeoutSeconds int32  `json:"queueTimeoutSeconds,omitempty"`
+	ScanParallelism     int32  `json:"scanParallelism,omitempty"`
+	JoinMemoryBytes     int64  `json:"joinMemoryBytes,omitempty"`
+	SpillDir            string `json:"spillDir,omitempty"`
+}
+
+type SQLCacheConfig struct {
+	TTLSeconds int32 `json:"ttlSeconds,omitempty"`
+	MaxEntries int32 `json:"maxEntries,omitempty"`
+}
+
+type SQLManifestConfig struct {
+	Enabled              bool   `json:"enabled,omitempty"`
+	Key                  string `json:"key,omitempty"`
+	TTLSeconds           int32  `json:"ttlSeconds,omitempty"`
+	BuildIntervalSeconds int32  `json:"buildIntervalSeconds,omitempty"`
+	BuildMaxSegments     int32  `json:"buildMaxSegments,omitempty"`
+	BuildMaxBytes        int64  `json:"buildMaxBytes,omitempty"`
+	BuildLeaseTTLSeconds int32  `json:"buildLeaseTTLSeconds,omitempty"`
+}
+
+type SQLTimeIndexConfig struct {
+	Enabled              bool   `json:"enabled,omitempty"`
+	KeySuffix            string `json:"keySuffix,omitempty"`
+	BuildMaxSegments     int32  `json:"buildMaxSegments,omitempty"`
+	BuildMaxBytes        int64  `json:"buildMaxBytes,omitempty"`
+	BuildLeaseTTLSeconds int32  `json:"buildLeaseTTLSeconds,omitempty"`
+}
+
+type SQLResultCacheConfig struct {
+	TTLSeconds int32 `json:"ttlSeconds,omitempty"`
+	MaxEntries int32 `json:"maxEntries,omitempty"`
+	MaxRows    int32 `json:"maxRows,omitempty"`
+}
+
+// SQLHTTPConfig tunes the HTTP/JSON query API, served on port 8080 when enabled.
+type SQLHTTPConfig struct {
+	Enabled           bool  `json:"enabled,omitempty"`
+	MaxJobs           int32 `json:"maxJobs,omitempty"`
+	JobTTLSeconds     int32 `json:"jobTTLSeconds,omitempty"`
+	JobTimeoutSeconds int32 `json:"jobTimeoutSeconds,omitempty"`
+	PageSize          int32 `json:"pageSize,omitempty"`
+	MaxPageSize       int32 `json:"maxPageSize,omitempty"`
+}
+
+type SQLExportConfig struct {
+	Enabled        bool     `json:"enabled,omitempty"`
+	AllowedTargets []string `json:"allowedTargets,omitempty"`
+	MaxRowsPerFile int32    `json:"maxRowsPerFile,omitempty"`
+}
+
+// KafscaleSQLGatewayStatus surfaces the gateway workers and endpoint.
+type KafscaleSQLGatewayStatus struct {
+	Phase         string             `json:"phase,omitempty"`
+	Conditions    []metav1.Condition `json:"conditions,omitempty"`
+	Replicas      int32              `json:"replicas,omitempty"`
+	ReadyReplicas int32              `json:"readyReplicas,omitempty"`
+	Endpoint      string             `json:"endpoint,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// KafscaleSQLGateway declares an operator-managed SQL query gateway.
+type KafscaleSQLGateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KafscaleSQLGatewaySpec   `json:"spec,omitempty"`
+	Status KafscaleSQLGatewayStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KafscaleSQLGatewayList contains multiple SQL gateways.
+type KafscaleSQLGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KafscaleSQLGateway `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KafscaleSQLGateway{}, &KafscaleSQLGatewayList{})
+}
+
+func (in *SQLTopicConfig) DeepCopyInto(out *SQLTopicConfig) {
+	*out = *in
+	if in.Partitions != nil {
+		out.Partitions = make([]int32, len(in.Partitions))
+		copy(out.Partitions, in.Partitions)
+	}
+	if in.Schema.Columns != nil {
+		out.Schema.Columns = make([]SQLSchemaColumn, len(in.Schema.Columns))
+		copy(out.Schema.Columns, in.Schema.Columns)
+	}
+}
+
+func (in *SQLGatewayConfig) DeepCopyInto(out *SQLGatewayConfig) {
+	*out = *in
+	if in.Metadata.Topics != nil {
+		out.Metadata.Topics = make([]SQLTopicConfig, len(in.Metadata.Topics))
+		for i := range in.Metadata.Topics {
+			in.Metadata.Topics[i].DeepCopyInto(&out.Metadata.Topics[i])
+		}
+	}
+	if in.Export.AllowedTargets != nil {
+		out.Export.AllowedTargets = make([]string, len(in.Export.AllowedTargets))
+		copy(out.Export.AllowedTargets, in.Export.AllowedTargets)
+	}
+}
+
+func (in *KafscaleSQLGatewaySpec) DeepCopyInto(out *KafscaleSQLGatewaySpec) {
+	*out = *in
+	in.ProcessorWorkloadSpec.DeepCopyInto(&out.ProcessorWorkloadSpec)
+	in.SQLGatewayConfig.DeepCopyInto(&out.SQLGatewayConfig)
+}
+
+func (in *KafscaleSQLGatewayStatus) DeepCopyInto(out *KafscaleSQLGatewayStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(in.Conditions))
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
+
+func (in *KafscaleSQLGateway) DeepCopyInto(out *KafscaleSQLGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+func (in *KafscaleSQLGateway) DeepCopy() *KafscaleSQLGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleSQLGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleSQLGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func (in *KafscaleSQLGatewayList) DeepCopyInto(out *KafscaleSQLGatewayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]KafscaleSQLGateway, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+func (in *KafscaleSQLGatewayList) DeepCopy() *KafscaleSQLGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleSQLGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleSQLGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -28,6 +28,10 @@ type KafscaleTopicSpec struct {
 	RetentionMs    *int64            `json:"retentionMs,omitempty"`
 	RetentionBytes *int64            `json:"retentionBytes,omitempty"`
 	Config         map[string]string `json:"config,omitempty"`
+	// DeletionPolicy controls what happens to the topic when the resource is
+	// deleted: Delete (default) removes its metadata and offsets from etcd,
+	// Purge also removes its segments from S3, Retain leaves both in place.
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
 }
 
 // TopicPartitionStatus describes the observed state of a topic partition.
@@ -0,0 +1,150 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	metadatapb "github.com/KafScale/platform/pkg/gen/metadata"
+	"github.com/KafScale/platform/pkg/metadata"
+)
+
+// Topic deletion policies accepted in spec.deletionPolicy.
+const (
+	TopicDeletionPolicyDelete = "Delete"
+	TopicDeletionPolicyPurge  = "Purge"
+	TopicDeletionPolicyRetain = "Retain"
+)
+
+//+kubebuilder:webhook:path=/mutate-kafscale-io-v1alpha1-kafscaletopic,mutating=true,failurePolicy=fail,sideEffects=None,groups=kafscale.io,resources=kafscaletopics,verbs=create;update,versions=v1alpha1,name=mkafscaletopic.kafscale.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-kafscale-io-v1alpha1-kafscaletopic,mutating=false,failurePolicy=fail,sideEffects=None,groups=kafscale.io,resources=kafscaletopics,verbs=create;update,versions=v1alpha1,name=vkafscaletopic.kafscale.io,admissionReviewVersions=v1
+
+// SetupKafscaleTopicWebhookWithManager registers the defaulting and
+// validating webhooks for KafscaleTopic.
+func SetupKafscaleTopicWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &KafscaleTopic{}).
+		WithDefaulter(KafscaleTopicWebhook{}).
+		WithValidator(KafscaleTopicWebhook{}).
+		Complete()
+}
+
+// KafscaleTopicWebhook defaults and validates KafscaleTopic objects.
+type KafscaleTopicWebhook struct{}
+
+var (
+	_ admission.Defaulter[*KafscaleTopic] = KafscaleTopicWebhook{}
+	_ admission.Validator[*KafscaleTopic] = KafscaleTopicWebhook{}
+)
+
+// Default implements admission.Defaulter.
+func (KafscaleTopicWebhook) Default(_ context.Context, topic *KafscaleTopic) error {
+	if topic.Spec.DeletionPolicy == "" {
+		topic.Spec.DeletionPolicy = TopicDeletionPolicyDelete
+	}
+	return nil
+}
+
+// ValidateCreate implements admission.Validator.
+func (KafscaleTopicWebhook) ValidateCreate(_ context.Context, topic *KafscaleTopic) (admission.Warnings, error) {
+	errs, warnings := validateKafscaleTopicSpec(&topic.Spec)
+	return warnings, topicInvalid(topic, errs)
+}
+
+// ValidateUpdate implements admission.Validator. Partitions can only be
+// added, and a topic cannot move to another cluster.
+func (KafscaleTopicWebhook) ValidateUpdate(_ context.Context, oldTopic, topic *KafscaleTopic) (admission.Warnings, error) {
+	errs, warnings := validateKafscaleTopicSpec(&topic.Spec)
+	spec := field.NewPath("spec")
+	if topic.Spec.Partitions < oldTopic.Spec.Partitions {
+		errs = append(errs, field.Invalid(spec.Child("partitions"), topic.Spec.Partitions,
+			fmt.Sprintf("cannot be decreased from %d; Kafka topics can only gain partitions", oldTopic.Spec.Partitions)))
+	}
+	if topic.Spec.ClusterRef != oldTopic.Spec.ClusterRef {
+		errs = append(errs, field.Forbidden(spec.Child("clusterRef"), "is immutable; create a new topic in the other cluster instead"))
+	}
+	return warnings, topicInvalid(topic, errs)
+}
+
+// ValidateDelete implements admission.Validator.
+func (KafscaleTopicWebhook) ValidateDelete(context.Context, *KafscaleTopic) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateKafscaleTopicSpec(spec *KafscaleTopicSpec) (field.ErrorList, admission.Warnings) {
+	var errs field.ErrorList
+	var warnings admission.Warnings
+	root := field.NewPath("spec")
+	if strings.TrimSpace(spec.ClusterRef) == "" {
+		errs = append(errs, field.Required(root.Child("clusterRef"), "must name the KafscaleCluster that owns the topic"))
+	}
+	if spec.Partitions < 1 {
+		errs = append(errs, field.Invalid(root.Child("partitions"), spec.Partitions, "must be at least 1"))
+	}
+	if r := spec.RetentionMs; r != nil && *r < -1 {
+		errs = append(errs, field.Invalid(root.Child("retentionMs"), *r, "must be -1 (unlimited) or non-negative"))
+	}
+	if r := spec.RetentionBytes; r != nil && *r < -1 {
+		errs = append(errs, field.Invalid(root.Child("retentionBytes"), *r, "must be -1 (unlimited) or non-negative"))
+	}
+	switch spec.DeletionPolicy {
+	case "", TopicDeletionPolicyDelete, TopicDeletionPolicyPurge, TopicDeletionPolicyRetain:
+	default:
+		errs = append(errs, field.NotSupported(root.Child("deletionPolicy"), spec.DeletionPolicy,
+			[]string{TopicDeletionPolicyDelete, TopicDeletionPolicyPurge, TopicDeletionPolicyRetain}))
+	}
+
+	keys := make([]string, 0, len(spec.Config))
+	for key := range spec.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	scratch := &metadatapb.TopicConfig{}
+	for _, key := range keys {
+		value := spec.Config[key]
+		if !slices.Contains(metadata.TopicConfigKeys, key) {
+			errs = append(errs, field.NotSupported(root.Child("config"), key, metadata.TopicConfigKeys))
+			continue
+		}
+		if err := metadata.ApplyTopicConfig(scratch, key, value); err != nil {
+			errs = append(errs, field.Invalid(root.Child("config").Key(key), value, strings.TrimPrefix(err.Error(), metadata.ErrInvalidConfig.Error()+": ")))
+			continue
+		}
+		if key == metadata.ConfigRetentionMs && spec.RetentionMs != nil && value != strconv.FormatInt(*spec.RetentionMs, 10) {
+			warnings = append(warnings, "spec.retentionMs overrides config[retention.ms]")
+		}
+		if key == metadata.ConfigRetentionBytes && spec.RetentionBytes != nil && value != strconv.FormatInt(*spec.RetentionBytes, 10) {
+			warnings = append(warnings, "spec.retentionBytes overrides config[retention.bytes]")
+		}
+	}
+	return errs, warnings
+}
+
+func topicInvalid(topic *KafscaleTopic, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("KafscaleTopic").GroupKind(), topic.Name, errs)
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// KafscaleUserSpec declares a Kafka principal and how it authenticates.
+type KafscaleUserSpec struct {
+	ClusterRef     string                 `json:"clusterRef"`
+	Authentication UserAuthenticationSpec `json:"authentication"`
+}
+
+// UserAuthenticationSpec selects SCRAM credentials or an mTLS subject.
+type UserAuthenticationSpec struct {
+	// Type is scram-sha-256, scram-sha-512 or tls.
+	Type string `json:"type"`
+	// SecretName holds the SCRAM password under the "password" key. The
+	// operator generates it when missing. Defaults to the user name.
+	SecretName string `json:"secretName,omitempty"`
+	// Subject is the client certificate subject DN mapped to this user for tls.
+	Subject string `json:"subject,omitempty"`
+}
+
+// KafscaleUserStatus surfaces observed reconciliation details.
+type KafscaleUserStatus struct {
+	Phase      string             `json:"phase,omitempty"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	Principal  string             `json:"principal,omitempty"`
+	SecretName string             `json:"secretName,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// KafscaleUser declares a user whose credentials the operator publishes to brokers.
+type KafscaleUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KafscaleUserSpec   `json:"spec,omitempty"`
+	Status KafscaleUserStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KafscaleUserList contains multiple users.
+type KafscaleUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KafscaleUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KafscaleUser{}, &KafscaleUserList{})
+}
+
+func (in *KafscaleUserSpec) DeepCopyInto(out *KafscaleUserSpec) {
+	*out = *in
+}
+
+func (in *KafscaleUserSpec) DeepCopy() *KafscaleUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleUserStatus) DeepCopyInto(out *KafscaleUserStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(in.Conditions))
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
+
+func (in *KafscaleUserStatus) DeepCopy() *KafscaleUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleUser) DeepCopyInto(out *KafscaleUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+func (in *KafscaleUser) DeepCopy() *KafscaleUser {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+func (in *KafscaleUserList) DeepCopyInto(out *KafscaleUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]KafscaleUser, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+func (in *KafscaleUserList) DeepCopy() *KafscaleUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(KafscaleUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *KafscaleUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProcessorWorkloadSpec sizes the Deployment the operator runs for a processor.
+type ProcessorWorkloadSpec struct {
+	Replicas        *int32                       `json:"replicas,omitempty"`
+	Image           string                       `json:"image,omitempty"`
+	ImagePullPolicy string                       `json:"imagePullPolicy,omitempty"`
+	Resources       *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// ProcessorTopicStatus reports how far a processor is behind on one topic.
+type ProcessorTopicStatus struct {
+	Topic string `json:"topic"`
+	// Lag is the number of records written to the topic that the processor
+	// has not committed yet, summed over partitions.
+	Lag int64 `json:"lag"`
+	// WatermarkOffset is the lowest committed offset across partitions.
+	WatermarkOffset int64        `json:"watermarkOffset"`
+	WatermarkTime   *metav1.Time `json:"watermarkTime,omitempty"`
+}
+
+func (in *ProcessorWorkloadSpec) DeepCopyInto(out *ProcessorWorkloadSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		out.Replicas = new(int32)
+		*out.Replicas = *in.Replicas
+	}
+	if in.Resources != nil {
+		out.Resources = in.Resources.DeepCopy()
+	}
+}
+
+func (in *ProcessorWorkloadSpec) DeepCopy() *ProcessorWorkloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorWorkloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *ProcessorTopicStatus) DeepCopyInto(out *ProcessorTopicStatus) {
+	*out = *in
+	if in.WatermarkTime != nil {
+		out.WatermarkTime = in.WatermarkTime.DeepCopy()
+	}
+}
+
+func (in *ProcessorTopicStatus) DeepCopy() *ProcessorTopicStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessorTopicStatus)
+	in.DeepCopyInto(out)
+	return out
+}
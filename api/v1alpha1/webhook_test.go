@@ -0,0 +1,232 @@
+// Copyright 2025-2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func validCluster() *KafscaleCluster {
+	return &KafscaleCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: "default"},
+		Spec: KafscaleClusterSpec{
+			S3:   S3Spec{Bucket: "segments", Region: "us-east-1"},
+			Etcd: EtcdSpec{Endpoints: []string{"http://etcd:2379"}},
+		},
+	}
+}
+
+func validTopic() *KafscaleTopic {
+	return &KafscaleTopic{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+		Spec:       KafscaleTopicSpec{ClusterRef: "prod", Partitions: 3},
+	}
+}
+
+func TestKafscaleClusterWebhookDefault(t *testing.T) {
+	cluster := validCluster()
+	cluster.Spec.S3.Bucket = " segments "
+	cluster.Spec.Autoscaling = &AutoscalingSpec{}
+	if err := (KafscaleClusterWebhook{}).Default(context.Background(), cluster); err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+	b := cluster.Spec.Brokers
+	if *b.Replicas != DefaultBrokerReplicas || *b.AdvertisedPort != DefaultBrokerPort || *b.DrainTimeoutSeconds != DefaultDrainTimeoutSeconds {
+		t.Fatalf("unexpected broker defaults: %+v", b)
+	}
+	if b.Service.Type != "ClusterIP" || cluster.Spec.S3.Bucket != "segments" {
+		t.Fatalf("unexpected defaults: service=%q bucket=%q", b.Service.Type, cluster.Spec.S3.Bucket)
+	}
+	if *cluster.Spec.Autoscaling.ScaleDownStabilizationSeconds != DefaultScaleDownStabilizationSeconds {
+		t.Fatalf("expected autoscaling stabilization default")
+	}
+	if cluster.Spec.Proxy != nil || cluster.Spec.Console != nil {
+		t.Fatalf("expected proxy and console to stay disabled")
+	}
+
+	cluster = validCluster()
+	cluster.Spec.Proxy = &ProxySpec{}
+	cluster.Spec.Console = &ConsoleSpec{}
+	_ = (KafscaleClusterWebhook{}).Default(context.Background(), cluster)
+	if p := cluster.Spec.Proxy; *p.Replicas != DefaultProxyReplicas || *p.AdvertisedPort != DefaultBrokerPort || p.Service.Type != "ClusterIP" {
+		t.Fatalf("unexpected proxy defaults: %+v", p)
+	}
+	if c := cluster.Spec.Console; *c.Replicas != DefaultConsoleReplicas || c.Service.Type != "ClusterIP" {
+		t.Fatalf("unexpected console defaults: %+v", c)
+	}
+
+	replicas := int32(5)
+	cluster = validCluster()
+	cluster.Spec.Brokers.Replicas = &replicas
+	_ = (KafscaleClusterWebhook{}).Default(context.Background(), cluster)
+	if *cluster.Spec.Brokers.Replicas != 5 || cluster.Spec.Autoscaling != nil {
+		t.Fatalf("expected explicit values to be kept")
+	}
+}
+
+func TestKafscaleClusterWebhookValidate(t *testing.T) {
+	neg := int32(-1)
+	port := int32(70000)
+	min, max := int32(4), int32(2)
+	tests := []struct {
+		name   string
+		mutate func(*KafscaleCluster)
+		field  string
+	}{
+		{name: "valid", mutate: func(*KafscaleCluster) {}},
+		{name: "valid cache size", mutate: func(c *KafscaleCluster) { c.Spec.Config.CacheSize = "512Mi" }},
+		{name: "missing bucket", mutate: func(c *KafscaleCluster) { c.Spec.S3.Bucket = " " }, field: "spec.s3.bucket"},
+		{name: "missing region", mutate: func(c *KafscaleCluster) { c.Spec.S3.Region = "" }, field: "spec.s3.region"},
+		{name: "bad cache size", mutate: func(c *KafscaleCluster) { c.Spec.Config.CacheSize = "lots" }, field: "spec.config.cacheSize"},
+		{name: "zero cache size", mutate: func(c *KafscaleCluster) { c.Spec.Config.CacheSize = "0" }, field: "spec.config.cacheSize"},
+		{name: "negative replicas", mutate: func(c *KafscaleCluster) { c.Spec.Brokers.Replicas = &neg }, field: "spec.brokers.replicas"},
+		{name: "bad port", mutate: func(c *KafscaleCluster) { c.Spec.Brokers.AdvertisedPort = &port }, field: "spec.brokers.advertisedPort"},
+		{name: "bad service type", mutate: func(c *KafscaleCluster) { c.Spec.Brokers.Service.Type = "External" }, field: "spec.brokers.service.type"},
+		{name: "bad endpoint", mutate: func(c *KafscaleCluster) { c.Spec.S3.Endpoint = "minio:9000" }, field: "spec.s3.endpoint"},
+		{name: "negative segment bytes", mutate: func(c *KafscaleCluster) { c.Spec.Config.SegmentBytes = -1 }, field: "spec.config.segmentBytes"},
+		{name: "request above limit", mutate: func(c *KafscaleCluster) {
+			c.Spec.Brokers.Resources = BrokerResources{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}
+		}, field: "spec.brokers.resources.requests[memory]"},
+		{name: "autoscaling bounds", mutate: func(c *KafscaleCluster) {
+			c.Spec.Autoscaling = &AutoscalingSpec{MinReplicas: &min, MaxReplicas: &max}
+		}, field: "spec.autoscaling.maxReplicas"},
+		{name: "valid proxy and console", mutate: func(c *KafscaleCluster) {
+			c.Spec.Proxy = &ProxySpec{Service: ComponentServiceSpec{Type: "LoadBalancer"}}
+			c.Spec.Console = &ConsoleSpec{AuthSecretRef: "console-auth"}
+		}},
+		{name: "bad proxy port", mutate: func(c *KafscaleCluster) {
+			c.Spec.Proxy = &ProxySpec{AdvertisedPort: &port}
+		}, field: "spec.proxy.advertisedPort"},
+		{name: "bad console service type", mutate: func(c *KafscaleCluster) {
+			c.Spec.Console = &ConsoleSpec{Service: ComponentServiceSpec{Type: "Ingress"}}
+		}, field: "spec.console.service.type"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := validCluster()
+			tc.mutate(cluster)
+			_, err := (KafscaleClusterWebhook{}).ValidateCreate(context.Background(), cluster)
+			assertInvalidField(t, err, tc.field)
+		})
+	}
+}
+
+func TestKafscaleClusterWebhookWarnsOnBucketChange(t *testing.T) {
+	oldCluster := validCluster()
+	cluster := validCluster()
+	cluster.Spec.S3.Bucket = "other"
+	warnings, err := (KafscaleClusterWebhook{}).ValidateUpdate(context.Background(), oldCluster, cluster)
+	if err != nil || len(warnings) != 1 || !strings.Contains(warnings[0], "spec.s3.bucket") {
+		t.Fatalf("expected bucket change warning, got %v (%v)", warnings, err)
+	}
+}
+
+func TestKafscaleTopicWebhookValidate(t *testing.T) {
+	neg := int64(-2)
+	tests := []struct {
+		name   string
+		mutate func(*KafscaleTopic)
+		field  string
+	}{
+		{name: "valid", mutate: func(*KafscaleTopic) {}},
+		{name: "valid config", mutate: func(tp *KafscaleTopic) {
+			tp.Spec.Config = map[string]string{"retention.ms": "-1", "segment.bytes": "1048576"}
+		}},
+		{name: "zero partitions", mutate: func(tp *KafscaleTopic) { tp.Spec.Partitions = 0 }, field: "spec.partitions"},
+		{name: "missing cluster", mutate: func(tp *KafscaleTopic) { tp.Spec.ClusterRef = "" }, field: "spec.clusterRef"},
+		{name: "unknown config key", mutate: func(tp *KafscaleTopic) { tp.Spec.Config = map[string]string{"cleanup.policy": "compact"} }, field: "spec.config"},
+		{name: "bad config value", mutate: func(tp *KafscaleTopic) { tp.Spec.Config = map[string]string{"segment.bytes": "1MB"} }, field: "spec.config[segment.bytes]"},
+		{name: "bad retention", mutate: func(tp *KafscaleTopic) { tp.Spec.RetentionMs = &neg }, field: "spec.retentionMs"},
+		{name: "bad deletion policy", mutate: func(tp *KafscaleTopic) { tp.Spec.DeletionPolicy = "Orphan" }, field: "spec.deletionPolicy"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			topic := validTopic()
+			tc.mutate(topic)
+			_, err := (KafscaleTopicWebhook{}).ValidateCreate(context.Background(), topic)
+			assertInvalidField(t, err, tc.field)
+		})
+	}
+}
+
+func TestKafscaleTopicWebhookValidateUpdate(t *testing.T) {
+	oldTopic := validTopic()
+	topic := validTopic()
+	topic.Spec.Partitions = 2
+	_, err := (KafscaleTopicWebhook{}).ValidateUpdate(context.Background(), oldTopic, topic)
+	assertInvalidField(t, err, "spec.partitions")
+	if !strings.Contains(err.Error(), "cannot be decreased from 3") {
+		t.Fatalf("expected decrease message, got %v", err)
+	}
+
+	topic = validTopic()
+	topic.Spec.ClusterRef = "staging"
+	_, err = (KafscaleTopicWebhook{}).ValidateUpdate(context.Background(), oldTopic, topic)
+	assertInvalidField(t, err, "spec.clusterRef")
+
+	topic = validTopic()
+	topic.Spec.Partitions = 6
+	if _, err := (KafscaleTopicWebhook{}).ValidateUpdate(context.Background(), oldTopic, topic); err != nil {
+		t.Fatalf("expected partition increase to be allowed: %v", err)
+	}
+}
+
+func TestKafscaleTopicWebhookDefaultAndWarnings(t *testing.T) {
+	topic := validTopic()
+	if err := (KafscaleTopicWebhook{}).Default(context.Background(), topic); err != nil || topic.Spec.DeletionPolicy != TopicDeletionPolicyDelete {
+		t.Fatalf("expected Delete policy default, got %q (%v)", topic.Spec.DeletionPolicy, err)
+	}
+	retention := int64(1000)
+	topic.Spec.RetentionMs = &retention
+	topic.Spec.Config = map[string]string{"retention.ms": "5000"}
+	warnings, err := (KafscaleTopicWebhook{}).ValidateCreate(context.Background(), topic)
+	if err != nil || len(warnings) != 1 {
+		t.Fatalf("expected retention override warning, got %v (%v)", warnings, err)
+	}
+}
+
+func assertInvalidField(t *testing.T, err error, field string) {
+	t.Helper()
+	if field == "" {
+		if err != nil {
+			t.Fatalf("expected valid object, got %v", err)
+		}
+		return
+	}
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("expected Invalid error for %s, got %v", field, err)
+	}
+	status, ok := err.(apierrors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		t.Fatalf("expected status details, got %v", err)
+	}
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Field == field {
+			return
+		}
+	}
+	t.Fatalf("expected cause for %s, got %v", field, err)
+}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -54,6 +55,8 @@ const (
 	defaultMinioAccessKey = "minioadmin"
 	defaultMinioSecretKey = "minioadmin"
 	brokerVersion         = "dev"
+	defaultDrainDeadline  = 30 * time.Second
+	defaultMetricsPeriod  = time.Second
 )
 
 type handler struct {
@@ -75,6 +78,8 @@ type handler struct {
 	traceKafka           bool
 	produceRate          *throughputTracker
 	fetchRate            *throughputTracker
+	produceBytesRate     *throughputTracker
+	connections          func() int64
 	produceLatency       *histogram
 	consumerLag          *lagMetrics
 	startTime            time.Time
@@ -85,6 +90,12 @@ type handler struct {
 	flushInterval        time.Duration
 	flushOnAck           bool
 	adminMetrics         *adminMetrics
+	drainTimeout         time.Duration
+	drainMu              sync.RWMutex
+	draining             map[string]map[int32]int
+	released             map[string]map[int32]partitionLeader
+	drainAll             int
+	retired              bool
 }
 
 type etcdAvailability interface {
@@ -460,9 +471,17 @@ func (h *handler) metricsHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintln(w, "# HELP kafscale_produce_rps Broker ingest throughput measured over the sliding window.")
 	fmt.Fprintln(w, "# TYPE kafscale_produce_rps gauge")
 	fmt.Fprintf(w, "kafscale_produce_rps %f\n", h.produceRate.rate())
-	fmt.Fprintln(w, "# HELP kafscale_fetch_rps Broker fetch throughput measured over the sliding window.")
+	fmt.Fprintln(w, "# HELP kafscale_fetch_rps Records fetched per second measured over the sliding window.")
 	fmt.Fprintln(w, "# TYPE kafscale_fetch_rps gauge")
 	fmt.Fprintf(w, "kafscale_fetch_rps %f\n", h.fetchRate.rate())
+	fmt.Fprintln(w, "# HELP kafscale_produce_bytes_per_second Broker ingest bytes per second measured over the sliding window.")
+	fmt.Fprintln(w, "# TYPE kafscale_produce_bytes_per_second gauge")
+	fmt.Fprintf(w, "kafscale_produce_bytes_per_second %f\n", h.produceBytesRate.rate())
+	if h.connections != nil {
+		fmt.Fprintln(w, "# HELP kafscale_active_connections Open Kafka client connections.")
+		fmt.Fprintln(w, "# TYPE kafscale_active_connections gauge")
+		fmt.Fprintf(w, "kafscale_active_connections %d\n", h.connections())
+	}
 	if h.produceLatency != nil {
 		h.produceLatency.WritePrometheus(w, "kafscale_produce_latency_ms", "Produce request latency in milliseconds.")
 	}
@@ -501,7 +520,7 @@ func (h *handler) handleProduce(ctx context.Context, header *protocol.RequestHea
 	}()
 	topicResponses := make([]protocol.ProduceTopicResponse, 0, len(req.Topics))
 	now := time.Now().UnixMilli()
-	var producedMessages int64
+	var producedMessages, producedBytes int64
 
 	for _, topic := range req.Topics {
 		if h.traceKafka {
@@ -529,57 +548,11 @@ func (h *handler) handleProduce(ctx context.Context, header *protocol.RequestHea
 				}
 				continue
 			}
-			plog, err := h.getPartitionLog(ctx, topic.Name, part.Partition)
-			if err != nil {
-				h.logger.Error("partition log init failed", "error", err, "topic", topic.Name, "partition", part.Partition)
-				partitionResponses = append(partitionResponses, protocol.ProducePartitionResponse{
-					Partition: part.Partition,
-					ErrorCode: protocol.UNKNOWN_SERVER_ERROR,
-				})
-				continue
-			}
-			batch, err := storage.NewRecordBatchFromBytes(part.Records)
-			if err != nil {
-				partitionResponses = append(partitionResponses, protocol.ProducePartitionResponse{
-					Partition: part.Partition,
-					ErrorCode: protocol.UNKNOWN_SERVER_ERROR,
-				})
-				if h.traceKafka {
-					h.logger.Debug("produce record batch decode failed", "topic", topic.Name, "partition", part.Partition, "error", err)
-				}
-				continue
-			}
-			result, err := plog.AppendBatch(ctx, batch)
-			if err != nil {
-				partitionResponses = append(partitionResponses, protocol.ProducePartitionResponse{
-					Partition: part.Partition,
-					ErrorCode: h.backpressureErrorCode(),
-				})
-				if h.traceKafka {
-					h.logger.Debug("produce append failed", "topic", topic.Name, "partition", part.Partition, "error", err)
-				}
-				continue
-			}
-			if req.Acks != 0 && h.flushOnAck {
-				if err := plog.Flush(ctx); err != nil {
-					h.logger.Error("flush failed", "error", err, "topic", topic.Name, "partition", part.Partition)
-					partitionResponses = append(partitionResponses, protocol.ProducePartitionResponse{
-						Partition: part.Partition,
-						ErrorCode: h.backpressureErrorCode(),
-					})
-					continue
-				}
-			}
-			partitionResponses = append(partitionResponses, protocol.ProducePartitionResponse{
-				Partition:       part.Partition,
-				ErrorCode:       0,
-				BaseOffset:      result.BaseOffset,
-				LogAppendTimeMs: now,
-				LogStartOffset:  0,
-			})
-			producedMessages += int64(batch.MessageCount)
-			if h.traceKafka {
-				h.logger.Debug("produce append success", "topic", topic.Name, "partition", part.Partition, "base_offset", result.BaseOffset, "last_offset", result.LastOffset)
+			resp, produced := h.produceToPartition(ctx, topic.Name, part, req.Acks, now)
+			partitionResponses = append(partitionResponses, resp)
+			producedMessages += produced
+			if produced > 0 {
+				producedBytes += int64(len(part.Records))
 			}
 		}
 		topicResponses = append(topicResponses, protocol.ProduceTopicResponse{
@@ -590,6 +563,7 @@ func (h *handler) handleProduce(ctx context.Context, header *protocol.RequestHea
 
 	if producedMessages > 0 {
 		h.produceRate.add(producedMessages)
+		h.produceBytesRate.add(producedBytes)
 	}
 
 	if req.Acks == 0 {
@@ -603,6 +577,71 @@ func (h *handler) handleProduce(ctx context.Context, header *protocol.RequestHea
 	}, header.APIVersion)
 }
 
+// produceToPartition appends one partition's records and returns the partition
+// response and the number of messages written. Partitions that are being
+// drained are rejected so clients refresh metadata and move on.
+func (h *handler) produceToPartition(ctx context.Context, topic string, part protocol.ProducePartition, acks int16, now int64) (protocol.ProducePartitionResponse, int64) {
+	h.resumeMovedPartition(ctx, topic, part.Partition)
+	h.drainMu.RLock()
+	defer h.drainMu.RUnlock()
+	if h.drainingLocked(topic, part.Partition) {
+		if h.traceKafka {
+			h.logger.Debug("produce rejected while draining", "topic", topic, "partition", part.Partition)
+		}
+		return protocol.ProducePartitionResponse{
+			Partition: part.Partition,
+			ErrorCode: protocol.NOT_LEADER_OR_FOLLOWER,
+		}, 0
+	}
+	plog, err := h.getPartitionLog(ctx, topic, part.Partition)
+	if err != nil {
+		h.logger.Error("partition log init failed", "error", err, "topic", topic, "partition", part.Partition)
+		return protocol.ProducePartitionResponse{
+			Partition: part.Partition,
+			ErrorCode: protocol.UNKNOWN_SERVER_ERROR,
+		}, 0
+	}
+	batch, err := storage.NewRecordBatchFromBytes(part.Records)
+	if err != nil {
+		if h.traceKafka {
+			h.logger.Debug("produce record batch decode failed", "topic", topic, "partition", part.Partition, "error", err)
+		}
+		return protocol.ProducePartitionResponse{
+			Partition: part.Partition,
+			ErrorCode: protocol.UNKNOWN_SERVER_ERROR,
+		}, 0
+	}
+	result, err := plog.AppendBatch(ctx, batch)
+	if err != nil {
+		if h.traceKafka {
+			h.logger.Debug("produce append failed", "topic", topic, "partition", part.Partition, "error", err)
+		}
+		return protocol.ProducePartitionResponse{
+			Partition: part.Partition,
+			ErrorCode: h.backpressureErrorCode(),
+		}, 0
+	}
+	if acks != 0 && h.flushOnAck {
+		if err := plog.Flush(ctx); err != nil {
+			h.logger.Error("flush failed", "error", err, "topic", topic, "partition", part.Partition)
+			return protocol.ProducePartitionResponse{
+				Partition: part.Partition,
+				ErrorCode: h.backpressureErrorCode(),
+			}, 0
+		}
+	}
+	if h.traceKafka {
+		h.logger.Debug("produce append success", "topic", topic, "partition", part.Partition, "base_offset", result.BaseOffset, "last_offset", result.LastOffset)
+	}
+	return protocol.ProducePartitionResponse{
+		Partition:       part.Partition,
+		ErrorCode:       0,
+		BaseOffset:      result.BaseOffset,
+		LogAppendTimeMs: now,
+		LogStartOffset:  0,
+	}, int64(batch.MessageCount)
+}
+
 func (h *handler) handleCreateTopics(ctx context.Context, header *protocol.RequestHeader, req *protocol.CreateTopicsRequest) ([]byte, error) {
 	if header.APIVersion < 0 || header.APIVersion > 2 {
 		return nil, fmt.Errorf("create topics version %d not supported", header.APIVersion)
@@ -764,9 +803,9 @@ func (h *handler) etcdAvailable() bool {
 }
 
 const (
-	configRetentionMs    = "retention.ms"
-	configRetentionBytes = "retention.bytes"
-	configSegmentBytes   = "segment.bytes"
+	configRetentionMs    = metadata.ConfigRetentionMs
+	configRetentionBytes = metadata.ConfigRetentionBytes
+	configSegmentBytes   = metadata.ConfigSegmentBytes
 	configBrokerID       = "broker.id"
 	configAdvertised     = "advertised.listeners"
 	configS3Bucket       = "kafscale.s3.bucket"
@@ -934,36 +973,8 @@ func (h *handler) handleAlterConfigs(ctx context.Context, header *protocol.Reque
 		}
 		errorCode := protocol.NONE
 		for _, entry := range resource.Configs {
-			if entry.Value == nil {
-				errorCode = protocol.INVALID_CONFIG
-				break
-			}
-			switch entry.Name {
-			case configRetentionMs:
-				value, err := parseConfigInt64(*entry.Value)
-				if err != nil || (value < 0 && value != -1) {
-					errorCode = protocol.INVALID_CONFIG
-					break
-				}
-				updated.RetentionMs = value
-			case configRetentionBytes:
-				value, err := parseConfigInt64(*entry.Value)
-				if err != nil || (value < 0 && value != -1) {
-					errorCode = protocol.INVALID_CONFIG
-					break
-				}
-				updated.RetentionBytes = value
-			case configSegmentBytes:
-				value, err := parseConfigInt64(*entry.Value)
-				if err != nil || value <= 0 {
-					errorCode = protocol.INVALID_CONFIG
-					break
-				}
-				updated.SegmentBytes = value
-			default:
+			if entry.Value == nil || metadata.ApplyTopicConfig(updated, entry.Name, *entry.Value) != nil {
 				errorCode = protocol.INVALID_CONFIG
-			}
-			if errorCode != protocol.NONE {
 				break
 			}
 		}
@@ -1069,11 +1080,7 @@ func (h *handler) validateCreatePartitions(ctx context.Context, topic string, co
 	if len(meta.Topics) == 0 || meta.Topics[0].ErrorCode != 0 {
 		return metadata.ErrUnknownTopic
 	}
-	current := int32(len(meta.Topics[0].Partitions))
-	if count <= current {
-		return metadata.ErrInvalidTopic
-	}
-	return nil
+	return metadata.ValidatePartitionIncrease(int32(len(meta.Topics[0].Partitions)), count)
 }
 
 func (h *handler) topicConfigEntries(cfg *metadatapb.TopicConfig, requested []string) []protocol.DescribeConfigsResponseConfig {
@@ -1159,11 +1166,6 @@ func normalizeSegmentBytes(value int64, fallback int64) (string, bool) {
 	return fmt.Sprintf("%d", value), false
 }
 
-func parseConfigInt64(value string) (int64, error) {
-	trimmed := strings.TrimSpace(value)
-	return strconv.ParseInt(trimmed, 10, 64)
-}
-
 func (h *handler) handleListOffsets(ctx context.Context, header *protocol.RequestHeader, req *protocol.ListOffsetsRequest) ([]byte, error) {
 	if header.APIVersion < 0 || header.APIVersion > 4 {
 		return nil, fmt.Errorf("list offsets version %d not supported", header.APIVersion)
@@ -1339,7 +1341,7 @@ func (h *handler) handleFetch(ctx context.Context, header *protocol.RequestHeade
 					h.logger.Debug("fetch partition response", "topic", topicName, "partition", part.Partition, "records_bytes", len(recordSet), "high_watermark", highWatermark)
 				}
 				if len(recordSet) > 0 {
-					fetchedMessages += int64(storage.CountRecordBatchMessages(recordSet))
+					fetchedMessages += int64(storage.CountRecordBatchMessagesFrom(recordSet, part.FetchOffset))
 				}
 			} else if h.traceKafka {
 				h.logger.Debug("fetch partition error", "topic", topicName, "partition", part.Partition, "error_code", errorCode)
@@ -1491,6 +1493,7 @@ func newHandler(store metadata.Store, s3Client storage.S3Client, brokerInfo prot
 	segmentBytes := parseEnvInt("KAFSCALE_SEGMENT_BYTES", 4<<20)
 	flushInterval := time.Duration(parseEnvInt("KAFSCALE_FLUSH_INTERVAL_MS", 500)) * time.Millisecond
 	flushOnAck := parseEnvBool("KAFSCALE_PRODUCE_SYNC_FLUSH", true)
+	drainTimeout := time.Duration(parseEnvInt("KAFSCALE_DRAIN_TIMEOUT_SEC", 30)) * time.Second
 	produceLatencyBuckets := []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2000, 5000}
 	consumerLagBuckets := []float64{1, 10, 100, 1000, 5000, 10000, 50000, 100000, 500000, 1000000}
 	if autoPartitions < 1 {
@@ -1525,6 +1528,7 @@ func newHandler(store metadata.Store, s3Client storage.S3Client, brokerInfo prot
 		traceKafka:           traceKafka,
 		produceRate:          newThroughputTracker(throughputWindow),
 		fetchRate:            newThroughputTracker(throughputWindow),
+		produceBytesRate:     newThroughputTracker(throughputWindow),
 		produceLatency:       newHistogram(produceLatencyBuckets),
 		consumerLag:          newLagMetrics(consumerLagBuckets),
 		startTime:            time.Now(),
@@ -1534,7 +1538,10 @@ func newHandler(store metadata.Store, s3Client storage.S3Client, brokerInfo prot
 		segmentBytes:         segmentBytes,
 		flushInterval:        flushInterval,
 		flushOnAck:           flushOnAck,
+		drainTimeout:         drainTimeout,
 		adminMetrics:         newAdminMetrics(),
+		draining:             make(map[string]map[int32]int),
+		released:             make(map[string]map[int32]partitionLeader),
 	}
 }
 
@@ -1598,13 +1605,14 @@ func main() {
 	handler.startConsumerLagSampler(ctx)
 	metricsAddr := envOrDefault("KAFSCALE_METRICS_ADDR", defaultMetricsAddr)
 	controlAddr := envOrDefault("KAFSCALE_CONTROL_ADDR", defaultControlAddr)
-	startMetricsServer(ctx, metricsAddr, handler, logger)
-	startControlServer(ctx, controlAddr, handler, logger)
 	kafkaAddr := envOrDefault("KAFSCALE_BROKER_ADDR", defaultKafkaAddr)
 	srv := &broker.Server{
 		Addr:    kafkaAddr,
 		Handler: handler,
 	}
+	handler.connections = srv.ActiveConnections
+	startMetricsServer(ctx, metricsAddr, handler, logger)
+	startControlServer(ctx, controlAddr, handler, logger)
 	if err := srv.ListenAndServe(ctx); err != nil {
 		logger.Error("broker server error", "error", err)
 		os.Exit(1)
@@ -1780,6 +1788,7 @@ func startMetricsServer(ctx context.Context, addr string, h *handler, logger *sl
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		fmt.Fprintf(w, "ok state=%s\n", h.s3Health.State())
 	})
+	mux.HandleFunc("/prestop", h.preStopHandler)
 	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		if ready, state := h.readiness(); !ready {
@@ -1839,46 +1848,418 @@ type controlServer struct {
 	handler *handler
 }
 
+// hostedPartition is a partition log this broker has open.
+type hostedPartition struct {
+	topic     string
+	partition int32
+	log       *storage.PartitionLog
+}
+
 func (s *controlServer) GetStatus(ctx context.Context, req *controlpb.BrokerStatusRequest) (*controlpb.BrokerStatusResponse, error) {
-	snap := s.handler.s3Health.Snapshot()
-	state := string(snap.State)
-	status := &controlpb.PartitionStatus{
-		Topic:          "__s3_health",
-		Partition:      0,
-		Leader:         true,
-		State:          state,
-		LogStartOffset: int64(snap.AvgLatency / time.Millisecond),
-		LogEndOffset:   int64(snap.AvgLatency / time.Millisecond),
-		HighWatermark:  int64(snap.ErrorRate * 1000),
-	}
-	ready := snap.State == broker.S3StateHealthy
+	h := s.handler
+	snap := h.s3Health.Snapshot()
+	hosted := h.lookupPartitions(nil)
+	h.drainMu.RLock()
+	drainAll := h.drainingAllLocked()
+	partitions := make([]*controlpb.PartitionStatus, 0, len(hosted))
+	for _, p := range hosted {
+		draining := h.drainingLocked(p.topic, p.partition)
+		state := "online"
+		if draining {
+			state = "draining"
+		}
+		partitions = append(partitions, &controlpb.PartitionStatus{
+			Topic:          p.topic,
+			Partition:      p.partition,
+			Leader:         !draining,
+			State:          state,
+			LogStartOffset: p.log.EarliestOffset(),
+			LogEndOffset:   p.log.LogEndOffset(),
+			HighWatermark:  p.log.HighWatermark(),
+		})
+	}
+	h.drainMu.RUnlock()
 	return &controlpb.BrokerStatusResponse{
-		BrokerId: fmt.Sprintf("%d", s.handler.brokerInfo.NodeID),
-		Version:  brokerVersion,
-		Ready:    ready,
-		Partitions: []*controlpb.PartitionStatus{
-			status,
-		},
+		BrokerId:   fmt.Sprintf("%d", h.brokerInfo.NodeID),
+		Version:    brokerVersion,
+		Ready:      snap.State == broker.S3StateHealthy && !drainAll,
+		Partitions: partitions,
 	}, nil
 }
 
+// DrainPartitions stops accepting produce requests for the given partitions
+// (all partitions when none are listed), flushes their buffers to S3 and
+// closes their logs. Partitions that cannot be flushed before the deadline
+// are reported as pending; calling again retries them.
+//
+// Released partitions keep rejecting produce until UndrainPartitions or until
+// metadata moves them. Once a drain of every partition completes the broker
+// stays retired, rejecting produce and reporting not ready, until it
+// restarts. Pending partitions go back to serving when the call returns.
 func (s *controlServer) DrainPartitions(ctx context.Context, req *controlpb.DrainPartitionsRequest) (*controlpb.DrainPartitionsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "DrainPartitions not implemented")
+	if err := validatePartitionRefs(req.GetPartitions()); err != nil {
+		return nil, err
+	}
+	h := s.handler
+	deadline := time.Duration(req.GetDeadlineSeconds()) * time.Second
+	if deadline <= 0 {
+		deadline = defaultDrainDeadline
+	}
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	// Taking the write lock waits for in-flight produce requests, so nothing
+	// is appended to a partition after it is marked.
+	h.drainMu.Lock()
+	h.markDrainingLocked(req.GetPartitions(), 1)
+	h.drainMu.Unlock()
+	h.logger.Info("draining partitions", "reason", req.GetReason(), "partitions", len(req.GetPartitions()), "deadline", deadline)
+
+	resp := &controlpb.DrainPartitionsResponse{}
+	defer func() {
+		h.drainMu.Lock()
+		h.markDrainingLocked(req.GetPartitions(), -1)
+		if len(req.GetPartitions()) == 0 && len(resp.Pending) == 0 {
+			h.retired = true
+		}
+		h.drainMu.Unlock()
+	}()
+	for _, p := range h.lookupPartitions(req.GetPartitions()) {
+		ref := &controlpb.PartitionRef{Topic: p.topic, Partition: p.partition}
+		if p.log != nil {
+			if err := ctx.Err(); err != nil {
+				resp.Pending = append(resp.Pending, ref)
+				continue
+			}
+			if err := h.releasePartition(ctx, p); err != nil {
+				h.logger.Warn("drain flush failed", "error", err, "topic", p.topic, "partition", p.partition)
+				resp.Pending = append(resp.Pending, ref)
+				continue
+			}
+		}
+		leader := h.partitionLeader(ctx, p.topic, p.partition)
+		h.drainMu.Lock()
+		h.markReleasedLocked(p.topic, p.partition, leader)
+		h.drainMu.Unlock()
+		resp.Drained = append(resp.Drained, ref)
+	}
+	return resp, nil
+}
+
+// UndrainPartitions lets the broker serve partitions released by
+// DrainPartitions again (every released partition when none are listed).
+// Their logs reopen on the next produce. A retired broker stays retired.
+func (s *controlServer) UndrainPartitions(ctx context.Context, req *controlpb.UndrainPartitionsRequest) (*controlpb.UndrainPartitionsResponse, error) {
+	if err := validatePartitionRefs(req.GetPartitions()); err != nil {
+		return nil, err
+	}
+	h := s.handler
+	refs := req.GetPartitions()
+	h.drainMu.Lock()
+	defer h.drainMu.Unlock()
+	if len(refs) == 0 {
+		for topic, partitions := range h.released {
+			for partition := range partitions {
+				refs = append(refs, &controlpb.PartitionRef{Topic: topic, Partition: partition})
+			}
+		}
+		sort.Slice(refs, func(i, j int) bool {
+			if refs[i].GetTopic() != refs[j].GetTopic() {
+				return refs[i].GetTopic() < refs[j].GetTopic()
+			}
+			return refs[i].GetPartition() < refs[j].GetPartition()
+		})
+	}
+	resp := &controlpb.UndrainPartitionsResponse{}
+	for _, ref := range refs {
+		if _, ok := h.released[ref.GetTopic()][ref.GetPartition()]; !ok {
+			continue
+		}
+		h.unmarkReleasedLocked(ref.GetTopic(), ref.GetPartition())
+		resp.Resumed = append(resp.Resumed, &controlpb.PartitionRef{Topic: ref.GetTopic(), Partition: ref.GetPartition()})
+	}
+	h.logger.Info("undrained partitions", "partitions", len(resp.Resumed))
+	return resp, nil
 }
 
 func (s *controlServer) TriggerFlush(ctx context.Context, req *controlpb.TriggerFlushRequest) (*controlpb.TriggerFlushResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "TriggerFlush not implemented")
+	if len(req.GetPartitions()) == 0 && !req.GetAllAssigned() {
+		return nil, status.Error(codes.InvalidArgument, "partitions or all_assigned is required")
+	}
+	if err := validatePartitionRefs(req.GetPartitions()); err != nil {
+		return nil, err
+	}
+	refs := req.GetPartitions()
+	if req.GetAllAssigned() {
+		refs = nil
+	}
+	resp := &controlpb.TriggerFlushResponse{}
+	for _, p := range s.handler.lookupPartitions(refs) {
+		if p.log == nil {
+			continue
+		}
+		if err := p.log.Flush(ctx); err != nil {
+			s.handler.logger.Error("control flush failed", "error", err, "topic", p.topic, "partition", p.partition)
+			return nil, status.Errorf(codes.Unavailable, "flush %s/%d: %v", p.topic, p.partition, err)
+		}
+		resp.Flushed = append(resp.Flushed, &controlpb.PartitionRef{Topic: p.topic, Partition: p.partition})
+	}
+	return resp, nil
+}
+
+// StreamMetrics sends a sample of the broker's produce and fetch counters
+// every interval_ms (default one second) until the client cancels.
+func (s *controlServer) StreamMetrics(req *controlpb.StreamMetricsRequest, stream controlpb.BrokerControl_StreamMetricsServer) error {
+	interval := time.Duration(req.GetIntervalMs()) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultMetricsPeriod
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := stream.Send(s.handler.metricsSample()); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *handler) metricsSample() *controlpb.MetricsSample {
+	return &controlpb.MetricsSample{
+		BrokerId:    fmt.Sprintf("%d", h.brokerInfo.NodeID),
+		TimestampMs: time.Now().UnixMilli(),
+		Gauges: map[string]float64{
+			"kafscale_produce_rps":              h.produceRate.rate(),
+			"kafscale_fetch_rps":                h.fetchRate.rate(),
+			"kafscale_produce_bytes_per_second": h.produceBytesRate.rate(),
+		},
+		Counters: map[string]float64{
+			"kafscale_produced_records_total": float64(h.produceRate.count()),
+			"kafscale_fetched_records_total":  float64(h.fetchRate.count()),
+			"kafscale_produced_bytes_total":   float64(h.produceBytesRate.count()),
+		},
+	}
+}
+
+func validatePartitionRefs(refs []*controlpb.PartitionRef) error {
+	for _, ref := range refs {
+		if strings.TrimSpace(ref.GetTopic()) == "" {
+			return status.Error(codes.InvalidArgument, "partition topic is required")
+		}
+	}
+	return nil
+}
+
+// lookupPartitions resolves refs to open partition logs. Partitions this
+// broker does not host have a nil log. With no refs, every open partition is
+// returned, sorted by topic and partition.
+func (h *handler) lookupPartitions(refs []*controlpb.PartitionRef) []hostedPartition {
+	h.logMu.Lock()
+	defer h.logMu.Unlock()
+	if len(refs) > 0 {
+		out := make([]hostedPartition, 0, len(refs))
+		for _, ref := range refs {
+			out = append(out, hostedPartition{
+				topic:     ref.GetTopic(),
+				partition: ref.GetPartition(),
+				log:       h.logs[ref.GetTopic()][ref.GetPartition()],
+			})
+		}
+		return out
+	}
+	var out []hostedPartition
+	for topic, partitions := range h.logs {
+		for partition, log := range partitions {
+			out = append(out, hostedPartition{topic: topic, partition: partition, log: log})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].topic != out[j].topic {
+			return out[i].topic < out[j].topic
+		}
+		return out[i].partition < out[j].partition
+	})
+	return out
+}
+
+// releasePartition flushes and closes a drained partition log and drops it,
+// so this broker no longer owns the partition.
+func (h *handler) releasePartition(ctx context.Context, p hostedPartition) error {
+	if err := p.log.Close(ctx); err != nil {
+		return err
+	}
+	h.logMu.Lock()
+	defer h.logMu.Unlock()
+	if h.logs[p.topic][p.partition] == p.log {
+		delete(h.logs[p.topic], p.partition)
+	}
+	if len(h.logs[p.topic]) == 0 {
+		delete(h.logs, p.topic)
+	}
+	return nil
+}
+
+// markDrainingLocked adds delta to the drain marks of refs, or of the whole
+// broker when refs is empty. Marks are counted so overlapping drains do not
+// clear each other.
+func (h *handler) markDrainingLocked(refs []*controlpb.PartitionRef, delta int) {
+	if len(refs) == 0 {
+		h.drainAll += delta
+		return
+	}
+	for _, ref := range refs {
+		partitions := h.draining[ref.GetTopic()]
+		if partitions == nil {
+			partitions = make(map[int32]int)
+			h.draining[ref.GetTopic()] = partitions
+		}
+		partitions[ref.GetPartition()] += delta
+		if partitions[ref.GetPartition()] <= 0 {
+			delete(partitions, ref.GetPartition())
+		}
+		if len(partitions) == 0 {
+			delete(h.draining, ref.GetTopic())
+		}
+	}
+}
+
+// partitionLeader identifies a partition's metadata leader. known is false
+// when metadata could not be read.
+type partitionLeader struct {
+	leaderID    int32
+	leaderEpoch int32
+	known       bool
+}
+
+func (h *handler) partitionLeader(ctx context.Context, topic string, partition int32) partitionLeader {
+	meta, err := h.store.Metadata(ctx, []string{topic})
+	if err != nil {
+		return partitionLeader{}
+	}
+	for _, t := range meta.Topics {
+		if t.Name != topic || t.ErrorCode != protocol.NONE {
+			continue
+		}
+		for _, p := range t.Partitions {
+			if p.PartitionIndex == partition {
+				return partitionLeader{leaderID: p.LeaderID, leaderEpoch: p.LeaderEpoch, known: true}
+			}
+		}
+	}
+	return partitionLeader{}
 }
 
-func (s *controlServer) StreamMetrics(stream grpc.ClientStreamingServer[controlpb.MetricsSample, controlpb.Ack]) error {
-	return status.Error(codes.Unimplemented, "StreamMetrics not implemented")
+func (h *handler) markReleasedLocked(topic string, partition int32, leader partitionLeader) {
+	if h.released[topic] == nil {
+		h.released[topic] = make(map[int32]partitionLeader)
+	}
+	h.released[topic][partition] = leader
+}
+
+func (h *handler) unmarkReleasedLocked(topic string, partition int32) {
+	delete(h.released[topic], partition)
+	if len(h.released[topic]) == 0 {
+		delete(h.released, topic)
+	}
+}
+
+// resumeMovedPartition drops the drain mark of a released partition once
+// metadata reports a different leader or epoch than when it was released, so
+// a reassignment back to this broker is served again.
+func (h *handler) resumeMovedPartition(ctx context.Context, topic string, partition int32) {
+	h.drainMu.RLock()
+	released, ok := h.released[topic][partition]
+	h.drainMu.RUnlock()
+	if !ok || !released.known {
+		return
+	}
+	current := h.partitionLeader(ctx, topic, partition)
+	if !current.known || current == released {
+		return
+	}
+	h.drainMu.Lock()
+	if mark, ok := h.released[topic][partition]; ok && mark == released {
+		h.unmarkReleasedLocked(topic, partition)
+		h.logger.Info("released partition moved; serving it again", "topic", topic, "partition", partition, "leader", current.leaderID, "leader_epoch", current.leaderEpoch)
+	}
+	h.drainMu.Unlock()
+}
+
+func (h *handler) drainingAllLocked() bool {
+	return h.retired || h.drainAll > 0
+}
+
+func (h *handler) drainingLocked(topic string, partition int32) bool {
+	if h.drainingAllLocked() || h.draining[topic][partition] > 0 {
+		return true
+	}
+	_, released := h.released[topic][partition]
+	return released
 }
 
 func (h *handler) readiness() (bool, string) {
+	h.drainMu.RLock()
+	draining := h.drainingAllLocked()
+	h.drainMu.RUnlock()
+	if draining {
+		return false, "draining"
+	}
 	snap := h.s3Health.Snapshot()
 	return snap.State != broker.S3StateUnavailable, string(snap.State)
 }
 
+// preStopHandler backs the pod preStop hook. It keeps the broker running
+// until the operator has drained it through DrainPartitions, and drains it
+// locally if that has not happened within the drain timeout.
+func (h *handler) preStopHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.drainTimeout)
+	defer cancel()
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for !h.drained() {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+			continue
+		}
+		break
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if h.drained() {
+		fmt.Fprintln(w, "drained")
+		return
+	}
+	h.logger.Warn("broker not drained by operator; draining locally", "timeout", h.drainTimeout)
+	resp, err := (&controlServer{handler: h}).DrainPartitions(context.Background(), &controlpb.DrainPartitionsRequest{Reason: "prestop"})
+	if err != nil || len(resp.Pending) > 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "drain incomplete pending=%d\n", len(resp.GetPending()))
+		return
+	}
+	fmt.Fprintf(w, "drained locally partitions=%d\n", len(resp.Drained))
+}
+
+// drained reports whether a drain of every partition has completed and
+// nothing is left in the write buffers.
+func (h *handler) drained() bool {
+	h.drainMu.RLock()
+	retired := h.retired
+	h.drainMu.RUnlock()
+	if !retired {
+		return false
+	}
+	for _, p := range h.lookupPartitions(nil) {
+		if p.log.HighWatermark() < p.log.LogEndOffset() {
+			return false
+		}
+	}
+	return true
+}
+
 func newLogger() *slog.Logger {
 	level := logLevelFromEnv()
 	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
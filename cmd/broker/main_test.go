@@ -37,6 +37,9 @@ import (
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/kmsg"
 	"go.etcd.io/etcd/server/v3/embed"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/KafScale/platform/pkg/broker"
 	controlpb "github.com/KafScale/platform/pkg/gen/control"
@@ -943,6 +946,15 @@ func (f *failingS3Client) EnsureBucket(ctx context.Context) error {
 	return errors.New("s3 unavailable")
 }
 
+// uploadFailingS3Client reads like an empty bucket but rejects uploads.
+type uploadFailingS3Client struct {
+	storage.S3Client
+}
+
+func (f uploadFailingS3Client) UploadSegment(ctx context.Context, key string, body []byte) error {
+	return errors.New("s3 unavailable")
+}
+
 type failingMetadataStore struct {
 	metadata.Store
 	err error
@@ -1578,6 +1590,23 @@ func TestMetricsHandlerExposesAdminMetrics(t *testing.T) {
 	}
 }
 
+func TestMetricsHandlerExposesScalingSignals(t *testing.T) {
+	handler, _ := newBufferingTestHandler(t)
+	handler.connections = func() int64 { return 4 }
+	if code := produceTestBatch(t, handler, "payments", 0); code != protocol.NONE {
+		t.Fatalf("produce error code %d", code)
+	}
+	rec := httptest.NewRecorder()
+	handler.metricsHandler(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "kafscale_active_connections 4") {
+		t.Fatalf("expected connection gauge, got:\n%s", body)
+	}
+	if handler.produceBytesRate.rate() <= 0 || strings.Contains(body, "kafscale_produce_bytes_per_second 0.000000") {
+		t.Fatalf("expected produce bytes rate, got:\n%s", body)
+	}
+}
+
 func TestControlServerReportsHealth(t *testing.T) {
 	t.Setenv("KAFSCALE_S3_ERROR_RATE_CRIT", "0.01")
 	store := metadata.NewInMemoryStore(defaultMetadata())
@@ -1591,8 +1620,339 @@ func TestControlServerReportsHealth(t *testing.T) {
 	if resp.Ready {
 		t.Fatalf("expected broker not ready while S3 unavailable")
 	}
-	if len(resp.Partitions) == 0 || resp.Partitions[0].GetState() != string(broker.S3StateUnavailable) {
-		t.Fatalf("unexpected partition state: %+v", resp.Partitions)
+	if len(resp.Partitions) != 0 {
+		t.Fatalf("expected no partitions before any produce, got %+v", resp.Partitions)
+	}
+}
+
+func newBufferingTestHandler(t *testing.T) (*handler, metadata.Store) {
+	t.Helper()
+	t.Setenv("KAFSCALE_PRODUCE_SYNC_FLUSH", "false")
+	t.Setenv("KAFSCALE_FLUSH_INTERVAL_MS", "3600000")
+	store := metadata.NewInMemoryStore(defaultMetadata())
+	if _, err := store.CreateTopic(context.Background(), metadata.TopicSpec{Name: "payments", NumPartitions: 2, ReplicationFactor: 1}); err != nil {
+		t.Fatalf("CreateTopic: %v", err)
+	}
+	return newTestHandler(store), store
+}
+
+func produceTestBatch(t *testing.T, h *handler, topic string, partition int32) int16 {
+	t.Helper()
+	payload, err := h.handleProduce(context.Background(), &protocol.RequestHeader{CorrelationID: 1}, &protocol.ProduceRequest{
+		Acks:      -1,
+		TimeoutMs: 1000,
+		Topics: []protocol.ProduceTopic{{
+			Name:       topic,
+			Partitions: []protocol.ProducePartition{{Partition: partition, Records: testBatchBytes(0, 1, 2)}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("handleProduce: %v", err)
+	}
+	return decodeProduceResponse(t, payload, 0).Topics[0].Partitions[0].ErrorCode
+}
+
+func TestControlServerReportsPartitionOffsets(t *testing.T) {
+	handler, _ := newBufferingTestHandler(t)
+	for i := 0; i < 2; i++ {
+		if code := produceTestBatch(t, handler, "payments", 0); code != protocol.NONE {
+			t.Fatalf("produce error code %d", code)
+		}
+	}
+	if code := produceTestBatch(t, handler, "payments", 1); code != protocol.NONE {
+		t.Fatalf("produce error code %d", code)
+	}
+	srv := &controlServer{handler: handler}
+	resp, err := srv.GetStatus(context.Background(), &controlpb.BrokerStatusRequest{})
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	if !resp.Ready || resp.BrokerId != "1" {
+		t.Fatalf("unexpected broker status: %+v", resp)
+	}
+	if len(resp.Partitions) != 2 {
+		t.Fatalf("expected 2 partitions, got %+v", resp.Partitions)
+	}
+	first := resp.Partitions[0]
+	if first.Topic != "payments" || first.Partition != 0 || first.State != "online" || !first.Leader {
+		t.Fatalf("unexpected partition status: %+v", first)
+	}
+	if first.LogStartOffset != 0 || first.LogEndOffset != 4 || first.HighWatermark != 0 {
+		t.Fatalf("expected buffered offsets 0/4/0, got %d/%d/%d", first.LogStartOffset, first.LogEndOffset, first.HighWatermark)
+	}
+}
+
+func TestControlServerTriggerFlush(t *testing.T) {
+	handler, store := newBufferingTestHandler(t)
+	produceTestBatch(t, handler, "payments", 0)
+	produceTestBatch(t, handler, "payments", 1)
+	srv := &controlServer{handler: handler}
+
+	if _, err := srv.TriggerFlush(context.Background(), &controlpb.TriggerFlushRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected invalid argument for empty request, got %v", err)
+	}
+	resp, err := srv.TriggerFlush(context.Background(), &controlpb.TriggerFlushRequest{
+		Partitions: []*controlpb.PartitionRef{{Topic: "payments", Partition: 1}, {Topic: "payments", Partition: 7}},
+	})
+	if err != nil {
+		t.Fatalf("TriggerFlush: %v", err)
+	}
+	if len(resp.Flushed) != 1 || resp.Flushed[0].Partition != 1 {
+		t.Fatalf("expected only partition 1 flushed, got %+v", resp.Flushed)
+	}
+	if next, _ := store.NextOffset(context.Background(), "payments", 1); next != 2 {
+		t.Fatalf("expected partition 1 offsets committed, got %d", next)
+	}
+	if next, _ := store.NextOffset(context.Background(), "payments", 0); next != 0 {
+		t.Fatalf("expected partition 0 still buffered, got %d", next)
+	}
+
+	resp, err = srv.TriggerFlush(context.Background(), &controlpb.TriggerFlushRequest{AllAssigned: true})
+	if err != nil {
+		t.Fatalf("TriggerFlush all: %v", err)
+	}
+	if len(resp.Flushed) != 2 {
+		t.Fatalf("expected both partitions flushed, got %+v", resp.Flushed)
+	}
+	if next, _ := store.NextOffset(context.Background(), "payments", 0); next != 2 {
+		t.Fatalf("expected partition 0 offsets committed, got %d", next)
+	}
+}
+
+func TestControlServerDrainPartitions(t *testing.T) {
+	handler, store := newBufferingTestHandler(t)
+	produceTestBatch(t, handler, "payments", 0)
+	produceTestBatch(t, handler, "payments", 1)
+	srv := &controlServer{handler: handler}
+
+	resp, err := srv.DrainPartitions(context.Background(), &controlpb.DrainPartitionsRequest{
+		Partitions:      []*controlpb.PartitionRef{{Topic: "payments", Partition: 0}},
+		Reason:          "scale-down",
+		DeadlineSeconds: 5,
+	})
+	if err != nil {
+		t.Fatalf("DrainPartitions: %v", err)
+	}
+	if len(resp.Drained) != 1 || len(resp.Pending) != 0 {
+		t.Fatalf("unexpected drain response: %+v", resp)
+	}
+	if next, _ := store.NextOffset(context.Background(), "payments", 0); next != 2 {
+		t.Fatalf("expected drained partition flushed, got %d", next)
+	}
+	brokerStatus, err := srv.GetStatus(context.Background(), &controlpb.BrokerStatusRequest{})
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	if len(brokerStatus.Partitions) != 1 || brokerStatus.Partitions[0].Partition != 1 || !brokerStatus.Ready {
+		t.Fatalf("expected drained partition released, got %+v", brokerStatus)
+	}
+	if code := produceTestBatch(t, handler, "payments", 1); code != protocol.NONE {
+		t.Fatalf("expected other partitions to accept produce, got %d", code)
+	}
+	if code := produceTestBatch(t, handler, "payments", 0); code != protocol.NOT_LEADER_OR_FOLLOWER {
+		t.Fatalf("expected produce to drained partition rejected after the drain, got %d", code)
+	}
+	if p := handler.lookupPartitions([]*controlpb.PartitionRef{{Topic: "payments", Partition: 0}}); p[0].log != nil {
+		t.Fatalf("expected drained partition to stay released")
+	}
+
+	undrain, err := srv.UndrainPartitions(context.Background(), &controlpb.UndrainPartitionsRequest{})
+	if err != nil {
+		t.Fatalf("UndrainPartitions: %v", err)
+	}
+	if len(undrain.Resumed) != 1 || undrain.Resumed[0].Partition != 0 {
+		t.Fatalf("unexpected undrain response: %+v", undrain)
+	}
+	if code := produceTestBatch(t, handler, "payments", 0); code != protocol.NONE {
+		t.Fatalf("expected produce after undrain to reopen the log, got %d", code)
+	}
+	if p := handler.lookupPartitions([]*controlpb.PartitionRef{{Topic: "payments", Partition: 0}}); p[0].log == nil || p[0].log.LogEndOffset() != 4 {
+		t.Fatalf("expected reopened partition to continue at offset 2, got %+v", p)
+	}
+
+	// Draining everything marks the broker not ready.
+	resp, err = srv.DrainPartitions(context.Background(), &controlpb.DrainPartitionsRequest{})
+	if err != nil {
+		t.Fatalf("DrainPartitions all: %v", err)
+	}
+	if len(resp.Drained) != 2 {
+		t.Fatalf("unexpected drain-all response: %+v", resp)
+	}
+	if next, _ := store.NextOffset(context.Background(), "payments", 1); next != 4 {
+		t.Fatalf("expected partition 1 flushed, got %d", next)
+	}
+	if next, _ := store.NextOffset(context.Background(), "payments", 0); next != 4 {
+		t.Fatalf("expected reopened partition 0 flushed, got %d", next)
+	}
+	if code := produceTestBatch(t, handler, "payments", 0); code != protocol.NOT_LEADER_OR_FOLLOWER {
+		t.Fatalf("expected a retired broker to reject produce, got %d", code)
+	}
+	brokerStatus, err = srv.GetStatus(context.Background(), &controlpb.BrokerStatusRequest{})
+	if err != nil {
+		t.Fatalf("GetStatus: %v", err)
+	}
+	if brokerStatus.Ready || len(brokerStatus.Partitions) != 0 {
+		t.Fatalf("expected drained broker to report not ready, got %+v", brokerStatus)
+	}
+}
+
+func TestControlServerDrainEndsWhenMetadataMovesPartition(t *testing.T) {
+	handler, store := newBufferingTestHandler(t)
+	produceTestBatch(t, handler, "payments", 0)
+	srv := &controlServer{handler: handler}
+	if _, err := srv.DrainPartitions(context.Background(), &controlpb.DrainPartitionsRequest{
+		Partitions: []*controlpb.PartitionRef{{Topic: "payments", Partition: 0}},
+	}); err != nil {
+		t.Fatalf("DrainPartitions: %v", err)
+	}
+	if code := produceTestBatch(t, handler, "payments", 0); code != protocol.NOT_LEADER_OR_FOLLOWER {
+		t.Fatalf("expected drained partition rejected, got %d", code)
+	}
+
+	meta, err := store.Metadata(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Metadata: %v", err)
+	}
+	for i, topic := range meta.Topics {
+		if topic.Name == "payments" {
+			meta.Topics[i].Partitions[0].LeaderEpoch++
+		}
+	}
+	store.(*metadata.InMemoryStore).Update(*meta)
+	if code := produceTestBatch(t, handler, "payments", 0); code != protocol.NONE {
+		t.Fatalf("expected moved partition served again, got %d", code)
+	}
+}
+
+type metricsStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	samples chan *controlpb.MetricsSample
+}
+
+func (s *metricsStream) Context() context.Context { return s.ctx }
+
+func (s *metricsStream) Send(sample *controlpb.MetricsSample) error {
+	select {
+	case s.samples <- sample:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+func TestControlServerStreamMetrics(t *testing.T) {
+	handler, _ := newBufferingTestHandler(t)
+	produceTestBatch(t, handler, "payments", 0)
+	srv := &controlServer{handler: handler}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &metricsStream{ctx: ctx, samples: make(chan *controlpb.MetricsSample, 8)}
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.StreamMetrics(&controlpb.StreamMetricsRequest{IntervalMs: 10}, stream)
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case sample := <-stream.samples:
+			if sample.BrokerId != "1" || sample.Counters["kafscale_produced_records_total"] != 2 || sample.Gauges["kafscale_produce_rps"] <= 0 {
+				t.Fatalf("unexpected sample: %+v", sample)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected periodic samples")
+		}
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil && !errors.Is(err, context.Canceled) {
+			t.Fatalf("StreamMetrics: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("StreamMetrics did not return after cancel")
+	}
+}
+
+func TestPreStopWaitsForOperatorDrain(t *testing.T) {
+	handler, store := newBufferingTestHandler(t)
+	produceTestBatch(t, handler, "payments", 0)
+	srv := &controlServer{handler: handler}
+
+	done := make(chan string, 1)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.preStopHandler(rec, httptest.NewRequest("GET", "/prestop", nil))
+		done <- rec.Body.String()
+	}()
+	select {
+	case body := <-done:
+		t.Fatalf("expected prestop to wait for drain, got %q", body)
+	case <-time.After(300 * time.Millisecond):
+	}
+	if ready, state := handler.readiness(); !ready {
+		t.Fatalf("expected broker ready before drain, got %s", state)
+	}
+	if _, err := srv.DrainPartitions(context.Background(), &controlpb.DrainPartitionsRequest{Reason: "scale-down"}); err != nil {
+		t.Fatalf("DrainPartitions: %v", err)
+	}
+	select {
+	case body := <-done:
+		if strings.TrimSpace(body) != "drained" {
+			t.Fatalf("unexpected prestop response %q", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("prestop did not return after drain")
+	}
+	if next, _ := store.NextOffset(context.Background(), "payments", 0); next != 2 {
+		t.Fatalf("expected drained partition flushed, got %d", next)
+	}
+	if ready, state := handler.readiness(); ready || state != "draining" {
+		t.Fatalf("expected drained broker not ready, got %v %s", ready, state)
+	}
+}
+
+func TestPreStopDrainsLocallyAfterTimeout(t *testing.T) {
+	t.Setenv("KAFSCALE_DRAIN_TIMEOUT_SEC", "0")
+	handler, store := newBufferingTestHandler(t)
+	produceTestBatch(t, handler, "payments", 1)
+
+	rec := httptest.NewRecorder()
+	handler.preStopHandler(rec, httptest.NewRequest("GET", "/prestop", nil))
+	if rec.Code != 200 || !strings.Contains(rec.Body.String(), "drained locally partitions=1") {
+		t.Fatalf("unexpected prestop response %d %q", rec.Code, rec.Body.String())
+	}
+	if next, _ := store.NextOffset(context.Background(), "payments", 1); next != 2 {
+		t.Fatalf("expected partition flushed by local drain, got %d", next)
+	}
+}
+
+func TestControlServerDrainReportsPendingOnFlushFailure(t *testing.T) {
+	t.Setenv("KAFSCALE_PRODUCE_SYNC_FLUSH", "false")
+	t.Setenv("KAFSCALE_FLUSH_INTERVAL_MS", "3600000")
+	store := metadata.NewInMemoryStore(defaultMetadata())
+	handler := newHandler(store, uploadFailingS3Client{S3Client: storage.NewMemoryS3Client()}, protocol.MetadataBroker{NodeID: 1}, testLogger())
+	if code := produceTestBatch(t, handler, "orders", 0); code != protocol.NONE {
+		t.Fatalf("produce error code %d", code)
+	}
+	srv := &controlServer{handler: handler}
+	resp, err := srv.DrainPartitions(context.Background(), &controlpb.DrainPartitionsRequest{DeadlineSeconds: 1})
+	if err != nil {
+		t.Fatalf("DrainPartitions: %v", err)
+	}
+	if len(resp.Pending) != 1 || len(resp.Drained) != 0 {
+		t.Fatalf("expected partition pending after failed flush, got %+v", resp)
+	}
+	handler.drainMu.RLock()
+	draining := handler.drainingLocked("orders", 0)
+	handler.drainMu.RUnlock()
+	if draining {
+		t.Fatalf("expected an unfinished drain to be rolled back")
+	}
+	if handler.drained() {
+		t.Fatalf("expected unfinished drain not to count as drained")
+	}
+	if _, err := srv.TriggerFlush(context.Background(), &controlpb.TriggerFlushRequest{AllAssigned: true}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected unavailable flush error, got %v", err)
 	}
 }
 
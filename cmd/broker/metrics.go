@@ -23,6 +23,7 @@ import (
 type throughputTracker struct {
 	mu         sync.Mutex
 	buckets    map[int64]int64
+	total      int64
 	window     time.Duration
 	resolution time.Duration
 }
@@ -46,10 +47,21 @@ func (t *throughputTracker) add(count int64) {
 	bucket := time.Now().UnixNano() / t.resolution.Nanoseconds()
 	t.mu.Lock()
 	t.buckets[bucket] += count
+	t.total += count
 	t.pruneLocked(bucket)
 	t.mu.Unlock()
 }
 
+// count returns everything added since the tracker was created.
+func (t *throughputTracker) count() int64 {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
 func (t *throughputTracker) rate() float64 {
 	if t == nil {
 		return 0
@@ -0,0 +1,469 @@
+// Copyright 2025-2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command metadata-recovery rebuilds KafScale metadata in etcd from the
+// segments stored in S3, for when etcd is lost and no snapshot exists.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	metadatapb "github.com/KafScale/platform/pkg/gen/metadata"
+	"github.com/KafScale/platform/pkg/metadata"
+	"github.com/KafScale/platform/pkg/protocol"
+	"github.com/KafScale/platform/pkg/storage"
+)
+
+const snapshotKey = "/kafscale/metadata/snapshot"
+
+// consumerOffsetEntry is one line of an exported consumer offset file.
+type consumerOffsetEntry struct {
+	Group     string `json:"group"`
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+	Metadata  string `json:"metadata,omitempty"`
+}
+
+// change is a single etcd write. Before is empty when the key is created.
+type change struct {
+	Key    string
+	Value  []byte
+	Before string
+	After  string
+}
+
+func main() {
+	var (
+		namespace     string
+		dryRun        bool
+		importOffsets string
+		exportOffsets string
+	)
+	flag.StringVar(&namespace, "namespace", envOrDefault("KAFSCALE_S3_NAMESPACE", "default"), "S3 namespace (key prefix) that holds the topic segments.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print the changes that would be written to etcd without applying them.")
+	flag.StringVar(&importOffsets, "import-consumer-offsets", "", "JSON file of consumer offsets to restore, as written by -export-consumer-offsets.")
+	flag.StringVar(&exportOffsets, "export-consumer-offsets", "", "Write the consumer offsets currently stored in etcd to this JSON file and exit.")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(parseEnvInt("KAFSCALE_RECOVERY_TIMEOUT_SEC", 600))*time.Second)
+	defer cancel()
+
+	endpoints := strings.TrimSpace(os.Getenv("KAFSCALE_ETCD_ENDPOINTS"))
+	if endpoints == "" {
+		log.Fatalf("KAFSCALE_ETCD_ENDPOINTS is required")
+	}
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   strings.Split(endpoints, ","),
+		Username:    os.Getenv("KAFSCALE_ETCD_USERNAME"),
+		Password:    os.Getenv("KAFSCALE_ETCD_PASSWORD"),
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("connect etcd: %v", err)
+	}
+	defer cli.Close()
+
+	if exportOffsets != "" {
+		entries, err := readConsumerOffsets(ctx, cli)
+		if err != nil {
+			log.Fatalf("read consumer offsets: %v", err)
+		}
+		if err := writeOffsetFile(exportOffsets, entries); err != nil {
+			log.Fatalf("write %s: %v", exportOffsets, err)
+		}
+		log.Printf("exported %d consumer offsets to %s", len(entries), exportOffsets)
+		return
+	}
+
+	var offsets []consumerOffsetEntry
+	if importOffsets != "" {
+		if offsets, err = readOffsetFile(importOffsets); err != nil {
+			log.Fatalf("read %s: %v", importOffsets, err)
+		}
+	}
+	cfg, err := s3ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	s3, err := storage.NewS3Client(ctx, cfg)
+	if err != nil {
+		log.Fatalf("create S3 client: %v", err)
+	}
+	extents, err := storage.ScanPartitionExtents(ctx, s3, namespace)
+	if err != nil {
+		log.Fatalf("scan s3://%s/%s: %v", cfg.Bucket, namespace, err)
+	}
+	log.Printf("found %d partitions with segments under s3://%s/%s/", len(extents), cfg.Bucket, namespace)
+
+	changes, err := buildPlan(ctx, cli, extents, offsets)
+	if err != nil {
+		log.Fatalf("build recovery plan: %v", err)
+	}
+	printPlan(os.Stdout, changes)
+	if dryRun || len(changes) == 0 {
+		return
+	}
+	if err := applyPlan(ctx, cli, changes); err != nil {
+		log.Fatalf("apply recovery plan: %v", err)
+	}
+	log.Printf("applied %d changes", len(changes))
+}
+
+// buildPlan compares etcd with the partitions found in S3. Missing topics,
+// partitions, configs and states are created; next offsets are only raised
+// and consumer offsets are only written where none exist, so running the
+// tool against a partially intact etcd is safe.
+func buildPlan(ctx context.Context, cli *clientv3.Client, extents []storage.PartitionExtent, offsets []consumerOffsetEntry) ([]change, error) {
+	snap, err := readSnapshot(ctx, cli)
+	if err != nil {
+		return nil, err
+	}
+	byTopic := make(map[string][]storage.PartitionExtent)
+	var names []string
+	for _, ext := range extents {
+		if _, ok := byTopic[ext.Topic]; !ok {
+			names = append(names, ext.Topic)
+		}
+		byTopic[ext.Topic] = append(byTopic[ext.Topic], ext)
+	}
+	sort.Strings(names)
+
+	var changes []change
+	var snapshotNotes []string
+	for _, name := range names {
+		parts := byTopic[name]
+		count := parts[len(parts)-1].Partition + 1
+		topic, note := ensureSnapshotTopic(&snap, name, count)
+		if note != "" {
+			snapshotNotes = append(snapshotNotes, note)
+		}
+
+		replicationFactor := int32(len(topic.Partitions[0].ReplicaNodes))
+		if replicationFactor == 0 {
+			replicationFactor = 1
+		}
+		cfgChange, err := topicConfigChange(ctx, cli, name, int32(len(topic.Partitions)), replicationFactor)
+		if err != nil {
+			return nil, err
+		}
+		if cfgChange != nil {
+			changes = append(changes, *cfgChange)
+		}
+		leaders := make(map[int32]protocol.MetadataPartition, len(topic.Partitions))
+		for _, part := range topic.Partitions {
+			leaders[part.PartitionIndex] = part
+		}
+		for _, ext := range parts {
+			partChanges, err := partitionChanges(ctx, cli, leaders[ext.Partition], name, ext)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, partChanges...)
+		}
+	}
+	if len(snapshotNotes) > 0 {
+		payload, err := json.Marshal(snap)
+		if err != nil {
+			return nil, err
+		}
+		changes = append([]change{{Key: snapshotKey, Value: payload, After: strings.Join(snapshotNotes, ", ")}}, changes...)
+	}
+
+	for _, entry := range offsets {
+		key := metadata.ConsumerOffsetKey(entry.Group, entry.Topic, entry.Partition)
+		existing, err := getValue(ctx, cli, key)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			continue
+		}
+		payload, err := metadata.EncodeConsumerOffset(entry.Offset, entry.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change{Key: key, Value: payload, After: fmt.Sprintf("offset=%d", entry.Offset)})
+	}
+	return changes, nil
+}
+
+// ensureSnapshotTopic adds the topic to the broker metadata snapshot or grows
+// its partition list, spreading new leaders across the known brokers.
+func ensureSnapshotTopic(snap *metadata.ClusterMetadata, name string, partitions int32) (*protocol.MetadataTopic, string) {
+	replicas := make([]int32, 0, len(snap.Brokers))
+	for _, broker := range snap.Brokers {
+		replicas = append(replicas, broker.NodeID)
+	}
+	if len(replicas) == 0 {
+		replicas = []int32{0}
+	}
+	var topic *protocol.MetadataTopic
+	for i := range snap.Topics {
+		if snap.Topics[i].Name == name {
+			topic = &snap.Topics[i]
+		}
+	}
+	note := ""
+	if topic == nil {
+		snap.Topics = append(snap.Topics, protocol.MetadataTopic{Name: name, TopicID: metadata.TopicIDForName(name)})
+		topic = &snap.Topics[len(snap.Topics)-1]
+		note = fmt.Sprintf("add topic %s (partitions=%d)", name, partitions)
+	}
+	current := int32(len(topic.Partitions))
+	if current >= partitions {
+		return topic, note
+	}
+	if note == "" {
+		note = fmt.Sprintf("grow topic %s partitions %d -> %d", name, current, partitions)
+	}
+	for i := current; i < partitions; i++ {
+		topic.Partitions = append(topic.Partitions, protocol.MetadataPartition{
+			PartitionIndex: i,
+			LeaderID:       replicas[int(i)%len(replicas)],
+			ReplicaNodes:   replicas,
+			ISRNodes:       replicas,
+		})
+	}
+	return topic, note
+}
+
+func topicConfigChange(ctx context.Context, cli *clientv3.Client, name string, partitions, replicationFactor int32) (*change, error) {
+	key := metadata.TopicConfigKey(name)
+	raw, err := getValue(ctx, cli, key)
+	if err != nil {
+		return nil, err
+	}
+	var cfg *metadatapb.TopicConfig
+	before := ""
+	if raw != nil {
+		if cfg, err = metadata.DecodeTopicConfig(raw); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", key, err)
+		}
+		if cfg.Partitions >= partitions {
+			return nil, nil
+		}
+		before = fmt.Sprintf("partitions=%d", cfg.Partitions)
+		cfg.Partitions = partitions
+	} else {
+		cfg = &metadatapb.TopicConfig{
+			Name:              name,
+			Partitions:        partitions,
+			ReplicationFactor: replicationFactor,
+			RetentionMs:       -1,
+			RetentionBytes:    -1,
+			CreatedAt:         time.Now().UTC().Format(time.RFC3339),
+			Config:            map[string]string{},
+		}
+	}
+	payload, err := metadata.EncodeTopicConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &change{Key: key, Value: payload, Before: before, After: fmt.Sprintf("partitions=%d", partitions)}, nil
+}
+
+func partitionChanges(ctx context.Context, cli *clientv3.Client, part protocol.MetadataPartition, topic string, ext storage.PartitionExtent) ([]change, error) {
+	var changes []change
+	next := ext.LastOffset + 1
+	stateKey := metadata.PartitionStateKey(topic, ext.Partition)
+	raw, err := getValue(ctx, cli, stateKey)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		payload, err := metadata.EncodePartitionState(&metadatapb.PartitionState{
+			Topic:          topic,
+			Partition:      ext.Partition,
+			LeaderBroker:   fmt.Sprintf("%d", part.LeaderID),
+			LeaderEpoch:    part.LeaderEpoch,
+			LogStartOffset: ext.LogStartOffset,
+			LogEndOffset:   next,
+			HighWatermark:  next,
+		})
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change{Key: stateKey, Value: payload, After: fmt.Sprintf("log_start=%d log_end=%d", ext.LogStartOffset, next)})
+	}
+
+	offsetKey := metadata.NextOffsetKey(topic, ext.Partition)
+	raw, err = getValue(ctx, cli, offsetKey)
+	if err != nil {
+		return nil, err
+	}
+	before := ""
+	if raw != nil {
+		current, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+		if err == nil && current >= next {
+			return changes, nil
+		}
+		before = strings.TrimSpace(string(raw))
+	}
+	value := strconv.FormatInt(next, 10)
+	return append(changes, change{Key: offsetKey, Value: []byte(value), Before: before, After: value}), nil
+}
+
+func applyPlan(ctx context.Context, cli *clientv3.Client, changes []change) error {
+	for _, c := range changes {
+		putCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		_, err := cli.Put(putCtx, c.Key, string(c.Value))
+		cancel()
+		if err != nil {
+			return fmt.Errorf("put %s: %w", c.Key, err)
+		}
+	}
+	return nil
+}
+
+func printPlan(w io.Writer, changes []change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "etcd already matches S3; nothing to do")
+		return
+	}
+	for _, c := range changes {
+		if c.Before == "" {
+			fmt.Fprintf(w, "+ %s: %s\n", c.Key, c.After)
+		} else {
+			fmt.Fprintf(w, "~ %s: %s -> %s\n", c.Key, c.Before, c.After)
+		}
+	}
+}
+
+func readSnapshot(ctx context.Context, cli *clientv3.Client) (metadata.ClusterMetadata, error) {
+	var snap metadata.ClusterMetadata
+	raw, err := getValue(ctx, cli, snapshotKey)
+	if err != nil || raw == nil {
+		return snap, err
+	}
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		return snap, fmt.Errorf("decode %s: %w", snapshotKey, err)
+	}
+	return snap, nil
+}
+
+func readConsumerOffsets(ctx context.Context, cli *clientv3.Client) ([]consumerOffsetEntry, error) {
+	getCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	resp, err := cli.Get(getCtx, metadata.ConsumerGroupPrefix()+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]consumerOffsetEntry, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		group, topic, partition, ok := metadata.ParseConsumerOffsetKey(string(kv.Key))
+		if !ok {
+			continue
+		}
+		offset, meta, err := metadata.DecodeConsumerOffset(kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", kv.Key, err)
+		}
+		entries = append(entries, consumerOffsetEntry{Group: group, Topic: topic, Partition: partition, Offset: offset, Metadata: meta})
+	}
+	return entries, nil
+}
+
+func readOffsetFile(path string) ([]consumerOffsetEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []consumerOffsetEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Group == "" || entry.Topic == "" || entry.Partition < 0 || entry.Offset < 0 {
+			return nil, fmt.Errorf("invalid consumer offset entry %+v", entry)
+		}
+	}
+	return entries, nil
+}
+
+func writeOffsetFile(path string, entries []consumerOffsetEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+func getValue(ctx context.Context, cli *clientv3.Client, key string) ([]byte, error) {
+	getCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := cli.Get(getCtx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+func s3ConfigFromEnv() (storage.S3Config, error) {
+	bucket := strings.TrimSpace(os.Getenv("KAFSCALE_S3_BUCKET"))
+	if bucket == "" {
+		return storage.S3Config{}, fmt.Errorf("KAFSCALE_S3_BUCKET is required")
+	}
+	endpoint := strings.TrimSpace(os.Getenv("KAFSCALE_S3_ENDPOINT"))
+	return storage.S3Config{
+		Bucket:          bucket,
+		Region:          envOrDefault("KAFSCALE_S3_REGION", "us-east-1"),
+		Endpoint:        endpoint,
+		ForcePathStyle:  parseEnvBool("KAFSCALE_S3_PATH_STYLE", endpoint != ""),
+		AccessKeyID:     os.Getenv("KAFSCALE_S3_ACCESS_KEY"),
+		SecretAccessKey: os.Getenv("KAFSCALE_S3_SECRET_KEY"),
+		SessionToken:    os.Getenv("KAFSCALE_S3_SESSION_TOKEN"),
+	}, nil
+}
+
+func envOrDefault(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fallback
+}
+
+func parseEnvInt(key string, fallback int) int {
+	if val := strings.TrimSpace(os.Getenv(key)); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func parseEnvBool(key string, fallback bool) bool {
+	if val := strings.TrimSpace(os.Getenv(key)); val != "" {
+		if parsed, err := strconv.ParseBool(val); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
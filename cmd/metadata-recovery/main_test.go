@@ -0,0 +1,225 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+
+	"github.com/KafScale/platform/pkg/metadata"
+	"github.com/KafScale/platform/pkg/protocol"
+	"github.com/KafScale/platform/pkg/storage"
+)
+
+func TestRecoveryPlanRebuildsMetadataFromSegments(t *testing.T) {
+	cli := startTestEtcd(t)
+	ctx := context.Background()
+
+	// etcd only remembers one partition of orders and an old offset for it.
+	seed := metadata.ClusterMetadata{
+		Brokers: []protocol.MetadataBroker{{NodeID: 0, Host: "b0", Port: 9092}, {NodeID: 1, Host: "b1", Port: 9092}},
+		Topics: []protocol.MetadataTopic{{
+			Name:       "orders",
+			Partitions: []protocol.MetadataPartition{{PartitionIndex: 0, LeaderID: 0, ReplicaNodes: []int32{0, 1}}},
+		}},
+	}
+	payload, _ := json.Marshal(seed)
+	mustPut(t, cli, snapshotKey, string(payload))
+	mustPut(t, cli, metadata.NextOffsetKey("orders", 0), "1")
+	existing, _ := metadata.EncodeConsumerOffset(7, "")
+	mustPut(t, cli, metadata.ConsumerOffsetKey("g1", "orders", 0), string(existing))
+
+	s3 := storage.NewMemoryS3Client()
+	writeSegments(t, s3, "orders", 0, 3)
+	writeSegments(t, s3, "orders", 1, 1)
+	writeSegments(t, s3, "payments", 0, 2)
+	extents, err := storage.ScanPartitionExtents(ctx, s3, "default")
+	if err != nil {
+		t.Fatalf("ScanPartitionExtents: %v", err)
+	}
+	offsets := []consumerOffsetEntry{
+		{Group: "g1", Topic: "orders", Partition: 0, Offset: 2},
+		{Group: "g2", Topic: "payments", Partition: 0, Offset: 1},
+	}
+
+	changes, err := buildPlan(ctx, cli, extents, offsets)
+	if err != nil {
+		t.Fatalf("buildPlan: %v", err)
+	}
+	var out bytes.Buffer
+	printPlan(&out, changes)
+	for _, line := range []string{
+		"+ /kafscale/metadata/snapshot: grow topic orders partitions 1 -> 2, add topic payments (partitions=1)",
+		"~ /kafscale/topics/orders/partitions/0/next_offset: 1 -> 3",
+		"+ /kafscale/topics/orders/partitions/1/next_offset: 1",
+		"+ /kafscale/topics/payments/config: partitions=1",
+		"+ /kafscale/consumers/g2/offsets/payments/0: offset=1",
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Fatalf("expected plan to contain %q, got:\n%s", line, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "/kafscale/consumers/g1/") {
+		t.Fatalf("expected existing consumer offset to be kept, got:\n%s", out.String())
+	}
+	if err := applyPlan(ctx, cli, changes); err != nil {
+		t.Fatalf("applyPlan: %v", err)
+	}
+
+	snap, err := readSnapshot(ctx, cli)
+	if err != nil {
+		t.Fatalf("readSnapshot: %v", err)
+	}
+	if len(snap.Topics) != 2 || len(snap.Topics[0].Partitions) != 2 || snap.Topics[1].Name != "payments" {
+		t.Fatalf("unexpected snapshot topics: %+v", snap.Topics)
+	}
+	if leader := snap.Topics[0].Partitions[1].LeaderID; leader != 1 {
+		t.Fatalf("expected new partition led by broker 1, got %d", leader)
+	}
+	raw, _ := getValue(ctx, cli, metadata.TopicConfigKey("orders"))
+	cfg, err := metadata.DecodeTopicConfig(raw)
+	if err != nil || cfg.Partitions != 2 || cfg.ReplicationFactor != 2 {
+		t.Fatalf("unexpected orders config: %+v (%v)", cfg, err)
+	}
+	raw, _ = getValue(ctx, cli, metadata.PartitionStateKey("payments", 0))
+	state, err := metadata.DecodePartitionState(raw)
+	if err != nil || state.LogEndOffset != 2 || state.HighWatermark != 2 {
+		t.Fatalf("unexpected payments state: %+v (%v)", state, err)
+	}
+	raw, _ = getValue(ctx, cli, metadata.ConsumerOffsetKey("g1", "orders", 0))
+	if offset, _, _ := metadata.DecodeConsumerOffset(raw); offset != 7 {
+		t.Fatalf("expected g1 offset to stay 7, got %d", offset)
+	}
+
+	changes, err = buildPlan(ctx, cli, extents, offsets)
+	if err != nil {
+		t.Fatalf("buildPlan: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("expected second run to be a no-op, got %+v", changes)
+	}
+}
+
+func TestConsumerOffsetFileRoundTrip(t *testing.T) {
+	cli := startTestEtcd(t)
+	ctx := context.Background()
+	value, _ := metadata.EncodeConsumerOffset(42, "meta")
+	mustPut(t, cli, metadata.ConsumerOffsetKey("g1", "orders", 3), string(value))
+	mustPut(t, cli, metadata.ConsumerGroupKey("g1"), "ignored")
+
+	entries, err := readConsumerOffsets(ctx, cli)
+	if err != nil {
+		t.Fatalf("readConsumerOffsets: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "offsets.json")
+	if err := writeOffsetFile(path, entries); err != nil {
+		t.Fatalf("writeOffsetFile: %v", err)
+	}
+	loaded, err := readOffsetFile(path)
+	if err != nil {
+		t.Fatalf("readOffsetFile: %v", err)
+	}
+	want := consumerOffsetEntry{Group: "g1", Topic: "orders", Partition: 3, Offset: 42, Metadata: "meta"}
+	if len(loaded) != 1 || loaded[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, loaded)
+	}
+}
+
+func writeSegments(t *testing.T, s3 storage.S3Client, topic string, partition int32, batches int) {
+	t.Helper()
+	plog := storage.NewPartitionLog("default", topic, partition, 0, s3, nil, storage.PartitionLogConfig{
+		Buffer:  storage.WriteBufferConfig{MaxBytes: 1, FlushInterval: time.Millisecond},
+		Segment: storage.SegmentWriterConfig{IndexIntervalMessages: 1},
+	}, nil, nil)
+	for i := 0; i < batches; i++ {
+		batch, err := storage.NewRecordBatchFromBytes(make([]byte, 70))
+		if err != nil {
+			t.Fatalf("NewRecordBatchFromBytes: %v", err)
+		}
+		if _, err := plog.AppendBatch(context.Background(), batch); err != nil {
+			t.Fatalf("AppendBatch: %v", err)
+		}
+		if err := plog.Flush(context.Background()); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+	}
+}
+
+func mustPut(t *testing.T, cli *clientv3.Client, key, value string) {
+	t.Helper()
+	if _, err := cli.Put(context.Background(), key, value); err != nil {
+		t.Fatalf("put %s: %v", key, err)
+	}
+}
+
+func startTestEtcd(t *testing.T) *clientv3.Client {
+	t.Helper()
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	cfg.Logger = "zap"
+	clientURL, _ := url.Parse("http://" + freeLocalAddr(t))
+	peerURL, _ := url.Parse("http://" + freeLocalAddr(t))
+	cfg.ListenClientUrls = []url.URL{*clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{*clientURL}
+	cfg.ListenPeerUrls = []url.URL{*peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{*peerURL}
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		if strings.Contains(err.Error(), "operation not permitted") {
+			t.Skipf("skipping embedded etcd test: %v", err)
+		}
+		t.Fatalf("start embedded etcd: %v", err)
+	}
+	t.Cleanup(e.Close)
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatalf("etcd server took too long to start")
+	}
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{fmt.Sprintf("http://%s", e.Clients[0].Addr().String())},
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("etcd client: %v", err)
+	}
+	t.Cleanup(func() { _ = cli.Close() })
+	return cli
+}
+
+func freeLocalAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("skipping embedded etcd test: %v", err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
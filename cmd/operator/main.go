@@ -26,6 +26,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
 	"github.com/KafScale/platform/pkg/operator"
@@ -44,8 +45,10 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var enableWebhooks bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", true, "Enable leader election for controller manager.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", webhooksEnabledFromEnv(), "Serve the KafscaleCluster and KafscaleTopic admission webhooks.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -59,14 +62,30 @@ func main() {
 		Metrics: metricsserver.Options{
 			BindAddress: metricsAddr,
 		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    9443,
+			CertDir: strings.TrimSpace(os.Getenv("KAFSCALE_OPERATOR_WEBHOOK_CERT_DIR")),
+		}),
 		LeaderElection:   enableLeaderElection,
 		LeaderElectionID: leaderElectionID(),
+		Cache:            operator.CacheOptions(),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	if enableWebhooks {
+		if err := kafscalev1alpha1.SetupKafscaleClusterWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "KafscaleCluster")
+			os.Exit(1)
+		}
+		if err := kafscalev1alpha1.SetupKafscaleTopicWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "KafscaleTopic")
+			os.Exit(1)
+		}
+	}
+
 	publisher := operator.NewSnapshotPublisher(mgr.GetClient())
 
 	if err := operator.NewClusterReconciler(mgr, publisher).SetupWithManager(mgr); err != nil {
@@ -79,6 +98,31 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := operator.NewUserReconciler(mgr).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KafscaleUser")
+		os.Exit(1)
+	}
+
+	if err := operator.NewACLReconciler(mgr).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KafscaleACL")
+		os.Exit(1)
+	}
+
+	if err := operator.NewIcebergSinkReconciler(mgr).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KafscaleIcebergSink")
+		os.Exit(1)
+	}
+
+	if err := operator.NewSQLGatewayReconciler(mgr).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KafscaleSQLGateway")
+		os.Exit(1)
+	}
+
+	if err := operator.NewBrokerDrainReconciler(mgr).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BrokerDrain")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -92,3 +136,12 @@ func leaderElectionID() string {
 	}
 	return "kafscale-operator"
 }
+
+func webhooksEnabledFromEnv() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("KAFSCALE_OPERATOR_ENABLE_WEBHOOKS"))) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
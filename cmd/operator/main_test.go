@@ -30,3 +30,12 @@ func TestLeaderElectionIDOverride(t *testing.T) {
 		t.Fatalf("expected override leader election id, got %q", got)
 	}
 }
+
+func TestWebhooksEnabledFromEnv(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "0": false, "1": true, "true": true, "On": true} {
+		t.Setenv("KAFSCALE_OPERATOR_ENABLE_WEBHOOKS", value)
+		if got := webhooksEnabledFromEnv(); got != want {
+			t.Fatalf("KAFSCALE_OPERATOR_ENABLE_WEBHOOKS=%q: expected %v, got %v", value, want, got)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"log"
 	"net"
 	"sync"
+	"sync/atomic"
 
 	"github.com/KafScale/platform/pkg/protocol"
 )
@@ -37,6 +38,7 @@ type Server struct {
 	Handler  Handler
 	listener net.Listener
 	wg       sync.WaitGroup
+	conns    atomic.Int64
 }
 
 // ListenAndServe starts accepting Kafka protocol connections.
@@ -83,6 +85,11 @@ func (s *Server) Wait() {
 	s.wg.Wait()
 }
 
+// ActiveConnections returns the number of open client connections.
+func (s *Server) ActiveConnections() int64 {
+	return s.conns.Load()
+}
+
 // ListenAddress returns the actual listener address if the server has started.
 func (s *Server) ListenAddress() string {
 	if s.listener != nil {
@@ -92,6 +99,8 @@ func (s *Server) ListenAddress() string {
 }
 
 func (s *Server) handleConnection(conn net.Conn) {
+	s.conns.Add(1)
+	defer s.conns.Add(-1)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	defer conn.Close()
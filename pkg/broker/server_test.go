@@ -137,6 +137,31 @@ func TestServerHandleConnection_ApiVersions(t *testing.T) {
 	}
 }
 
+func TestServerCountsActiveConnections(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	s := &Server{Handler: &testHandler{}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.handleConnection(serverConn)
+	}()
+
+	if err := protocol.WriteFrame(clientConn, buildApiVersionsRequest()); err != nil {
+		t.Fatalf("WriteFrame client: %v", err)
+	}
+	if _, err := protocol.ReadFrame(clientConn); err != nil {
+		t.Fatalf("ReadFrame client: %v", err)
+	}
+	if got := s.ActiveConnections(); got != 1 {
+		t.Fatalf("expected 1 active connection, got %d", got)
+	}
+	clientConn.Close()
+	<-done
+	if got := s.ActiveConnections(); got != 0 {
+		t.Fatalf("expected no active connections after close, got %d", got)
+	}
+}
+
 func TestServerHandleConnection_Metadata(t *testing.T) {
 	serverConn, clientConn := net.Pipe()
 	defer clientConn.Close()
@@ -329,6 +329,94 @@ func (x *DrainPartitionsResponse) GetPending() []*PartitionRef {
 	return nil
 }
 
+type UndrainPartitionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Partitions    []*PartitionRef        `protobuf:"bytes,1,rep,name=partitions,proto3" json:"partitions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndrainPartitionsRequest) Reset() {
+	*x = UndrainPartitionsRequest{}
+	mi := &file_control_broker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndrainPartitionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndrainPartitionsRequest) ProtoMessage() {}
+
+func (x *UndrainPartitionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_broker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndrainPartitionsRequest.ProtoReflect.Descriptor instead.
+func (*UndrainPartitionsRequest) Descriptor() ([]byte, []int) {
+	return file_control_broker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UndrainPartitionsRequest) GetPartitions() []*PartitionRef {
+	if x != nil {
+		return x.Partitions
+	}
+	return nil
+}
+
+type UndrainPartitionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Resumed       []*PartitionRef        `protobuf:"bytes,1,rep,name=resumed,proto3" json:"resumed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndrainPartitionsResponse) Reset() {
+	*x = UndrainPartitionsResponse{}
+	mi := &file_control_broker_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndrainPartitionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndrainPartitionsResponse) ProtoMessage() {}
+
+func (x *UndrainPartitionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_control_broker_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndrainPartitionsResponse.ProtoReflect.Descriptor instead.
+func (*UndrainPartitionsResponse) Descriptor() ([]byte, []int) {
+	return file_control_broker_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UndrainPartitionsResponse) GetResumed() []*PartitionRef {
+	if x != nil {
+		return x.Resumed
+	}
+	return nil
+}
+
 type TriggerFlushRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Partitions    []*PartitionRef        `protobuf:"bytes,1,rep,name=partitions,proto3" json:"partitions,omitempty"`
@@ -339,7 +427,7 @@ type TriggerFlushRequest struct {
 
 func (x *TriggerFlushRequest) Reset() {
 	*x = TriggerFlushRequest{}
-	mi := &file_control_broker_proto_msgTypes[5]
+	mi := &file_control_broker_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -351,7 +439,7 @@ func (x *TriggerFlushRequest) String() string {
 func (*TriggerFlushRequest) ProtoMessage() {}
 
 func (x *TriggerFlushRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_broker_proto_msgTypes[5]
+	mi := &file_control_broker_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -364,7 +452,7 @@ func (x *TriggerFlushRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TriggerFlushRequest.ProtoReflect.Descriptor instead.
 func (*TriggerFlushRequest) Descriptor() ([]byte, []int) {
-	return file_control_broker_proto_rawDescGZIP(), []int{5}
+	return file_control_broker_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *TriggerFlushRequest) GetPartitions() []*PartitionRef {
@@ -390,7 +478,7 @@ type TriggerFlushResponse struct {
 
 func (x *TriggerFlushResponse) Reset() {
 	*x = TriggerFlushResponse{}
-	mi := &file_control_broker_proto_msgTypes[6]
+	mi := &file_control_broker_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -402,7 +490,7 @@ func (x *TriggerFlushResponse) String() string {
 func (*TriggerFlushResponse) ProtoMessage() {}
 
 func (x *TriggerFlushResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_control_broker_proto_msgTypes[6]
+	mi := &file_control_broker_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -415,7 +503,7 @@ func (x *TriggerFlushResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TriggerFlushResponse.ProtoReflect.Descriptor instead.
 func (*TriggerFlushResponse) Descriptor() ([]byte, []int) {
-	return file_control_broker_proto_rawDescGZIP(), []int{6}
+	return file_control_broker_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *TriggerFlushResponse) GetFlushed() []*PartitionRef {
@@ -425,6 +513,50 @@ func (x *TriggerFlushResponse) GetFlushed() []*PartitionRef {
 	return nil
 }
 
+type StreamMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IntervalMs    int32                  `protobuf:"varint,1,opt,name=interval_ms,json=intervalMs,proto3" json:"interval_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMetricsRequest) Reset() {
+	*x = StreamMetricsRequest{}
+	mi := &file_control_broker_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMetricsRequest) ProtoMessage() {}
+
+func (x *StreamMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_control_broker_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMetricsRequest.ProtoReflect.Descriptor instead.
+func (*StreamMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_control_broker_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamMetricsRequest) GetIntervalMs() int32 {
+	if x != nil {
+		return x.IntervalMs
+	}
+	return 0
+}
+
 type MetricsSample struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BrokerId      string                 `protobuf:"bytes,1,opt,name=broker_id,json=brokerId,proto3" json:"broker_id,omitempty"`
@@ -437,7 +569,7 @@ type MetricsSample struct {
 
 func (x *MetricsSample) Reset() {
 	*x = MetricsSample{}
-	mi := &file_control_broker_proto_msgTypes[7]
+	mi := &file_control_broker_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -449,7 +581,7 @@ func (x *MetricsSample) String() string {
 func (*MetricsSample) ProtoMessage() {}
 
 func (x *MetricsSample) ProtoReflect() protoreflect.Message {
-	mi := &file_control_broker_proto_msgTypes[7]
+	mi := &file_control_broker_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -462,7 +594,7 @@ func (x *MetricsSample) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MetricsSample.ProtoReflect.Descriptor instead.
 func (*MetricsSample) Descriptor() ([]byte, []int) {
-	return file_control_broker_proto_rawDescGZIP(), []int{7}
+	return file_control_broker_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *MetricsSample) GetBrokerId() string {
@@ -502,7 +634,7 @@ type Ack struct {
 
 func (x *Ack) Reset() {
 	*x = Ack{}
-	mi := &file_control_broker_proto_msgTypes[8]
+	mi := &file_control_broker_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -514,7 +646,7 @@ func (x *Ack) String() string {
 func (*Ack) ProtoMessage() {}
 
 func (x *Ack) ProtoReflect() protoreflect.Message {
-	mi := &file_control_broker_proto_msgTypes[8]
+	mi := &file_control_broker_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -527,7 +659,7 @@ func (x *Ack) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Ack.ProtoReflect.Descriptor instead.
 func (*Ack) Descriptor() ([]byte, []int) {
-	return file_control_broker_proto_rawDescGZIP(), []int{8}
+	return file_control_broker_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Ack) GetMessage() string {
@@ -546,7 +678,7 @@ type AssignmentWatchRequest struct {
 
 func (x *AssignmentWatchRequest) Reset() {
 	*x = AssignmentWatchRequest{}
-	mi := &file_control_broker_proto_msgTypes[9]
+	mi := &file_control_broker_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -558,7 +690,7 @@ func (x *AssignmentWatchRequest) String() string {
 func (*AssignmentWatchRequest) ProtoMessage() {}
 
 func (x *AssignmentWatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_control_broker_proto_msgTypes[9]
+	mi := &file_control_broker_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,7 +703,7 @@ func (x *AssignmentWatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AssignmentWatchRequest.ProtoReflect.Descriptor instead.
 func (*AssignmentWatchRequest) Descriptor() ([]byte, []int) {
-	return file_control_broker_proto_rawDescGZIP(), []int{9}
+	return file_control_broker_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *AssignmentWatchRequest) GetBrokerId() string {
@@ -593,7 +725,7 @@ type PartitionAssignmentEvent struct {
 
 func (x *PartitionAssignmentEvent) Reset() {
 	*x = PartitionAssignmentEvent{}
-	mi := &file_control_broker_proto_msgTypes[10]
+	mi := &file_control_broker_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -605,7 +737,7 @@ func (x *PartitionAssignmentEvent) String() string {
 func (*PartitionAssignmentEvent) ProtoMessage() {}
 
 func (x *PartitionAssignmentEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_control_broker_proto_msgTypes[10]
+	mi := &file_control_broker_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -618,7 +750,7 @@ func (x *PartitionAssignmentEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PartitionAssignmentEvent.ProtoReflect.Descriptor instead.
 func (*PartitionAssignmentEvent) Descriptor() ([]byte, []int) {
-	return file_control_broker_proto_rawDescGZIP(), []int{10}
+	return file_control_broker_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *PartitionAssignmentEvent) GetPartition() *PartitionRef {
@@ -659,7 +791,7 @@ type PartitionRef struct {
 
 func (x *PartitionRef) Reset() {
 	*x = PartitionRef{}
-	mi := &file_control_broker_proto_msgTypes[11]
+	mi := &file_control_broker_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -671,7 +803,7 @@ func (x *PartitionRef) String() string {
 func (*PartitionRef) ProtoMessage() {}
 
 func (x *PartitionRef) ProtoReflect() protoreflect.Message {
-	mi := &file_control_broker_proto_msgTypes[11]
+	mi := &file_control_broker_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -684,7 +816,7 @@ func (x *PartitionRef) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PartitionRef.ProtoReflect.Descriptor instead.
 func (*PartitionRef) Descriptor() ([]byte, []int) {
-	return file_control_broker_proto_rawDescGZIP(), []int{11}
+	return file_control_broker_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *PartitionRef) GetTopic() string {
@@ -730,14 +862,23 @@ const file_control_broker_proto_rawDesc = "" +
 	"\x10deadline_seconds\x18\x03 \x01(\x05R\x0fdeadlineSeconds\"\x8d\x01\n" +
 	"\x17DrainPartitionsResponse\x128\n" +
 	"\adrained\x18\x01 \x03(\v2\x1e.kafscale.control.PartitionRefR\adrained\x128\n" +
-	"\apending\x18\x02 \x03(\v2\x1e.kafscale.control.PartitionRefR\apending\"x\n" +
+	"\apending\x18\x02 \x03(\v2\x1e.kafscale.control.PartitionRefR\apending\"Z\n" +
+	"\x18UndrainPartitionsRequest\x12>\n" +
+	"\n" +
+	"partitions\x18\x01 \x03(\v2\x1e.kafscale.control.PartitionRefR\n" +
+	"partitions\"U\n" +
+	"\x19UndrainPartitionsResponse\x128\n" +
+	"\aresumed\x18\x01 \x03(\v2\x1e.kafscale.control.PartitionRefR\aresumed\"x\n" +
 	"\x13TriggerFlushRequest\x12>\n" +
 	"\n" +
 	"partitions\x18\x01 \x03(\v2\x1e.kafscale.control.PartitionRefR\n" +
 	"partitions\x12!\n" +
 	"\fall_assigned\x18\x02 \x01(\bR\vallAssigned\"P\n" +
 	"\x14TriggerFlushResponse\x128\n" +
-	"\aflushed\x18\x01 \x03(\v2\x1e.kafscale.control.PartitionRefR\aflushed\"\xd7\x02\n" +
+	"\aflushed\x18\x01 \x03(\v2\x1e.kafscale.control.PartitionRefR\aflushed\"7\n" +
+	"\x14StreamMetricsRequest\x12\x1f\n" +
+	"\vinterval_ms\x18\x01 \x01(\x05R\n" +
+	"intervalMs\"\xd7\x02\n" +
 	"\rMetricsSample\x12\x1b\n" +
 	"\tbroker_id\x18\x01 \x01(\tR\bbrokerId\x12!\n" +
 	"\ftimestamp_ms\x18\x02 \x01(\x03R\vtimestampMs\x12C\n" +
@@ -760,12 +901,13 @@ const file_control_broker_proto_rawDesc = "" +
 	"\x05epoch\x18\x04 \x01(\x05R\x05epoch\"B\n" +
 	"\fPartitionRef\x12\x14\n" +
 	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x1c\n" +
-	"\tpartition\x18\x02 \x01(\x05R\tpartition2\xfd\x02\n" +
+	"\tpartition\x18\x02 \x01(\x05R\tpartition2\xfc\x03\n" +
 	"\rBrokerControl\x12Z\n" +
 	"\tGetStatus\x12%.kafscale.control.BrokerStatusRequest\x1a&.kafscale.control.BrokerStatusResponse\x12f\n" +
-	"\x0fDrainPartitions\x12(.kafscale.control.DrainPartitionsRequest\x1a).kafscale.control.DrainPartitionsResponse\x12]\n" +
-	"\fTriggerFlush\x12%.kafscale.control.TriggerFlushRequest\x1a&.kafscale.control.TriggerFlushResponse\x12I\n" +
-	"\rStreamMetrics\x12\x1f.kafscale.control.MetricsSample\x1a\x15.kafscale.control.Ack(\x012~\n" +
+	"\x0fDrainPartitions\x12(.kafscale.control.DrainPartitionsRequest\x1a).kafscale.control.DrainPartitionsResponse\x12l\n" +
+	"\x11UndrainPartitions\x12*.kafscale.control.UndrainPartitionsRequest\x1a+.kafscale.control.UndrainPartitionsResponse\x12]\n" +
+	"\fTriggerFlush\x12%.kafscale.control.TriggerFlushRequest\x1a&.kafscale.control.TriggerFlushResponse\x12Z\n" +
+	"\rStreamMetrics\x12&.kafscale.control.StreamMetricsRequest\x1a\x1f.kafscale.control.MetricsSample0\x012~\n" +
 	"\x10AssignmentStream\x12j\n" +
 	"\x10WatchAssignments\x12(.kafscale.control.AssignmentWatchRequest\x1a*.kafscale.control.PartitionAssignmentEvent0\x01B\xad\x01\n" +
 	"\x14com.kafscale.controlB\vBrokerProtoP\x01Z'github.com/alo/kafscale/pkg/gen/control\xa2\x02\x03KCX\xaa\x02\x10Kafscale.Control\xca\x02\x10Kafscale\\Control\xe2\x02\x1cKafscale\\Control\\GPBMetadata\xea\x02\x11Kafscale::Controlb\x06proto3"
@@ -782,48 +924,55 @@ func file_control_broker_proto_rawDescGZIP() []byte {
 	return file_control_broker_proto_rawDescData
 }
 
-var file_control_broker_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_control_broker_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
 var file_control_broker_proto_goTypes = []any{
-	(*BrokerStatusRequest)(nil),      // 0: kafscale.control.BrokerStatusRequest
-	(*BrokerStatusResponse)(nil),     // 1: kafscale.control.BrokerStatusResponse
-	(*PartitionStatus)(nil),          // 2: kafscale.control.PartitionStatus
-	(*DrainPartitionsRequest)(nil),   // 3: kafscale.control.DrainPartitionsRequest
-	(*DrainPartitionsResponse)(nil),  // 4: kafscale.control.DrainPartitionsResponse
-	(*TriggerFlushRequest)(nil),      // 5: kafscale.control.TriggerFlushRequest
-	(*TriggerFlushResponse)(nil),     // 6: kafscale.control.TriggerFlushResponse
-	(*MetricsSample)(nil),            // 7: kafscale.control.MetricsSample
-	(*Ack)(nil),                      // 8: kafscale.control.Ack
-	(*AssignmentWatchRequest)(nil),   // 9: kafscale.control.AssignmentWatchRequest
-	(*PartitionAssignmentEvent)(nil), // 10: kafscale.control.PartitionAssignmentEvent
-	(*PartitionRef)(nil),             // 11: kafscale.control.PartitionRef
-	nil,                              // 12: kafscale.control.MetricsSample.GaugesEntry
-	nil,                              // 13: kafscale.control.MetricsSample.CountersEntry
+	(*BrokerStatusRequest)(nil),       // 0: kafscale.control.BrokerStatusRequest
+	(*BrokerStatusResponse)(nil),      // 1: kafscale.control.BrokerStatusResponse
+	(*PartitionStatus)(nil),           // 2: kafscale.control.PartitionStatus
+	(*DrainPartitionsRequest)(nil),    // 3: kafscale.control.DrainPartitionsRequest
+	(*DrainPartitionsResponse)(nil),   // 4: kafscale.control.DrainPartitionsResponse
+	(*UndrainPartitionsRequest)(nil),  // 5: kafscale.control.UndrainPartitionsRequest
+	(*UndrainPartitionsResponse)(nil), // 6: kafscale.control.UndrainPartitionsResponse
+	(*TriggerFlushRequest)(nil),       // 7: kafscale.control.TriggerFlushRequest
+	(*TriggerFlushResponse)(nil),      // 8: kafscale.control.TriggerFlushResponse
+	(*StreamMetricsRequest)(nil),      // 9: kafscale.control.StreamMetricsRequest
+	(*MetricsSample)(nil),             // 10: kafscale.control.MetricsSample
+	(*Ack)(nil),                       // 11: kafscale.control.Ack
+	(*AssignmentWatchRequest)(nil),    // 12: kafscale.control.AssignmentWatchRequest
+	(*PartitionAssignmentEvent)(nil),  // 13: kafscale.control.PartitionAssignmentEvent
+	(*PartitionRef)(nil),              // 14: kafscale.control.PartitionRef
+	nil,                               // 15: kafscale.control.MetricsSample.GaugesEntry
+	nil,                               // 16: kafscale.control.MetricsSample.CountersEntry
 }
 var file_control_broker_proto_depIdxs = []int32{
 	2,  // 0: kafscale.control.BrokerStatusResponse.partitions:type_name -> kafscale.control.PartitionStatus
-	11, // 1: kafscale.control.DrainPartitionsRequest.partitions:type_name -> kafscale.control.PartitionRef
-	11, // 2: kafscale.control.DrainPartitionsResponse.drained:type_name -> kafscale.control.PartitionRef
-	11, // 3: kafscale.control.DrainPartitionsResponse.pending:type_name -> kafscale.control.PartitionRef
-	11, // 4: kafscale.control.TriggerFlushRequest.partitions:type_name -> kafscale.control.PartitionRef
-	11, // 5: kafscale.control.TriggerFlushResponse.flushed:type_name -> kafscale.control.PartitionRef
-	12, // 6: kafscale.control.MetricsSample.gauges:type_name -> kafscale.control.MetricsSample.GaugesEntry
-	13, // 7: kafscale.control.MetricsSample.counters:type_name -> kafscale.control.MetricsSample.CountersEntry
-	11, // 8: kafscale.control.PartitionAssignmentEvent.partition:type_name -> kafscale.control.PartitionRef
-	0,  // 9: kafscale.control.BrokerControl.GetStatus:input_type -> kafscale.control.BrokerStatusRequest
-	3,  // 10: kafscale.control.BrokerControl.DrainPartitions:input_type -> kafscale.control.DrainPartitionsRequest
-	5,  // 11: kafscale.control.BrokerControl.TriggerFlush:input_type -> kafscale.control.TriggerFlushRequest
-	7,  // 12: kafscale.control.BrokerControl.StreamMetrics:input_type -> kafscale.control.MetricsSample
-	9,  // 13: kafscale.control.AssignmentStream.WatchAssignments:input_type -> kafscale.control.AssignmentWatchRequest
-	1,  // 14: kafscale.control.BrokerControl.GetStatus:output_type -> kafscale.control.BrokerStatusResponse
-	4,  // 15: kafscale.control.BrokerControl.DrainPartitions:output_type -> kafscale.control.DrainPartitionsResponse
-	6,  // 16: kafscale.control.BrokerControl.TriggerFlush:output_type -> kafscale.control.TriggerFlushResponse
-	8,  // 17: kafscale.control.BrokerControl.StreamMetrics:output_type -> kafscale.control.Ack
-	10, // 18: kafscale.control.AssignmentStream.WatchAssignments:output_type -> kafscale.control.PartitionAssignmentEvent
-	14, // [14:19] is the sub-list for method output_type
-	9,  // [9:14] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	14, // 1: kafscale.control.DrainPartitionsRequest.partitions:type_name -> kafscale.control.PartitionRef
+	14, // 2: kafscale.control.DrainPartitionsResponse.drained:type_name -> kafscale.control.PartitionRef
+	14, // 3: kafscale.control.DrainPartitionsResponse.pending:type_name -> kafscale.control.PartitionRef
+	14, // 4: kafscale.control.UndrainPartitionsRequest.partitions:type_name -> kafscale.control.PartitionRef
+	14, // 5: kafscale.control.UndrainPartitionsResponse.resumed:type_name -> kafscale.control.PartitionRef
+	14, // 6: kafscale.control.TriggerFlushRequest.partitions:type_name -> kafscale.control.PartitionRef
+	14, // 7: kafscale.control.TriggerFlushResponse.flushed:type_name -> kafscale.control.PartitionRef
+	15, // 8: kafscale.control.MetricsSample.gauges:type_name -> kafscale.control.MetricsSample.GaugesEntry
+	16, // 9: kafscale.control.MetricsSample.counters:type_name -> kafscale.control.MetricsSample.CountersEntry
+	14, // 10: kafscale.control.PartitionAssignmentEvent.partition:type_name -> kafscale.control.PartitionRef
+	0,  // 11: kafscale.control.BrokerControl.GetStatus:input_type -> kafscale.control.BrokerStatusRequest
+	3,  // 12: kafscale.control.BrokerControl.DrainPartitions:input_type -> kafscale.control.DrainPartitionsRequest
+	5,  // 13: kafscale.control.BrokerControl.UndrainPartitions:input_type -> kafscale.control.UndrainPartitionsRequest
+	7,  // 14: kafscale.control.BrokerControl.TriggerFlush:input_type -> kafscale.control.TriggerFlushRequest
+	9,  // 15: kafscale.control.BrokerControl.StreamMetrics:input_type -> kafscale.control.StreamMetricsRequest
+	12, // 16: kafscale.control.AssignmentStream.WatchAssignments:input_type -> kafscale.control.AssignmentWatchRequest
+	1,  // 17: kafscale.control.BrokerControl.GetStatus:output_type -> kafscale.control.BrokerStatusResponse
+	4,  // 18: kafscale.control.BrokerControl.DrainPartitions:output_type -> kafscale.control.DrainPartitionsResponse
+	6,  // 19: kafscale.control.BrokerControl.UndrainPartitions:output_type -> kafscale.control.UndrainPartitionsResponse
+	8,  // 20: kafscale.control.BrokerControl.TriggerFlush:output_type -> kafscale.control.TriggerFlushResponse
+	10, // 21: kafscale.control.BrokerControl.StreamMetrics:output_type -> kafscale.control.MetricsSample
+	13, // 22: kafscale.control.AssignmentStream.WatchAssignments:output_type -> kafscale.control.PartitionAssignmentEvent
+	17, // [17:23] is the sub-list for method output_type
+	11, // [11:17] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_control_broker_proto_init() }
@@ -837,7 +986,7 @@ func file_control_broker_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_control_broker_proto_rawDesc), len(file_control_broker_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   17,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -19,10 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	BrokerControl_GetStatus_FullMethodName       = "/kafscale.control.BrokerControl/GetStatus"
-	BrokerControl_DrainPartitions_FullMethodName = "/kafscale.control.BrokerControl/DrainPartitions"
-	BrokerControl_TriggerFlush_FullMethodName    = "/kafscale.control.BrokerControl/TriggerFlush"
-	BrokerControl_StreamMetrics_FullMethodName   = "/kafscale.control.BrokerControl/StreamMetrics"
+	BrokerControl_GetStatus_FullMethodName         = "/kafscale.control.BrokerControl/GetStatus"
+	BrokerControl_DrainPartitions_FullMethodName   = "/kafscale.control.BrokerControl/DrainPartitions"
+	BrokerControl_UndrainPartitions_FullMethodName = "/kafscale.control.BrokerControl/UndrainPartitions"
+	BrokerControl_TriggerFlush_FullMethodName      = "/kafscale.control.BrokerControl/TriggerFlush"
+	BrokerControl_StreamMetrics_FullMethodName     = "/kafscale.control.BrokerControl/StreamMetrics"
 )
 
 // BrokerControlClient is the client API for BrokerControl service.
@@ -31,8 +32,9 @@ const (
 type BrokerControlClient interface {
 	GetStatus(ctx context.Context, in *BrokerStatusRequest, opts ...grpc.CallOption) (*BrokerStatusResponse, error)
 	DrainPartitions(ctx context.Context, in *DrainPartitionsRequest, opts ...grpc.CallOption) (*DrainPartitionsResponse, error)
+	UndrainPartitions(ctx context.Context, in *UndrainPartitionsRequest, opts ...grpc.CallOption) (*UndrainPartitionsResponse, error)
 	TriggerFlush(ctx context.Context, in *TriggerFlushRequest, opts ...grpc.CallOption) (*TriggerFlushResponse, error)
-	StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MetricsSample, Ack], error)
+	StreamMetrics(ctx context.Context, in *StreamMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MetricsSample], error)
 }
 
 type brokerControlClient struct {
@@ -63,6 +65,16 @@ func (c *brokerControlClient) DrainPartitions(ctx context.Context, in *DrainPart
 	return out, nil
 }
 
+func (c *brokerControlClient) UndrainPartitions(ctx context.Context, in *UndrainPartitionsRequest, opts ...grpc.CallOption) (*UndrainPartitionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UndrainPartitionsResponse)
+	err := c.cc.Invoke(ctx, BrokerControl_UndrainPartitions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *brokerControlClient) TriggerFlush(ctx context.Context, in *TriggerFlushRequest, opts ...grpc.CallOption) (*TriggerFlushResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TriggerFlushResponse)
@@ -73,18 +85,24 @@ func (c *brokerControlClient) TriggerFlush(ctx context.Context, in *TriggerFlush
 	return out, nil
 }
 
-func (c *brokerControlClient) StreamMetrics(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MetricsSample, Ack], error) {
+func (c *brokerControlClient) StreamMetrics(ctx context.Context, in *StreamMetricsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[MetricsSample], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &BrokerControl_ServiceDesc.Streams[0], BrokerControl_StreamMetrics_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[MetricsSample, Ack]{ClientStream: stream}
+	x := &grpc.GenericClientStream[StreamMetricsRequest, MetricsSample]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type BrokerControl_StreamMetricsClient = grpc.ClientStreamingClient[MetricsSample, Ack]
+type BrokerControl_StreamMetricsClient = grpc.ServerStreamingClient[MetricsSample]
 
 // BrokerControlServer is the server API for BrokerControl service.
 // All implementations must embed UnimplementedBrokerControlServer
@@ -92,8 +110,9 @@ type BrokerControl_StreamMetricsClient = grpc.ClientStreamingClient[MetricsSampl
 type BrokerControlServer interface {
 	GetStatus(context.Context, *BrokerStatusRequest) (*BrokerStatusResponse, error)
 	DrainPartitions(context.Context, *DrainPartitionsRequest) (*DrainPartitionsResponse, error)
+	UndrainPartitions(context.Context, *UndrainPartitionsRequest) (*UndrainPartitionsResponse, error)
 	TriggerFlush(context.Context, *TriggerFlushRequest) (*TriggerFlushResponse, error)
-	StreamMetrics(grpc.ClientStreamingServer[MetricsSample, Ack]) error
+	StreamMetrics(*StreamMetricsRequest, grpc.ServerStreamingServer[MetricsSample]) error
 	mustEmbedUnimplementedBrokerControlServer()
 }
 
@@ -110,10 +129,13 @@ func (UnimplementedBrokerControlServer) GetStatus(context.Context, *BrokerStatus
 func (UnimplementedBrokerControlServer) DrainPartitions(context.Context, *DrainPartitionsRequest) (*DrainPartitionsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method DrainPartitions not implemented")
 }
+func (UnimplementedBrokerControlServer) UndrainPartitions(context.Context, *UndrainPartitionsRequest) (*UndrainPartitionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UndrainPartitions not implemented")
+}
 func (UnimplementedBrokerControlServer) TriggerFlush(context.Context, *TriggerFlushRequest) (*TriggerFlushResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method TriggerFlush not implemented")
 }
-func (UnimplementedBrokerControlServer) StreamMetrics(grpc.ClientStreamingServer[MetricsSample, Ack]) error {
+func (UnimplementedBrokerControlServer) StreamMetrics(*StreamMetricsRequest, grpc.ServerStreamingServer[MetricsSample]) error {
 	return status.Error(codes.Unimplemented, "method StreamMetrics not implemented")
 }
 func (UnimplementedBrokerControlServer) mustEmbedUnimplementedBrokerControlServer() {}
@@ -173,6 +195,24 @@ func _BrokerControl_DrainPartitions_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BrokerControl_UndrainPartitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndrainPartitionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerControlServer).UndrainPartitions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BrokerControl_UndrainPartitions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerControlServer).UndrainPartitions(ctx, req.(*UndrainPartitionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BrokerControl_TriggerFlush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TriggerFlushRequest)
 	if err := dec(in); err != nil {
@@ -192,11 +232,15 @@ func _BrokerControl_TriggerFlush_Handler(srv interface{}, ctx context.Context, d
 }
 
 func _BrokerControl_StreamMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(BrokerControlServer).StreamMetrics(&grpc.GenericServerStream[MetricsSample, Ack]{ServerStream: stream})
+	m := new(StreamMetricsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BrokerControlServer).StreamMetrics(m, &grpc.GenericServerStream[StreamMetricsRequest, MetricsSample]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type BrokerControl_StreamMetricsServer = grpc.ClientStreamingServer[MetricsSample, Ack]
+type BrokerControl_StreamMetricsServer = grpc.ServerStreamingServer[MetricsSample]
 
 // BrokerControl_ServiceDesc is the grpc.ServiceDesc for BrokerControl service.
 // It's only intended for direct use with grpc.RegisterService,
@@ -213,6 +257,10 @@ var BrokerControl_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DrainPartitions",
 			Handler:    _BrokerControl_DrainPartitions_Handler,
 		},
+		{
+			MethodName: "UndrainPartitions",
+			Handler:    _BrokerControl_UndrainPartitions_Handler,
+		},
 		{
 			MethodName: "TriggerFlush",
 			Handler:    _BrokerControl_TriggerFlush_Handler,
@@ -222,7 +270,7 @@ var BrokerControl_ServiceDesc = grpc.ServiceDesc{
 		{
 			StreamName:    "StreamMetrics",
 			Handler:       _BrokerControl_StreamMetrics_Handler,
-			ClientStreams: true,
+			ServerStreams: true,
 		},
 	},
 	Metadata: "control/broker.proto",
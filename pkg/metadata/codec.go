@@ -42,6 +42,11 @@ func PartitionStateKey(topic string, partition int32) string {
 	return fmt.Sprintf("%s/%s/partitions/%d", topicConfigPrefix, topic, partition)
 }
 
+// NextOffsetKey returns the etcd key holding the next offset a partition will assign.
+func NextOffsetKey(topic string, partition int32) string {
+	return fmt.Sprintf("%s/next_offset", PartitionStateKey(topic, partition))
+}
+
 // ConsumerGroupKey returns the etcd key for a consumer group metadata blob.
 func ConsumerGroupKey(groupID string) string {
 	return fmt.Sprintf("%s/%s/metadata", consumerGroupPrefix, groupID)
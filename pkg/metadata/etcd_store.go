@@ -54,6 +54,24 @@ type consumerOffsetRecord struct {
 	CommittedAt string `json:"committed_at"`
 }
 
+// EncodeConsumerOffset serializes a committed consumer offset as stored in etcd.
+func EncodeConsumerOffset(offset int64, metadata string) ([]byte, error) {
+	return json.Marshal(consumerOffsetRecord{
+		Offset:      offset,
+		Metadata:    metadata,
+		CommittedAt: time.Now().UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// DecodeConsumerOffset parses a committed consumer offset stored in etcd.
+func DecodeConsumerOffset(data []byte) (int64, string, error) {
+	var rec consumerOffsetRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return 0, "", err
+	}
+	return rec.Offset, rec.Metadata, nil
+}
+
 // NewEtcdStore initializes a store backed by etcd.
 func NewEtcdStore(ctx context.Context, snapshot ClusterMetadata, cfg EtcdStoreConfig) (*EtcdStore, error) {
 	if len(cfg.Endpoints) == 0 {
@@ -145,7 +163,7 @@ func (s *EtcdStore) UpdateOffsets(ctx context.Context, topic string, partition i
 }
 
 func offsetKey(topic string, partition int32) string {
-	return fmt.Sprintf("/kafscale/topics/%s/partitions/%d/next_offset", topic, partition)
+	return NextOffsetKey(topic, partition)
 }
 
 func consumerOffsetKey(group, topic string, partition int32) string {
@@ -156,12 +174,7 @@ func consumerOffsetKey(group, topic string, partition int32) string {
 func (s *EtcdStore) CommitConsumerOffset(ctx context.Context, group, topic string, partition int32, offset int64, metadata string) error {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
-	rec := consumerOffsetRecord{
-		Offset:      offset,
-		Metadata:    metadata,
-		CommittedAt: time.Now().UTC().Format(time.RFC3339Nano),
-	}
-	bytes, err := json.Marshal(rec)
+	bytes, err := EncodeConsumerOffset(offset, metadata)
 	if err != nil {
 		return err
 	}
@@ -183,11 +196,7 @@ func (s *EtcdStore) FetchConsumerOffset(ctx context.Context, group, topic string
 	if len(resp.Kvs) == 0 {
 		return 0, "", nil
 	}
-	var rec consumerOffsetRecord
-	if err := json.Unmarshal(resp.Kvs[0].Value, &rec); err != nil {
-		return 0, "", err
-	}
-	return rec.Offset, rec.Metadata, nil
+	return DecodeConsumerOffset(resp.Kvs[0].Value)
 }
 
 // ListConsumerOffsets returns all committed offsets stored in etcd.
@@ -350,8 +359,8 @@ func (s *EtcdStore) CreatePartitions(ctx context.Context, topic string, partitio
 		return ErrUnknownTopic
 	}
 	current := int32(len(meta.Topics[0].Partitions))
-	if partitionCount <= current {
-		return ErrInvalidTopic
+	if err := ValidatePartitionIncrease(current, partitionCount); err != nil {
+		return err
 	}
 	if err := s.metadata.CreatePartitions(ctx, topic, partitionCount); err != nil {
 		return err
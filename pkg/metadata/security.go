@@ -0,0 +1,202 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+const (
+	securityUserPrefix = "/kafscale/security/users"
+	securityACLPrefix  = "/kafscale/security/acls"
+
+	// ScramSHA256 and ScramSHA512 are the SASL mechanism names clients use.
+	ScramSHA256 = "SCRAM-SHA-256"
+	ScramSHA512 = "SCRAM-SHA-512"
+
+	// DefaultScramIterations matches the Kafka default for new credentials.
+	DefaultScramIterations = 4096
+)
+
+// ErrInvalidACL is returned when an ACL binding cannot be enforced.
+var ErrInvalidACL = errors.New("invalid acl")
+
+// UserCredentialKey returns the etcd key holding a user's credentials.
+func UserCredentialKey(user string) string {
+	return fmt.Sprintf("%s/%s", securityUserPrefix, user)
+}
+
+// UserCredentialPrefix returns the etcd prefix for all user credentials.
+func UserCredentialPrefix() string {
+	return securityUserPrefix + "/"
+}
+
+// ACLKey returns the etcd key holding a named set of ACL bindings.
+func ACLKey(name string) string {
+	return fmt.Sprintf("%s/%s", securityACLPrefix, name)
+}
+
+// ACLPrefix returns the etcd prefix for all ACL sets.
+func ACLPrefix() string {
+	return securityACLPrefix + "/"
+}
+
+// UserCredential is the JSON record brokers read to authenticate a user.
+// A user authenticates either with SCRAM or with a TLS client certificate
+// whose subject matches TLSSubject; both map to the principal User:<Name>.
+type UserCredential struct {
+	Name       string           `json:"name"`
+	Scram      *ScramCredential `json:"scram,omitempty"`
+	TLSSubject string           `json:"tlsSubject,omitempty"`
+}
+
+// ScramCredential stores the RFC 5802 server-side keys; the password itself
+// is never written to etcd.
+type ScramCredential struct {
+	Mechanism  string `json:"mechanism"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	StoredKey  []byte `json:"storedKey"`
+	ServerKey  []byte `json:"serverKey"`
+}
+
+// NewScramCredential derives SCRAM keys for password. A random salt is
+// generated when salt is empty.
+func NewScramCredential(mechanism, password string, salt []byte, iterations int) (*ScramCredential, error) {
+	newHash, err := scramHash(mechanism)
+	if err != nil {
+		return nil, err
+	}
+	if iterations <= 0 {
+		iterations = DefaultScramIterations
+	}
+	if len(salt) == 0 {
+		salt = make([]byte, 24)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+	}
+	salted, err := pbkdf2.Key(newHash, password, salt, iterations, newHash().Size())
+	if err != nil {
+		return nil, err
+	}
+	clientKey := scramHMAC(newHash, salted, "Client Key")
+	h := newHash()
+	h.Write(clientKey)
+	return &ScramCredential{
+		Mechanism:  mechanism,
+		Iterations: iterations,
+		Salt:       append([]byte(nil), salt...),
+		StoredKey:  h.Sum(nil),
+		ServerKey:  scramHMAC(newHash, salted, "Server Key"),
+	}, nil
+}
+
+// Matches reports whether password derives the same keys as the credential.
+func (c *ScramCredential) Matches(password string) bool {
+	if c == nil {
+		return false
+	}
+	derived, err := NewScramCredential(c.Mechanism, password, c.Salt, c.Iterations)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(derived.StoredKey, c.StoredKey) && hmac.Equal(derived.ServerKey, c.ServerKey)
+}
+
+func scramHash(mechanism string) (func() hash.Hash, error) {
+	switch mechanism {
+	case ScramSHA256:
+		return sha256.New, nil
+	case ScramSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported scram mechanism %q", mechanism)
+	}
+}
+
+func scramHMAC(newHash func() hash.Hash, key []byte, msg string) []byte {
+	mac := hmac.New(newHash, key)
+	mac.Write([]byte(msg))
+	return mac.Sum(nil)
+}
+
+// ACLSet is the JSON record for one group of ACL bindings, stored under
+// ACLKey(Name).
+type ACLSet struct {
+	Name     string       `json:"name"`
+	Bindings []ACLBinding `json:"bindings"`
+}
+
+// ACLBinding grants or denies one operation on a resource pattern to a
+// principal, following Kafka's ACL model.
+type ACLBinding struct {
+	Principal    string `json:"principal"`
+	Host         string `json:"host"`
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	PatternType  string `json:"patternType"`
+	Operation    string `json:"operation"`
+	Permission   string `json:"permission"`
+}
+
+var (
+	aclResourceTypes = map[string]struct{}{"Topic": {}, "Group": {}, "Cluster": {}, "TransactionalId": {}}
+	aclPatternTypes  = map[string]struct{}{"Literal": {}, "Prefixed": {}}
+	aclPermissions   = map[string]struct{}{"Allow": {}, "Deny": {}}
+	aclOperations    = map[string]struct{}{
+		"All": {}, "Read": {}, "Write": {}, "Create": {}, "Delete": {}, "Alter": {}, "Describe": {},
+		"ClusterAction": {}, "DescribeConfigs": {}, "AlterConfigs": {}, "IdempotentWrite": {},
+	}
+)
+
+// ValidateACLBinding checks that a binding uses known resource types,
+// pattern types, operations and permissions.
+func ValidateACLBinding(b ACLBinding) error {
+	if !strings.HasPrefix(b.Principal, "User:") || len(b.Principal) == len("User:") {
+		return fmt.Errorf("%w: principal %q must be User:<name>", ErrInvalidACL, b.Principal)
+	}
+	if _, ok := aclResourceTypes[b.ResourceType]; !ok {
+		return fmt.Errorf("%w: unknown resource type %q", ErrInvalidACL, b.ResourceType)
+	}
+	if b.ResourceName == "" {
+		return fmt.Errorf("%w: resource name required", ErrInvalidACL)
+	}
+	if b.ResourceType == "Cluster" && b.ResourceName != "kafka-cluster" {
+		return fmt.Errorf("%w: cluster resource name must be kafka-cluster", ErrInvalidACL)
+	}
+	if _, ok := aclPatternTypes[b.PatternType]; !ok {
+		return fmt.Errorf("%w: unknown pattern type %q", ErrInvalidACL, b.PatternType)
+	}
+	if _, ok := aclOperations[b.Operation]; !ok {
+		return fmt.Errorf("%w: unknown operation %q", ErrInvalidACL, b.Operation)
+	}
+	if _, ok := aclPermissions[b.Permission]; !ok {
+		return fmt.Errorf("%w: unknown permission %q", ErrInvalidACL, b.Permission)
+	}
+	if b.Host == "" {
+		return fmt.Errorf("%w: host required", ErrInvalidACL)
+	}
+	return nil
+}
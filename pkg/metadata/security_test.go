@@ -0,0 +1,91 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestNewScramCredentialRFC7677(t *testing.T) {
+	salt, _ := base64.StdEncoding.DecodeString("W22ZaJ0SNY7soEsUEjb6gQ==")
+	cred, err := NewScramCredential(ScramSHA256, "pencil", salt, 4096)
+	if err != nil {
+		t.Fatalf("NewScramCredential: %v", err)
+	}
+	authMessage := "n=user,r=rOprNGfwEbeRWgbNEkqO," +
+		"r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096," +
+		"c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0"
+	mac := hmac.New(sha256.New, cred.ServerKey)
+	mac.Write([]byte(authMessage))
+	if got := base64.StdEncoding.EncodeToString(mac.Sum(nil)); got != "6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=" {
+		t.Fatalf("unexpected server signature %s", got)
+	}
+	if !cred.Matches("pencil") || cred.Matches("pen") {
+		t.Fatalf("Matches did not recognise the password")
+	}
+}
+
+func TestNewScramCredentialRandomSalt(t *testing.T) {
+	a, err := NewScramCredential(ScramSHA512, "secret", nil, 0)
+	if err != nil {
+		t.Fatalf("NewScramCredential: %v", err)
+	}
+	b, err := NewScramCredential(ScramSHA512, "secret", nil, 0)
+	if err != nil {
+		t.Fatalf("NewScramCredential: %v", err)
+	}
+	if a.Iterations != DefaultScramIterations || len(a.Salt) == 0 || string(a.Salt) == string(b.Salt) {
+		t.Fatalf("expected distinct random salts with default iterations: %+v %+v", a, b)
+	}
+	if _, err := NewScramCredential("PLAIN", "secret", nil, 0); err == nil {
+		t.Fatalf("expected unsupported mechanism error")
+	}
+}
+
+func TestValidateACLBinding(t *testing.T) {
+	valid := ACLBinding{
+		Principal:    "User:alice",
+		Host:         "*",
+		ResourceType: "Topic",
+		ResourceName: "orders",
+		PatternType:  "Literal",
+		Operation:    "Read",
+		Permission:   "Allow",
+	}
+	if err := ValidateACLBinding(valid); err != nil {
+		t.Fatalf("expected valid binding: %v", err)
+	}
+	for name, mutate := range map[string]func(*ACLBinding){
+		"principal":  func(b *ACLBinding) { b.Principal = "alice" },
+		"resource":   func(b *ACLBinding) { b.ResourceType = "Broker" },
+		"name":       func(b *ACLBinding) { b.ResourceName = "" },
+		"cluster":    func(b *ACLBinding) { b.ResourceType = "Cluster" },
+		"pattern":    func(b *ACLBinding) { b.PatternType = "Match" },
+		"operation":  func(b *ACLBinding) { b.Operation = "Produce" },
+		"permission": func(b *ACLBinding) { b.Permission = "Maybe" },
+		"empty host": func(b *ACLBinding) { b.Host = "" },
+	} {
+		b := valid
+		mutate(&b)
+		if err := ValidateACLBinding(b); !errors.Is(err, ErrInvalidACL) {
+			t.Fatalf("%s: expected ErrInvalidACL, got %v", name, err)
+		}
+	}
+}
@@ -94,6 +94,15 @@ type ClusterMetadata struct {
 	Topics       []protocol.MetadataTopic
 	ClusterName  *string
 	ClusterID    *string
+	// Proxy is the client-facing endpoint of the operator-managed proxy, set
+	// once the proxy is exposed. Brokers keep routing to Brokers.
+	Proxy *ProxyEndpoint `json:"Proxy,omitempty"`
+}
+
+// ProxyEndpoint is the address Kafka clients use to reach the proxy.
+type ProxyEndpoint struct {
+	Host string
+	Port int32
 }
 
 // InMemoryStore is a simple Store backed by in-process state. Useful for early development and tests.
@@ -176,7 +185,16 @@ func cloneMetadata(src ClusterMetadata) ClusterMetadata {
 		Topics:       cloneTopics(src.Topics),
 		ClusterName:  cloneStringPtr(src.ClusterName),
 		ClusterID:    cloneStringPtr(src.ClusterID),
+		Proxy:        cloneProxyEndpoint(src.Proxy),
+	}
+}
+
+func cloneProxyEndpoint(src *ProxyEndpoint) *ProxyEndpoint {
+	if src == nil {
+		return nil
 	}
+	out := *src
+	return &out
 }
 
 func cloneBrokers(brokers []protocol.MetadataBroker) []protocol.MetadataBroker {
@@ -0,0 +1,78 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	metadatapb "github.com/KafScale/platform/pkg/gen/metadata"
+)
+
+// Topic config keys accepted by AlterConfigs and the KafscaleTopic CRD.
+const (
+	ConfigRetentionMs    = "retention.ms"
+	ConfigRetentionBytes = "retention.bytes"
+	ConfigSegmentBytes   = "segment.bytes"
+)
+
+// TopicConfigKeys lists the topic config keys ApplyTopicConfig accepts.
+var TopicConfigKeys = []string{ConfigRetentionMs, ConfigRetentionBytes, ConfigSegmentBytes}
+
+// ErrInvalidConfig indicates a topic config key or value is not accepted.
+var ErrInvalidConfig = errors.New("invalid topic config")
+
+// ApplyTopicConfig validates a single topic config entry and sets it on cfg.
+func ApplyTopicConfig(cfg *metadatapb.TopicConfig, name, value string) error {
+	switch name {
+	case ConfigRetentionMs, ConfigRetentionBytes, ConfigSegmentBytes:
+	default:
+		return fmt.Errorf("%w: %s is not supported", ErrInvalidConfig, name)
+	}
+	parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s=%q is not an integer", ErrInvalidConfig, name, value)
+	}
+	switch name {
+	case ConfigRetentionMs:
+		if parsed < -1 {
+			return fmt.Errorf("%w: %s must be -1 or non-negative", ErrInvalidConfig, name)
+		}
+		cfg.RetentionMs = parsed
+	case ConfigRetentionBytes:
+		if parsed < -1 {
+			return fmt.Errorf("%w: %s must be -1 or non-negative", ErrInvalidConfig, name)
+		}
+		cfg.RetentionBytes = parsed
+	case ConfigSegmentBytes:
+		if parsed <= 0 {
+			return fmt.Errorf("%w: %s must be positive", ErrInvalidConfig, name)
+		}
+		cfg.SegmentBytes = parsed
+	}
+	return nil
+}
+
+// ValidatePartitionIncrease checks a CreatePartitions target against the
+// current partition count; partitions can only be added.
+func ValidatePartitionIncrease(current, requested int32) error {
+	if requested <= 0 || requested <= current {
+		return fmt.Errorf("%w: partition count %d must exceed the current %d", ErrInvalidTopic, requested, current)
+	}
+	return nil
+}
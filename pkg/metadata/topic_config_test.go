@@ -0,0 +1,61 @@
+// Copyright 2025, 2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"errors"
+	"testing"
+
+	metadatapb "github.com/KafScale/platform/pkg/gen/metadata"
+)
+
+func TestApplyTopicConfig(t *testing.T) {
+	cfg := &metadatapb.TopicConfig{Name: "orders"}
+	for name, value := range map[string]string{
+		ConfigRetentionMs:    "-1",
+		ConfigRetentionBytes: " 1048576 ",
+		ConfigSegmentBytes:   "4194304",
+	} {
+		if err := ApplyTopicConfig(cfg, name, value); err != nil {
+			t.Fatalf("apply %s=%s: %v", name, value, err)
+		}
+	}
+	if cfg.RetentionMs != -1 || cfg.RetentionBytes != 1048576 || cfg.SegmentBytes != 4194304 {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	for _, tc := range []struct{ name, value string }{
+		{ConfigRetentionMs, "-2"},
+		{ConfigSegmentBytes, "0"},
+		{ConfigRetentionBytes, "lots"},
+		{"cleanup.policy", "compact"},
+	} {
+		if err := ApplyTopicConfig(cfg, tc.name, tc.value); !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("expected %s=%s to be rejected, got %v", tc.name, tc.value, err)
+		}
+	}
+}
+
+func TestValidatePartitionIncrease(t *testing.T) {
+	if err := ValidatePartitionIncrease(3, 6); err != nil {
+		t.Fatalf("expected increase to be valid: %v", err)
+	}
+	for _, requested := range []int32{0, 2, 3} {
+		if err := ValidatePartitionIncrease(3, requested); !errors.Is(err, ErrInvalidTopic) {
+			t.Fatalf("expected %d partitions to be rejected, got %v", requested, err)
+		}
+	}
+}
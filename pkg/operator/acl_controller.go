@@ -0,0 +1,163 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/metadata"
+)
+
+const aclCleanupFinalizer = "kafscale.io/acl-cleanup"
+
+// ACLReconciler publishes KafscaleACL rules to etcd as ACL bindings.
+type ACLReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func NewACLReconciler(mgr ctrl.Manager) *ACLReconciler {
+	return &ACLReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+}
+
+func (r *ACLReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var acl kafscalev1alpha1.KafscaleACL
+	if err := r.Get(ctx, req.NamespacedName, &acl); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	var cluster kafscalev1alpha1.KafscaleCluster
+	if err := r.Get(ctx, types.NamespacedName{Name: acl.Spec.ClusterRef, Namespace: acl.Namespace}, &cluster); err != nil {
+		if apierrors.IsNotFound(err) && !acl.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, removeFinalizer(ctx, r.Client, &acl, aclCleanupFinalizer)
+		}
+		return ctrl.Result{}, err
+	}
+	etcdResolution, err := EnsureEtcd(ctx, r.Client, r.Scheme, &cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !acl.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&acl, aclCleanupFinalizer) {
+			if err := deleteEtcdKey(ctx, etcdResolution.Endpoints, metadata.ACLKey(acl.Name)); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, removeFinalizer(ctx, r.Client, &acl, aclCleanupFinalizer)
+	}
+	if !controllerutil.ContainsFinalizer(&acl, aclCleanupFinalizer) {
+		controllerutil.AddFinalizer(&acl, aclCleanupFinalizer)
+		if err := r.Update(ctx, &acl); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	set, err := buildACLSet(&acl)
+	if err != nil {
+		return r.setStatus(ctx, &acl, metav1.ConditionFalse, "InvalidRules", err.Error(), 0)
+	}
+	payload, err := json.Marshal(set)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := putEtcdValue(ctx, etcdResolution.Endpoints, metadata.ACLKey(acl.Name), payload); err != nil {
+		return r.setStatus(ctx, &acl, metav1.ConditionFalse, "EtcdUnavailable", fmt.Sprintf("Publish ACLs failed: %v", err), publishRequeueDelay)
+	}
+	acl.Status.Bindings = int32(len(set.Bindings))
+	return r.setStatus(ctx, &acl, metav1.ConditionTrue, "Reconciled", "ACL bindings published", 0)
+}
+
+// buildACLSet expands each rule into one binding per operation and applies
+// the Literal/Allow/"*" defaults.
+func buildACLSet(acl *kafscalev1alpha1.KafscaleACL) (*metadata.ACLSet, error) {
+	user := strings.TrimSpace(acl.Spec.User)
+	if user == "" {
+		return nil, fmt.Errorf("user is required")
+	}
+	if len(acl.Spec.Rules) == 0 {
+		return nil, fmt.Errorf("at least one rule is required")
+	}
+	set := &metadata.ACLSet{Name: acl.Name}
+	for i, rule := range acl.Spec.Rules {
+		if len(rule.Operations) == 0 {
+			return nil, fmt.Errorf("rule %d: at least one operation is required", i)
+		}
+		for _, op := range rule.Operations {
+			binding := metadata.ACLBinding{
+				Principal:    "User:" + user,
+				Host:         aclRuleDefault(rule.Host, "*"),
+				ResourceType: rule.ResourceType,
+				ResourceName: rule.ResourceName,
+				PatternType:  aclRuleDefault(rule.PatternType, "Literal"),
+				Operation:    op,
+				Permission:   aclRuleDefault(rule.Permission, "Allow"),
+			}
+			if err := metadata.ValidateACLBinding(binding); err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i, err)
+			}
+			set.Bindings = append(set.Bindings, binding)
+		}
+	}
+	return set, nil
+}
+
+func (r *ACLReconciler) setStatus(ctx context.Context, acl *kafscalev1alpha1.KafscaleACL, status metav1.ConditionStatus, reason, message string, requeue time.Duration) (ctrl.Result, error) {
+	meta.SetStatusCondition(&acl.Status.Conditions, metav1.Condition{
+		Type:               "Ready",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: acl.Generation,
+	})
+	acl.Status.Phase = reason
+	if status == metav1.ConditionTrue {
+		acl.Status.Phase = "Ready"
+	}
+	if err := r.Status().Update(ctx, acl); err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+func (r *ACLReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kafscalev1alpha1.KafscaleACL{}).
+		Complete(r)
+}
+
+func aclRuleDefault(value, fallback string) string {
+	if trimmed := strings.TrimSpace(value); trimmed != "" {
+		return trimmed
+	}
+	return fallback
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/metadata"
+)
+
+func TestACLReconcilerPublishesBindings(t *testing.T) {
+	endpoints := startTestEtcd(t)
+	cluster := testCluster("prod", endpoints)
+	acl := &kafscalev1alpha1.KafscaleACL{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice-orders", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleACLSpec{
+			ClusterRef: "prod",
+			User:       "alice",
+			Rules: []kafscalev1alpha1.ACLRuleSpec{
+				{ResourceType: "Topic", ResourceName: "orders-", PatternType: "Prefixed", Operations: []string{"Read", "Describe"}},
+				{ResourceType: "Group", ResourceName: "billing", Operations: []string{"Read"}, Permission: "Deny", Host: "10.0.0.1"},
+			},
+		},
+	}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, acl).WithStatusSubresource(acl).Build()
+	r := &ACLReconciler{Client: c, Scheme: scheme}
+	ctx := context.Background()
+
+	reconcileACL(t, r, "alice-orders")
+	raw, err := getEtcdValue(ctx, endpoints, metadata.ACLKey("alice-orders"))
+	if err != nil || raw == nil {
+		t.Fatalf("read acl set: %v", err)
+	}
+	var set metadata.ACLSet
+	if err := json.Unmarshal(raw, &set); err != nil {
+		t.Fatalf("decode acl set: %v", err)
+	}
+	if len(set.Bindings) != 3 {
+		t.Fatalf("expected 3 bindings, got %+v", set.Bindings)
+	}
+	want := metadata.ACLBinding{
+		Principal: "User:alice", Host: "*", ResourceType: "Topic", ResourceName: "orders-",
+		PatternType: "Prefixed", Operation: "Read", Permission: "Allow",
+	}
+	if set.Bindings[0] != want {
+		t.Fatalf("unexpected first binding %+v", set.Bindings[0])
+	}
+	if deny := set.Bindings[2]; deny.Permission != "Deny" || deny.Host != "10.0.0.1" || deny.PatternType != "Literal" {
+		t.Fatalf("unexpected deny binding %+v", deny)
+	}
+
+	var updated kafscalev1alpha1.KafscaleACL
+	assertFound(t, c, &updated, "default", "alice-orders")
+	if updated.Status.Phase != "Ready" || updated.Status.Bindings != 3 {
+		t.Fatalf("unexpected status: %+v", updated.Status)
+	}
+
+	updated.Spec.Rules[0].Operations = []string{"Produce"}
+	if err := c.Update(ctx, &updated); err != nil {
+		t.Fatalf("update acl: %v", err)
+	}
+	reconcileACL(t, r, "alice-orders")
+	assertFound(t, c, &updated, "default", "alice-orders")
+	if updated.Status.Phase != "InvalidRules" {
+		t.Fatalf("expected InvalidRules, got %+v", updated.Status)
+	}
+	if again, _ := getEtcdValue(ctx, endpoints, metadata.ACLKey("alice-orders")); string(again) != string(raw) {
+		t.Fatalf("expected invalid rules to leave published bindings untouched")
+	}
+
+	if err := c.Delete(ctx, &updated); err != nil {
+		t.Fatalf("delete acl: %v", err)
+	}
+	reconcileACL(t, r, "alice-orders")
+	assertNotFound(t, c, &kafscalev1alpha1.KafscaleACL{}, "default", "alice-orders")
+	if raw, err := getEtcdValue(ctx, endpoints, metadata.ACLKey("alice-orders")); err != nil || raw != nil {
+		t.Fatalf("expected acl set removed from etcd, got %q (%v)", raw, err)
+	}
+}
+
+func reconcileACL(t *testing.T, r *ACLReconciler, name string) {
+	t.Helper()
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}); err != nil {
+		t.Fatalf("reconcile %s: %v", name, err)
+	}
+}
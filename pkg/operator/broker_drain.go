@@ -0,0 +1,242 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	controlpb "github.com/KafScale/platform/pkg/gen/control"
+)
+
+const (
+	brokerDrainFinalizer = "kafscale.io/broker-drain"
+	brokerControlPort    = 19094
+	defaultDrainTimeout  = 60 * time.Second
+	drainCallTimeout     = 10 * time.Second
+	drainPollInterval    = 2 * time.Second
+)
+
+// BrokerControlDialer opens a BrokerControl client for a broker address.
+type BrokerControlDialer func(addr string) (controlpb.BrokerControlClient, io.Closer, error)
+
+// BrokerDrainReconciler drains broker pods before they go away. Broker pods
+// carry a finalizer and a preStop hook that keeps the broker running; when a
+// pod is deleted (scale-down, rollout or eviction) the reconciler calls
+// DrainPartitions and releases the finalizer once the broker reports its
+// buffers flushed and its partitions released, or the drain timeout passes.
+type BrokerDrainReconciler struct {
+	Client      client.Client
+	Dial        BrokerControlDialer
+	ControlPort int32
+}
+
+func NewBrokerDrainReconciler(mgr ctrl.Manager) *BrokerDrainReconciler {
+	return &BrokerDrainReconciler{
+		Client:      mgr.GetClient(),
+		Dial:        dialBrokerControl,
+		ControlPort: brokerControlPort,
+	}
+}
+
+func (r *BrokerDrainReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var pod corev1.Pod
+	if err := r.Client.Get(ctx, req.NamespacedName, &pod); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !isBrokerPod(&pod) {
+		return ctrl.Result{}, nil
+	}
+	if pod.DeletionTimestamp.IsZero() {
+		// Pods created before the finalizer was part of the template.
+		if controllerutil.AddFinalizer(&pod, brokerDrainFinalizer) {
+			return ctrl.Result{}, r.Client.Update(ctx, &pod)
+		}
+		return ctrl.Result{}, nil
+	}
+	if !controllerutil.ContainsFinalizer(&pod, brokerDrainFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var cluster *kafscalev1alpha1.KafscaleCluster
+	found := &kafscalev1alpha1.KafscaleCluster{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: pod.Labels["cluster"]}, found); err == nil {
+		cluster = found
+	} else if !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	timeout := brokerDrainTimeout(cluster)
+	elapsed := time.Since(pod.DeletionTimestamp.Time)
+
+	result := kafscalev1alpha1.BrokerDrainStatus{Pod: pod.Name, Reason: "Draining"}
+	addr := r.controlAddress(&pod)
+	if addr == "" {
+		result.Reason = "DrainSkipped"
+		result.Message = "Broker pod is not running; nothing to drain."
+	} else {
+		pending, err := r.drain(ctx, addr, timeout-elapsed)
+		switch {
+		case err == nil && pending == 0:
+			result.Reason = "Drained"
+			result.Message = "Broker flushed its buffers and released its partitions."
+		case err != nil:
+			result.Message = fmt.Sprintf("Draining: %v", err)
+		default:
+			result.Message = fmt.Sprintf("Draining: %d partitions pending.", pending)
+		}
+		if result.Reason == "Draining" && elapsed >= timeout {
+			result.Reason = "DrainTimedOut"
+			result.Message = fmt.Sprintf("Not drained within %s. %s", timeout, result.Message)
+		}
+	}
+	if cluster != nil {
+		// Drains of several pods update the same list, so the patch fails
+		// on a stale cluster instead of dropping another pod's entry.
+		patch := client.MergeFromWithOptions(cluster.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		setBrokerDrainStatus(&cluster.Status.BrokerDrains, result)
+		if err := r.Client.Status().Patch(ctx, cluster, patch); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+	if result.Reason == "Draining" {
+		return ctrl.Result{RequeueAfter: drainPollInterval}, nil
+	}
+	controllerutil.RemoveFinalizer(&pod, brokerDrainFinalizer)
+	if err := r.Client.Update(ctx, &pod); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *BrokerDrainReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("brokerdrain").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetLabels()["app"] == "kafscale-broker"
+		}))).
+		Complete(r)
+}
+
+// drain asks the broker to drain all partitions and returns how many are
+// still pending.
+func (r *BrokerDrainReconciler) drain(ctx context.Context, addr string, remaining time.Duration) (int, error) {
+	if remaining > drainCallTimeout {
+		remaining = drainCallTimeout
+	}
+	if remaining < time.Second {
+		remaining = time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, remaining)
+	defer cancel()
+	dial := r.Dial
+	if dial == nil {
+		dial = dialBrokerControl
+	}
+	broker, conn, err := dial(addr)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	resp, err := broker.DrainPartitions(ctx, &controlpb.DrainPartitionsRequest{
+		Reason:          "pod terminating",
+		DeadlineSeconds: int32(remaining / time.Second),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.GetPending()) > 0 {
+		return len(resp.GetPending()), nil
+	}
+	// Fetches can reopen a partition for reads after the drain; those hold
+	// no buffered data and no longer lead.
+	status, err := broker.GetStatus(ctx, &controlpb.BrokerStatusRequest{})
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, part := range status.GetPartitions() {
+		if part.GetLeader() || part.GetHighWatermark() < part.GetLogEndOffset() {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+func (r *BrokerDrainReconciler) controlAddress(pod *corev1.Pod) string {
+	if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+		return ""
+	}
+	port := r.ControlPort
+	if port == 0 {
+		port = brokerControlPort
+	}
+	return net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port)))
+}
+
+// setBrokerDrainStatus records the drain of one pod, replacing its previous
+// entry. The transition time only moves when the reason changes.
+func setBrokerDrainStatus(drains *[]kafscalev1alpha1.BrokerDrainStatus, status kafscalev1alpha1.BrokerDrainStatus) {
+	status.LastTransitionTime = metav1.NewTime(time.Now())
+	for i := range *drains {
+		existing := &(*drains)[i]
+		if existing.Pod != status.Pod {
+			continue
+		}
+		if existing.Reason == status.Reason {
+			status.LastTransitionTime = existing.LastTransitionTime
+		}
+		*existing = status
+		return
+	}
+	*drains = append(*drains, status)
+	sort.Slice(*drains, func(i, j int) bool { return (*drains)[i].Pod < (*drains)[j].Pod })
+}
+
+func isBrokerPod(pod *corev1.Pod) bool {
+	return pod.Labels["app"] == "kafscale-broker" && pod.Labels["cluster"] != ""
+}
+
+func brokerDrainTimeout(cluster *kafscalev1alpha1.KafscaleCluster) time.Duration {
+	if cluster != nil && cluster.Spec.Brokers.DrainTimeoutSeconds != nil && *cluster.Spec.Brokers.DrainTimeoutSeconds > 0 {
+		return time.Duration(*cluster.Spec.Brokers.DrainTimeoutSeconds) * time.Second
+	}
+	return defaultDrainTimeout
+}
+
+func dialBrokerControl(addr string) (controlpb.BrokerControlClient, io.Closer, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return controlpb.NewBrokerControlClient(conn), conn, nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	controlpb "github.com/KafScale/platform/pkg/gen/control"
+)
+
+// fakeBrokerControl keeps its partitions pending for the first pendingDrains
+// DrainPartitions calls.
+type fakeBrokerControl struct {
+	controlpb.UnimplementedBrokerControlServer
+
+	mu            sync.Mutex
+	pendingDrains int
+	drains        int
+	drained       bool
+}
+
+func (f *fakeBrokerControl) DrainPartitions(ctx context.Context, req *controlpb.DrainPartitionsRequest) (*controlpb.DrainPartitionsResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.drains++
+	ref := &controlpb.PartitionRef{Topic: "orders", Partition: 0}
+	if f.drains <= f.pendingDrains {
+		return &controlpb.DrainPartitionsResponse{Pending: []*controlpb.PartitionRef{ref}}, nil
+	}
+	f.drained = true
+	return &controlpb.DrainPartitionsResponse{Drained: []*controlpb.PartitionRef{ref}}, nil
+}
+
+func (f *fakeBrokerControl) GetStatus(ctx context.Context, req *controlpb.BrokerStatusRequest) (*controlpb.BrokerStatusResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.drained {
+		return &controlpb.BrokerStatusResponse{Ready: true}, nil
+	}
+	return &controlpb.BrokerStatusResponse{Ready: true, Partitions: []*controlpb.PartitionStatus{
+		{Topic: "orders", Partition: 0, Leader: true, State: "online", LogEndOffset: 10, HighWatermark: 8},
+	}}, nil
+}
+
+func startFakeBrokerControl(t *testing.T, srv *fakeBrokerControl) int32 {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	controlpb.RegisterBrokerControlServer(server, srv)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	return int32(lis.Addr().(*net.TCPAddr).Port)
+}
+
+func brokerPod(cluster *kafscalev1alpha1.KafscaleCluster, ip string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       cluster.Name + "-broker-0",
+			Namespace:  cluster.Namespace,
+			Labels:     map[string]string{"app": "kafscale-broker", "cluster": cluster.Name},
+			Finalizers: []string{brokerDrainFinalizer},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+	}
+}
+
+func TestBrokerDrainReconcilerDrainsBeforeReleasingPod(t *testing.T) {
+	ctx := context.Background()
+	fakeBroker := &fakeBrokerControl{pendingDrains: 1}
+	port := startFakeBrokerControl(t, fakeBroker)
+
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	pod := brokerPod(cluster, "127.0.0.1")
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, pod).WithStatusSubresource(cluster).Build()
+	r := &BrokerDrainReconciler{Client: c, Dial: dialBrokerControl, ControlPort: port}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)}
+
+	if err := c.Delete(ctx, pod); err != nil {
+		t.Fatalf("delete pod: %v", err)
+	}
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if res.RequeueAfter == 0 {
+		t.Fatalf("expected a requeue while partitions are pending")
+	}
+	assertFound(t, c, &corev1.Pod{}, pod.Namespace, pod.Name)
+	assertBrokerDrain(t, c, cluster, pod.Name, "Draining")
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	assertNotFound(t, c, &corev1.Pod{}, pod.Namespace, pod.Name)
+	assertBrokerDrain(t, c, cluster, pod.Name, "Drained")
+	if fakeBroker.drains != 2 {
+		t.Fatalf("expected two drain calls, got %d", fakeBroker.drains)
+	}
+}
+
+func TestBrokerDrainReconcilerGivesUpAfterTimeout(t *testing.T) {
+	ctx := context.Background()
+	port := startFakeBrokerControl(t, &fakeBrokerControl{pendingDrains: 100})
+
+	timeout := int32(1)
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	cluster.Spec.Brokers.DrainTimeoutSeconds = &timeout
+	pod := brokerPod(cluster, "127.0.0.1")
+	pod.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, pod).WithStatusSubresource(cluster).Build()
+	r := &BrokerDrainReconciler{Client: c, Dial: dialBrokerControl, ControlPort: port}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)}); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	assertNotFound(t, c, &corev1.Pod{}, pod.Namespace, pod.Name)
+	assertBrokerDrain(t, c, cluster, pod.Name, "DrainTimedOut")
+}
+
+func TestBrokerDrainReconcilerAddsFinalizer(t *testing.T) {
+	ctx := context.Background()
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	pod := brokerPod(cluster, "")
+	pod.Finalizers = nil
+	c := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(cluster, pod).WithStatusSubresource(cluster).Build()
+	r := &BrokerDrainReconciler{Client: c}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)}); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	updated := &corev1.Pod{}
+	assertFound(t, c, updated, pod.Namespace, pod.Name)
+	if len(updated.Finalizers) != 1 || updated.Finalizers[0] != brokerDrainFinalizer {
+		t.Fatalf("expected drain finalizer, got %v", updated.Finalizers)
+	}
+
+	// A pod that never started has nothing to drain.
+	if err := c.Delete(ctx, updated); err != nil {
+		t.Fatalf("delete pod: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)}); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	assertNotFound(t, c, &corev1.Pod{}, pod.Namespace, pod.Name)
+	assertBrokerDrain(t, c, cluster, pod.Name, "DrainSkipped")
+}
+
+func TestBrokerDrainReconcilerTracksEachPod(t *testing.T) {
+	ctx := context.Background()
+	drainedPort := startFakeBrokerControl(t, &fakeBrokerControl{})
+	pendingPort := startFakeBrokerControl(t, &fakeBrokerControl{pendingDrains: 100})
+
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	drained := brokerPod(cluster, "127.0.0.1")
+	pending := brokerPod(cluster, "127.0.0.1")
+	pending.Name = cluster.Name + "-broker-1"
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, drained, pending).WithStatusSubresource(cluster).Build()
+	for _, pod := range []*corev1.Pod{pending, drained} {
+		if err := c.Delete(ctx, pod); err != nil {
+			t.Fatalf("delete pod: %v", err)
+		}
+	}
+
+	r := &BrokerDrainReconciler{Client: c, Dial: dialBrokerControl, ControlPort: pendingPort}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pending)}); err != nil {
+		t.Fatalf("reconcile pending pod: %v", err)
+	}
+	r.ControlPort = drainedPort
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(drained)}); err != nil {
+		t.Fatalf("reconcile drained pod: %v", err)
+	}
+
+	assertBrokerDrain(t, c, cluster, pending.Name, "Draining")
+	assertBrokerDrain(t, c, cluster, drained.Name, "Drained")
+}
+
+func assertBrokerDrain(t *testing.T, c client.Client, cluster *kafscalev1alpha1.KafscaleCluster, pod, reason string) {
+	t.Helper()
+	updated := &kafscalev1alpha1.KafscaleCluster{}
+	assertFound(t, c, updated, cluster.Namespace, cluster.Name)
+	for _, drain := range updated.Status.BrokerDrains {
+		if drain.Pod == pod {
+			if drain.Reason != reason {
+				t.Fatalf("expected drain of %s to be %s, got %+v", pod, reason, drain)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected drain status for %s, got %+v", pod, updated.Status.BrokerDrains)
+}
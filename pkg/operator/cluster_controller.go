@@ -28,14 +28,19 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/storage"
 )
 
 const (
@@ -52,6 +57,24 @@ type ClusterReconciler struct {
 	Client    client.Client
 	Scheme    *runtime.Scheme
 	Publisher *SnapshotPublisher
+	// NewS3Client builds the clients used for etcd restores. Defaults to
+	// storage.NewS3Client.
+	NewS3Client func(ctx context.Context, cfg storage.S3Config) (storage.S3Client, error)
+}
+
+// CacheOptions configures the manager cache for the operator. Only broker
+// pods (for draining) and etcd pods (for restores) are cached; the rest of
+// the cluster's pods stay out of memory.
+func CacheOptions() cache.Options {
+	managed, err := labels.NewRequirement("app", selection.In, []string{"kafscale-broker", "kafscale-etcd"})
+	if err != nil {
+		panic(err)
+	}
+	return cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}: {Label: labels.NewSelector().Add(*managed)},
+		},
+	}
 }
 
 func NewClusterReconciler(mgr ctrl.Manager, publisher *SnapshotPublisher) *ClusterReconciler {
@@ -62,7 +85,8 @@ func NewClusterReconciler(mgr ctrl.Manager, publisher *SnapshotPublisher) *Clust
 	}
 }
 
-// Reconcile ensures broker workloads exist for every KafscaleCluster spec.
+// Reconcile ensures broker, proxy and console workloads exist for every
+// KafscaleCluster spec.
 func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	var cluster kafscalev1alpha1.KafscaleCluster
 	if err := r.Client.Get(ctx, req.NamespacedName, &cluster); err != nil {
@@ -78,6 +102,16 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 	r.populateEtcdSnapshotStatus(ctx, &cluster, etcdResolution)
+	restoring, err := r.reconcileEtcdRestore(ctx, &cluster, etcdResolution)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if restoring {
+		if err := r.updateStatus(ctx, &cluster, metav1.ConditionFalse, "EtcdRestoring", cluster.Status.EtcdRestore.Message); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: etcdRestorePollInterval}, nil
+	}
 	if err := r.deleteLegacyBrokerDeployment(ctx, &cluster); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -93,6 +127,12 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err := r.reconcileBrokerHPA(ctx, &cluster); err != nil {
 		return ctrl.Result{}, err
 	}
+	if err := r.reconcileProxy(ctx, &cluster, etcdResolution.Endpoints); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileConsole(ctx, &cluster, etcdResolution.Endpoints); err != nil {
+		return ctrl.Result{}, err
+	}
 	if err := r.Publisher.Publish(ctx, &cluster, etcdResolution.Endpoints); err != nil {
 		setClusterCondition(&cluster.Status.Conditions, metav1.Condition{
 			Type:               "EtcdAvailable",
@@ -123,6 +163,7 @@ func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kafscalev1alpha1.KafscaleCluster{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
 		Complete(r)
@@ -139,6 +180,19 @@ func (r *ClusterReconciler) reconcileBrokerDeployment(ctx context.Context, clust
 		if cluster.Spec.Brokers.Replicas != nil {
 			replicas = *cluster.Spec.Brokers.Replicas
 		}
+		if cluster.Spec.Autoscaling != nil {
+			// The HPA owns the replica count; only keep it within bounds.
+			if sts.Spec.Replicas != nil {
+				replicas = *sts.Spec.Replicas
+			}
+			min, max := brokerReplicaBounds(cluster)
+			if replicas < min {
+				replicas = min
+			}
+			if replicas > max {
+				replicas = max
+			}
+		}
 		labels := map[string]string{
 			"app":     "kafscale-broker",
 			"cluster": cluster.Name,
@@ -147,6 +201,11 @@ func (r *ClusterReconciler) reconcileBrokerDeployment(ctx context.Context, clust
 		sts.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
 		sts.Spec.Replicas = &replicas
 		sts.Spec.Template.ObjectMeta.Labels = labels
+		sts.Spec.Template.ObjectMeta.Finalizers = []string{brokerDrainFinalizer}
+		// The preStop hook may wait the full drain timeout and then drain
+		// locally, so leave room for both before the kubelet kills the broker.
+		grace := int64(2*brokerDrainTimeout(cluster)/time.Second) + 10
+		sts.Spec.Template.Spec.TerminationGracePeriodSeconds = &grace
 		sts.Spec.Template.Spec.Containers = []corev1.Container{
 			r.brokerContainer(cluster, endpoints),
 		}
@@ -194,6 +253,8 @@ func (r *ClusterReconciler) brokerContainer(cluster *kafscalev1alpha1.KafscaleCl
 		{Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
 		{Name: "KAFSCALE_BROKER_PORT", Value: fmt.Sprintf("%d", brokerPort)},
 		{Name: "KAFSCALE_METRICS_ADDR", Value: ":9093"},
+		{Name: "KAFSCALE_CONTROL_ADDR", Value: fmt.Sprintf(":%d", brokerControlPort)},
+		{Name: "KAFSCALE_DRAIN_TIMEOUT_SEC", Value: fmt.Sprintf("%d", int64(brokerDrainTimeout(cluster)/time.Second))},
 	}
 	if brokerHost != "" {
 		env = append(env, corev1.EnvVar{Name: "KAFSCALE_BROKER_HOST", Value: brokerHost})
@@ -224,9 +285,14 @@ func (r *ClusterReconciler) brokerContainer(cluster *kafscalev1alpha1.KafscaleCl
 		})
 	}
 	if cluster.Spec.Config.CacheSize != "" {
+		// The broker expects plain bytes; accept quantities such as 512Mi.
+		cacheBytes := cluster.Spec.Config.CacheSize
+		if q, err := resource.ParseQuantity(cacheBytes); err == nil {
+			cacheBytes = fmt.Sprintf("%d", q.Value())
+		}
 		env = append(env, corev1.EnvVar{
 			Name:  "KAFSCALE_CACHE_BYTES",
-			Value: cluster.Spec.Config.CacheSize,
+			Value: cacheBytes,
 		})
 	}
 	var envFrom []corev1.EnvFromSource
@@ -251,10 +317,16 @@ func (r *ClusterReconciler) brokerContainer(cluster *kafscalev1alpha1.KafscaleCl
 		Ports: []corev1.ContainerPort{
 			{Name: "kafka", ContainerPort: 9092},
 			{Name: "metrics", ContainerPort: 9093},
+			{Name: "control", ContainerPort: brokerControlPort},
 		},
 		Env:       env,
 		EnvFrom:   envFrom,
 		Resources: resources,
+		Lifecycle: &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/prestop", Port: intstr.FromString("metrics")},
+			},
+		},
 	}
 }
 
@@ -382,11 +454,7 @@ func (r *ClusterReconciler) reconcileBrokerHPA(ctx context.Context, cluster *kaf
 		},
 	}
 	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, hpa, func() error {
-		min := int32(3)
-		if cluster.Spec.Brokers.Replicas != nil && *cluster.Spec.Brokers.Replicas > 0 {
-			min = *cluster.Spec.Brokers.Replicas
-		}
-		max := min * 4
+		min, max := brokerReplicaBounds(cluster)
 		hpa.Spec.MinReplicas = &min
 		hpa.Spec.MaxReplicas = max
 		hpa.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
@@ -394,31 +462,109 @@ func (r *ClusterReconciler) reconcileBrokerHPA(ctx context.Context, cluster *kaf
 			Name:       fmt.Sprintf("%s-broker", cluster.Name),
 			APIVersion: "apps/v1",
 		}
-		hpa.Spec.Metrics = []autoscalingv2.MetricSpec{
-			{
-				Type: autoscalingv2.ResourceMetricSourceType,
-				Resource: &autoscalingv2.ResourceMetricSource{
-					Name: corev1.ResourceCPU,
-					Target: autoscalingv2.MetricTarget{
-						Type:               autoscalingv2.UtilizationMetricType,
-						AverageUtilization: int32Ptr(70),
-					},
+		hpa.Spec.Metrics = brokerHPAMetrics(cluster.Spec.Autoscaling)
+		hpa.Spec.Behavior = brokerHPABehavior(cluster.Spec.Autoscaling)
+		return controllerutil.SetControllerReference(cluster, hpa, r.Scheme)
+	})
+	return err
+}
+
+// brokerReplicaBounds returns the HPA replica range: spec.autoscaling when
+// set, otherwise brokers.replicas up to four times that.
+func brokerReplicaBounds(cluster *kafscalev1alpha1.KafscaleCluster) (int32, int32) {
+	min := int32(3)
+	if cluster.Spec.Brokers.Replicas != nil && *cluster.Spec.Brokers.Replicas > 0 {
+		min = *cluster.Spec.Brokers.Replicas
+	}
+	max := min * 4
+	if as := cluster.Spec.Autoscaling; as != nil {
+		if as.MinReplicas != nil && *as.MinReplicas > 0 {
+			min = *as.MinReplicas
+			max = min * 4
+		}
+		if as.MaxReplicas != nil && *as.MaxReplicas > 0 {
+			max = *as.MaxReplicas
+		}
+	}
+	if max < min {
+		max = min
+	}
+	return min, max
+}
+
+func brokerHPAMetrics(as *kafscalev1alpha1.AutoscalingSpec) []autoscalingv2.MetricSpec {
+	var metrics []autoscalingv2.MetricSpec
+	if as != nil {
+		if as.TargetProduceBytesPerSecond != nil {
+			metrics = append(metrics, brokerPodsMetric("kafscale_produce_bytes_per_second", *as.TargetProduceBytesPerSecond))
+		}
+		if as.TargetFetchRecordsPerSecond != nil {
+			metrics = append(metrics, brokerPodsMetric("kafscale_fetch_rps", *as.TargetFetchRecordsPerSecond))
+		}
+		if as.TargetConnections != nil {
+			metrics = append(metrics, brokerPodsMetric("kafscale_active_connections", int64(*as.TargetConnections)))
+		}
+		if as.ConsumerLagThreshold != nil {
+			metrics = append(metrics, brokerPodsMetric("kafscale_consumer_lag_max", *as.ConsumerLagThreshold))
+		}
+	}
+	if len(metrics) > 0 {
+		return metrics
+	}
+	return []autoscalingv2.MetricSpec{
+		{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: int32Ptr(70),
 				},
 			},
-			{
-				Type: autoscalingv2.ResourceMetricSourceType,
-				Resource: &autoscalingv2.ResourceMetricSource{
-					Name: corev1.ResourceMemory,
-					Target: autoscalingv2.MetricTarget{
-						Type:               autoscalingv2.UtilizationMetricType,
-						AverageUtilization: int32Ptr(80),
-					},
+		},
+		{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: corev1.ResourceMemory,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: int32Ptr(80),
 				},
 			},
-		}
-		return controllerutil.SetControllerReference(cluster, hpa, r.Scheme)
-	})
-	return err
+		},
+	}
+}
+
+func brokerPodsMetric(name string, target int64) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{Name: name},
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: resource.NewQuantity(target, resource.DecimalSI),
+			},
+		},
+	}
+}
+
+func brokerHPABehavior(as *kafscalev1alpha1.AutoscalingSpec) *autoscalingv2.HorizontalPodAutoscalerBehavior {
+	if as == nil {
+		return nil
+	}
+	window := int32(300)
+	if as.ScaleDownStabilizationSeconds != nil && *as.ScaleDownStabilizationSeconds >= 0 {
+		window = *as.ScaleDownStabilizationSeconds
+	}
+	// Remove one broker at a time so each drain finishes before the next.
+	return &autoscalingv2.HorizontalPodAutoscalerBehavior{
+		ScaleDown: &autoscalingv2.HPAScalingRules{
+			StabilizationWindowSeconds: &window,
+			Policies: []autoscalingv2.HPAScalingPolicy{
+				{Type: autoscalingv2.PodsScalingPolicy, Value: 1, PeriodSeconds: 60},
+			},
+		},
+	}
 }
 
 func (r *ClusterReconciler) updateStatus(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, status metav1.ConditionStatus, reason, message string) error {
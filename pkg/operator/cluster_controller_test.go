@@ -20,8 +20,12 @@ import (
 	"fmt"
 	"testing"
 
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
@@ -55,6 +59,34 @@ func TestBrokerContainerAdvertisedEndpoint(t *testing.T) {
 	}
 }
 
+func TestBrokerContainerDrainHook(t *testing.T) {
+	timeout := int32(90)
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	cluster.Spec.Brokers.DrainTimeoutSeconds = &timeout
+	container := (&ClusterReconciler{}).brokerContainer(cluster, nil)
+	if got := envValue(container.Env, "KAFSCALE_DRAIN_TIMEOUT_SEC"); got != "90" {
+		t.Fatalf("expected drain timeout env 90, got %q", got)
+	}
+	if got := envValue(container.Env, "KAFSCALE_CONTROL_ADDR"); got != ":19094" {
+		t.Fatalf("expected control addr :19094, got %q", got)
+	}
+	if container.Lifecycle == nil || container.Lifecycle.PreStop == nil || container.Lifecycle.PreStop.HTTPGet == nil ||
+		container.Lifecycle.PreStop.HTTPGet.Path != "/prestop" {
+		t.Fatalf("expected /prestop hook, got %+v", container.Lifecycle)
+	}
+}
+
+func TestBrokerContainerCacheBytes(t *testing.T) {
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	for raw, want := range map[string]string{"512Mi": "536870912", "1048576": "1048576"} {
+		cluster.Spec.Config.CacheSize = raw
+		container := (&ClusterReconciler{}).brokerContainer(cluster, nil)
+		if got := envValue(container.Env, "KAFSCALE_CACHE_BYTES"); got != want {
+			t.Fatalf("cacheSize %q: expected %q, got %q", raw, want, got)
+		}
+	}
+}
+
 func TestReconcileBrokerHeadlessService(t *testing.T) {
 	cluster := &kafscalev1alpha1.KafscaleCluster{
 		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"},
@@ -165,3 +197,106 @@ func TestServiceParsingHelpers(t *testing.T) {
 		t.Fatalf("expected empty external traffic policy, got %q", got)
 	}
 }
+
+func TestReconcileBrokerHPADefaultsToResourceMetrics(t *testing.T) {
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+	r := &ClusterReconciler{Client: c, Scheme: scheme}
+
+	if err := r.reconcileBrokerHPA(context.Background(), cluster); err != nil {
+		t.Fatalf("reconcile broker hpa: %v", err)
+	}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	assertFound(t, c, hpa, cluster.Namespace, "demo-broker")
+	if *hpa.Spec.MinReplicas != 3 || hpa.Spec.MaxReplicas != 12 {
+		t.Fatalf("expected 3-12 replicas, got %d-%d", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+	if len(hpa.Spec.Metrics) != 2 || hpa.Spec.Metrics[0].Type != autoscalingv2.ResourceMetricSourceType {
+		t.Fatalf("expected cpu and memory metrics, got %+v", hpa.Spec.Metrics)
+	}
+	if hpa.Spec.Behavior != nil {
+		t.Fatalf("expected default HPA behavior, got %+v", hpa.Spec.Behavior)
+	}
+}
+
+func TestReconcileBrokerHPAUsesThroughputTargets(t *testing.T) {
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	cluster.Spec.Autoscaling = &kafscalev1alpha1.AutoscalingSpec{
+		MinReplicas:                   ptr.To(int32(2)),
+		MaxReplicas:                   ptr.To(int32(10)),
+		TargetProduceBytesPerSecond:   ptr.To(int64(50 << 20)),
+		TargetConnections:             ptr.To(int32(500)),
+		ConsumerLagThreshold:          ptr.To(int64(10000)),
+		ScaleDownStabilizationSeconds: ptr.To(int32(600)),
+	}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+	r := &ClusterReconciler{Client: c, Scheme: scheme}
+
+	if err := r.reconcileBrokerHPA(context.Background(), cluster); err != nil {
+		t.Fatalf("reconcile broker hpa: %v", err)
+	}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	assertFound(t, c, hpa, cluster.Namespace, "demo-broker")
+	if *hpa.Spec.MinReplicas != 2 || hpa.Spec.MaxReplicas != 10 {
+		t.Fatalf("expected 2-10 replicas, got %d-%d", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+	}
+	targets := map[string]int64{}
+	for _, metric := range hpa.Spec.Metrics {
+		if metric.Type != autoscalingv2.PodsMetricSourceType {
+			t.Fatalf("expected only pods metrics, got %s", metric.Type)
+		}
+		targets[metric.Pods.Metric.Name] = metric.Pods.Target.AverageValue.Value()
+	}
+	want := map[string]int64{
+		"kafscale_produce_bytes_per_second": 50 << 20,
+		"kafscale_active_connections":       500,
+		"kafscale_consumer_lag_max":         10000,
+	}
+	if fmt.Sprint(targets) != fmt.Sprint(want) {
+		t.Fatalf("expected targets %v, got %v", want, targets)
+	}
+	down := hpa.Spec.Behavior.ScaleDown
+	if down == nil || *down.StabilizationWindowSeconds != 600 {
+		t.Fatalf("expected 600s scale-down window, got %+v", down)
+	}
+}
+
+func TestReconcileBrokerDeploymentKeepsAutoscaledReplicas(t *testing.T) {
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	cluster.Spec.Autoscaling = &kafscalev1alpha1.AutoscalingSpec{MaxReplicas: ptr.To(int32(6))}
+	scheme := testScheme(t)
+	existing := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-broker", Namespace: cluster.Namespace},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(5))},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, existing).Build()
+	r := &ClusterReconciler{Client: c, Scheme: scheme}
+
+	if err := r.reconcileBrokerDeployment(context.Background(), cluster, nil); err != nil {
+		t.Fatalf("reconcile broker statefulset: %v", err)
+	}
+	sts := &appsv1.StatefulSet{}
+	assertFound(t, c, sts, cluster.Namespace, "demo-broker")
+	if *sts.Spec.Replicas != 5 {
+		t.Fatalf("expected HPA-chosen replicas to be kept, got %d", *sts.Spec.Replicas)
+	}
+}
+
+func TestCacheOptionsSelectsManagedPods(t *testing.T) {
+	var selector labels.Selector
+	for obj, byObject := range CacheOptions().ByObject {
+		if _, ok := obj.(*corev1.Pod); ok {
+			selector = byObject.Label
+		}
+	}
+	if selector == nil {
+		t.Fatalf("expected a pod label selector")
+	}
+	for app, want := range map[string]bool{"kafscale-broker": true, "kafscale-etcd": true, "other": false} {
+		if got := selector.Matches(labels.Set{"app": app}); got != want {
+			t.Fatalf("app=%s: expected match %v, got %v", app, want, got)
+		}
+	}
+}
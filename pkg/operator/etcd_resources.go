@@ -183,6 +183,13 @@ func reconcileEtcdStatefulSet(ctx context.Context, c client.Client, scheme *runt
 		sts.Spec.Replicas = &replicas
 		sts.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
 		sts.Spec.Template.ObjectMeta.Labels = labels
+		restoreID := etcdRestoreID(cluster)
+		if restoreID != "" {
+			if sts.Spec.Template.ObjectMeta.Annotations == nil {
+				sts.Spec.Template.ObjectMeta.Annotations = map[string]string{}
+			}
+			sts.Spec.Template.ObjectMeta.Annotations[etcdRestoreIDAnnotation] = restoreID
+		}
 
 		useMemory := parseBoolEnv(operatorEtcdStorageMemoryEnv)
 		if useMemory {
@@ -245,6 +252,12 @@ func reconcileEtcdStatefulSet(ctx context.Context, c client.Client, scheme *runt
 			if endpoint != "" {
 				restoreEnv = append(restoreEnv, corev1.EnvVar{Name: "AWS_ENDPOINT_URL", Value: endpoint})
 			}
+			var restoreIDEnv []corev1.EnvVar
+			if restoreID != "" {
+				restoreIDEnv = []corev1.EnvVar{{Name: "RESTORE_ID", Value: restoreID}}
+				restoreEnv = append(restoreEnv, corev1.EnvVar{Name: "RESTORE_SNAPSHOT_KEY", Value: cluster.Status.EtcdRestore.Snapshot})
+				restoreEnv = append(restoreEnv, restoreIDEnv...)
+			}
 			if strings.TrimSpace(cluster.Spec.S3.CredentialsSecretRef) != "" {
 				secretRef := corev1.LocalObjectReference{Name: cluster.Spec.S3.CredentialsSecretRef}
 				restoreEnv = append(restoreEnv,
@@ -303,12 +316,20 @@ func reconcileEtcdStatefulSet(ctx context.Context, c client.Client, scheme *runt
 				"--name \"$POD_NAME\" " +
 				"--initial-cluster \"$INITIAL_CLUSTER\" " +
 				"--initial-cluster-token \"" + cluster.Name + "-etcd\" " +
-				"--initial-advertise-peer-urls \"$PEER_URL\"\n"
+				"--initial-advertise-peer-urls \"$PEER_URL\"\n" +
+				"if [ -n \"${RESTORE_ID:-}\" ]; then echo \"$RESTORE_ID\" > \"$DATA_DIR/" + etcdRestoreMarkerFile + "\"; fi\n"
 
 			downloadScript := "set -euo pipefail\n" +
 				"DATA_DIR=/var/lib/etcd\n" +
 				"ENDPOINT_OPT=\"\"\n" +
 				"if [ -n \"$AWS_ENDPOINT_URL\" ]; then ENDPOINT_OPT=\"--endpoint-url $AWS_ENDPOINT_URL\"; fi\n" +
+				"MARKER=\"$DATA_DIR/" + etcdRestoreMarkerFile + "\"\n" +
+				"if [ -n \"${RESTORE_ID:-}\" ] && [ \"$(cat \"$MARKER\" 2>/dev/null)\" != \"$RESTORE_ID\" ]; then\n" +
+				"  echo \"restoring etcd from $RESTORE_SNAPSHOT_KEY; discarding existing data\"\n" +
+				"  rm -rf \"$DATA_DIR/member\" \"$MARKER\"\n" +
+				"  aws $ENDPOINT_OPT s3 cp \"s3://$SNAPSHOT_BUCKET/$RESTORE_SNAPSHOT_KEY\" /snapshots/etcd-snapshot.db\n" +
+				"  exit 0\n" +
+				"fi\n" +
 				"if [ -d \"$DATA_DIR/member\" ] && [ \"$(ls -A \"$DATA_DIR\")\" ]; then\n" +
 				"  echo \"etcd data dir not empty; skipping snapshot download\"\n" +
 				"  exit 0\n" +
@@ -357,11 +378,11 @@ func reconcileEtcdStatefulSet(ctx context.Context, c client.Client, scheme *runt
 						"-c",
 						restoreScript,
 					},
-					Env: []corev1.EnvVar{
+					Env: append([]corev1.EnvVar{
 						{Name: "ETCDCTL_API", Value: "3"},
 						{Name: "POD_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
 						{Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
-					},
+					}, restoreIDEnv...),
 					VolumeMounts: []corev1.VolumeMount{
 						{Name: "snapshots", MountPath: "/snapshots"},
 						{Name: "data", MountPath: "/var/lib/etcd"},
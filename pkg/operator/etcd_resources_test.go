@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
@@ -232,6 +233,9 @@ func testScheme(t *testing.T) *runtime.Scheme {
 	if err := batchv1.AddToScheme(scheme); err != nil {
 		t.Fatalf("add batch scheme: %v", err)
 	}
+	if err := autoscalingv2.AddToScheme(scheme); err != nil {
+		t.Fatalf("add autoscaling scheme: %v", err)
+	}
 	return scheme
 }
 
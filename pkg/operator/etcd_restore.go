@@ -0,0 +1,314 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/metadata"
+	"github.com/KafScale/platform/pkg/storage"
+)
+
+const (
+	etcdRestoreLatest = "latest"
+
+	etcdRestorePhaseRestoring = "Restoring"
+	etcdRestorePhaseRepairing = "RepairingOffsets"
+	etcdRestorePhaseCompleted = "Completed"
+	etcdRestorePhaseFailed    = "Failed"
+
+	// etcdRestoreIDAnnotation marks etcd pods created for a restore; pods
+	// without the current ID still run on the pre-restore data.
+	etcdRestoreIDAnnotation = "kafscale.io/etcd-restore-id"
+	etcdRestoreMarkerFile   = ".kafscale-restore-id"
+	etcdRestorePollInterval = 10 * time.Second
+)
+
+var errSnapshotNotFound = errors.New("etcd snapshot not found")
+
+// reconcileEtcdRestore drives spec.etcd.restoreFrom. Each distinct value is
+// applied once: all etcd members are recreated from the snapshot together,
+// then partition next offsets are raised to match the segments in S3, since
+// brokers may have flushed data after the snapshot was taken. It returns
+// true while the restore is still running.
+func (r *ClusterReconciler) reconcileEtcdRestore(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, resolution EtcdResolution) (bool, error) {
+	requested := strings.TrimSpace(cluster.Spec.Etcd.RestoreFrom)
+	if requested == "" {
+		return false, nil
+	}
+	restore := cluster.Status.EtcdRestore
+	if restore == nil || restore.RestoreFrom != requested {
+		return true, r.startEtcdRestore(ctx, cluster, resolution, requested)
+	}
+
+	switch restore.Phase {
+	case etcdRestorePhaseRestoring:
+		if err := r.restartEtcdPods(ctx, cluster); err != nil {
+			return true, err
+		}
+		ready, err := r.etcdRestoreReady(ctx, cluster)
+		if err != nil || !ready {
+			return true, err
+		}
+		restore.Phase = etcdRestorePhaseRepairing
+		restore.Message = "Etcd restored; repairing partition offsets from S3"
+		if err := r.Client.Status().Update(ctx, cluster); err != nil {
+			return true, err
+		}
+		fallthrough
+	case etcdRestorePhaseRepairing:
+		checked, repaired, err := r.repairOffsetsFromS3(ctx, cluster, resolution.Endpoints)
+		if err != nil {
+			restore.Message = fmt.Sprintf("Offset repair failed: %v", err)
+			return true, client.IgnoreNotFound(r.Client.Status().Update(ctx, cluster))
+		}
+		now := metav1.Now()
+		restore.Phase = etcdRestorePhaseCompleted
+		restore.CompletedAt = &now
+		restore.PartitionsChecked = checked
+		restore.OffsetsRepaired = repaired
+		restore.Message = fmt.Sprintf("Restored from %s; repaired %d of %d partition offsets", restore.Snapshot, repaired, checked)
+		return false, r.Client.Status().Update(ctx, cluster)
+	default:
+		return false, nil
+	}
+}
+
+func (r *ClusterReconciler) startEtcdRestore(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, resolution EtcdResolution, requested string) error {
+	now := metav1.Now()
+	restore := &kafscalev1alpha1.EtcdRestoreStatus{RestoreFrom: requested, StartedAt: &now}
+	cluster.Status.EtcdRestore = restore
+	if !resolution.Managed {
+		restore.Phase = etcdRestorePhaseFailed
+		restore.Message = "restoreFrom requires operator-managed etcd"
+		return r.Client.Status().Update(ctx, cluster)
+	}
+	key, err := r.resolveEtcdSnapshot(ctx, cluster, requested)
+	if err != nil {
+		if !errors.Is(err, errSnapshotNotFound) {
+			return err
+		}
+		restore.Phase = etcdRestorePhaseFailed
+		restore.Message = err.Error()
+		return r.Client.Status().Update(ctx, cluster)
+	}
+	restore.Snapshot = key
+	restore.Phase = etcdRestorePhaseRestoring
+	restore.Message = fmt.Sprintf("Restoring etcd from s3://%s/%s", snapshotBucket(cluster), key)
+	if err := r.Client.Status().Update(ctx, cluster); err != nil {
+		return err
+	}
+	if err := reconcileEtcdStatefulSet(ctx, r.Client, r.Scheme, cluster); err != nil {
+		return err
+	}
+	return r.restartEtcdPods(ctx, cluster)
+}
+
+// resolveEtcdSnapshot maps restoreFrom to an object key in the snapshot
+// bucket. Keys may be given relative to the snapshot prefix.
+func (r *ClusterReconciler) resolveEtcdSnapshot(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, requested string) (string, error) {
+	s3, err := r.s3Client(ctx, cluster, snapshotS3Config(cluster))
+	if err != nil {
+		return "", err
+	}
+	prefix := snapshotPrefix(cluster)
+	if prefix != "" {
+		prefix += "/"
+	}
+	objects, err := s3.ListSegments(ctx, prefix)
+	if err != nil {
+		return "", err
+	}
+	keys := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		if strings.HasSuffix(obj.Key, ".db") {
+			keys = append(keys, obj.Key)
+		}
+	}
+	if requested == etcdRestoreLatest {
+		if len(keys) == 0 {
+			return "", fmt.Errorf("%w: no snapshots under s3://%s/%s", errSnapshotNotFound, snapshotBucket(cluster), prefix)
+		}
+		// Snapshot names are UTC timestamps, so the newest sorts last.
+		sort.Strings(keys)
+		return keys[len(keys)-1], nil
+	}
+	want := strings.TrimPrefix(requested, "/")
+	if !strings.HasPrefix(want, prefix) {
+		want = prefix + want
+	}
+	for _, key := range keys {
+		if key == want {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("%w: s3://%s/%s", errSnapshotNotFound, snapshotBucket(cluster), want)
+}
+
+// restartEtcdPods deletes etcd pods that predate the current restore so every
+// member is rebuilt from the same snapshot instead of rolling one at a time.
+func (r *ClusterReconciler) restartEtcdPods(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster) error {
+	restoreID := etcdRestoreID(cluster)
+	var pods corev1.PodList
+	if err := r.Client.List(ctx, &pods, client.InNamespace(cluster.Namespace), client.MatchingLabels(etcdLabels(cluster))); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Annotations[etcdRestoreIDAnnotation] == restoreID || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Client.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// etcdRestoreReady reports whether every etcd member runs on restored data.
+// StatefulSet status can lag pod deletion, so pods are checked directly.
+func (r *ClusterReconciler) etcdRestoreReady(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster) (bool, error) {
+	var pods corev1.PodList
+	if err := r.Client.List(ctx, &pods, client.InNamespace(cluster.Namespace), client.MatchingLabels(etcdLabels(cluster))); err != nil {
+		return false, err
+	}
+	restoreID := etcdRestoreID(cluster)
+	var ready int32
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Annotations[etcdRestoreIDAnnotation] != restoreID || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				ready++
+			}
+		}
+	}
+	return ready >= etcdReplicas(), nil
+}
+
+func (r *ClusterReconciler) repairOffsetsFromS3(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, endpoints []string) (int32, int32, error) {
+	cfg := storage.S3Config{
+		Bucket:         cluster.Spec.S3.Bucket,
+		Region:         cluster.Spec.S3.Region,
+		Endpoint:       cluster.Spec.S3.Endpoint,
+		ForcePathStyle: cluster.Spec.S3.Endpoint != "",
+	}
+	s3, err := r.s3Client(ctx, cluster, cfg)
+	if err != nil {
+		return 0, 0, err
+	}
+	return repairPartitionOffsets(ctx, endpoints, s3, cluster.Namespace)
+}
+
+// repairPartitionOffsets raises each partition's next offset in etcd to one
+// past the last offset found in its S3 segment footers. Offsets ahead of S3
+// are left alone: that data was never flushed and cannot be recovered.
+func repairPartitionOffsets(ctx context.Context, endpoints []string, s3 storage.S3Client, namespace string) (int32, int32, error) {
+	snap, err := readSnapshotFromEtcd(ctx, endpoints)
+	if err != nil {
+		return 0, 0, err
+	}
+	extents, err := storage.ScanPartitionExtents(ctx, s3, namespace)
+	if err != nil {
+		return 0, 0, fmt.Errorf("scan segments: %w", err)
+	}
+	lastOffsets := make(map[string]int64, len(extents))
+	for _, ext := range extents {
+		lastOffsets[metadata.NextOffsetKey(ext.Topic, ext.Partition)] = ext.LastOffset
+	}
+	cli, err := newOperatorEtcdClient(endpoints)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cli.Close()
+
+	var checked, repaired int32
+	for _, topic := range snap.Topics {
+		for _, part := range topic.Partitions {
+			checked++
+			key := metadata.NextOffsetKey(topic.Name, part.PartitionIndex)
+			last, ok := lastOffsets[key]
+			if !ok || last < 0 {
+				continue
+			}
+			opCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			resp, err := cli.Get(opCtx, key)
+			if err == nil {
+				var current int64
+				if len(resp.Kvs) > 0 {
+					current, _ = strconv.ParseInt(strings.TrimSpace(string(resp.Kvs[0].Value)), 10, 64)
+				}
+				if current <= last {
+					_, err = cli.Put(opCtx, key, strconv.FormatInt(last+1, 10))
+					repaired++
+				}
+			}
+			cancel()
+			if err != nil {
+				return checked, repaired, err
+			}
+		}
+	}
+	return checked, repaired, nil
+}
+
+func (r *ClusterReconciler) s3Client(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, cfg storage.S3Config) (storage.S3Client, error) {
+	if err := loadS3Credentials(ctx, r.Client, cluster, &cfg); err != nil {
+		return nil, err
+	}
+	if r.NewS3Client != nil {
+		return r.NewS3Client(ctx, cfg)
+	}
+	return storage.NewS3Client(ctx, cfg)
+}
+
+func snapshotS3Config(cluster *kafscalev1alpha1.KafscaleCluster) storage.S3Config {
+	endpoint := strings.TrimSpace(os.Getenv(operatorEtcdSnapshotEndpointEnv))
+	if endpoint == "" {
+		endpoint = strings.TrimSpace(cluster.Spec.S3.Endpoint)
+	}
+	return storage.S3Config{
+		Bucket:         snapshotBucket(cluster),
+		Region:         cluster.Spec.S3.Region,
+		Endpoint:       endpoint,
+		ForcePathStyle: endpoint != "",
+	}
+}
+
+// etcdRestoreID identifies the active restore. It includes the start time so
+// restoring the same snapshot twice still rebuilds the members.
+func etcdRestoreID(cluster *kafscalev1alpha1.KafscaleCluster) string {
+	restore := cluster.Status.EtcdRestore
+	if restore == nil || restore.Snapshot == "" || restore.StartedAt == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s@%d", restore.Snapshot, restore.StartedAt.Unix())
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/KafScale/platform/pkg/cache"
+	"github.com/KafScale/platform/pkg/metadata"
+	"github.com/KafScale/platform/pkg/protocol"
+	"github.com/KafScale/platform/pkg/storage"
+)
+
+func TestRepairPartitionOffsetsRaisesNextOffsetFromS3(t *testing.T) {
+	endpoints := startTestEtcd(t)
+	cli := testEtcdClient(t, endpoints)
+	ctx := context.Background()
+
+	snap := metadata.ClusterMetadata{Topics: []protocol.MetadataTopic{{
+		Name: "orders",
+		Partitions: []protocol.MetadataPartition{
+			{PartitionIndex: 0}, {PartitionIndex: 1}, {PartitionIndex: 2},
+		},
+	}}}
+	payload, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatalf("marshal snapshot: %v", err)
+	}
+	if _, err := cli.Put(ctx, metadataSnapshotKey, string(payload)); err != nil {
+		t.Fatalf("put snapshot: %v", err)
+	}
+
+	s3 := storage.NewMemoryS3Client()
+	last := writeTestSegments(t, s3, "orders", 0, 3)
+	writeTestSegments(t, s3, "orders", 2, 1)
+	// Partition 0 lost offsets in the restore; partition 2 is already ahead.
+	if _, err := cli.Put(ctx, "/kafscale/topics/orders/partitions/0/next_offset", "1"); err != nil {
+		t.Fatalf("put offset: %v", err)
+	}
+	if _, err := cli.Put(ctx, "/kafscale/topics/orders/partitions/2/next_offset", "100"); err != nil {
+		t.Fatalf("put offset: %v", err)
+	}
+
+	checked, repaired, err := repairPartitionOffsets(ctx, endpoints, s3, "default")
+	if err != nil {
+		t.Fatalf("repairPartitionOffsets: %v", err)
+	}
+	if checked != 3 || repaired != 1 {
+		t.Fatalf("expected 3 checked and 1 repaired, got %d and %d", checked, repaired)
+	}
+	assertEtcdValue(t, cli, "/kafscale/topics/orders/partitions/0/next_offset", strconv.FormatInt(last+1, 10))
+	assertEtcdValue(t, cli, "/kafscale/topics/orders/partitions/2/next_offset", "100")
+	if value, err := getEtcdValue(ctx, endpoints, "/kafscale/topics/orders/partitions/1/next_offset"); err != nil || value != nil {
+		t.Fatalf("expected partition without segments to be untouched: %v", err)
+	}
+}
+
+func TestReconcileEtcdRestoreStartsFromLatestSnapshot(t *testing.T) {
+	cluster := testCluster("prod", nil)
+	cluster.Spec.Etcd.RestoreFrom = "latest"
+	stalePod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "prod-etcd-0",
+		Namespace: "default",
+		Labels:    etcdLabels(cluster),
+	}}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, stalePod).WithStatusSubresource(cluster).Build()
+	s3 := storage.NewMemoryS3Client()
+	for _, key := range []string{
+		"etcd-snapshots/20250101000000.db",
+		"etcd-snapshots/20250301000000.db",
+		"etcd-snapshots/notes.txt",
+	} {
+		if err := s3.UploadSegment(context.Background(), key, []byte("snap")); err != nil {
+			t.Fatalf("upload %s: %v", key, err)
+		}
+	}
+	r := &ClusterReconciler{Client: c, Scheme: scheme, NewS3Client: memoryS3Factory(s3)}
+
+	restoring, err := r.reconcileEtcdRestore(context.Background(), cluster, EtcdResolution{Managed: true})
+	if err != nil {
+		t.Fatalf("reconcileEtcdRestore: %v", err)
+	}
+	if !restoring {
+		t.Fatalf("expected restore to be in progress")
+	}
+	status := cluster.Status.EtcdRestore
+	if status == nil || status.Phase != etcdRestorePhaseRestoring || status.Snapshot != "etcd-snapshots/20250301000000.db" {
+		t.Fatalf("unexpected restore status: %+v", status)
+	}
+
+	sts := &appsv1.StatefulSet{}
+	assertFound(t, c, sts, "default", "prod-etcd")
+	if sts.Spec.Template.Annotations[etcdRestoreIDAnnotation] != etcdRestoreID(cluster) {
+		t.Fatalf("expected restore id annotation on etcd pods")
+	}
+	var download *corev1.Container
+	for i := range sts.Spec.Template.Spec.InitContainers {
+		if sts.Spec.Template.Spec.InitContainers[i].Name == "snapshot-download" {
+			download = &sts.Spec.Template.Spec.InitContainers[i]
+		}
+	}
+	if download == nil {
+		t.Fatalf("expected snapshot-download init container")
+	}
+	if got := envValue(download.Env, "RESTORE_SNAPSHOT_KEY"); got != status.Snapshot {
+		t.Fatalf("expected RESTORE_SNAPSHOT_KEY %q, got %q", status.Snapshot, got)
+	}
+	if !strings.Contains(download.Command[2], etcdRestoreMarkerFile) {
+		t.Fatalf("expected download script to check the restore marker")
+	}
+	assertNotFound(t, c, &corev1.Pod{}, "default", "prod-etcd-0")
+
+	// The same request does not restart the restore once it is underway.
+	startedAt := status.StartedAt
+	if _, err := r.reconcileEtcdRestore(context.Background(), cluster, EtcdResolution{Managed: true}); err != nil {
+		t.Fatalf("reconcileEtcdRestore: %v", err)
+	}
+	if cluster.Status.EtcdRestore.StartedAt != startedAt {
+		t.Fatalf("expected restore to continue rather than restart")
+	}
+}
+
+func TestReconcileEtcdRestoreFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		restore    string
+		resolution EtcdResolution
+		message    string
+	}{
+		{name: "external etcd", restore: "latest", resolution: EtcdResolution{Endpoints: []string{"http://etcd:2379"}}, message: "operator-managed"},
+		{name: "missing snapshot", restore: "20240101000000.db", resolution: EtcdResolution{Managed: true}, message: "etcd-snapshots/20240101000000.db"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := testCluster("prod", nil)
+			cluster.Spec.Etcd.RestoreFrom = tc.restore
+			scheme := testScheme(t)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).WithStatusSubresource(cluster).Build()
+			r := &ClusterReconciler{Client: c, Scheme: scheme, NewS3Client: memoryS3Factory(storage.NewMemoryS3Client())}
+
+			if _, err := r.reconcileEtcdRestore(context.Background(), cluster, tc.resolution); err != nil {
+				t.Fatalf("reconcileEtcdRestore: %v", err)
+			}
+			status := cluster.Status.EtcdRestore
+			if status == nil || status.Phase != etcdRestorePhaseFailed || !strings.Contains(status.Message, tc.message) {
+				t.Fatalf("unexpected restore status: %+v", status)
+			}
+			restoring, err := r.reconcileEtcdRestore(context.Background(), cluster, tc.resolution)
+			if err != nil || restoring {
+				t.Fatalf("expected failed restore to stay failed, got restoring=%v err=%v", restoring, err)
+			}
+			assertNotFound(t, c, &appsv1.StatefulSet{}, "default", "prod-etcd")
+		})
+	}
+}
+
+func assertEtcdValue(t *testing.T, cli *clientv3.Client, key, want string) {
+	t.Helper()
+	resp, err := cli.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("get %s: %v", key, err)
+	}
+	if len(resp.Kvs) == 0 || string(resp.Kvs[0].Value) != want {
+		t.Fatalf("expected %s=%q, got %v", key, want, resp.Kvs)
+	}
+}
+
+func memoryS3Factory(s3 storage.S3Client) func(context.Context, storage.S3Config) (storage.S3Client, error) {
+	return func(context.Context, storage.S3Config) (storage.S3Client, error) {
+		return s3, nil
+	}
+}
+
+// writeTestSegments flushes one segment per batch and returns the last offset.
+func writeTestSegments(t *testing.T, s3 storage.S3Client, topic string, partition int32, batches int) int64 {
+	t.Helper()
+	plog := storage.NewPartitionLog("default", topic, partition, 0, s3, cache.NewSegmentCache(1024), storage.PartitionLogConfig{
+		Buffer:  storage.WriteBufferConfig{MaxBytes: 1, FlushInterval: time.Millisecond},
+		Segment: storage.SegmentWriterConfig{IndexIntervalMessages: 1},
+	}, nil, nil)
+	last := int64(-1)
+	for i := 0; i < batches; i++ {
+		batch, err := storage.NewRecordBatchFromBytes(make([]byte, 70))
+		if err != nil {
+			t.Fatalf("NewRecordBatchFromBytes: %v", err)
+		}
+		res, err := plog.AppendBatch(context.Background(), batch)
+		if err != nil {
+			t.Fatalf("AppendBatch: %v", err)
+		}
+		if err := plog.Flush(context.Background()); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+		last = res.LastOffset
+	}
+	return last
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+)
+
+const (
+	defaultIcebergProcessorImage = "ghcr.io/kafscale/kafscale-iceberg-processor:latest"
+	icebergProcessorMetricsPort  = 9093
+)
+
+var icebergProcessorImage = getEnv("ICEBERG_PROCESSOR_IMAGE", defaultIcebergProcessorImage)
+var icebergProcessorImagePullPolicy = getEnv("ICEBERG_PROCESSOR_IMAGE_PULL_POLICY", defaultBrokerImagePullPolicy)
+
+// IcebergSinkReconciler runs iceberg-processor workers for KafscaleIcebergSink resources.
+type IcebergSinkReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func NewIcebergSinkReconciler(mgr ctrl.Manager) *IcebergSinkReconciler {
+	return &IcebergSinkReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+}
+
+func (r *IcebergSinkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var sink kafscalev1alpha1.KafscaleIcebergSink
+	if err := r.Get(ctx, req.NamespacedName, &sink); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if len(sink.Spec.Mappings) == 0 {
+		return r.setStatus(ctx, &sink, metav1.ConditionFalse, "InvalidSpec", "spec.mappings must not be empty")
+	}
+	var cluster kafscalev1alpha1.KafscaleCluster
+	if err := r.Get(ctx, types.NamespacedName{Name: sink.Spec.ClusterRef, Namespace: sink.Namespace}, &cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.setStatus(ctx, &sink, metav1.ConditionFalse, "ClusterNotFound", fmt.Sprintf("KafscaleCluster %q not found", sink.Spec.ClusterRef))
+		}
+		return ctrl.Result{}, err
+	}
+	etcdResolution, err := EnsureEtcd(ctx, r.Client, r.Scheme, &cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	keyPrefix := icebergSinkKeyPrefix(&sink)
+	config, err := renderProcessorConfig(sink.Spec.IcebergProcessorConfig, map[string]interface{}{
+		"s3":   processorS3Config(&cluster),
+		"etcd": map[string]interface{}{"endpoints": etcdResolution.Endpoints},
+		"offsets": map[string]interface{}{
+			"backend":    "etcd",
+			"key_prefix": keyPrefix,
+		},
+	}, "iceberg.catalog.credentials_secret_ref")
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	hash, err := reconcileProcessorConfigMap(ctx, r.Client, r.Scheme, &sink, icebergSinkConfigMapName(&sink), config)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	deploy, err := reconcileProcessorDeployment(ctx, r.Client, r.Scheme, &sink, &cluster, processorWorkload{
+		Name:       icebergSinkDeploymentName(&sink),
+		ConfigMap:  icebergSinkConfigMapName(&sink),
+		ConfigHash: hash,
+		Labels: map[string]string{
+			"app":          "kafscale-iceberg-sink",
+			"cluster":      cluster.Name,
+			"iceberg-sink": sink.Name,
+		},
+		Spec:         sink.Spec.ProcessorWorkloadSpec,
+		DefaultImage: icebergProcessorImage,
+		PullPolicy:   icebergProcessorImagePullPolicy,
+		Container:    icebergProcessorContainer(&sink),
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	sink.Status.Replicas = deploy.Status.Replicas
+	sink.Status.ReadyReplicas = deploy.Status.ReadyReplicas
+	sink.Status.Phase = processorPhase(deploy)
+	sink.Status.TotalLag = 0
+	topics := make([]kafscalev1alpha1.ProcessorTopicStatus, 0, len(sink.Spec.Mappings))
+	seen := make(map[string]bool)
+	for _, mapping := range sink.Spec.Mappings {
+		if seen[mapping.Topic] {
+			continue
+		}
+		seen[mapping.Topic] = true
+		status, err := readProcessorTopicStatus(ctx, etcdResolution.Endpoints, keyPrefix, mapping.Topic)
+		if err != nil {
+			return r.setStatus(ctx, &sink, metav1.ConditionFalse, "EtcdUnavailable", fmt.Sprintf("Read processor offsets failed: %v", err))
+		}
+		topics = append(topics, status)
+		sink.Status.TotalLag += status.Lag
+	}
+	sink.Status.Topics = topics
+	return r.setStatus(ctx, &sink, metav1.ConditionTrue, "Reconciled", fmt.Sprintf("%d/%d workers ready", deploy.Status.ReadyReplicas, deploy.Status.Replicas))
+}
+
+func (r *IcebergSinkReconciler) setStatus(ctx context.Context, sink *kafscalev1alpha1.KafscaleIcebergSink, status metav1.ConditionStatus, reason, message string) (ctrl.Result, error) {
+	meta.SetStatusCondition(&sink.Status.Conditions, metav1.Condition{
+		Type:               "Ready",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: sink.Generation,
+	})
+	if status != metav1.ConditionTrue {
+		sink.Status.Phase = reason
+	}
+	if err := r.Status().Update(ctx, sink); err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	// Lag and watermarks live in etcd, so poll them rather than waiting for events.
+	return ctrl.Result{RequeueAfter: processorStatusRequeue}, nil
+}
+
+func (r *IcebergSinkReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kafscalev1alpha1.KafscaleIcebergSink{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.ConfigMap{}).
+		Complete(r)
+}
+
+func icebergProcessorContainer(sink *kafscalev1alpha1.KafscaleIcebergSink) corev1.Container {
+	env := []corev1.EnvVar{
+		{Name: "KAFSCALE_METRICS_ADDR", Value: fmt.Sprintf(":%d", icebergProcessorMetricsPort)},
+	}
+	if secret := strings.TrimSpace(sink.Spec.Iceberg.Catalog.CredentialsSecretRef); secret != "" {
+		for _, item := range []struct{ env, key string }{
+			{"ICEBERG_CATALOG_TOKEN", "token"},
+			{"ICEBERG_CATALOG_USERNAME", "username"},
+			{"ICEBERG_CATALOG_PASSWORD", "password"},
+		} {
+			env = append(env, corev1.EnvVar{
+				Name: item.env,
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+					Key:                  item.key,
+					Optional:             boolPtr(true),
+				}},
+			})
+		}
+	}
+	return corev1.Container{
+		Name:  "iceberg-processor",
+		Env:   env,
+		Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: icebergProcessorMetricsPort}},
+	}
+}
+
+// icebergSinkKeyPrefix scopes checkpoints per sink so two sinks reading the
+// same topic keep independent offsets.
+func icebergSinkKeyPrefix(sink *kafscalev1alpha1.KafscaleIcebergSink) string {
+	if prefix := strings.TrimSpace(sink.Spec.Offsets.KeyPrefix); prefix != "" {
+		return strings.TrimSuffix(prefix, "/")
+	}
+	return "processors/" + sink.Name
+}
+
+func icebergSinkDeploymentName(sink *kafscalev1alpha1.KafscaleIcebergSink) string {
+	return sink.Name + "-iceberg-sink"
+}
+
+func icebergSinkConfigMapName(sink *kafscalev1alpha1.KafscaleIcebergSink) string {
+	return sink.Name + "-iceberg-sink-config"
+}
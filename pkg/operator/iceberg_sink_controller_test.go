@@ -0,0 +1,153 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+)
+
+func TestSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"bucket":               "bucket",
+		"createTableIfMissing": "create_table_if_missing",
+		"leaseTTLSeconds":      "lease_ttl_seconds",
+		"baseURL":              "base_url",
+		"catalogID":            "catalog_id",
+		"initialBackoffMs":     "initial_backoff_ms",
+		"s3":                   "s3",
+	}
+	for in, want := range cases {
+		if got := snakeCase(in); got != want {
+			t.Fatalf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIcebergSinkReconcilerDeploysWorkersAndReportsLag(t *testing.T) {
+	endpoints := startTestEtcd(t)
+	cluster := testCluster("prod", endpoints)
+	cluster.Spec.S3.CredentialsSecretRef = "s3-creds"
+	replicas := int32(2)
+	sink := &kafscalev1alpha1.KafscaleIcebergSink{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleIcebergSinkSpec{
+			ClusterRef:            "prod",
+			ProcessorWorkloadSpec: kafscalev1alpha1.ProcessorWorkloadSpec{Replicas: &replicas},
+			IcebergProcessorConfig: kafscalev1alpha1.IcebergProcessorConfig{
+				Iceberg: kafscalev1alpha1.IcebergConfig{
+					Catalog: kafscalev1alpha1.IcebergCatalogConfig{
+						Type:                 "rest",
+						URI:                  "http://catalog:8181",
+						CredentialsSecretRef: "catalog-creds",
+					},
+				},
+				Schema: kafscalev1alpha1.IcebergSchemaConfig{
+					Registry: kafscalev1alpha1.IcebergRegistryConfig{BaseURL: "http://registry"},
+				},
+				Mappings: []kafscalev1alpha1.IcebergMapping{{
+					Topic:                "orders",
+					Table:                "prod.orders",
+					CreateTableIfMissing: true,
+				}},
+				Offsets: kafscalev1alpha1.IcebergOffsetsConfig{LeaseTTLSeconds: 15},
+			},
+		},
+	}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, sink).WithStatusSubresource(sink).Build()
+	r := &IcebergSinkReconciler{Client: c, Scheme: scheme}
+
+	cli := testEtcdClient(t, endpoints)
+	ctx := context.Background()
+	for key, value := range map[string]string{
+		"/kafscale/topics/orders/partitions/0/next_offset": "100",
+		"/kafscale/topics/orders/partitions/1/next_offset": "40",
+		"processors/orders/offsets/orders/0":               `{"offset":89,"last_timestamp_ms":1700000000000}`,
+		"processors/orders/watermarks/orders":              `{"offset":89,"last_timestamp_ms":1700000000000}`,
+	} {
+		if _, err := cli.Put(ctx, key, value); err != nil {
+			t.Fatalf("seed %s: %v", key, err)
+		}
+	}
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "orders"}}); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	assertFound(t, c, cm, "default", "orders-iceberg-sink-config")
+	var rendered map[string]interface{}
+	if err := yaml.Unmarshal([]byte(cm.Data[processorConfigKey]), &rendered); err != nil {
+		t.Fatalf("parse rendered config: %v", err)
+	}
+	config := cm.Data[processorConfigKey]
+	for _, want := range []string{"create_table_if_missing: true", "base_url: http://registry", "lease_ttl_seconds: 15", "key_prefix: processors/orders", "backend: etcd", "bucket: bucket", "namespace: default"} {
+		if !strings.Contains(config, want) {
+			t.Fatalf("expected %q in rendered config:\n%s", want, config)
+		}
+	}
+	if strings.Contains(config, "credentials_secret_ref") {
+		t.Fatalf("secret reference leaked into config:\n%s", config)
+	}
+
+	deploy := &appsv1.Deployment{}
+	assertFound(t, c, deploy, "default", "orders-iceberg-sink")
+	if *deploy.Spec.Replicas != 2 || deploy.Spec.Template.Annotations[processorConfigHashKey] == "" {
+		t.Fatalf("unexpected deployment spec: %+v", deploy.Spec)
+	}
+	container := deploy.Spec.Template.Spec.Containers[0]
+	if container.Image != icebergProcessorImage || container.Args[0] != "-config=/config/config.yaml" {
+		t.Fatalf("unexpected container: %+v", container)
+	}
+	if len(container.EnvFrom) != 1 || container.EnvFrom[0].SecretRef.Name != "s3-creds" {
+		t.Fatalf("expected S3 credentials from cluster secret, got %+v", container.EnvFrom)
+	}
+	var tokenFromSecret bool
+	for _, env := range container.Env {
+		if env.Name == "ICEBERG_CATALOG_TOKEN" && env.ValueFrom.SecretKeyRef.Name == "catalog-creds" {
+			tokenFromSecret = true
+		}
+	}
+	if !tokenFromSecret {
+		t.Fatalf("expected catalog token from secret, got %+v", container.Env)
+	}
+
+	var updated kafscalev1alpha1.KafscaleIcebergSink
+	assertFound(t, c, &updated, "default", "orders")
+	if len(updated.Status.Topics) != 1 {
+		t.Fatalf("expected one topic status, got %+v", updated.Status.Topics)
+	}
+	topic := updated.Status.Topics[0]
+	// Partition 0 has 10 unprocessed records, partition 1 has never been processed.
+	if topic.Lag != 50 || updated.Status.TotalLag != 50 {
+		t.Fatalf("unexpected lag: %+v (total %d)", topic, updated.Status.TotalLag)
+	}
+	if topic.WatermarkOffset != 89 || topic.WatermarkTime == nil || topic.WatermarkTime.UnixMilli() != 1700000000000 {
+		t.Fatalf("unexpected watermark: %+v", topic)
+	}
+}
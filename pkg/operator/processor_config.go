@@ -0,0 +1,312 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+)
+
+const (
+	processorConfigKey       = "config.yaml"
+	processorConfigMountPath = "/config"
+	processorConfigHashKey   = "kafscale.io/config-hash"
+	processorStatusRequeue   = 30 * time.Second
+)
+
+// renderProcessorConfig converts a CRD config block into the processor's
+// YAML config file. CRD fields use camelCase JSON names while the processors
+// read snake_case keys, so keys are rewritten recursively; overrides are
+// merged on top so operator-owned settings always win.
+func renderProcessorConfig(spec interface{}, overrides map[string]interface{}, drop ...string) (string, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return "", err
+	}
+	out, _ := snakeCaseKeys(doc).(map[string]interface{})
+	if out == nil {
+		out = map[string]interface{}{}
+	}
+	for _, path := range drop {
+		deletePath(out, strings.Split(path, "."))
+	}
+	mergeConfig(out, overrides)
+	rendered, err := yaml.Marshal(out)
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
+
+func snakeCaseKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[snakeCase(key)] = snakeCaseKeys(item)
+		}
+		return out
+	case []interface{}:
+		for i := range v {
+			v[i] = snakeCaseKeys(v[i])
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// snakeCase converts a camelCase JSON name, keeping acronyms together:
+// leaseTTLSeconds becomes lease_ttl_seconds and baseURL becomes base_url.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					b.WriteByte('_')
+				}
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func deletePath(doc map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(doc, path[0])
+		return
+	}
+	if child, ok := doc[path[0]].(map[string]interface{}); ok {
+		deletePath(child, path[1:])
+	}
+}
+
+func mergeConfig(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if child, ok := value.(map[string]interface{}); ok {
+			existing, ok := dst[key].(map[string]interface{})
+			if !ok {
+				existing = map[string]interface{}{}
+				dst[key] = existing
+			}
+			mergeConfig(existing, child)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// processorS3Config points a processor at the cluster's segment bucket.
+func processorS3Config(cluster *kafscalev1alpha1.KafscaleCluster) map[string]interface{} {
+	s3 := map[string]interface{}{
+		"bucket":    cluster.Spec.S3.Bucket,
+		"namespace": cluster.Namespace,
+		"region":    cluster.Spec.S3.Region,
+	}
+	if endpoint := strings.TrimSpace(cluster.Spec.S3.Endpoint); endpoint != "" {
+		s3["endpoint"] = endpoint
+		s3["path_style"] = true
+	}
+	return s3
+}
+
+// reconcileProcessorConfigMap stores the rendered config and returns its
+// hash, which is stamped on the pod template so config edits roll the pods.
+func reconcileProcessorConfigMap(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner metav1.Object, name, config string) (string, error) {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.GetNamespace()}}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, cm, func() error {
+		cm.Data = map[string]string{processorConfigKey: config}
+		return controllerutil.SetControllerReference(owner, cm, scheme)
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// processorWorkload describes a processor Deployment: one container running
+// the processor binary against a mounted config file.
+type processorWorkload struct {
+	Name         string
+	ConfigMap    string
+	ConfigHash   string
+	Labels       map[string]string
+	Spec         kafscalev1alpha1.ProcessorWorkloadSpec
+	DefaultImage string
+	PullPolicy   string
+	Container    corev1.Container
+}
+
+func reconcileProcessorDeployment(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner metav1.Object, cluster *kafscalev1alpha1.KafscaleCluster, w processorWorkload) (*appsv1.Deployment, error) {
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: w.Name, Namespace: owner.GetNamespace()}}
+	_, err := controllerutil.CreateOrUpdate(ctx, c, deploy, func() error {
+		replicas := int32(1)
+		if w.Spec.Replicas != nil {
+			replicas = *w.Spec.Replicas
+		}
+		container := w.Container
+		container.Image = w.DefaultImage
+		if image := strings.TrimSpace(w.Spec.Image); image != "" {
+			container.Image = image
+		}
+		container.ImagePullPolicy = parsePullPolicy(w.PullPolicy)
+		if w.Spec.ImagePullPolicy != "" {
+			container.ImagePullPolicy = parsePullPolicy(w.Spec.ImagePullPolicy)
+		}
+		container.Args = []string{fmt.Sprintf("-config=%s/%s", processorConfigMountPath, processorConfigKey)}
+		container.VolumeMounts = []corev1.VolumeMount{{Name: "config", MountPath: processorConfigMountPath, ReadOnly: true}}
+		if cluster.Spec.S3.CredentialsSecretRef != "" {
+			container.EnvFrom = append(container.EnvFrom, corev1.EnvFromSource{
+				SecretRef: &corev1.SecretEnvSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: cluster.Spec.S3.CredentialsSecretRef},
+					Optional:             boolPtr(true),
+				},
+			})
+		}
+		if w.Spec.Resources != nil {
+			container.Resources = *w.Spec.Resources.DeepCopy()
+		}
+
+		deploy.Spec.Replicas = &replicas
+		deploy.Spec.Selector = &metav1.LabelSelector{MatchLabels: w.Labels}
+		deploy.Spec.Template.ObjectMeta.Labels = w.Labels
+		deploy.Spec.Template.ObjectMeta.Annotations = map[string]string{processorConfigHashKey: w.ConfigHash}
+		deploy.Spec.Template.Spec.Containers = []corev1.Container{container}
+		deploy.Spec.Template.Spec.Volumes = []corev1.Volume{{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: w.ConfigMap},
+				},
+			},
+		}}
+		return controllerutil.SetControllerReference(owner, deploy, scheme)
+	})
+	return deploy, err
+}
+
+type processorCheckpoint struct {
+	Offset          int64 `json:"offset"`
+	LastTimestampMs int64 `json:"last_timestamp_ms"`
+}
+
+// readProcessorTopicStatus compares the processor's committed offsets under
+// keyPrefix with the broker log end offsets to compute lag per topic.
+func readProcessorTopicStatus(ctx context.Context, endpoints []string, keyPrefix, topic string) (kafscalev1alpha1.ProcessorTopicStatus, error) {
+	status := kafscalev1alpha1.ProcessorTopicStatus{Topic: topic, WatermarkOffset: -1}
+	cli, err := newOperatorEtcdClient(endpoints)
+	if err != nil {
+		return status, err
+	}
+	defer cli.Close()
+	getCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	logPrefix := fmt.Sprintf("/kafscale/topics/%s/partitions/", topic)
+	resp, err := cli.Get(getCtx, logPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return status, err
+	}
+	nextOffsets := make(map[string]int64)
+	for _, kv := range resp.Kvs {
+		id, suffix, _ := strings.Cut(strings.TrimPrefix(string(kv.Key), logPrefix), "/")
+		if suffix != "next_offset" {
+			continue
+		}
+		if offset, err := strconv.ParseInt(string(kv.Value), 10, 64); err == nil {
+			nextOffsets[id] = offset
+		}
+	}
+
+	offsetPrefix := fmt.Sprintf("%s/offsets/%s/", keyPrefix, topic)
+	resp, err = cli.Get(getCtx, offsetPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return status, err
+	}
+	committed := make(map[string]int64)
+	for _, kv := range resp.Kvs {
+		var cp processorCheckpoint
+		if err := json.Unmarshal(kv.Value, &cp); err != nil {
+			continue
+		}
+		committed[strings.TrimPrefix(string(kv.Key), offsetPrefix)] = cp.Offset
+	}
+	for partition, next := range nextOffsets {
+		processed, ok := committed[partition]
+		if !ok {
+			processed = -1
+		}
+		if lag := next - (processed + 1); lag > 0 {
+			status.Lag += lag
+		}
+	}
+
+	resp, err = cli.Get(getCtx, fmt.Sprintf("%s/watermarks/%s", keyPrefix, topic))
+	if err != nil {
+		return status, err
+	}
+	if len(resp.Kvs) > 0 {
+		var cp processorCheckpoint
+		if err := json.Unmarshal(resp.Kvs[0].Value, &cp); err == nil {
+			status.WatermarkOffset = cp.Offset
+			if cp.LastTimestampMs > 0 {
+				ts := metav1.NewTime(time.UnixMilli(cp.LastTimestampMs).UTC())
+				status.WatermarkTime = &ts
+			}
+		}
+	}
+	return status, nil
+}
+
+func processorPhase(deploy *appsv1.Deployment) string {
+	switch {
+	case deploy.Status.ReadyReplicas == 0:
+		return "Pending"
+	case deploy.Spec.Replicas != nil && deploy.Status.ReadyReplicas < *deploy.Spec.Replicas:
+		return "Progressing"
+	default:
+		return "Ready"
+	}
+}
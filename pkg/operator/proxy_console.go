@@ -0,0 +1,318 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/metadata"
+)
+
+const (
+	defaultProxyImage   = "ghcr.io/kafscale/kafscale-proxy:latest"
+	defaultConsoleImage = "ghcr.io/kafscale/kafscale-console:latest"
+	proxyKafkaPort      = 9092
+	proxyHealthPort     = 9094
+	consoleHTTPPort     = 8080
+
+	proxyReadyCondition = "ProxyReady"
+)
+
+var proxyImage = getEnv("PROXY_IMAGE", defaultProxyImage)
+var proxyImagePullPolicy = getEnv("PROXY_IMAGE_PULL_POLICY", defaultBrokerImagePullPolicy)
+var consoleImage = getEnv("CONSOLE_IMAGE", defaultConsoleImage)
+var consoleImagePullPolicy = getEnv("CONSOLE_IMAGE_PULL_POLICY", defaultBrokerImagePullPolicy)
+
+func proxyName(cluster *kafscalev1alpha1.KafscaleCluster) string {
+	return fmt.Sprintf("%s-proxy", cluster.Name)
+}
+
+func consoleName(cluster *kafscalev1alpha1.KafscaleCluster) string {
+	return fmt.Sprintf("%s-console", cluster.Name)
+}
+
+func componentLabels(cluster *kafscalev1alpha1.KafscaleCluster, app string) map[string]string {
+	return map[string]string{
+		"app":     app,
+		"cluster": cluster.Name,
+	}
+}
+
+// proxyAdvertisedEndpoint is the address clients are told to use. Without an
+// explicit host it falls back to the proxy Service DNS name.
+func proxyAdvertisedEndpoint(cluster *kafscalev1alpha1.KafscaleCluster) (string, int32) {
+	host := strings.TrimSpace(cluster.Spec.Proxy.AdvertisedHost)
+	if host == "" {
+		host = fmt.Sprintf("%s.%s.svc.cluster.local", proxyName(cluster), cluster.Namespace)
+	}
+	port := int32(proxyKafkaPort)
+	if p := cluster.Spec.Proxy.AdvertisedPort; p != nil && *p > 0 {
+		port = *p
+	}
+	return host, port
+}
+
+// proxySnapshotEndpoint returns the proxy endpoint to publish in the
+// metadata snapshot, or nil until the proxy has been exposed.
+func proxySnapshotEndpoint(cluster *kafscalev1alpha1.KafscaleCluster) *metadata.ProxyEndpoint {
+	if cluster.Spec.Proxy == nil || !meta.IsStatusConditionTrue(cluster.Status.Conditions, proxyReadyCondition) {
+		return nil
+	}
+	host, port := proxyAdvertisedEndpoint(cluster)
+	return &metadata.ProxyEndpoint{Host: host, Port: port}
+}
+
+// reconcileProxy runs the proxy Deployment and, once every broker is ready,
+// the Service that exposes it. An exposed proxy stays exposed while brokers
+// roll or scale, since it routes around brokers that are not ready.
+func (r *ClusterReconciler) reconcileProxy(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, endpoints []string) error {
+	if cluster.Spec.Proxy == nil {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, proxyReadyCondition)
+		return r.deleteComponent(ctx, cluster, proxyName(cluster))
+	}
+	labels := componentLabels(cluster, "kafscale-proxy")
+	if err := r.reconcileComponentDeployment(ctx, cluster, proxyName(cluster), labels, cluster.Spec.Proxy.Replicas, r.proxyContainer(cluster, endpoints)); err != nil {
+		return err
+	}
+
+	svc := &corev1.Service{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: proxyName(cluster)}, svc)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if apierrors.IsNotFound(err) {
+		ready, err := r.brokersReady(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			setClusterCondition(&cluster.Status.Conditions, metav1.Condition{
+				Type:               proxyReadyCondition,
+				Status:             metav1.ConditionFalse,
+				Reason:             "WaitingForBrokers",
+				Message:            "Proxy Service is created once all brokers are ready.",
+				LastTransitionTime: metav1.NewTime(time.Now()),
+			})
+			return nil
+		}
+	}
+	// Clients dial the advertised port, so the Service listens on it and
+	// forwards to the container's fixed kafka port.
+	host, advertisedPort := proxyAdvertisedEndpoint(cluster)
+	port := corev1.ServicePort{Name: "kafka", Port: advertisedPort, TargetPort: intstr.FromString("kafka")}
+	if err := r.reconcileComponentService(ctx, cluster, proxyName(cluster), labels, port, &cluster.Spec.Proxy.Service); err != nil {
+		return err
+	}
+	setClusterCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               proxyReadyCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Exposed",
+		Message:            fmt.Sprintf("Proxy advertised at %s:%d", host, advertisedPort),
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	})
+	return nil
+}
+
+// reconcileConsole runs the console Deployment and Service.
+func (r *ClusterReconciler) reconcileConsole(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, endpoints []string) error {
+	if cluster.Spec.Console == nil {
+		return r.deleteComponent(ctx, cluster, consoleName(cluster))
+	}
+	labels := componentLabels(cluster, "kafscale-console")
+	if err := r.reconcileComponentDeployment(ctx, cluster, consoleName(cluster), labels, cluster.Spec.Console.Replicas, r.consoleContainer(cluster, endpoints)); err != nil {
+		return err
+	}
+	port := corev1.ServicePort{Name: "http", Port: consoleHTTPPort, TargetPort: intstr.FromString("http")}
+	return r.reconcileComponentService(ctx, cluster, consoleName(cluster), labels, port, &cluster.Spec.Console.Service)
+}
+
+// brokersReady reports whether every desired broker pod is ready.
+func (r *ClusterReconciler) brokersReady(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster) (bool, error) {
+	sts := &appsv1.StatefulSet{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: fmt.Sprintf("%s-broker", cluster.Name)}, sts); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	return desired > 0 && sts.Status.ReadyReplicas >= desired, nil
+}
+
+func (r *ClusterReconciler) proxyContainer(cluster *kafscalev1alpha1.KafscaleCluster, endpoints []string) corev1.Container {
+	spec := cluster.Spec.Proxy
+	host, port := proxyAdvertisedEndpoint(cluster)
+	image := proxyImage
+	if strings.TrimSpace(spec.Image) != "" {
+		image = strings.TrimSpace(spec.Image)
+	}
+	container := corev1.Container{
+		Name:            "proxy",
+		Image:           image,
+		ImagePullPolicy: parsePullPolicy(proxyImagePullPolicy),
+		Env: []corev1.EnvVar{
+			{Name: "KAFSCALE_PROXY_ADDR", Value: fmt.Sprintf(":%d", proxyKafkaPort)},
+			{Name: "KAFSCALE_PROXY_HEALTH_ADDR", Value: fmt.Sprintf(":%d", proxyHealthPort)},
+			{Name: "KAFSCALE_PROXY_ADVERTISED_HOST", Value: host},
+			{Name: "KAFSCALE_PROXY_ADVERTISED_PORT", Value: fmt.Sprintf("%d", port)},
+			{Name: "KAFSCALE_PROXY_ETCD_ENDPOINTS", Value: strings.Join(endpoints, ",")},
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "kafka", ContainerPort: proxyKafkaPort},
+			{Name: "health", ContainerPort: proxyHealthPort},
+		},
+		// The proxy only reports ready once it has broker backends.
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler:     corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/readyz", Port: intstr.FromString("health")}},
+			PeriodSeconds:    5,
+			FailureThreshold: 6,
+		},
+		LivenessProbe: &corev1.Probe{
+			ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/livez", Port: intstr.FromString("health")}},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       10,
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: cloneResourceList(spec.Resources.Requests),
+			Limits:   cloneResourceList(spec.Resources.Limits),
+		},
+	}
+	if spec.AuthSecretRef != "" {
+		container.EnvFrom = append(container.EnvFrom, corev1.EnvFromSource{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: spec.AuthSecretRef}},
+		})
+	}
+	return container
+}
+
+func (r *ClusterReconciler) consoleContainer(cluster *kafscalev1alpha1.KafscaleCluster, endpoints []string) corev1.Container {
+	spec := cluster.Spec.Console
+	image := consoleImage
+	if strings.TrimSpace(spec.Image) != "" {
+		image = strings.TrimSpace(spec.Image)
+	}
+	brokerMetrics := fmt.Sprintf("http://%s-broker.%s.svc.cluster.local:9093/metrics", cluster.Name, cluster.Namespace)
+	container := corev1.Container{
+		Name:            "console",
+		Image:           image,
+		ImagePullPolicy: parsePullPolicy(consoleImagePullPolicy),
+		Env: []corev1.EnvVar{
+			{Name: "KAFSCALE_CONSOLE_HTTP_ADDR", Value: fmt.Sprintf(":%d", consoleHTTPPort)},
+			{Name: "KAFSCALE_CONSOLE_ETCD_ENDPOINTS", Value: strings.Join(endpoints, ",")},
+			{Name: "KAFSCALE_CONSOLE_BROKER_METRICS_URL", Value: brokerMetrics},
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "http", ContainerPort: consoleHTTPPort},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("http")}},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       10,
+		},
+		LivenessProbe: &corev1.Probe{
+			ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromString("http")}},
+			InitialDelaySeconds: 15,
+			PeriodSeconds:       20,
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: cloneResourceList(spec.Resources.Requests),
+			Limits:   cloneResourceList(spec.Resources.Limits),
+		},
+	}
+	if spec.AuthSecretRef != "" {
+		container.EnvFrom = append(container.EnvFrom, corev1.EnvFromSource{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: spec.AuthSecretRef}},
+		})
+	}
+	return container
+}
+
+func (r *ClusterReconciler) reconcileComponentDeployment(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, name string, labels map[string]string, replicas *int32, container corev1.Container) error {
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, deploy, func() error {
+		count := int32(1)
+		if replicas != nil {
+			count = *replicas
+		}
+		deploy.Spec.Replicas = &count
+		deploy.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		deploy.Spec.Template.ObjectMeta.Labels = labels
+		deploy.Spec.Template.Spec.Containers = []corev1.Container{container}
+		return controllerutil.SetControllerReference(cluster, deploy, r.Scheme)
+	})
+	return err
+}
+
+func (r *ClusterReconciler) reconcileComponentService(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, name string, labels map[string]string, port corev1.ServicePort, spec *kafscalev1alpha1.ComponentServiceSpec) error {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		svc.Spec.Selector = labels
+		svc.Spec.Type = parseServiceType(spec.Type)
+		if svc.Spec.Type == "" {
+			svc.Spec.Type = corev1.ServiceTypeClusterIP
+		}
+		if nodePort := spec.NodePort; nodePort != nil && *nodePort > 0 && svc.Spec.Type != corev1.ServiceTypeClusterIP {
+			port.NodePort = *nodePort
+		}
+		svc.Spec.Ports = []corev1.ServicePort{port}
+		if annotations := spec.Annotations; len(annotations) > 0 {
+			svc.Annotations = copyStringMap(annotations)
+		}
+		if strings.TrimSpace(spec.LoadBalancerIP) != "" {
+			svc.Spec.LoadBalancerIP = strings.TrimSpace(spec.LoadBalancerIP)
+		}
+		if ranges := spec.LoadBalancerSourceRanges; len(ranges) > 0 {
+			svc.Spec.LoadBalancerSourceRanges = append([]string(nil), ranges...)
+		}
+		return controllerutil.SetControllerReference(cluster, svc, r.Scheme)
+	})
+	return err
+}
+
+// deleteComponent removes the Deployment and Service left behind when a
+// proxy or console section is dropped from the spec. Objects the cluster does
+// not own, such as a Helm-managed proxy with the same name, are left alone.
+func (r *ClusterReconciler) deleteComponent(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, name string) error {
+	key := client.ObjectKey{Namespace: cluster.Namespace, Name: name}
+	for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}} {
+		if err := r.Client.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !metav1.IsControlledBy(obj, cluster) {
+			continue
+		}
+		if err := r.Client.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+)
+
+func TestReconcileProxyWaitsForBrokers(t *testing.T) {
+	ctx := context.Background()
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	cluster.UID = "demo-uid"
+	cluster.Spec.Proxy = &kafscalev1alpha1.ProxySpec{Replicas: ptr.To(int32(2))}
+	brokers := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-broker", Namespace: cluster.Namespace},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(3))},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+	}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, brokers).Build()
+	r := &ClusterReconciler{Client: c, Scheme: scheme}
+
+	if err := r.reconcileProxy(ctx, cluster, []string{"http://etcd:2379"}); err != nil {
+		t.Fatalf("reconcile proxy: %v", err)
+	}
+	deploy := &appsv1.Deployment{}
+	assertFound(t, c, deploy, cluster.Namespace, "demo-proxy")
+	if *deploy.Spec.Replicas != 2 {
+		t.Fatalf("expected 2 proxy replicas, got %d", *deploy.Spec.Replicas)
+	}
+	if resourceFound(c, &corev1.Service{}, cluster.Namespace, "demo-proxy") {
+		t.Fatalf("expected proxy service to wait for brokers")
+	}
+	if cond := meta.FindStatusCondition(cluster.Status.Conditions, proxyReadyCondition); cond == nil || cond.Reason != "WaitingForBrokers" {
+		t.Fatalf("expected WaitingForBrokers condition, got %+v", cond)
+	}
+	if snap := BuildClusterMetadata(cluster, nil); snap.Proxy != nil {
+		t.Fatalf("expected no proxy in snapshot before exposure, got %+v", snap.Proxy)
+	}
+
+	brokers.Status.ReadyReplicas = 3
+	if err := c.Status().Update(ctx, brokers); err != nil {
+		t.Fatalf("update brokers: %v", err)
+	}
+	if err := r.reconcileProxy(ctx, cluster, []string{"http://etcd:2379"}); err != nil {
+		t.Fatalf("reconcile proxy: %v", err)
+	}
+	svc := &corev1.Service{}
+	assertFound(t, c, svc, cluster.Namespace, "demo-proxy")
+	if svc.Spec.Type != corev1.ServiceTypeClusterIP || svc.Spec.Ports[0].Port != 9092 {
+		t.Fatalf("unexpected proxy service: %+v", svc.Spec)
+	}
+	snap := BuildClusterMetadata(cluster, nil)
+	if snap.Proxy == nil || snap.Proxy.Host != "demo-proxy.default.svc.cluster.local" || snap.Proxy.Port != 9092 {
+		t.Fatalf("expected proxy endpoint in snapshot, got %+v", snap.Proxy)
+	}
+
+	// A broker restart must not withdraw an already exposed proxy.
+	brokers.Status.ReadyReplicas = 2
+	if err := c.Status().Update(ctx, brokers); err != nil {
+		t.Fatalf("update brokers: %v", err)
+	}
+	if err := r.reconcileProxy(ctx, cluster, nil); err != nil {
+		t.Fatalf("reconcile proxy: %v", err)
+	}
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, proxyReadyCondition) {
+		t.Fatalf("expected proxy to stay exposed")
+	}
+}
+
+func TestReconcileProxyServiceUsesAdvertisedPort(t *testing.T) {
+	ctx := context.Background()
+	cluster := testCluster("demo", []string{"http://etcd:2379"})
+	cluster.UID = "demo-uid"
+	cluster.Spec.Proxy = &kafscalev1alpha1.ProxySpec{AdvertisedPort: ptr.To(int32(19092))}
+	brokers := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-broker", Namespace: cluster.Namespace},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(1))},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+	}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, brokers).Build()
+	r := &ClusterReconciler{Client: c, Scheme: scheme}
+
+	if err := r.reconcileProxy(ctx, cluster, []string{"http://etcd:2379"}); err != nil {
+		t.Fatalf("reconcile proxy: %v", err)
+	}
+	svc := &corev1.Service{}
+	assertFound(t, c, svc, cluster.Namespace, "demo-proxy")
+	if port := svc.Spec.Ports[0]; port.Port != 19092 || port.TargetPort.String() != "kafka" {
+		t.Fatalf("expected service port 19092 targeting kafka, got %+v", port)
+	}
+	snap := BuildClusterMetadata(cluster, nil)
+	if snap.Proxy == nil || snap.Proxy.Host != "demo-proxy.default.svc.cluster.local" || snap.Proxy.Port != 19092 {
+		t.Fatalf("expected advertised endpoint to match the service, got %+v", snap.Proxy)
+	}
+}
+
+func TestProxyAndConsoleContainers(t *testing.T) {
+	cluster := testCluster("demo", nil)
+	cluster.Spec.Proxy = &kafscalev1alpha1.ProxySpec{
+		AdvertisedHost: "kafka.example.com",
+		AdvertisedPort: ptr.To(int32(19092)),
+		AuthSecretRef:  "proxy-etcd",
+	}
+	cluster.Spec.Console = &kafscalev1alpha1.ConsoleSpec{AuthSecretRef: "console-auth"}
+	r := &ClusterReconciler{}
+
+	proxy := r.proxyContainer(cluster, []string{"http://a:2379", "http://b:2379"})
+	if got := envValue(proxy.Env, "KAFSCALE_PROXY_ADVERTISED_HOST"); got != "kafka.example.com" {
+		t.Fatalf("expected advertised host, got %q", got)
+	}
+	if got := envValue(proxy.Env, "KAFSCALE_PROXY_ADVERTISED_PORT"); got != "19092" {
+		t.Fatalf("expected advertised port, got %q", got)
+	}
+	if got := envValue(proxy.Env, "KAFSCALE_PROXY_ETCD_ENDPOINTS"); got != "http://a:2379,http://b:2379" {
+		t.Fatalf("expected etcd endpoints, got %q", got)
+	}
+	if proxy.ReadinessProbe == nil || proxy.ReadinessProbe.HTTPGet.Path != "/readyz" {
+		t.Fatalf("expected /readyz readiness probe, got %+v", proxy.ReadinessProbe)
+	}
+	if len(proxy.EnvFrom) != 1 || proxy.EnvFrom[0].SecretRef.Name != "proxy-etcd" {
+		t.Fatalf("expected proxy auth secret, got %+v", proxy.EnvFrom)
+	}
+
+	console := r.consoleContainer(cluster, []string{"http://a:2379"})
+	if got := envValue(console.Env, "KAFSCALE_CONSOLE_ETCD_ENDPOINTS"); got != "http://a:2379" {
+		t.Fatalf("expected console etcd endpoints, got %q", got)
+	}
+	if got := envValue(console.Env, "KAFSCALE_CONSOLE_BROKER_METRICS_URL"); got != "http://demo-broker.default.svc.cluster.local:9093/metrics" {
+		t.Fatalf("unexpected broker metrics url %q", got)
+	}
+	if len(console.EnvFrom) != 1 || console.EnvFrom[0].SecretRef.Name != "console-auth" {
+		t.Fatalf("expected console auth secret, got %+v", console.EnvFrom)
+	}
+}
+
+func TestReconcileConsoleRemovedWhenDisabled(t *testing.T) {
+	ctx := context.Background()
+	cluster := testCluster("demo", nil)
+	cluster.UID = "demo-uid"
+	cluster.Spec.Console = &kafscalev1alpha1.ConsoleSpec{
+		Service: kafscalev1alpha1.ComponentServiceSpec{Type: "NodePort", NodePort: ptr.To(int32(30080))},
+	}
+	// A proxy Service the cluster does not own, e.g. from the Helm chart.
+	foreign := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "demo-proxy", Namespace: cluster.Namespace}}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, foreign).Build()
+	r := &ClusterReconciler{Client: c, Scheme: scheme}
+
+	if err := r.reconcileConsole(ctx, cluster, nil); err != nil {
+		t.Fatalf("reconcile console: %v", err)
+	}
+	svc := &corev1.Service{}
+	assertFound(t, c, svc, cluster.Namespace, "demo-console")
+	if svc.Spec.Type != corev1.ServiceTypeNodePort || svc.Spec.Ports[0].NodePort != 30080 {
+		t.Fatalf("unexpected console service: %+v", svc.Spec)
+	}
+
+	cluster.Spec.Console = nil
+	if err := r.reconcileConsole(ctx, cluster, nil); err != nil {
+		t.Fatalf("reconcile console: %v", err)
+	}
+	if err := r.reconcileProxy(ctx, cluster, nil); err != nil {
+		t.Fatalf("reconcile proxy: %v", err)
+	}
+	if resourceFound(c, &appsv1.Deployment{}, cluster.Namespace, "demo-console") || resourceFound(c, &corev1.Service{}, cluster.Namespace, "demo-console") {
+		t.Fatalf("expected console resources to be removed")
+	}
+	if !resourceFound(c, &corev1.Service{}, cluster.Namespace, "demo-proxy") {
+		t.Fatalf("expected unowned proxy service to be kept")
+	}
+}
+
+func resourceFound(c client.Client, obj client.Object, ns, name string) bool {
+	return c.Get(context.Background(), client.ObjectKey{Namespace: ns, Name: name}, obj) == nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2025-2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"bytes"
+	"context"
+	"time"
+)
+
+// putEtcdValue writes value under key unless it is already stored there.
+func putEtcdValue(ctx context.Context, endpoints []string, key string, value []byte) error {
+	cli, err := newOperatorEtcdClient(endpoints)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	opCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := cli.Get(opCtx, key)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) > 0 && bytes.Equal(resp.Kvs[0].Value, value) {
+		return nil
+	}
+	_, err = cli.Put(opCtx, key, string(value))
+	return err
+}
+
+func getEtcdValue(ctx context.Context, endpoints []string, key string) ([]byte, error) {
+	cli, err := newOperatorEtcdClient(endpoints)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+	opCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := cli.Get(opCtx, key)
+	if err != nil || len(resp.Kvs) == 0 {
+		return nil, err
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+func deleteEtcdKey(ctx context.Context, endpoints []string, key string) error {
+	cli, err := newOperatorEtcdClient(endpoints)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+	opCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err = cli.Delete(opCtx, key)
+	return err
+}
@@ -56,7 +56,7 @@ func (p *SnapshotPublisher) Publish(ctx context.Context, cluster *kafscalev1alph
 	}
 	topics := make([]kafscalev1alpha1.KafscaleTopic, 0, len(topicList.Items))
 	for _, topic := range topicList.Items {
-		if topic.Spec.ClusterRef == cluster.Name {
+		if topic.Spec.ClusterRef == cluster.Name && topic.DeletionTimestamp == nil {
 			topics = append(topics, topic)
 		}
 	}
@@ -188,6 +188,7 @@ func BuildClusterMetadata(cluster *kafscalev1alpha1.KafscaleCluster, topics []ka
 		Topics:       metaTopics,
 		ClusterName:  clusterNamePtr,
 		ClusterID:    clusterIDPtr,
+		Proxy:        proxySnapshotEndpoint(cluster),
 	}
 }
 
@@ -283,22 +284,27 @@ func PublishMetadataSnapshot(ctx context.Context, endpoints []string, snapshot m
 	return lastErr
 }
 
+// mergeSnapshots carries over topics that only exist in etcd and never
+// shrinks a topic's partition list, since partitions cannot be removed.
 func mergeSnapshots(next, existing metadata.ClusterMetadata) metadata.ClusterMetadata {
 	if len(existing.Topics) == 0 {
 		return next
 	}
-	seen := make(map[string]struct{}, len(next.Topics))
-	for _, topic := range next.Topics {
+	seen := make(map[string]int, len(next.Topics))
+	for i, topic := range next.Topics {
 		if topic.Name == "" {
 			continue
 		}
-		seen[topic.Name] = struct{}{}
+		seen[topic.Name] = i
 	}
 	for _, topic := range existing.Topics {
 		if topic.Name == "" || topic.ErrorCode != 0 {
 			continue
 		}
-		if _, ok := seen[topic.Name]; ok {
+		if idx, ok := seen[topic.Name]; ok {
+			if len(topic.Partitions) > len(next.Topics[idx].Partitions) {
+				next.Topics[idx].Partitions = topic.Partitions
+			}
 			continue
 		}
 		next.Topics = append(next.Topics, topic)
@@ -18,7 +18,6 @@ package operator
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -68,16 +67,7 @@ func (r *ClusterReconciler) verifySnapshotS3Access(ctx context.Context, cluster
 		return err
 	}
 
-	endpoint := strings.TrimSpace(os.Getenv(operatorEtcdSnapshotEndpointEnv))
-	if endpoint == "" {
-		endpoint = strings.TrimSpace(cluster.Spec.S3.Endpoint)
-	}
-	cfg := storage.S3Config{
-		Bucket:         bucket,
-		Region:         cluster.Spec.S3.Region,
-		Endpoint:       endpoint,
-		ForcePathStyle: endpoint != "",
-	}
+	cfg := snapshotS3Config(cluster)
 	if err := r.loadS3Credentials(ctx, cluster, &cfg); err != nil {
 		r.recordSnapshotAccessFailure(ctx, cluster, clusterKey, err)
 		return err
@@ -117,12 +107,16 @@ func (r *ClusterReconciler) verifySnapshotS3Access(ctx context.Context, cluster
 }
 
 func (r *ClusterReconciler) loadS3Credentials(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, cfg *storage.S3Config) error {
+	return loadS3Credentials(ctx, r.Client, cluster, cfg)
+}
+
+func loadS3Credentials(ctx context.Context, c client.Client, cluster *kafscalev1alpha1.KafscaleCluster, cfg *storage.S3Config) error {
 	if strings.TrimSpace(cluster.Spec.S3.CredentialsSecretRef) == "" {
 		return nil
 	}
 	secret := &corev1.Secret{}
 	key := client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.S3.CredentialsSecretRef}
-	if err := r.Client.Get(ctx, key, secret); err != nil {
+	if err := c.Get(ctx, key, secret); err != nil {
 		if apierrors.IsNotFound(err) {
 			return fmt.Errorf("s3 credentials secret %s not found", cluster.Spec.S3.CredentialsSecretRef)
 		}
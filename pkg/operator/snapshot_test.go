@@ -24,6 +24,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/metadata"
+	"github.com/KafScale/platform/pkg/protocol"
 )
 
 func TestBuildClusterMetadata(t *testing.T) {
@@ -114,3 +116,20 @@ func TestSleepWithContextCancel(t *testing.T) {
 		t.Fatalf("expected error on canceled context")
 	}
 }
+
+func TestMergeSnapshotsNeverShrinksPartitions(t *testing.T) {
+	existing := metadata.ClusterMetadata{Topics: []protocol.MetadataTopic{
+		{Name: "orders", Partitions: make([]protocol.MetadataPartition, 4)},
+		{Name: "legacy", Partitions: make([]protocol.MetadataPartition, 1)},
+	}}
+	next := metadata.ClusterMetadata{Topics: []protocol.MetadataTopic{
+		{Name: "orders", Partitions: make([]protocol.MetadataPartition, 2)},
+	}}
+	merged := mergeSnapshots(next, existing)
+	if len(merged.Topics) != 2 {
+		t.Fatalf("expected legacy topic to be carried over, got %+v", merged.Topics)
+	}
+	if len(merged.Topics[0].Partitions) != 4 {
+		t.Fatalf("expected orders to keep 4 partitions, got %d", len(merged.Topics[0].Partitions))
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+)
+
+const (
+	defaultSQLProcessorImage = "ghcr.io/kafscale/kafscale-sql-processor:latest"
+
+	sqlGatewayPostgresPort = 5432
+	sqlGatewayMetricsPort  = 9090
+	sqlGatewayFlightPort   = 8815
+	sqlGatewayHTTPPort     = 8080
+)
+
+var sqlProcessorImage = getEnv("SQL_PROCESSOR_IMAGE", defaultSQLProcessorImage)
+var sqlProcessorImagePullPolicy = getEnv("SQL_PROCESSOR_IMAGE_PULL_POLICY", defaultBrokerImagePullPolicy)
+
+// SQLGatewayReconciler runs sql-processor workers for KafscaleSQLGateway resources.
+type SQLGatewayReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func NewSQLGatewayReconciler(mgr ctrl.Manager) *SQLGatewayReconciler {
+	return &SQLGatewayReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+}
+
+func (r *SQLGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var gateway kafscalev1alpha1.KafscaleSQLGateway
+	if err := r.Get(ctx, req.NamespacedName, &gateway); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	var cluster kafscalev1alpha1.KafscaleCluster
+	if err := r.Get(ctx, types.NamespacedName{Name: gateway.Spec.ClusterRef, Namespace: gateway.Namespace}, &cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.setStatus(ctx, &gateway, metav1.ConditionFalse, "ClusterNotFound", fmt.Sprintf("KafscaleCluster %q not found", gateway.Spec.ClusterRef))
+		}
+		return ctrl.Result{}, err
+	}
+	etcdResolution, err := EnsureEtcd(ctx, r.Client, r.Scheme, &cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	server := map[string]interface{}{
+		"listen":         fmt.Sprintf(":%d", sqlGatewayPostgresPort),
+		"metrics_listen": fmt.Sprintf(":%d", sqlGatewayMetricsPort),
+	}
+	if gateway.Spec.Server.EnableFlight {
+		server["flight_listen"] = fmt.Sprintf(":%d", sqlGatewayFlightPort)
+	}
+	http := map[string]interface{}{}
+	if gateway.Spec.HTTP.Enabled {
+		http["listen"] = fmt.Sprintf(":%d", sqlGatewayHTTPPort)
+	}
+	config, err := renderProcessorConfig(gateway.Spec.SQLGatewayConfig, map[string]interface{}{
+		"s3":     processorS3Config(&cluster),
+		"server": server,
+		"http":   http,
+		"metadata": map[string]interface{}{
+			"discovery": "etcd",
+			"etcd":      map[string]interface{}{"endpoints": etcdResolution.Endpoints},
+		},
+	}, "server.enable_flight", "http.enabled")
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	hash, err := reconcileProcessorConfigMap(ctx, r.Client, r.Scheme, &gateway, sqlGatewayConfigMapName(&gateway), config)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	deploy, err := reconcileProcessorDeployment(ctx, r.Client, r.Scheme, &gateway, &cluster, processorWorkload{
+		Name:         sqlGatewayName(&gateway),
+		ConfigMap:    sqlGatewayConfigMapName(&gateway),
+		ConfigHash:   hash,
+		Labels:       sqlGatewayLabels(&gateway, &cluster),
+		Spec:         gateway.Spec.ProcessorWorkloadSpec,
+		DefaultImage: sqlProcessorImage,
+		PullPolicy:   sqlProcessorImagePullPolicy,
+		Container: corev1.Container{
+			Name:  "sql-processor",
+			Ports: sqlGatewayPorts(&gateway),
+		},
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileService(ctx, &gateway, &cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	gateway.Status.Replicas = deploy.Status.Replicas
+	gateway.Status.ReadyReplicas = deploy.Status.ReadyReplicas
+	gateway.Status.Phase = processorPhase(deploy)
+	gateway.Status.Endpoint = fmt.Sprintf("%s.%s.svc:%d", sqlGatewayName(&gateway), gateway.Namespace, sqlGatewayPostgresPort)
+	return r.setStatus(ctx, &gateway, metav1.ConditionTrue, "Reconciled", fmt.Sprintf("%d/%d workers ready", deploy.Status.ReadyReplicas, deploy.Status.Replicas))
+}
+
+func (r *SQLGatewayReconciler) reconcileService(ctx context.Context, gateway *kafscalev1alpha1.KafscaleSQLGateway, cluster *kafscalev1alpha1.KafscaleCluster) error {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: sqlGatewayName(gateway), Namespace: gateway.Namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		svc.Spec.Selector = sqlGatewayLabels(gateway, cluster)
+		var ports []corev1.ServicePort
+		for _, port := range sqlGatewayPorts(gateway) {
+			ports = append(ports, corev1.ServicePort{Name: port.Name, Port: port.ContainerPort, TargetPort: intstr.FromString(port.Name)})
+		}
+		svc.Spec.Ports = ports
+		svc.Spec.Type = parseServiceType(gateway.Spec.ServiceType)
+		if svc.Spec.Type == "" {
+			svc.Spec.Type = corev1.ServiceTypeClusterIP
+		}
+		return controllerutil.SetControllerReference(gateway, svc, r.Scheme)
+	})
+	return err
+}
+
+func (r *SQLGatewayReconciler) setStatus(ctx context.Context, gateway *kafscalev1alpha1.KafscaleSQLGateway, status metav1.ConditionStatus, reason, message string) (ctrl.Result, error) {
+	meta.SetStatusCondition(&gateway.Status.Conditions, metav1.Condition{
+		Type:               "Ready",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: gateway.Generation,
+	})
+	if status != metav1.ConditionTrue {
+		gateway.Status.Phase = reason
+	}
+	if err := r.Status().Update(ctx, gateway); err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if status != metav1.ConditionTrue {
+		return ctrl.Result{RequeueAfter: processorStatusRequeue}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *SQLGatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kafscalev1alpha1.KafscaleSQLGateway{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Service{}).
+		Complete(r)
+}
+
+func sqlGatewayPorts(gateway *kafscalev1alpha1.KafscaleSQLGateway) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{
+		{Name: "postgres", ContainerPort: sqlGatewayPostgresPort},
+		{Name: "metrics", ContainerPort: sqlGatewayMetricsPort},
+	}
+	if gateway.Spec.Server.EnableFlight {
+		ports = append(ports, corev1.ContainerPort{Name: "flight", ContainerPort: sqlGatewayFlightPort})
+	}
+	if gateway.Spec.HTTP.Enabled {
+		ports = append(ports, corev1.ContainerPort{Name: "http", ContainerPort: sqlGatewayHTTPPort})
+	}
+	return ports
+}
+
+func sqlGatewayLabels(gateway *kafscalev1alpha1.KafscaleSQLGateway, cluster *kafscalev1alpha1.KafscaleCluster) map[string]string {
+	return map[string]string{
+		"app":         "kafscale-sql-gateway",
+		"cluster":     cluster.Name,
+		"sql-gateway": gateway.Name,
+	}
+}
+
+func sqlGatewayName(gateway *kafscalev1alpha1.KafscaleSQLGateway) string {
+	return gateway.Name + "-sql-gateway"
+}
+
+func sqlGatewayConfigMapName(gateway *kafscalev1alpha1.KafscaleSQLGateway) string {
+	return gateway.Name + "-sql-gateway-config"
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+)
+
+func TestSQLGatewayReconcilerRendersConfigAndService(t *testing.T) {
+	cluster := testCluster("prod", []string{"http://etcd-0:2379"})
+	cluster.Spec.S3.Endpoint = "http://minio:9000"
+	gateway := &kafscalev1alpha1.KafscaleSQLGateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "analytics", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleSQLGatewaySpec{
+			ClusterRef:  "prod",
+			ServiceType: "LoadBalancer",
+			SQLGatewayConfig: kafscalev1alpha1.SQLGatewayConfig{
+				Server: kafscalev1alpha1.SQLServerConfig{MaxConnections: 50, EnableFlight: true},
+				Query: kafscalev1alpha1.SQLQueryConfig{
+					MaxUnboundedScan: 500,
+					MaxScanBytes:     1 << 30,
+					RequireTimeBound: true,
+				},
+				TimeIndex: kafscalev1alpha1.SQLTimeIndexConfig{Enabled: true},
+			},
+		},
+	}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, gateway).WithStatusSubresource(gateway).Build()
+	r := &SQLGatewayReconciler{Client: c, Scheme: scheme}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "analytics"}}); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	assertFound(t, c, cm, "default", "analytics-sql-gateway-config")
+	config := cm.Data[processorConfigKey]
+	for _, want := range []string{
+		"max_connections: 50",
+		"max_unbounded_scan: 500",
+		"max_scan_bytes: 1073741824",
+		"require_time_bound: true",
+		"time_index:",
+		"discovery: etcd",
+		"- http://etcd-0:2379",
+		"flight_listen: :8815",
+		"listen: :5432",
+		"path_style: true",
+	} {
+		if !strings.Contains(config, want) {
+			t.Fatalf("expected %q in rendered config:\n%s", want, config)
+		}
+	}
+	if strings.Contains(config, "enable_flight") || strings.Contains(config, ":8080") {
+		t.Fatalf("unexpected operator-only or disabled settings in config:\n%s", config)
+	}
+
+	deploy := &appsv1.Deployment{}
+	assertFound(t, c, deploy, "default", "analytics-sql-gateway")
+	if *deploy.Spec.Replicas != 1 || deploy.Spec.Template.Spec.Containers[0].Image != sqlProcessorImage {
+		t.Fatalf("unexpected deployment: %+v", deploy.Spec)
+	}
+
+	svc := &corev1.Service{}
+	assertFound(t, c, svc, "default", "analytics-sql-gateway")
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer || len(svc.Spec.Ports) != 3 {
+		t.Fatalf("unexpected service: %+v", svc.Spec)
+	}
+
+	var updated kafscalev1alpha1.KafscaleSQLGateway
+	assertFound(t, c, &updated, "default", "analytics")
+	if updated.Status.Endpoint != "analytics-sql-gateway.default.svc:5432" || updated.Status.Phase != "Pending" {
+		t.Fatalf("unexpected status: %+v", updated.Status)
+	}
+}
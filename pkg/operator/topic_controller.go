@@ -17,6 +17,10 @@ package operator
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,15 +28,32 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/metadata"
+	"github.com/KafScale/platform/pkg/protocol"
+	"github.com/KafScale/platform/pkg/storage"
 )
 
-// TopicReconciler ensures topic metadata is reflected in the etcd snapshot.
+const (
+	topicCleanupFinalizer = "kafscale.io/topic-cleanup"
+	topicStatusRefresh    = 30 * time.Second
+
+	topicDeletionPolicyDelete = "Delete"
+	topicDeletionPolicyPurge  = "Purge"
+	topicDeletionPolicyRetain = "Retain"
+)
+
+// TopicReconciler drives topic partitions and config in etcd from the
+// KafscaleTopic spec and reports live partition offsets back in status.
 type TopicReconciler struct {
 	client.Client
 	Scheme    *runtime.Scheme
 	Publisher *SnapshotPublisher
+	// PurgeStorage deletes every object under prefix in the cluster's bucket.
+	// Defaults to an S3 client built from the cluster spec.
+	PurgeStorage func(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, prefix string) error
 }
 
 func NewTopicReconciler(mgr ctrl.Manager, publisher *SnapshotPublisher) *TopicReconciler {
@@ -53,25 +74,47 @@ func (r *TopicReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		Name:      topic.Spec.ClusterRef,
 		Namespace: topic.Namespace,
 	}, &cluster); err != nil {
+		if apierrors.IsNotFound(err) && !topic.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, removeFinalizer(ctx, r.Client, &topic, topicCleanupFinalizer)
+		}
 		return ctrl.Result{}, err
 	}
+
+	if !topic.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, &topic, &cluster)
+	}
+	if !controllerutil.ContainsFinalizer(&topic, topicCleanupFinalizer) {
+		controllerutil.AddFinalizer(&topic, topicCleanupFinalizer)
+		if err := r.Update(ctx, &topic); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	etcdResolution, err := EnsureEtcd(ctx, r.Client, r.Scheme, &cluster)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	if err := r.Publisher.Publish(ctx, &cluster, etcdResolution.Endpoints); err != nil {
-		setTopicCondition(&topic.Status.Conditions, metav1.Condition{
-			Type:    "Ready",
-			Status:  metav1.ConditionFalse,
-			Reason:  "EtcdUnavailable",
-			Message: "Topic metadata publish failed",
-		})
-		topic.Status.Phase = "EtcdUnavailable"
-		if err := r.Status().Update(ctx, &topic); err != nil && !apierrors.IsNotFound(err) {
-			return ctrl.Result{}, err
+	existing, err := readSnapshotFromEtcd(ctx, etcdResolution.Endpoints)
+	if err != nil {
+		return r.etcdUnavailable(ctx, &topic)
+	}
+	if current := findSnapshotTopic(existing, topic.Name); current != nil && int32(len(current.Partitions)) != topic.Spec.Partitions {
+		if err := metadata.ValidatePartitionIncrease(int32(len(current.Partitions)), topic.Spec.Partitions); err != nil {
+			return r.setInvalid(ctx, &topic, "InvalidPartitions", err)
 		}
-		return ctrl.Result{RequeueAfter: publishRequeueDelay}, nil
 	}
+	if _, err := desiredTopicConfig(&topic, nil, 0); err != nil {
+		return r.setInvalid(ctx, &topic, "InvalidConfig", err)
+	}
+
+	if err := r.Publisher.Publish(ctx, &cluster, etcdResolution.Endpoints); err != nil {
+		return r.etcdUnavailable(ctx, &topic)
+	}
+	partitions, err := r.syncTopicState(ctx, &topic, etcdResolution.Endpoints)
+	if err != nil {
+		return r.etcdUnavailable(ctx, &topic)
+	}
+	topic.Status.Partitions = partitions
 	setTopicCondition(&topic.Status.Conditions, metav1.Condition{
 		Type:    "Ready",
 		Status:  metav1.ConditionTrue,
@@ -82,9 +125,149 @@ func (r *TopicReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	if err := r.Status().Update(ctx, &topic); err != nil && !apierrors.IsNotFound(err) {
 		return ctrl.Result{}, err
 	}
+	return ctrl.Result{RequeueAfter: topicStatusRefresh}, nil
+}
+
+// syncTopicState writes partition state for new partitions and the desired
+// topic config, then reads back per-partition offsets for status.
+func (r *TopicReconciler) syncTopicState(ctx context.Context, topic *kafscalev1alpha1.KafscaleTopic, endpoints []string) ([]kafscalev1alpha1.TopicPartitionStatus, error) {
+	snap, err := readSnapshotFromEtcd(ctx, endpoints)
+	if err != nil {
+		return nil, err
+	}
+	current := findSnapshotTopic(snap, topic.Name)
+	if current == nil {
+		return nil, fmt.Errorf("topic %s missing from metadata snapshot", topic.Name)
+	}
+	cli, err := newOperatorEtcdClient(endpoints)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+	if err := ensurePartitionStates(ctx, cli, *current); err != nil {
+		return nil, err
+	}
+	stored, err := readTopicConfig(ctx, cli, topic.Name)
+	if err != nil {
+		return nil, err
+	}
+	replicationFactor := int32(1)
+	if len(current.Partitions) > 0 && len(current.Partitions[0].ReplicaNodes) > 0 {
+		replicationFactor = int32(len(current.Partitions[0].ReplicaNodes))
+	}
+	desired, err := desiredTopicConfig(topic, stored, replicationFactor)
+	if err != nil {
+		return nil, err
+	}
+	desired.Partitions = int32(len(current.Partitions))
+	if !topicConfigEqual(stored, desired) {
+		if err := writeTopicConfig(ctx, cli, desired); err != nil {
+			return nil, err
+		}
+	}
+	return readTopicPartitionStatus(ctx, cli, *current)
+}
+
+func (r *TopicReconciler) reconcileDelete(ctx context.Context, topic *kafscalev1alpha1.KafscaleTopic, cluster *kafscalev1alpha1.KafscaleCluster) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(topic, topicCleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	policy := strings.TrimSpace(topic.Spec.DeletionPolicy)
+	if policy == "" {
+		policy = topicDeletionPolicyDelete
+	}
+	if policy != topicDeletionPolicyRetain {
+		etcdResolution, err := EnsureEtcd(ctx, r.Client, r.Scheme, cluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		cli, err := newOperatorEtcdClient(etcdResolution.Endpoints)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		err = deleteTopicState(ctx, cli, topic.Name)
+		_ = cli.Close()
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if policy == topicDeletionPolicyPurge {
+			purge := r.PurgeStorage
+			if purge == nil {
+				purge = r.purgeS3
+			}
+			if err := purge(ctx, cluster, topicStoragePrefix(cluster, topic.Name)); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+	return ctrl.Result{}, removeFinalizer(ctx, r.Client, topic, topicCleanupFinalizer)
+}
+
+func (r *TopicReconciler) purgeS3(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, prefix string) error {
+	cfg := storage.S3Config{
+		Bucket:         cluster.Spec.S3.Bucket,
+		Region:         cluster.Spec.S3.Region,
+		Endpoint:       cluster.Spec.S3.Endpoint,
+		ForcePathStyle: cluster.Spec.S3.Endpoint != "",
+	}
+	if err := loadS3Credentials(ctx, r.Client, cluster, &cfg); err != nil {
+		return err
+	}
+	s3Client, err := storage.NewS3Client(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	deleter, ok := s3Client.(storage.PrefixDeleter)
+	if !ok {
+		return errors.New("s3 client does not support prefix deletes")
+	}
+	_, err = deleter.DeletePrefix(ctx, prefix)
+	return err
+}
+
+// topicStoragePrefix mirrors the broker's segment layout:
+// <namespace>/<topic>/<partition>/segment-*.
+func topicStoragePrefix(cluster *kafscalev1alpha1.KafscaleCluster, topic string) string {
+	return fmt.Sprintf("%s/%s/", cluster.Namespace, topic)
+}
+
+func (r *TopicReconciler) setInvalid(ctx context.Context, topic *kafscalev1alpha1.KafscaleTopic, reason string, err error) (ctrl.Result, error) {
+	setTopicCondition(&topic.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: err.Error(),
+	})
+	topic.Status.Phase = reason
+	if err := r.Status().Update(ctx, topic); err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
+func (r *TopicReconciler) etcdUnavailable(ctx context.Context, topic *kafscalev1alpha1.KafscaleTopic) (ctrl.Result, error) {
+	setTopicCondition(&topic.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionFalse,
+		Reason:  "EtcdUnavailable",
+		Message: "Topic metadata publish failed",
+	})
+	topic.Status.Phase = "EtcdUnavailable"
+	if err := r.Status().Update(ctx, topic); err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: publishRequeueDelay}, nil
+}
+
+func findSnapshotTopic(snap metadata.ClusterMetadata, name string) *protocol.MetadataTopic {
+	for i := range snap.Topics {
+		if snap.Topics[i].Name == name {
+			return &snap.Topics[i]
+		}
+	}
+	return nil
+}
+
 func (r *TopicReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kafscalev1alpha1.KafscaleTopic{}).
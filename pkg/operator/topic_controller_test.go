@@ -0,0 +1,270 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/metadata"
+)
+
+func TestTopicReconcilerSyncsPartitionsConfigAndStatus(t *testing.T) {
+	endpoints := startTestEtcd(t)
+	cluster := testCluster("prod", endpoints)
+	retention := int64(60000)
+	topic := &kafscalev1alpha1.KafscaleTopic{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleTopicSpec{
+			ClusterRef:  "prod",
+			Partitions:  2,
+			RetentionMs: &retention,
+			Config:      map[string]string{metadata.ConfigSegmentBytes: "1048576"},
+		},
+	}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, topic).WithStatusSubresource(topic).Build()
+	r := &TopicReconciler{Client: c, Scheme: scheme, Publisher: NewSnapshotPublisher(c)}
+	ctx := context.Background()
+
+	reconcileTopic(t, r, "orders")
+	cli := testEtcdClient(t, endpoints)
+	cfg, err := readTopicConfig(ctx, cli, "orders")
+	if err != nil || cfg == nil {
+		t.Fatalf("read topic config: %v %+v", err, cfg)
+	}
+	if cfg.Partitions != 2 || cfg.RetentionMs != 60000 || cfg.RetentionBytes != -1 || cfg.SegmentBytes != 1048576 {
+		t.Fatalf("unexpected topic config: %+v", cfg)
+	}
+	if _, err := cli.Put(ctx, "/kafscale/topics/orders/partitions/1/next_offset", "42"); err != nil {
+		t.Fatalf("put next offset: %v", err)
+	}
+
+	updated := getTopic(t, c, "orders")
+	if len(updated.Finalizers) != 1 || updated.Finalizers[0] != topicCleanupFinalizer {
+		t.Fatalf("expected cleanup finalizer, got %v", updated.Finalizers)
+	}
+	updated.Spec.Partitions = 3
+	if err := c.Update(ctx, updated); err != nil {
+		t.Fatalf("update topic: %v", err)
+	}
+	reconcileTopic(t, r, "orders")
+
+	updated = getTopic(t, c, "orders")
+	if updated.Status.Phase != "Ready" || len(updated.Status.Partitions) != 3 {
+		t.Fatalf("unexpected status: %+v", updated.Status)
+	}
+	if updated.Status.Partitions[1].LogEndOffset != 42 || updated.Status.Partitions[0].LogEndOffset != 0 {
+		t.Fatalf("unexpected partition offsets: %+v", updated.Status.Partitions)
+	}
+	resp, err := cli.Get(ctx, metadata.PartitionStateKey("orders", 2))
+	if err != nil || len(resp.Kvs) != 1 {
+		t.Fatalf("expected partition state for new partition: %v", err)
+	}
+	if cfg, _ := readTopicConfig(ctx, cli, "orders"); cfg.Partitions != 3 {
+		t.Fatalf("expected config partitions 3, got %d", cfg.Partitions)
+	}
+
+	updated.Spec.Partitions = 1
+	if err := c.Update(ctx, updated); err != nil {
+		t.Fatalf("update topic: %v", err)
+	}
+	reconcileTopic(t, r, "orders")
+	updated = getTopic(t, c, "orders")
+	if updated.Status.Phase != "InvalidPartitions" {
+		t.Fatalf("expected InvalidPartitions, got %+v", updated.Status)
+	}
+	snap, err := readSnapshotFromEtcd(ctx, endpoints)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	if current := findSnapshotTopic(snap, "orders"); current == nil || len(current.Partitions) != 3 {
+		t.Fatalf("expected snapshot to keep 3 partitions, got %+v", current)
+	}
+
+	updated.Spec.Partitions = 3
+	updated.Spec.Config = map[string]string{"cleanup.policy": "compact"}
+	if err := c.Update(ctx, updated); err != nil {
+		t.Fatalf("update topic: %v", err)
+	}
+	reconcileTopic(t, r, "orders")
+	updated = getTopic(t, c, "orders")
+	if updated.Status.Phase != "InvalidConfig" {
+		t.Fatalf("expected InvalidConfig, got %+v", updated.Status)
+	}
+}
+
+func TestTopicReconcilerDeletionPolicies(t *testing.T) {
+	endpoints := startTestEtcd(t)
+	cli := testEtcdClient(t, endpoints)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		policy      string
+		wantDeleted bool
+		wantPurge   bool
+	}{
+		{policy: "", wantDeleted: true},
+		{policy: topicDeletionPolicyPurge, wantDeleted: true, wantPurge: true},
+		{policy: topicDeletionPolicyRetain},
+	} {
+		name := "topic-" + strings.ToLower(tc.policy)
+		if tc.policy == "" {
+			name = "topic-default"
+		}
+		t.Run(name, func(t *testing.T) {
+			cluster := testCluster("prod", endpoints)
+			topic := &kafscalev1alpha1.KafscaleTopic{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: kafscalev1alpha1.KafscaleTopicSpec{
+					ClusterRef:     "prod",
+					Partitions:     1,
+					DeletionPolicy: tc.policy,
+				},
+			}
+			scheme := testScheme(t)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, topic).WithStatusSubresource(topic).Build()
+			var purged []string
+			r := &TopicReconciler{
+				Client:    c,
+				Scheme:    scheme,
+				Publisher: NewSnapshotPublisher(c),
+				PurgeStorage: func(ctx context.Context, cluster *kafscalev1alpha1.KafscaleCluster, prefix string) error {
+					purged = append(purged, prefix)
+					return nil
+				},
+			}
+			reconcileTopic(t, r, name)
+			offsetKey := metadata.ConsumerOffsetKey("group-a", name, 0)
+			if _, err := cli.Put(ctx, offsetKey, "7"); err != nil {
+				t.Fatalf("put consumer offset: %v", err)
+			}
+
+			if err := c.Delete(ctx, getTopic(t, c, name)); err != nil {
+				t.Fatalf("delete topic: %v", err)
+			}
+			reconcileTopic(t, r, name)
+
+			var remaining kafscalev1alpha1.KafscaleTopic
+			if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, &remaining); err == nil {
+				t.Fatalf("expected topic to be released, finalizers %v", remaining.Finalizers)
+			}
+			snap, err := readSnapshotFromEtcd(ctx, endpoints)
+			if err != nil {
+				t.Fatalf("read snapshot: %v", err)
+			}
+			inSnapshot := findSnapshotTopic(snap, name) != nil
+			cfgResp, err := cli.Get(ctx, metadata.TopicConfigKey(name))
+			if err != nil {
+				t.Fatalf("get config: %v", err)
+			}
+			offsetResp, err := cli.Get(ctx, offsetKey)
+			if err != nil {
+				t.Fatalf("get offset: %v", err)
+			}
+			if tc.wantDeleted == inSnapshot || tc.wantDeleted == (len(cfgResp.Kvs) > 0) || tc.wantDeleted == (len(offsetResp.Kvs) > 0) {
+				t.Fatalf("policy %q: snapshot=%v config=%d offsets=%d", tc.policy, inSnapshot, len(cfgResp.Kvs), len(offsetResp.Kvs))
+			}
+			if tc.wantPurge != (len(purged) == 1) {
+				t.Fatalf("policy %q: unexpected purge calls %v", tc.policy, purged)
+			}
+			if tc.wantPurge && purged[0] != "default/"+name+"/" {
+				t.Fatalf("unexpected purge prefix %q", purged[0])
+			}
+		})
+	}
+}
+
+func reconcileTopic(t *testing.T, r *TopicReconciler, name string) {
+	t.Helper()
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}); err != nil {
+		t.Fatalf("reconcile %s: %v", name, err)
+	}
+}
+
+func getTopic(t *testing.T, c client.Client, name string) *kafscalev1alpha1.KafscaleTopic {
+	t.Helper()
+	var topic kafscalev1alpha1.KafscaleTopic
+	if err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &topic); err != nil {
+		t.Fatalf("get topic %s: %v", name, err)
+	}
+	return &topic
+}
+
+func testEtcdClient(t *testing.T, endpoints []string) *clientv3.Client {
+	t.Helper()
+	cli, err := newOperatorEtcdClient(endpoints)
+	if err != nil {
+		t.Fatalf("etcd client: %v", err)
+	}
+	t.Cleanup(func() { _ = cli.Close() })
+	return cli
+}
+
+func startTestEtcd(t *testing.T) []string {
+	t.Helper()
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	cfg.Logger = "zap"
+	clientURL, _ := url.Parse("http://" + freeLocalAddr(t))
+	peerURL, _ := url.Parse("http://" + freeLocalAddr(t))
+	cfg.ListenClientUrls = []url.URL{*clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{*clientURL}
+	cfg.ListenPeerUrls = []url.URL{*peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{*peerURL}
+	cfg.Name = "default"
+	cfg.InitialCluster = cfg.InitialClusterFromName(cfg.Name)
+
+	e, err := embed.StartEtcd(cfg)
+	if err != nil {
+		if strings.Contains(err.Error(), "operation not permitted") {
+			t.Skipf("skipping embedded etcd test: %v", err)
+		}
+		t.Fatalf("start embedded etcd: %v", err)
+	}
+	t.Cleanup(e.Close)
+	select {
+	case <-e.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatalf("etcd server took too long to start")
+	}
+	return []string{fmt.Sprintf("http://%s", e.Clients[0].Addr().String())}
+}
+
+func freeLocalAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("skipping embedded etcd test: %v", err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
@@ -0,0 +1,270 @@
+// Copyright 2025-2026 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/zap"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	metadatapb "github.com/KafScale/platform/pkg/gen/metadata"
+	"github.com/KafScale/platform/pkg/metadata"
+	"github.com/KafScale/platform/pkg/protocol"
+)
+
+const metadataSnapshotKey = "/kafscale/metadata/snapshot"
+
+func newOperatorEtcdClient(endpoints []string) (*clientv3.Client, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("etcd endpoints required")
+	}
+	cfg := clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: 5 * time.Second,
+	}
+	if parseBoolEnv(operatorEtcdSilenceLogsEnv) {
+		cfg.Logger = zap.NewNop()
+	}
+	return clientv3.New(cfg)
+}
+
+// desiredTopicConfig renders the topic config stored in etcd from the
+// KafscaleTopic spec, validating entries the same way AlterConfigs does.
+// Explicit retention fields win over the same keys in spec.config.
+func desiredTopicConfig(topic *kafscalev1alpha1.KafscaleTopic, existing *metadatapb.TopicConfig, replicationFactor int32) (*metadatapb.TopicConfig, error) {
+	cfg := &metadatapb.TopicConfig{
+		Name:              topic.Name,
+		Partitions:        topic.Spec.Partitions,
+		ReplicationFactor: replicationFactor,
+		RetentionMs:       -1,
+		RetentionBytes:    -1,
+		CreatedAt:         time.Now().UTC().Format(time.RFC3339),
+		Config:            map[string]string{},
+	}
+	if existing != nil && existing.CreatedAt != "" {
+		cfg.CreatedAt = existing.CreatedAt
+	}
+	keys := make([]string, 0, len(topic.Spec.Config))
+	for key := range topic.Spec.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := metadata.ApplyTopicConfig(cfg, key, topic.Spec.Config[key]); err != nil {
+			return nil, err
+		}
+	}
+	if topic.Spec.RetentionMs != nil {
+		if err := metadata.ApplyTopicConfig(cfg, metadata.ConfigRetentionMs, strconv.FormatInt(*topic.Spec.RetentionMs, 10)); err != nil {
+			return nil, err
+		}
+	}
+	if topic.Spec.RetentionBytes != nil {
+		if err := metadata.ApplyTopicConfig(cfg, metadata.ConfigRetentionBytes, strconv.FormatInt(*topic.Spec.RetentionBytes, 10)); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+func topicConfigEqual(a, b *metadatapb.TopicConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Name == b.Name &&
+		a.Partitions == b.Partitions &&
+		a.ReplicationFactor == b.ReplicationFactor &&
+		a.RetentionMs == b.RetentionMs &&
+		a.RetentionBytes == b.RetentionBytes &&
+		a.SegmentBytes == b.SegmentBytes
+}
+
+func readTopicConfig(ctx context.Context, cli *clientv3.Client, topic string) (*metadatapb.TopicConfig, error) {
+	getCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	resp, err := cli.Get(getCtx, metadata.TopicConfigKey(topic))
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	return metadata.DecodeTopicConfig(resp.Kvs[0].Value)
+}
+
+func writeTopicConfig(ctx context.Context, cli *clientv3.Client, cfg *metadatapb.TopicConfig) error {
+	payload, err := metadata.EncodeTopicConfig(cfg)
+	if err != nil {
+		return err
+	}
+	putCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	_, err = cli.Put(putCtx, metadata.TopicConfigKey(cfg.Name), string(payload))
+	return err
+}
+
+// ensurePartitionStates writes an initial state entry for partitions that do
+// not have one yet, mirroring what CreatePartitions does for new partitions.
+func ensurePartitionStates(ctx context.Context, cli *clientv3.Client, topic protocol.MetadataTopic) error {
+	for _, part := range topic.Partitions {
+		payload, err := metadata.EncodePartitionState(&metadatapb.PartitionState{
+			Topic:        topic.Name,
+			Partition:    part.PartitionIndex,
+			LeaderBroker: fmt.Sprintf("%d", part.LeaderID),
+			LeaderEpoch:  part.LeaderEpoch,
+		})
+		if err != nil {
+			return err
+		}
+		key := metadata.PartitionStateKey(topic.Name, part.PartitionIndex)
+		txnCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		_, err = cli.Txn(txnCtx).
+			If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+			Then(clientv3.OpPut(key, string(payload))).
+			Commit()
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readTopicPartitionStatus reports each partition's leader and offsets from
+// the partition state and next-offset keys the brokers maintain.
+func readTopicPartitionStatus(ctx context.Context, cli *clientv3.Client, topic protocol.MetadataTopic) ([]kafscalev1alpha1.TopicPartitionStatus, error) {
+	getCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	prefix := fmt.Sprintf("/kafscale/topics/%s/partitions/", topic.Name)
+	resp, err := cli.Get(getCtx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	nextOffsets := make(map[int32]int64)
+	states := make(map[int32]*metadatapb.PartitionState)
+	for _, kv := range resp.Kvs {
+		rest := strings.TrimPrefix(string(kv.Key), prefix)
+		id, suffix, _ := strings.Cut(rest, "/")
+		partition, err := strconv.ParseInt(id, 10, 32)
+		if err != nil {
+			continue
+		}
+		switch suffix {
+		case "next_offset":
+			if offset, err := strconv.ParseInt(string(kv.Value), 10, 64); err == nil {
+				nextOffsets[int32(partition)] = offset
+			}
+		case "":
+			if state, err := metadata.DecodePartitionState(kv.Value); err == nil {
+				states[int32(partition)] = state
+			}
+		}
+	}
+	out := make([]kafscalev1alpha1.TopicPartitionStatus, 0, len(topic.Partitions))
+	for _, part := range topic.Partitions {
+		status := kafscalev1alpha1.TopicPartitionStatus{
+			ID:           part.PartitionIndex,
+			Leader:       fmt.Sprintf("%d", part.LeaderID),
+			LogEndOffset: nextOffsets[part.PartitionIndex],
+		}
+		if state := states[part.PartitionIndex]; state != nil {
+			status.LogStartOffset = state.LogStartOffset
+			if state.LogEndOffset > status.LogEndOffset {
+				status.LogEndOffset = state.LogEndOffset
+			}
+		}
+		out = append(out, status)
+	}
+	return out, nil
+}
+
+// deleteTopicState removes a topic from the metadata snapshot along with its
+// config, partition state, next offsets and committed consumer offsets.
+func deleteTopicState(ctx context.Context, cli *clientv3.Client, topic string) error {
+	for attempt := 0; ; attempt++ {
+		getCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		resp, err := cli.Get(getCtx, metadataSnapshotKey)
+		cancel()
+		if err != nil {
+			return err
+		}
+		if len(resp.Kvs) == 0 {
+			break
+		}
+		var snap metadata.ClusterMetadata
+		if err := json.Unmarshal(resp.Kvs[0].Value, &snap); err != nil {
+			return err
+		}
+		kept := snap.Topics[:0]
+		for _, t := range snap.Topics {
+			if t.Name != topic {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == len(snap.Topics) {
+			break
+		}
+		snap.Topics = kept
+		payload, err := json.Marshal(snap)
+		if err != nil {
+			return err
+		}
+		txnCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		txnResp, err := cli.Txn(txnCtx).
+			If(clientv3.Compare(clientv3.ModRevision(metadataSnapshotKey), "=", resp.Kvs[0].ModRevision)).
+			Then(clientv3.OpPut(metadataSnapshotKey, string(payload))).
+			Commit()
+		cancel()
+		if err != nil {
+			return err
+		}
+		if txnResp.Succeeded {
+			break
+		}
+		if attempt >= 4 {
+			return fmt.Errorf("snapshot update conflict")
+		}
+		if err := sleepWithContext(ctx, 200*time.Millisecond); err != nil {
+			return err
+		}
+	}
+
+	delCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if _, err := cli.Delete(delCtx, fmt.Sprintf("/kafscale/topics/%s/", topic), clientv3.WithPrefix()); err != nil {
+		return err
+	}
+	resp, err := cli.Get(delCtx, "/kafscale/consumers/", clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return err
+	}
+	for _, kv := range resp.Kvs {
+		if _, offsetTopic, _, ok := metadata.ParseConsumerOffsetKey(string(kv.Key)); ok && offsetTopic == topic {
+			if _, err := cli.Delete(delCtx, string(kv.Key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/metadata"
+)
+
+const (
+	userCleanupFinalizer = "kafscale.io/user-cleanup"
+
+	userAuthScramSHA256 = "scram-sha-256"
+	userAuthScramSHA512 = "scram-sha-512"
+	userAuthTLS         = "tls"
+
+	userSecretUsernameKey  = "username"
+	userSecretPasswordKey  = "password"
+	userSecretMechanismKey = "sasl.mechanism"
+	userSecretJAASKey      = "sasl.jaas.config"
+)
+
+// UserReconciler publishes KafscaleUser credentials to etcd for brokers.
+type UserReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func NewUserReconciler(mgr ctrl.Manager) *UserReconciler {
+	return &UserReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+}
+
+func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var user kafscalev1alpha1.KafscaleUser
+	if err := r.Get(ctx, req.NamespacedName, &user); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	var cluster kafscalev1alpha1.KafscaleCluster
+	if err := r.Get(ctx, types.NamespacedName{Name: user.Spec.ClusterRef, Namespace: user.Namespace}, &cluster); err != nil {
+		if apierrors.IsNotFound(err) && !user.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, removeFinalizer(ctx, r.Client, &user, userCleanupFinalizer)
+		}
+		return ctrl.Result{}, err
+	}
+	etcdResolution, err := EnsureEtcd(ctx, r.Client, r.Scheme, &cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !user.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&user, userCleanupFinalizer) {
+			if err := deleteEtcdKey(ctx, etcdResolution.Endpoints, metadata.UserCredentialKey(user.Name)); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, removeFinalizer(ctx, r.Client, &user, userCleanupFinalizer)
+	}
+	if !controllerutil.ContainsFinalizer(&user, userCleanupFinalizer) {
+		controllerutil.AddFinalizer(&user, userCleanupFinalizer)
+		if err := r.Update(ctx, &user); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	var existing *metadata.UserCredential
+	raw, err := getEtcdValue(ctx, etcdResolution.Endpoints, metadata.UserCredentialKey(user.Name))
+	if err != nil {
+		return r.setStatus(ctx, &user, metav1.ConditionFalse, "EtcdUnavailable", fmt.Sprintf("Read credentials failed: %v", err), publishRequeueDelay)
+	}
+	if len(raw) > 0 {
+		existing = &metadata.UserCredential{}
+		if err := json.Unmarshal(raw, existing); err != nil {
+			existing = nil
+		}
+	}
+	cred, err := r.desiredCredential(ctx, &user, existing)
+	if err != nil {
+		return r.setStatus(ctx, &user, metav1.ConditionFalse, "InvalidAuthentication", err.Error(), 0)
+	}
+	payload, err := json.Marshal(cred)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := putEtcdValue(ctx, etcdResolution.Endpoints, metadata.UserCredentialKey(user.Name), payload); err != nil {
+		return r.setStatus(ctx, &user, metav1.ConditionFalse, "EtcdUnavailable", fmt.Sprintf("Publish credentials failed: %v", err), publishRequeueDelay)
+	}
+	return r.setStatus(ctx, &user, metav1.ConditionTrue, "Reconciled", "Credentials published", 0)
+}
+
+// desiredCredential builds the etcd record for the user. SCRAM keys are only
+// re-derived when the password or mechanism changed, so the stored salt stays
+// stable across reconciles.
+func (r *UserReconciler) desiredCredential(ctx context.Context, user *kafscalev1alpha1.KafscaleUser, existing *metadata.UserCredential) (*metadata.UserCredential, error) {
+	cred := &metadata.UserCredential{Name: user.Name}
+	user.Status.SecretName = ""
+	switch strings.ToLower(strings.TrimSpace(user.Spec.Authentication.Type)) {
+	case userAuthTLS:
+		subject := strings.TrimSpace(user.Spec.Authentication.Subject)
+		if subject == "" {
+			return nil, fmt.Errorf("authentication.subject is required for tls users")
+		}
+		cred.TLSSubject = subject
+	case userAuthScramSHA256, userAuthScramSHA512:
+		mechanism := metadata.ScramSHA256
+		if strings.EqualFold(user.Spec.Authentication.Type, userAuthScramSHA512) {
+			mechanism = metadata.ScramSHA512
+		}
+		secretName := userSecretName(user)
+		password, err := r.ensureUserSecret(ctx, user, secretName, mechanism)
+		if err != nil {
+			return nil, err
+		}
+		user.Status.SecretName = secretName
+		if existing != nil && existing.Scram != nil && existing.Scram.Mechanism == mechanism && existing.Scram.Matches(password) {
+			cred.Scram = existing.Scram
+			break
+		}
+		scram, err := metadata.NewScramCredential(mechanism, password, nil, metadata.DefaultScramIterations)
+		if err != nil {
+			return nil, err
+		}
+		cred.Scram = scram
+	default:
+		return nil, fmt.Errorf("unsupported authentication type %q", user.Spec.Authentication.Type)
+	}
+	return cred, nil
+}
+
+// ensureUserSecret returns the SCRAM password from the user's secret,
+// generating the secret with a random password when it does not exist.
+func (r *UserReconciler) ensureUserSecret(ctx context.Context, user *kafscalev1alpha1.KafscaleUser, name, mechanism string) (string, error) {
+	var secret corev1.Secret
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: user.Namespace}, &secret)
+	if err == nil {
+		password := string(secret.Data[userSecretPasswordKey])
+		if password == "" {
+			return "", fmt.Errorf("secret %s has no %q key", name, userSecretPasswordKey)
+		}
+		return password, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", err
+	}
+	password, err := generatePassword()
+	if err != nil {
+		return "", err
+	}
+	secret = corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: user.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "kafscale-operator",
+				"kafscale.io/user":             user.Name,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			userSecretUsernameKey:  []byte(user.Name),
+			userSecretPasswordKey:  []byte(password),
+			userSecretMechanismKey: []byte(mechanism),
+			userSecretJAASKey: []byte(fmt.Sprintf(
+				`org.apache.kafka.common.security.scram.ScramLoginModule required username="%s" password="%s";`,
+				user.Name, password)),
+		},
+	}
+	if err := controllerutil.SetControllerReference(user, &secret, r.Scheme); err != nil {
+		return "", err
+	}
+	if err := r.Create(ctx, &secret); err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+func (r *UserReconciler) setStatus(ctx context.Context, user *kafscalev1alpha1.KafscaleUser, status metav1.ConditionStatus, reason, message string, requeue time.Duration) (ctrl.Result, error) {
+	meta.SetStatusCondition(&user.Status.Conditions, metav1.Condition{
+		Type:               "Ready",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: user.Generation,
+	})
+	user.Status.Phase = reason
+	if status == metav1.ConditionTrue {
+		user.Status.Phase = "Ready"
+		user.Status.Principal = "User:" + user.Name
+	}
+	if err := r.Status().Update(ctx, user); err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+func (r *UserReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kafscalev1alpha1.KafscaleUser{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.usersForSecret)).
+		Complete(r)
+}
+
+// usersForSecret maps a Secret to the users whose password it holds, covering
+// both generated Secrets and external ones named by authentication.secretName,
+// so password rotations are republished.
+func (r *UserReconciler) usersForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	var users kafscalev1alpha1.KafscaleUserList
+	if err := r.List(ctx, &users, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range users.Items {
+		user := &users.Items[i]
+		if userSecretName(user) != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: user.Name, Namespace: user.Namespace}})
+	}
+	return requests
+}
+
+func userSecretName(user *kafscalev1alpha1.KafscaleUser) string {
+	if name := strings.TrimSpace(user.Spec.Authentication.SecretName); name != "" {
+		return name
+	}
+	return user.Name
+}
+
+func generatePassword() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// removeFinalizer drops finalizer from obj, ignoring objects already gone.
+func removeFinalizer(ctx context.Context, c client.Client, obj client.Object, finalizer string) error {
+	if !controllerutil.ContainsFinalizer(obj, finalizer) {
+		return nil
+	}
+	controllerutil.RemoveFinalizer(obj, finalizer)
+	return client.IgnoreNotFound(c.Update(ctx, obj))
+}
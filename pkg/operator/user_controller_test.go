@@ -0,0 +1,181 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/metadata"
+)
+
+func TestUserReconcilerScramCredentials(t *testing.T) {
+	endpoints := startTestEtcd(t)
+	cluster := testCluster("prod", endpoints)
+	user := &kafscalev1alpha1.KafscaleUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleUserSpec{
+			ClusterRef:     "prod",
+			Authentication: kafscalev1alpha1.UserAuthenticationSpec{Type: "scram-sha-512"},
+		},
+	}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, user).WithStatusSubresource(user).Build()
+	r := &UserReconciler{Client: c, Scheme: scheme}
+	ctx := context.Background()
+
+	reconcileUser(t, r, "alice")
+	secret := &corev1.Secret{}
+	assertFound(t, c, secret, "default", "alice")
+	password := string(secret.Data[userSecretPasswordKey])
+	if password == "" || string(secret.Data[userSecretMechanismKey]) != metadata.ScramSHA512 {
+		t.Fatalf("unexpected generated secret: %v", secret.Data)
+	}
+	if len(secret.OwnerReferences) != 1 || secret.OwnerReferences[0].Name != "alice" {
+		t.Fatalf("expected secret owned by user, got %+v", secret.OwnerReferences)
+	}
+	first := readUserCredential(t, endpoints, "alice")
+	if first.Scram == nil || first.Scram.Mechanism != metadata.ScramSHA512 || !first.Scram.Matches(password) {
+		t.Fatalf("unexpected stored credential: %+v", first)
+	}
+
+	reconcileUser(t, r, "alice")
+	if second := readUserCredential(t, endpoints, "alice"); string(second.Scram.Salt) != string(first.Scram.Salt) {
+		t.Fatalf("expected salt to stay stable across reconciles")
+	}
+
+	secret.Data[userSecretPasswordKey] = []byte("rotated")
+	if err := c.Update(ctx, secret); err != nil {
+		t.Fatalf("update secret: %v", err)
+	}
+	reconcileUser(t, r, "alice")
+	if rotated := readUserCredential(t, endpoints, "alice"); !rotated.Scram.Matches("rotated") {
+		t.Fatalf("expected credential to follow password rotation")
+	}
+
+	var updated kafscalev1alpha1.KafscaleUser
+	assertFound(t, c, &updated, "default", "alice")
+	if updated.Status.Phase != "Ready" || updated.Status.Principal != "User:alice" || updated.Status.SecretName != "alice" {
+		t.Fatalf("unexpected status: %+v", updated.Status)
+	}
+
+	if err := c.Delete(ctx, &updated); err != nil {
+		t.Fatalf("delete user: %v", err)
+	}
+	reconcileUser(t, r, "alice")
+	assertNotFound(t, c, &kafscalev1alpha1.KafscaleUser{}, "default", "alice")
+	if raw, err := getEtcdValue(ctx, endpoints, metadata.UserCredentialKey("alice")); err != nil || raw != nil {
+		t.Fatalf("expected credentials removed from etcd, got %q (%v)", raw, err)
+	}
+}
+
+func TestUserReconcilerTLSSubject(t *testing.T) {
+	endpoints := startTestEtcd(t)
+	cluster := testCluster("prod", endpoints)
+	user := &kafscalev1alpha1.KafscaleUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "billing", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleUserSpec{
+			ClusterRef:     "prod",
+			Authentication: kafscalev1alpha1.UserAuthenticationSpec{Type: "tls"},
+		},
+	}
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, user).WithStatusSubresource(user).Build()
+	r := &UserReconciler{Client: c, Scheme: scheme}
+	ctx := context.Background()
+
+	reconcileUser(t, r, "billing")
+	var updated kafscalev1alpha1.KafscaleUser
+	assertFound(t, c, &updated, "default", "billing")
+	if updated.Status.Phase != "InvalidAuthentication" {
+		t.Fatalf("expected InvalidAuthentication without a subject, got %+v", updated.Status)
+	}
+
+	updated.Spec.Authentication.Subject = "CN=billing,O=example"
+	if err := c.Update(ctx, &updated); err != nil {
+		t.Fatalf("update user: %v", err)
+	}
+	reconcileUser(t, r, "billing")
+	cred := readUserCredential(t, endpoints, "billing")
+	if cred.TLSSubject != "CN=billing,O=example" || cred.Scram != nil {
+		t.Fatalf("unexpected stored credential: %+v", cred)
+	}
+	assertNotFound(t, c, &corev1.Secret{}, "default", "billing")
+}
+
+func TestUserSecretMapsToReferencingUsers(t *testing.T) {
+	external := &kafscalev1alpha1.KafscaleUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleUserSpec{
+			ClusterRef:     "prod",
+			Authentication: kafscalev1alpha1.UserAuthenticationSpec{Type: "scram-sha-256", SecretName: "shared-creds"},
+		},
+	}
+	generated := &kafscalev1alpha1.KafscaleUser{
+		ObjectMeta: metav1.ObjectMeta{Name: "bob", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleUserSpec{
+			ClusterRef:     "prod",
+			Authentication: kafscalev1alpha1.UserAuthenticationSpec{Type: "scram-sha-256"},
+		},
+	}
+	other := external.DeepCopy()
+	other.Namespace = "other"
+	scheme := testScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(external, generated, other).Build()
+	r := &UserReconciler{Client: c, Scheme: scheme}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "shared-creds", Namespace: "default"}}
+	requests := r.usersForSecret(context.Background(), secret)
+	if len(requests) != 1 || requests[0].Name != "alice" || requests[0].Namespace != "default" {
+		t.Fatalf("expected external secret to map to alice, got %+v", requests)
+	}
+	secret.Name = "bob"
+	if requests := r.usersForSecret(context.Background(), secret); len(requests) != 1 || requests[0].Name != "bob" {
+		t.Fatalf("expected generated secret to map to bob, got %+v", requests)
+	}
+	secret.Name = "unrelated"
+	if requests := r.usersForSecret(context.Background(), secret); len(requests) != 0 {
+		t.Fatalf("expected no users for an unrelated secret, got %+v", requests)
+	}
+}
+
+func reconcileUser(t *testing.T, r *UserReconciler, name string) {
+	t.Helper()
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}); err != nil {
+		t.Fatalf("reconcile %s: %v", name, err)
+	}
+}
+
+func readUserCredential(t *testing.T, endpoints []string, name string) metadata.UserCredential {
+	t.Helper()
+	raw, err := getEtcdValue(context.Background(), endpoints, metadata.UserCredentialKey(name))
+	if err != nil || raw == nil {
+		t.Fatalf("read credential %s: %v", name, err)
+	}
+	var cred metadata.UserCredential
+	if err := json.Unmarshal(raw, &cred); err != nil {
+		t.Fatalf("decode credential: %v", err)
+	}
+	return cred
+}
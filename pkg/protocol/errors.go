@@ -19,6 +19,7 @@ const (
 	NONE                         int16 = 0
 	OFFSET_OUT_OF_RANGE          int16 = 1
 	UNKNOWN_TOPIC_OR_PARTITION   int16 = 3
+	NOT_LEADER_OR_FOLLOWER       int16 = 6
 	UNKNOWN_TOPIC_ID             int16 = 100
 	UNKNOWN_SERVER_ERROR         int16 = -1
 	REQUEST_TIMED_OUT            int16 = 7
@@ -47,10 +47,12 @@ type PartitionLog struct {
 	cfg          PartitionLogConfig
 	buffer       *WriteBuffer
 	nextOffset   int64
+	flushed      int64
 	onFlush      func(context.Context, *SegmentArtifact)
 	onS3Op       func(string, time.Duration, error)
 	segments     []segmentRange
 	indexEntries map[int64][]*IndexEntry
+	closed       bool
 	prefetchMu   sync.Mutex
 	mu           sync.Mutex
 }
@@ -64,6 +66,9 @@ type segmentRange struct {
 // ErrOffsetOutOfRange is returned when the requested offset is outside persisted data.
 var ErrOffsetOutOfRange = errors.New("offset out of range")
 
+// ErrLogClosed is returned when appending to a closed log.
+var ErrLogClosed = errors.New("partition log closed")
+
 // NewPartitionLog constructs a log for a topic partition.
 func NewPartitionLog(namespace string, topic string, partition int32, startOffset int64, s3Client S3Client, cache *cache.SegmentCache, cfg PartitionLogConfig, onFlush func(context.Context, *SegmentArtifact), onS3Op func(string, time.Duration, error)) *PartitionLog {
 	if namespace == "" {
@@ -78,6 +83,7 @@ func NewPartitionLog(namespace string, topic string, partition int32, startOffse
 		cfg:          cfg,
 		buffer:       NewWriteBuffer(cfg.Buffer),
 		nextOffset:   startOffset,
+		flushed:      startOffset,
 		onFlush:      onFlush,
 		onS3Op:       onS3Op,
 		segments:     make([]segmentRange, 0),
@@ -162,6 +168,9 @@ func (l *PartitionLog) RestoreFromS3(ctx context.Context) (int64, error) {
 	if last >= l.nextOffset {
 		l.nextOffset = last + 1
 	}
+	if last >= l.flushed {
+		l.flushed = last + 1
+	}
 	l.mu.Unlock()
 
 	return last, nil
@@ -172,6 +181,10 @@ func (l *PartitionLog) AppendBatch(ctx context.Context, batch RecordBatch) (*App
 	var flushed *SegmentArtifact
 
 	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, ErrLogClosed
+	}
 	baseOffset := l.nextOffset
 	PatchRecordBatchBaseOffset(&batch, baseOffset)
 	l.nextOffset = baseOffset + int64(batch.LastOffsetDelta) + 1
@@ -207,6 +220,21 @@ func (l *PartitionLog) EarliestOffset() int64 {
 	return l.segments[0].baseOffset
 }
 
+// LogEndOffset returns the offset that the next appended record will get.
+func (l *PartitionLog) LogEndOffset() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.nextOffset
+}
+
+// HighWatermark returns the offset after the last record persisted to S3.
+// Records between the high watermark and the log end offset are buffered.
+func (l *PartitionLog) HighWatermark() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.flushed
+}
+
 // Flush forces buffered batches to be written to S3 immediately.
 func (l *PartitionLog) Flush(ctx context.Context) error {
 	l.mu.Lock()
@@ -230,6 +258,28 @@ func (l *PartitionLog) Flush(ctx context.Context) error {
 	return nil
 }
 
+// Close flushes buffered batches to S3 and rejects further appends. Reads of
+// flushed segments keep working. If the flush fails the log stays open.
+func (l *PartitionLog) Close(ctx context.Context) error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	artifact, err := l.flushLocked(ctx)
+	if err == nil {
+		l.closed = true
+	}
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if artifact != nil && l.onFlush != nil {
+		l.onFlush(ctx, artifact)
+	}
+	return nil
+}
+
 func (l *PartitionLog) flushLocked(ctx context.Context) (*SegmentArtifact, error) {
 	batches := l.buffer.Drain()
 	if len(batches) == 0 {
@@ -248,6 +298,7 @@ func (l *PartitionLog) flushLocked(ctx context.Context) (*SegmentArtifact, error
 		l.onS3Op("upload_segment", time.Since(start), uploadErr)
 	}
 	if uploadErr != nil {
+		l.requeueLocked(batches)
 		return nil, uploadErr
 	}
 	start = time.Now()
@@ -256,6 +307,7 @@ func (l *PartitionLog) flushLocked(ctx context.Context) (*SegmentArtifact, error
 		l.onS3Op("upload_index", time.Since(start), uploadErr)
 	}
 	if uploadErr != nil {
+		l.requeueLocked(batches)
 		return nil, uploadErr
 	}
 	if l.cache != nil && l.cfg.CacheEnabled {
@@ -269,10 +321,19 @@ func (l *PartitionLog) flushLocked(ctx context.Context) (*SegmentArtifact, error
 	if artifact.RelativeIndex != nil {
 		l.indexEntries[artifact.BaseOffset] = artifact.RelativeIndex
 	}
+	l.flushed = artifact.LastOffset + 1
 	l.startPrefetch(ctx, len(l.segments)-1)
 	return artifact, nil
 }
 
+// requeueLocked puts batches back after a failed upload so the next flush
+// retries them instead of dropping acknowledged records.
+func (l *PartitionLog) requeueLocked(batches []RecordBatch) {
+	for _, batch := range batches {
+		l.buffer.Append(batch)
+	}
+}
+
 func (l *PartitionLog) segmentKey(baseOffset int64) string {
 	return path.Join(l.namespace, l.topic, fmt.Sprintf("%d", l.partition), fmt.Sprintf("segment-%020d.kfs", baseOffset))
 }
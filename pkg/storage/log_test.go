@@ -18,6 +18,7 @@ package storage
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"testing"
 	"time"
 
@@ -96,6 +97,32 @@ func TestPartitionLogRead(t *testing.T) {
 	}
 }
 
+func TestPartitionLogHighWatermark(t *testing.T) {
+	log := NewPartitionLog("default", "orders", 0, 5, NewMemoryS3Client(), nil, PartitionLogConfig{
+		Buffer: WriteBufferConfig{
+			MaxBytes:      1 << 20,
+			FlushInterval: time.Hour,
+		},
+		Segment: SegmentWriterConfig{
+			IndexIntervalMessages: 1,
+		},
+	}, nil, nil)
+
+	batch, _ := NewRecordBatchFromBytes(make([]byte, 70))
+	if _, err := log.AppendBatch(context.Background(), batch); err != nil {
+		t.Fatalf("AppendBatch: %v", err)
+	}
+	if end, hw := log.LogEndOffset(), log.HighWatermark(); end != 6 || hw != 5 {
+		t.Fatalf("expected buffered batch to advance only the log end offset, got end=%d hw=%d", end, hw)
+	}
+	if err := log.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if hw := log.HighWatermark(); hw != 6 {
+		t.Fatalf("expected high watermark 6 after flush, got %d", hw)
+	}
+}
+
 func TestPartitionLogReadUsesIndexRange(t *testing.T) {
 	s3 := NewMemoryS3Client()
 	log := NewPartitionLog("default", "orders", 0, 0, s3, nil, PartitionLogConfig{
@@ -170,6 +197,83 @@ func TestPartitionLogReportsS3Uploads(t *testing.T) {
 	}
 }
 
+type flakyUploadS3 struct {
+	S3Client
+	fail bool
+}
+
+func (f *flakyUploadS3) UploadSegment(ctx context.Context, key string, body []byte) error {
+	if f.fail {
+		return errors.New("upload failed")
+	}
+	return f.S3Client.UploadSegment(ctx, key, body)
+}
+
+func TestPartitionLogFlushRetriesAfterUploadFailure(t *testing.T) {
+	s3 := &flakyUploadS3{S3Client: NewMemoryS3Client(), fail: true}
+	log := NewPartitionLog("default", "orders", 0, 0, s3, nil, PartitionLogConfig{
+		Buffer: WriteBufferConfig{
+			MaxBytes:      1 << 20,
+			FlushInterval: time.Hour,
+		},
+		Segment: SegmentWriterConfig{
+			IndexIntervalMessages: 1,
+		},
+	}, nil, nil)
+
+	batch, _ := NewRecordBatchFromBytes(make([]byte, 70))
+	if _, err := log.AppendBatch(context.Background(), batch); err != nil {
+		t.Fatalf("AppendBatch: %v", err)
+	}
+	if err := log.Flush(context.Background()); err == nil {
+		t.Fatalf("expected flush error")
+	}
+	s3.fail = false
+	if err := log.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush retry: %v", err)
+	}
+	if hw := log.HighWatermark(); hw != 1 {
+		t.Fatalf("expected buffered batch persisted on retry, got high watermark %d", hw)
+	}
+}
+
+func TestPartitionLogCloseFlushesAndRejectsAppends(t *testing.T) {
+	s3 := &flakyUploadS3{S3Client: NewMemoryS3Client(), fail: true}
+	log := NewPartitionLog("default", "orders", 0, 0, s3, nil, PartitionLogConfig{
+		Buffer: WriteBufferConfig{
+			MaxBytes:      1 << 20,
+			FlushInterval: time.Hour,
+		},
+		Segment: SegmentWriterConfig{
+			IndexIntervalMessages: 1,
+		},
+	}, nil, nil)
+
+	batch, _ := NewRecordBatchFromBytes(make([]byte, 70))
+	if _, err := log.AppendBatch(context.Background(), batch); err != nil {
+		t.Fatalf("AppendBatch: %v", err)
+	}
+	if err := log.Close(context.Background()); err == nil {
+		t.Fatalf("expected close to fail while the upload fails")
+	}
+	if _, err := log.AppendBatch(context.Background(), batch); err != nil {
+		t.Fatalf("expected log to stay open after a failed close: %v", err)
+	}
+	s3.fail = false
+	if err := log.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if hw := log.HighWatermark(); hw != 2 {
+		t.Fatalf("expected close to flush both batches, got high watermark %d", hw)
+	}
+	if _, err := log.AppendBatch(context.Background(), batch); !errors.Is(err, ErrLogClosed) {
+		t.Fatalf("expected ErrLogClosed, got %v", err)
+	}
+	if _, err := log.Read(context.Background(), 0, 1<<20); err != nil {
+		t.Fatalf("expected flushed data readable after close: %v", err)
+	}
+}
+
 func TestPartitionLogRestoreFromS3(t *testing.T) {
 	s3 := NewMemoryS3Client()
 	c := cache.NewSegmentCache(1024)
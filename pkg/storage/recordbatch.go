@@ -48,27 +48,40 @@ func PatchRecordBatchBaseOffset(batch *RecordBatch, baseOffset int64) {
 // record set is expected to be a concatenation of Kafka record batches as
 // produced by the broker.
 func CountRecordBatchMessages(recordSet []byte) int {
+	return CountRecordBatchMessagesFrom(recordSet, 0)
+}
+
+// CountRecordBatchMessagesFrom counts the messages at or after offset. Fetches
+// return whole batches, so the first batch may start before the fetch offset.
+func CountRecordBatchMessagesFrom(recordSet []byte, offset int64) int {
 	const frameHeaderLen = 12
 	if len(recordSet) < recordBatchHeaderMinSize {
 		return 0
 	}
 	total := 0
-	offset := 0
-	for offset+frameHeaderLen <= len(recordSet) {
-		batchLen := int(binary.BigEndian.Uint32(recordSet[offset+8 : offset+12]))
+	pos := 0
+	for pos+frameHeaderLen <= len(recordSet) {
+		batchLen := int(binary.BigEndian.Uint32(recordSet[pos+8 : pos+12]))
 		if batchLen <= 0 {
 			break
 		}
 		frameLen := frameHeaderLen + batchLen
-		if offset+frameLen > len(recordSet) {
+		if pos+frameLen > len(recordSet) {
 			break
 		}
-		batch := recordSet[offset : offset+frameLen]
+		batch := recordSet[pos : pos+frameLen]
 		if len(batch) < recordBatchHeaderMinSize {
 			break
 		}
-		total += int(binary.BigEndian.Uint32(batch[57:61]))
-		offset += frameLen
+		baseOffset := int64(binary.BigEndian.Uint64(batch[0:8]))
+		count := int64(binary.BigEndian.Uint32(batch[57:61]))
+		if skip := offset - baseOffset; skip > 0 {
+			count -= skip
+		}
+		if count > 0 {
+			total += int(count)
+		}
+		pos += frameLen
 	}
 	return total
 }
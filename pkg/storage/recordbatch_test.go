@@ -61,6 +61,18 @@ func TestCountRecordBatchMessagesIgnoresPartialBatch(t *testing.T) {
 	}
 }
 
+func TestCountRecordBatchMessagesFromSkipsEarlierRecords(t *testing.T) {
+	first := makeRecordBatch(5, 0)
+	second := makeRecordBatch(3, 5)
+	recordSet := append(first, second...)
+	if got := CountRecordBatchMessagesFrom(recordSet, 3); got != 5 {
+		t.Fatalf("expected 5 messages from offset 3, got %d", got)
+	}
+	if got := CountRecordBatchMessagesFrom(recordSet, 6); got != 2 {
+		t.Fatalf("expected 2 messages from offset 6, got %d", got)
+	}
+}
+
 func makeRecordBatch(count int32, baseOffset int64) []byte {
 	const size = 90
 	data := make([]byte, size)
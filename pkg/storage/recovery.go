@@ -0,0 +1,107 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PartitionExtent summarizes the segments stored in S3 for one partition.
+type PartitionExtent struct {
+	Topic          string
+	Partition      int32
+	Segments       int
+	LogStartOffset int64
+	LastOffset     int64
+}
+
+// ScanPartitionExtents walks namespace/topic/partition/segment-*.kfs and
+// reports the offset range of every partition found. Only the footer of each
+// partition's newest segment is downloaded.
+func ScanPartitionExtents(ctx context.Context, s3 S3Client, namespace string) ([]PartitionExtent, error) {
+	prefix := strings.Trim(namespace, "/") + "/"
+	objects, err := s3.ListSegments(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	type partitionKey struct {
+		topic     string
+		partition int32
+	}
+	type newest struct {
+		key  string
+		base int64
+		size int64
+	}
+	extents := make(map[partitionKey]*PartitionExtent)
+	latest := make(map[partitionKey]newest)
+	for _, obj := range objects {
+		parts := strings.Split(strings.TrimPrefix(obj.Key, prefix), "/")
+		if len(parts) != 3 || parts[0] == "" {
+			continue
+		}
+		base, ok := parseSegmentBaseOffset(obj.Key)
+		if !ok {
+			continue
+		}
+		partition, err := strconv.ParseInt(strings.TrimPrefix(parts[1], "partition-"), 10, 32)
+		if err != nil || partition < 0 {
+			continue
+		}
+		key := partitionKey{topic: parts[0], partition: int32(partition)}
+		ext := extents[key]
+		if ext == nil {
+			ext = &PartitionExtent{Topic: key.topic, Partition: key.partition, LogStartOffset: base}
+			extents[key] = ext
+		}
+		ext.Segments++
+		if base < ext.LogStartOffset {
+			ext.LogStartOffset = base
+		}
+		if cur, ok := latest[key]; !ok || base > cur.base {
+			latest[key] = newest{key: obj.Key, base: base, size: obj.Size}
+		}
+	}
+
+	out := make([]PartitionExtent, 0, len(extents))
+	for key, ext := range extents {
+		seg := latest[key]
+		if seg.size < segmentFooterLen {
+			return nil, fmt.Errorf("segment %s too small for footer", seg.key)
+		}
+		footer, err := s3.DownloadSegment(ctx, seg.key, &ByteRange{Start: seg.size - segmentFooterLen, End: seg.size - 1})
+		if err != nil {
+			return nil, err
+		}
+		last, err := parseSegmentFooter(footer)
+		if err != nil {
+			return nil, fmt.Errorf("parse footer %s: %w", seg.key, err)
+		}
+		ext.LastOffset = last
+		out = append(out, *ext)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Topic != out[j].Topic {
+			return out[i].Topic < out[j].Topic
+		}
+		return out[i].Partition < out[j].Partition
+	})
+	return out, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestScanPartitionExtents(t *testing.T) {
+	s3 := NewMemoryS3Client()
+	ctx := context.Background()
+	write := func(topic string, partition int32, batches int) {
+		log := NewPartitionLog("prod", topic, partition, 0, s3, nil, PartitionLogConfig{
+			Buffer:  WriteBufferConfig{MaxBytes: 1, FlushInterval: time.Millisecond},
+			Segment: SegmentWriterConfig{IndexIntervalMessages: 1},
+		}, nil, nil)
+		for i := 0; i < batches; i++ {
+			batch, err := NewRecordBatchFromBytes(make([]byte, 70))
+			if err != nil {
+				t.Fatalf("NewRecordBatchFromBytes: %v", err)
+			}
+			if _, err := log.AppendBatch(ctx, batch); err != nil {
+				t.Fatalf("AppendBatch: %v", err)
+			}
+			if err := log.Flush(ctx); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+		}
+	}
+	write("orders", 1, 3)
+	write("orders", 0, 1)
+	write("payments", 0, 2)
+	if err := s3.UploadSegment(ctx, "prod/orders/notes.txt", []byte("x")); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if err := s3.UploadSegment(ctx, "staging/orders/0/segment-00000000000000000000.kfs", []byte("x")); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+
+	extents, err := ScanPartitionExtents(ctx, s3, "prod")
+	if err != nil {
+		t.Fatalf("ScanPartitionExtents: %v", err)
+	}
+	want := []PartitionExtent{
+		{Topic: "orders", Partition: 0, Segments: 1, LogStartOffset: 0, LastOffset: 0},
+		{Topic: "orders", Partition: 1, Segments: 3, LogStartOffset: 0, LastOffset: 2},
+		{Topic: "payments", Partition: 0, Segments: 2, LogStartOffset: 0, LastOffset: 1},
+	}
+	if len(extents) != len(want) {
+		t.Fatalf("expected %d extents, got %+v", len(want), extents)
+	}
+	for i := range want {
+		if extents[i] != want[i] {
+			t.Fatalf("extent %d: expected %+v, got %+v", i, want[i], extents[i])
+		}
+	}
+}
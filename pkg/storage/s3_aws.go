@@ -36,6 +36,7 @@ type awsS3API interface {
 	HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error)
 	CreateBucket(ctx context.Context, params *s3.CreateBucketInput, optFns ...func(*s3.Options)) (*s3.CreateBucketOutput, error)
 	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
 }
 
 type awsS3Client struct {
@@ -262,3 +263,34 @@ func (c *awsS3Client) ListSegments(ctx context.Context, prefix string) ([]S3Obje
 	}
 	return out, nil
 }
+
+func (c *awsS3Client) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	objects, err := c.ListSegments(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	// DeleteObjects accepts at most 1000 keys per call.
+	for start := 0; start < len(objects); start += 1000 {
+		end := start + 1000
+		if end > len(objects) {
+			end = len(objects)
+		}
+		ids := make([]types.ObjectIdentifier, 0, end-start)
+		for _, obj := range objects[start:end] {
+			ids = append(ids, types.ObjectIdentifier{Key: aws.String(obj.Key)})
+		}
+		resp, err := c.api.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(c.bucket),
+			Delete: &types.Delete{Objects: ids, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("delete objects %s: %w", prefix, err)
+		}
+		if len(resp.Errors) > 0 {
+			return deleted, fmt.Errorf("delete objects %s: %s: %s", prefix, aws.ToString(resp.Errors[0].Key), aws.ToString(resp.Errors[0].Message))
+		}
+		deleted += len(ids)
+	}
+	return deleted, nil
+}
@@ -106,3 +106,18 @@ func (m *MemoryS3Client) ListSegments(ctx context.Context, prefix string) ([]S3O
 	}
 	return out, nil
 }
+
+func (m *MemoryS3Client) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	deleted := 0
+	for _, objects := range []map[string][]byte{m.data, m.index} {
+		for key := range objects {
+			if strings.HasPrefix(key, prefix) {
+				delete(objects, key)
+				deleted++
+			}
+		}
+	}
+	return deleted, nil
+}
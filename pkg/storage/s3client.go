@@ -44,6 +44,12 @@ type S3Client interface {
 	EnsureBucket(ctx context.Context) error
 }
 
+// PrefixDeleter is implemented by S3 clients that can remove every object
+// under a key prefix, segments and indexes alike.
+type PrefixDeleter interface {
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
 // S3Object describes a stored segment object.
 type S3Object struct {
 	Key  string
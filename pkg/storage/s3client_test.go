@@ -23,6 +23,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 type fakeS3 struct {
@@ -34,6 +35,8 @@ type fakeS3 struct {
 	headErr   error
 	createErr error
 	listErr   error
+	listKeys  []string
+	deleted   []string
 }
 
 func (f *fakeS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
@@ -60,7 +63,18 @@ func (f *fakeS3) CreateBucket(ctx context.Context, params *s3.CreateBucketInput,
 }
 
 func (f *fakeS3) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
-	return &s3.ListObjectsV2Output{}, f.listErr
+	out := &s3.ListObjectsV2Output{}
+	for _, key := range f.listKeys {
+		out.Contents = append(out.Contents, types.Object{Key: aws.String(key)})
+	}
+	return out, f.listErr
+}
+
+func (f *fakeS3) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	for _, obj := range params.Delete.Objects {
+		f.deleted = append(f.deleted, aws.ToString(obj.Key))
+	}
+	return &s3.DeleteObjectsOutput{}, nil
 }
 
 func TestAWSS3Client_Upload(t *testing.T) {
@@ -102,3 +116,16 @@ func TestAWSS3Client_Download(t *testing.T) {
 		t.Fatalf("bucket mismatch: %s", aws.ToString(api.getInput.Bucket))
 	}
 }
+
+func TestAWSS3Client_DeletePrefix(t *testing.T) {
+	api := &fakeS3{listKeys: []string{"default/orders/0/segment-00000000000000000000.kfs", "default/orders/0/segment-00000000000000000000.index"}}
+	client := newAWSClientWithAPI("test-bucket", "us-east-1", "", api)
+
+	deleted, err := client.(PrefixDeleter).DeletePrefix(context.Background(), "default/orders/")
+	if err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	if deleted != 2 || len(api.deleted) != 2 {
+		t.Fatalf("expected 2 deleted objects, got %d (%v)", deleted, api.deleted)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	controlpb "github.com/KafScale/platform/pkg/gen/control"
+	"github.com/KafScale/platform/pkg/operator"
+)
+
+// drainingBrokerControl reports its partition pending for the first two
+// drain calls, then flushed and released.
+type drainingBrokerControl struct {
+	controlpb.UnimplementedBrokerControlServer
+	drains atomic.Int32
+}
+
+func (b *drainingBrokerControl) DrainPartitions(ctx context.Context, req *controlpb.DrainPartitionsRequest) (*controlpb.DrainPartitionsResponse, error) {
+	ref := &controlpb.PartitionRef{Topic: "orders", Partition: 0}
+	if b.drains.Add(1) <= 2 {
+		return &controlpb.DrainPartitionsResponse{Pending: []*controlpb.PartitionRef{ref}}, nil
+	}
+	return &controlpb.DrainPartitionsResponse{Drained: []*controlpb.PartitionRef{ref}}, nil
+}
+
+func (b *drainingBrokerControl) GetStatus(ctx context.Context, req *controlpb.BrokerStatusRequest) (*controlpb.BrokerStatusResponse, error) {
+	return &controlpb.BrokerStatusResponse{Ready: false}, nil
+}
+
+func TestOperatorDrainsBrokerPodBeforeRemoval(t *testing.T) {
+	setupTestLogger()
+	if !parseBoolEnv("KAFSCALE_E2E") {
+		t.Skip("set KAFSCALE_E2E=1 to run operator envtest")
+	}
+	if !envtestAssetsAvailable() {
+		t.Skip("envtest assets missing; set KUBEBUILDER_ASSETS or install setup-envtest")
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	broker := &drainingBrokerControl{}
+	server := grpc.NewServer()
+	controlpb.RegisterBrokerControlServer(server, broker)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join(repoRoot(t), "deploy", "helm", "kafscale", "crds")},
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("start envtest: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := env.Stop(); err != nil {
+			t.Fatalf("stop envtest: %v", err)
+		}
+	})
+
+	scheme := k8sruntime.NewScheme()
+	utilruntime.Must(kafscalev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(corev1.AddToScheme(scheme))
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		Controller: config.Controller{
+			SkipNameValidation: ptr.To(true),
+		},
+	})
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	drainer := operator.NewBrokerDrainReconciler(mgr)
+	drainer.ControlPort = int32(lis.Addr().(*net.TCPAddr).Port)
+	if err := drainer.SetupWithManager(mgr); err != nil {
+		t.Fatalf("drain reconciler: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("manager error: %v", err)
+		}
+	}()
+
+	c := mgr.GetClient()
+	cluster := &kafscalev1alpha1.KafscaleCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "drain", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleClusterSpec{
+			Brokers: kafscalev1alpha1.BrokerSpec{DrainTimeoutSeconds: ptr.To(int32(60))},
+			S3:      kafscalev1alpha1.S3Spec{Bucket: "segments", Region: "us-east-1"},
+			Etcd:    kafscalev1alpha1.EtcdSpec{Endpoints: []string{"http://127.0.0.1:2379"}},
+		},
+	}
+	if err := c.Create(ctx, cluster); err != nil {
+		t.Fatalf("create cluster: %v", err)
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "drain-broker-0",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "kafscale-broker", "cluster": cluster.Name},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "broker", Image: "kafscale-broker"}}},
+	}
+	if err := c.Create(ctx, pod); err != nil {
+		t.Fatalf("create pod: %v", err)
+	}
+	// envtest has no kubelet; fake a running pod on the fake broker's address.
+	pod.Status = corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "127.0.0.1"}
+	if err := c.Status().Update(ctx, pod); err != nil {
+		t.Fatalf("update pod status: %v", err)
+	}
+
+	key := client.ObjectKeyFromObject(pod)
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 20*time.Second, true, func(ctx context.Context) (bool, error) {
+		current := &corev1.Pod{}
+		if err := mgr.GetAPIReader().Get(ctx, key, current); err != nil {
+			return false, nil
+		}
+		return len(current.Finalizers) == 1, nil
+	}); err != nil {
+		t.Fatalf("expected drain finalizer on broker pod: %v", err)
+	}
+
+	if err := c.Delete(ctx, pod); err != nil {
+		t.Fatalf("delete pod: %v", err)
+	}
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 10*time.Second, true, func(ctx context.Context) (bool, error) {
+		current := &kafscalev1alpha1.KafscaleCluster{}
+		if err := mgr.GetAPIReader().Get(ctx, client.ObjectKeyFromObject(cluster), current); err != nil {
+			return false, nil
+		}
+		cond := apimeta.FindStatusCondition(current.Status.Conditions, "BrokerDrain")
+		return cond != nil && cond.Reason == "Draining", nil
+	}); err != nil {
+		t.Fatalf("expected draining progress on cluster status: %v", err)
+	}
+	if err := mgr.GetAPIReader().Get(ctx, key, &corev1.Pod{}); err != nil {
+		t.Fatalf("expected pod to be held while draining: %v", err)
+	}
+
+	if err := wait.PollUntilContextTimeout(ctx, 200*time.Millisecond, 30*time.Second, true, func(ctx context.Context) (bool, error) {
+		return !resourceExists(ctx, mgr.GetAPIReader(), &corev1.Pod{}, pod.Namespace, pod.Name), nil
+	}); err != nil {
+		t.Fatalf("expected pod removal after drain: %v", err)
+	}
+	current := &kafscalev1alpha1.KafscaleCluster{}
+	if err := mgr.GetAPIReader().Get(ctx, client.ObjectKeyFromObject(cluster), current); err != nil {
+		t.Fatalf("get cluster: %v", err)
+	}
+	cond := apimeta.FindStatusCondition(current.Status.Conditions, "BrokerDrain")
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "Drained" {
+		t.Fatalf("expected Drained condition, got %+v", cond)
+	}
+	if broker.drains.Load() < 3 {
+		t.Fatalf("expected repeated drain calls, got %d", broker.drains.Load())
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+	"github.com/KafScale/platform/pkg/operator"
+	"github.com/KafScale/platform/pkg/storage"
+)
+
+// TestOperatorEtcdRestoreThroughManagerCache runs a restore with the
+// operator's real cache options, so etcd pods must be visible to the cached
+// client for stale members to be replaced and the restore to advance.
+func TestOperatorEtcdRestoreThroughManagerCache(t *testing.T) {
+	setupTestLogger()
+	if !parseBoolEnv("KAFSCALE_E2E") {
+		t.Skip("set KAFSCALE_E2E=1 to run operator envtest")
+	}
+	if !envtestAssetsAvailable() {
+		t.Skip("envtest assets missing; set KUBEBUILDER_ASSETS or install setup-envtest")
+	}
+
+	t.Setenv("KAFSCALE_OPERATOR_ETCD_ENDPOINTS", "")
+	t.Setenv("KAFSCALE_OPERATOR_ETCD_REPLICAS", "1")
+	t.Setenv("KAFSCALE_OPERATOR_ETCD_SNAPSHOT_SKIP_PREFLIGHT", "1")
+	t.Setenv("KAFSCALE_OPERATOR_ETCD_SILENCE_LOGS", "1")
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join(repoRoot(t), "deploy", "helm", "kafscale", "crds")},
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("start envtest: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := env.Stop(); err != nil {
+			t.Fatalf("stop envtest: %v", err)
+		}
+	})
+
+	scheme := k8sruntime.NewScheme()
+	utilruntime.Must(kafscalev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(appsv1.AddToScheme(scheme))
+	utilruntime.Must(autoscalingv2.AddToScheme(scheme))
+	utilruntime.Must(batchv1.AddToScheme(scheme))
+	utilruntime.Must(policyv1.AddToScheme(scheme))
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: "0",
+		},
+		Controller: config.Controller{
+			SkipNameValidation: ptr.To(true),
+		},
+		Cache: operator.CacheOptions(),
+	})
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+
+	s3 := storage.NewMemoryS3Client()
+	const snapshotKey = "etcd-snapshots/20260101000000.db"
+	if err := s3.UploadSegment(context.Background(), snapshotKey, []byte("snap")); err != nil {
+		t.Fatalf("upload snapshot: %v", err)
+	}
+	reconciler := operator.NewClusterReconciler(mgr, operator.NewSnapshotPublisher(mgr.GetClient()))
+	reconciler.NewS3Client = func(context.Context, storage.S3Config) (storage.S3Client, error) {
+		return s3, nil
+	}
+	if err := reconciler.SetupWithManager(mgr); err != nil {
+		t.Fatalf("cluster reconciler: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("manager error: %v", err)
+		}
+	}()
+
+	// Read with the API reader so the assertions do not depend on the cache
+	// configuration under test.
+	api := mgr.GetAPIReader()
+	c := mgr.GetClient()
+
+	stale := etcdPod("restore-etcd-0", nil)
+	if err := c.Create(ctx, stale); err != nil {
+		t.Fatalf("create stale etcd pod: %v", err)
+	}
+
+	cluster := &kafscalev1alpha1.KafscaleCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "restore", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleClusterSpec{
+			S3:   kafscalev1alpha1.S3Spec{Bucket: "segments", Region: "us-east-1"},
+			Etcd: kafscalev1alpha1.EtcdSpec{RestoreFrom: "latest"},
+		},
+	}
+	if err := c.Create(ctx, cluster); err != nil {
+		t.Fatalf("create cluster: %v", err)
+	}
+
+	if err := wait.PollUntilContextTimeout(ctx, 200*time.Millisecond, 30*time.Second, true, func(ctx context.Context) (bool, error) {
+		err := api.Get(ctx, client.ObjectKeyFromObject(stale), &corev1.Pod{})
+		return client.IgnoreNotFound(err) == nil && err != nil, nil
+	}); err != nil {
+		t.Fatalf("expected stale etcd pod to be deleted: %v", err)
+	}
+
+	var current kafscalev1alpha1.KafscaleCluster
+	if err := api.Get(ctx, client.ObjectKeyFromObject(cluster), &current); err != nil {
+		t.Fatalf("get cluster: %v", err)
+	}
+	restore := current.Status.EtcdRestore
+	if restore == nil || restore.Snapshot != snapshotKey || restore.StartedAt == nil {
+		t.Fatalf("unexpected restore status: %+v", restore)
+	}
+
+	// Stand in for the StatefulSet controller: recreate the member with the
+	// restore annotation and mark it ready.
+	restored := etcdPod("restore-etcd-0", map[string]string{
+		"kafscale.io/etcd-restore-id": fmt.Sprintf("%s@%d", restore.Snapshot, restore.StartedAt.Unix()),
+	})
+	if err := c.Create(ctx, restored); err != nil {
+		t.Fatalf("create restored etcd pod: %v", err)
+	}
+	restored.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	if err := c.Status().Update(ctx, restored); err != nil {
+		t.Fatalf("mark etcd pod ready: %v", err)
+	}
+
+	if err := wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, 60*time.Second, true, func(ctx context.Context) (bool, error) {
+		if err := api.Get(ctx, client.ObjectKeyFromObject(cluster), &current); err != nil {
+			return false, nil
+		}
+		status := current.Status.EtcdRestore
+		return status != nil && status.Phase != "Restoring", nil
+	}); err != nil {
+		t.Fatalf("expected restore to leave the Restoring phase, got %+v: %v", current.Status.EtcdRestore, err)
+	}
+}
+
+func etcdPod(name string, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Labels:      map[string]string{"app": "kafscale-etcd", "cluster": "restore"},
+			Annotations: annotations,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "etcd", Image: "etcd"}},
+		},
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2025 Alexander Alten (novatechflow), NovaTechflow (novatechflow.com).
+// This project is supported and financed by Scalytics, Inc. (www.scalytics.io).
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	kafscalev1alpha1 "github.com/KafScale/platform/api/v1alpha1"
+)
+
+func TestOperatorAdmissionWebhooks(t *testing.T) {
+	setupTestLogger()
+	if !parseBoolEnv("KAFSCALE_E2E") {
+		t.Skip("set KAFSCALE_E2E=1 to run operator envtest")
+	}
+	if !envtestAssetsAvailable() {
+		t.Skip("envtest assets missing; set KUBEBUILDER_ASSETS or install setup-envtest")
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths: []string{filepath.Join(repoRoot(t), "deploy", "helm", "kafscale", "crds")},
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			MutatingWebhooks:   []*admissionregistrationv1.MutatingWebhookConfiguration{mutatingWebhookConfig()},
+			ValidatingWebhooks: []*admissionregistrationv1.ValidatingWebhookConfiguration{validatingWebhookConfig()},
+		},
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		t.Fatalf("start envtest: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := env.Stop(); err != nil {
+			t.Fatalf("stop envtest: %v", err)
+		}
+	})
+
+	scheme := k8sruntime.NewScheme()
+	utilruntime.Must(kafscalev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(corev1.AddToScheme(scheme))
+
+	opts := env.WebhookInstallOptions
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: "0",
+		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    opts.LocalServingHost,
+			Port:    opts.LocalServingPort,
+			CertDir: opts.LocalServingCertDir,
+		}),
+		Controller: config.Controller{
+			SkipNameValidation: ptr.To(true),
+		},
+	})
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	if err := kafscalev1alpha1.SetupKafscaleClusterWebhookWithManager(mgr); err != nil {
+		t.Fatalf("cluster webhook: %v", err)
+	}
+	if err := kafscalev1alpha1.SetupKafscaleTopicWebhookWithManager(mgr); err != nil {
+		t.Fatalf("topic webhook: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("manager error: %v", err)
+		}
+	}()
+
+	addr := net.JoinHostPort(opts.LocalServingHost, fmt.Sprint(opts.LocalServingPort))
+	if err := wait.PollUntilContextTimeout(ctx, 100*time.Millisecond, 30*time.Second, true, func(context.Context) (bool, error) {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", addr, &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return false, nil
+		}
+		_ = conn.Close()
+		return true, nil
+	}); err != nil {
+		t.Fatalf("webhook server not serving: %v", err)
+	}
+
+	c := mgr.GetClient()
+
+	cluster := &kafscalev1alpha1.KafscaleCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhooks", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleClusterSpec{
+			S3:   kafscalev1alpha1.S3Spec{Bucket: "segments", Region: "us-east-1"},
+			Etcd: kafscalev1alpha1.EtcdSpec{Endpoints: []string{"http://etcd:2379"}},
+		},
+	}
+	if err := c.Create(ctx, cluster); err != nil {
+		t.Fatalf("create cluster: %v", err)
+	}
+	if cluster.Spec.Brokers.Replicas == nil || *cluster.Spec.Brokers.Replicas != kafscalev1alpha1.DefaultBrokerReplicas {
+		t.Fatalf("expected defaulted broker replicas, got %v", cluster.Spec.Brokers.Replicas)
+	}
+	if cluster.Spec.Brokers.AdvertisedPort == nil || *cluster.Spec.Brokers.AdvertisedPort != kafscalev1alpha1.DefaultBrokerPort {
+		t.Fatalf("expected defaulted advertised port, got %v", cluster.Spec.Brokers.AdvertisedPort)
+	}
+	if cluster.Spec.Brokers.Service.Type != string(corev1.ServiceTypeClusterIP) {
+		t.Fatalf("expected defaulted service type, got %q", cluster.Spec.Brokers.Service.Type)
+	}
+
+	badCluster := &kafscalev1alpha1.KafscaleCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-bucket", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleClusterSpec{
+			S3:     kafscalev1alpha1.S3Spec{Region: "us-east-1"},
+			Config: kafscalev1alpha1.ClusterConfigSpec{CacheSize: "lots"},
+		},
+	}
+	expectRejected(t, c.Create(ctx, badCluster), "spec.s3.bucket", "spec.config.cacheSize")
+
+	topic := &kafscalev1alpha1.KafscaleTopic{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+		Spec:       kafscalev1alpha1.KafscaleTopicSpec{ClusterRef: cluster.Name, Partitions: 3},
+	}
+	if err := c.Create(ctx, topic); err != nil {
+		t.Fatalf("create topic: %v", err)
+	}
+	if topic.Spec.DeletionPolicy != kafscalev1alpha1.TopicDeletionPolicyDelete {
+		t.Fatalf("expected defaulted deletion policy, got %q", topic.Spec.DeletionPolicy)
+	}
+
+	topic.Spec.Partitions = 1
+	expectRejected(t, c.Update(ctx, topic), "spec.partitions", "cannot be decreased")
+
+	expectRejected(t, c.Create(ctx, &kafscalev1alpha1.KafscaleTopic{
+		ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "default"},
+		Spec:       kafscalev1alpha1.KafscaleTopicSpec{ClusterRef: cluster.Name},
+	}), "spec.partitions")
+	expectRejected(t, c.Create(ctx, &kafscalev1alpha1.KafscaleTopic{
+		ObjectMeta: metav1.ObjectMeta{Name: "unknown-config", Namespace: "default"},
+		Spec: kafscalev1alpha1.KafscaleTopicSpec{
+			ClusterRef: cluster.Name,
+			Partitions: 1,
+			Config:     map[string]string{"cleanup.policy": "compact"},
+		},
+	}), "spec.config", "cleanup.policy")
+}
+
+func expectRejected(t *testing.T, err error, wants ...string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected admission rejection mentioning %v", wants)
+	}
+	if !apierrors.IsInvalid(err) && !apierrors.IsForbidden(err) {
+		t.Fatalf("expected invalid/forbidden error, got %v", err)
+	}
+	for _, want := range wants {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error to mention %q, got %v", want, err)
+		}
+	}
+}
+
+func webhookClientConfig(path string) admissionregistrationv1.WebhookClientConfig {
+	return admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Name:      "kafscale-operator-webhook",
+			Namespace: "default",
+			Path:      ptr.To(path),
+		},
+	}
+}
+
+func webhookRules(resource string) []admissionregistrationv1.RuleWithOperations {
+	return []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+		Rule: admissionregistrationv1.Rule{
+			APIGroups:   []string{kafscalev1alpha1.GroupVersion.Group},
+			APIVersions: []string{kafscalev1alpha1.GroupVersion.Version},
+			Resources:   []string{resource},
+		},
+	}}
+}
+
+func mutatingWebhookConfig() *admissionregistrationv1.MutatingWebhookConfiguration {
+	cfg := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafscale-operator-mutating"},
+	}
+	for _, kind := range []string{"kafscalecluster", "kafscaletopic"} {
+		cfg.Webhooks = append(cfg.Webhooks, admissionregistrationv1.MutatingWebhook{
+			Name:                    "m" + kind + ".kafscale.io",
+			ClientConfig:            webhookClientConfig("/mutate-kafscale-io-v1alpha1-" + kind),
+			Rules:                   webhookRules(kind + "s"),
+			FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+		})
+	}
+	return cfg
+}
+
+func validatingWebhookConfig() *admissionregistrationv1.ValidatingWebhookConfiguration {
+	cfg := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafscale-operator-validating"},
+	}
+	for _, kind := range []string{"kafscalecluster", "kafscaletopic"} {
+		cfg.Webhooks = append(cfg.Webhooks, admissionregistrationv1.ValidatingWebhook{
+			Name:                    "v" + kind + ".kafscale.io",
+			ClientConfig:            webhookClientConfig("/validate-kafscale-io-v1alpha1-" + kind),
+			Rules:                   webhookRules(kind + "s"),
+			FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+		})
+	}
+	return cfg
+}